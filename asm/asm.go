@@ -1,4 +1,18 @@
 // Package asm implements a parser for LLVM IR assembly files.
+//
+// # Limitations
+//
+// The grammar of the parser does not yet support a number of LLVM IR constructs
+// which may be represented and printed by the ir package. LLVM IR assembly
+// using any of the following constructs is rejected with a syntax error:
+//
+//   - typed parameter attributes; e.g. byval(%T), sret(%T), byref(%T),
+//     preallocated(%T) and inalloca(%T) (the untyped byval, sret and inalloca
+//     parameter attributes are supported)
+//   - poison values
+//   - callbr terminators
+//   - dso_local_equivalent and no_cfi constants
+//   - function alignment in function headers; e.g. define void @f() align 16
 package asm
 
 import (
@@ -7,6 +21,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/llir/ll"
 	"github.com/llir/ll/ast"
 	"github.com/llir/llvm/ir"
 	"github.com/pkg/errors"
@@ -53,11 +68,39 @@ func ParseBytes(path string, b []byte) (*ir.Module, error) {
 // for error reporting.
 func ParseString(path, content string) (*ir.Module, error) {
 	parseStart := time.Now()
-	tree, err := ast.Parse(path, content)
+	tree, err := parseAST(path, content)
 	if err != nil {
-		return nil, errors.Wrapf(err, "unable to parse %q into an AST", path)
+		return nil, errors.Wrapf(positionError(path, content, err), "unable to parse %q into an AST", path)
 	}
 	dbg.Println("parsing into AST took:", time.Since(parseStart))
 	root := ast.ToLlvmNode(tree.Root())
-	return translate(root.(*ast.Module))
+	m, err := translate(root.(*ast.Module))
+	if err != nil {
+		return nil, positionError(path, content, err)
+	}
+	return m, nil
+}
+
+// parseAST parses the given LLVM IR assembly source into an AST. Invalid tokens
+// (e.g. keywords not yet supported by the grammar, such as poison) are reported
+// as syntax errors, rather than skipped by the parser.
+func parseAST(path, content string) (*ast.Tree, error) {
+	if err := checkTokens(content); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return ast.Parse(path, content)
+}
+
+// checkTokens returns a syntax error located at the first invalid token of the
+// given LLVM IR assembly source, if any.
+func checkTokens(content string) error {
+	l := &ll.Lexer{}
+	l.Init(content)
+	for tok := l.Next(); tok != ll.EOI; tok = l.Next() {
+		if tok == ll.INVALID_TOKEN {
+			start, end := l.Pos()
+			return ll.SyntaxError{Line: l.Line(), Offset: start, Endoffset: end}
+		}
+	}
+	return nil
 }
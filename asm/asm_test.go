@@ -2,13 +2,20 @@ package asm
 
 import (
 	"flag"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
 	"github.com/mewkiz/pkg/diffutil"
 	"github.com/mewkiz/pkg/osutil"
+	"github.com/pkg/errors"
 )
 
 // words specifies whether to colour words in diff output.
@@ -39,6 +46,55 @@ func TestParseFile(t *testing.T) {
 		// frem constant expression.
 		{path: "testdata/expr_frem.ll"},
 
+		// Loop metadata hints, including hints not decoded by llir/llvm.
+		{path: "testdata/loop_metadata.ll"},
+
+		// Convergence control tokens used in convergencectrl operand bundles.
+		{path: "testdata/convergence.ll"},
+
+		// Return of aggregate and vector values.
+		{path: "testdata/ret.ll"},
+
+		// Vector of pointers getelementptr and masked gather and scatter.
+		{path: "testdata/masked_gather.ll"},
+
+		// Metadata arguments, including local values wrapped as metadata.
+		{path: "testdata/dbg_value.ll"},
+
+		// getelementptr with undefined source address.
+		{path: "testdata/gep_undef.ll"},
+
+		// ppc_fp128 constants, including denormals.
+		{path: "testdata/ppc_fp128.ll"},
+
+		// weak and linkonce global variables and functions in comdats.
+		{path: "testdata/comdat.ll"},
+
+		// blockaddress constant stored in global variable and used by indirectbr.
+		{path: "testdata/blockaddress.ll"},
+
+		// sanitizer, fuzzing and shadow call stack function attributes.
+		{path: "testdata/sanitize.ll"},
+
+		// module header in non-canonical order.
+		{path: "testdata/header_order.ll"},
+
+		// Windows EH funclets.
+		{path: "testdata/funclet.ll"},
+
+		// Tail call markers.
+		{path: "testdata/tail.ll"},
+
+		// Instruction metadata attachments; e.g. branch weights, TBAA and range.
+		{path: "testdata/inst_metadata.ll"},
+
+		// Linkage kinds of global variables and aliases; generated by TestLinkage
+		// of package ir.
+		{path: "testdata/linkage.ll"},
+
+		// Parameter attributes; e.g. byval, sret, nonnull and align.
+		{path: "testdata/param_attrs.ll"},
+
 		// LLVM IR compatibility.
 		{path: "../testdata/llvm/test/Bitcode/compatibility.ll"},
 
@@ -94,7 +150,7 @@ func TestParseFile(t *testing.T) {
 		{path: "../testdata/llvm/test/Feature/OperandBundles/special-state.ll"},
 		//{path: "../testdata/llvm/test/Feature/alias2.ll"}, // TODO: fix grammar. syntax error at line 12. The issue is that the aliasee (a bitcast expression in this case is missing a type (in this case, the type should be i16*). `@a1 = alias i16, bitcast (i32* @v1 to i16*)`
 		//{path: "../testdata/llvm/test/Feature/aliases.ll"}, // TODO: fix grammar. syntax error at line 29. The issue is that the aliasee (a bitcast expression in this case is missing a type (in this case, the type should be i64*). `@A = alias i64, bitcast (i32* @bar to i64*)`
-		//{path: "../testdata/llvm/test/Feature/alignment.ll"}, // TODO: fix grammar. syntax error at line 7. The issue is that there is a parsing ambiguity between GlobalAttr and FuncAttr, both of which may be empty and both of which may contain Align.
+		//{path: "../testdata/llvm/test/Feature/alignment.ll"}, // TODO: blocked on llir/ll grammar. syntax error at line 7. The issue is that there is a parsing ambiguity between GlobalAttr and FuncAttr, both of which may be empty and both of which may contain Align; thus function alignment is rejected in function headers. `define i32* @test() align 32 {`
 		{path: "../testdata/llvm/test/Feature/attributes.ll"},
 		{path: "../testdata/llvm/test/Feature/basictest.ll"},
 		{path: "../testdata/llvm/test/Feature/callingconventions.ll"},
@@ -393,7 +449,7 @@ func TestParseFile(t *testing.T) {
 		{path: "../testdata/llvm/test/DebugInfo/strip-DIGlobalVariable.ll"},
 		{path: "../testdata/llvm/test/DebugInfo/strip-loop-metadata.ll"},
 		{path: "../testdata/llvm/test/DebugInfo/strip-module-flags.ll"},
-		//{path: "../testdata/llvm/test/DebugInfo/unrolled-loop-remainder.ll"}, // TODO: figure out how to handle duplicate (but distinct) AttrGroupDef
+		{path: "../testdata/llvm/test/DebugInfo/unrolled-loop-remainder.ll"},
 
 		// LLVM test/DebugInfo/X86.
 		{path: "../testdata/llvm/test/DebugInfo/X86/clang-module.ll"},
@@ -552,3 +608,967 @@ func TestParseFile(t *testing.T) {
 		}
 	}
 }
+
+func TestParseStringAttrGroupDefs(t *testing.T) {
+	golden := []struct {
+		in   string
+		want string
+		err  string
+	}{
+		// Identical duplicate attribute group definitions.
+		{
+			in:   "attributes #0 = { nounwind readnone }\nattributes #0 = { nounwind readnone }\n",
+			want: "attributes #0 = { nounwind readnone }\n",
+		},
+		// Conflicting duplicate attribute group definitions.
+		{
+			in:  "attributes #0 = { nounwind }\nattributes #0 = { readnone }\n",
+			err: `attribute group ID "#0" already present with different attributes`,
+		},
+	}
+	for _, g := range golden {
+		m, err := ParseString("<stdin>", g.in)
+		if len(g.err) > 0 {
+			if err == nil {
+				t.Errorf("expected error containing %q, got nil", g.err)
+				continue
+			}
+			if !strings.Contains(err.Error(), g.err) {
+				t.Errorf("error mismatch; expected error containing %q, got %q", g.err, err.Error())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unable to parse %q; %+v", g.in, err)
+			continue
+		}
+		got := m.String()
+		if g.want != got {
+			t.Errorf("module mismatch; expected `%s`, got `%s`", g.want, got)
+		}
+	}
+}
+
+func TestParseStringGlobalRedefinition(t *testing.T) {
+	golden := []struct {
+		in   string
+		want string
+	}{
+		// Global variable redefinition.
+		{
+			in:   "@B = global i32 7\n@B = global i32 7\n",
+			want: "2:1: redefinition of global '@B'",
+		},
+		// Function redefinition.
+		{
+			in:   "define void @B() {\n\tret void\n}\n\ndefine void @B() {\n\tret void\n}\n",
+			want: "5:1: redefinition of global '@B'",
+		},
+		// Alias redefinition.
+		{
+			in:   "@A = global i32 7\n@B = alias i32, i32* @A\n@B = alias i32, i32* @A\n",
+			want: "3:1: redefinition of global '@B'",
+		},
+		// Function and global variable sharing name.
+		{
+			in:   "declare void @B()\n@B = global i32 7\n",
+			want: "2:1: redefinition of global '@B'",
+		},
+	}
+	for _, g := range golden {
+		m, err := ParseString("<stdin>", g.in)
+		if err == nil {
+			t.Errorf("expected error containing %q, got nil", g.want)
+			continue
+		}
+		if !strings.Contains(err.Error(), g.want) {
+			t.Errorf("error mismatch; expected error containing %q, got %q", g.want, err.Error())
+		}
+		if m != nil {
+			t.Errorf("expected nil module on error, got %v", m)
+		}
+	}
+}
+
+func TestParseStringError(t *testing.T) {
+	golden := []struct {
+		in   string
+		want *Error
+	}{
+		// Syntax error.
+		{
+			in:   "@x = global i32 7\n@y = global i32 7 8\n",
+			want: &Error{Filename: "foo.ll", Line: 2, Column: 19, Msg: "syntax error"},
+		},
+		// Semantic error.
+		{
+			in:   "@x = global i32 7\n\n  @x = global i32 7\n",
+			want: &Error{Filename: "foo.ll", Line: 3, Column: 3, Msg: "redefinition of global '@x'"},
+		},
+		// Undefined local identifier.
+		{
+			in:   "define i32 @f(i32 %a) {\nentry:\n\t%b = add i32 %a, %c\n\tret i32 %b\n}\n",
+			want: &Error{Filename: "foo.ll", Line: 3, Column: 19, Msg: `undefined local identifier "%c" in function "@f"`},
+		},
+		// Constant expression type mismatch.
+		{
+			in:   "@x = global i32 7\n@y = global i64 add (i32 1, i32 2)\n",
+			want: &Error{Filename: "foo.ll", Line: 2, Column: 17, Msg: `constant expression type mismatch; expected "i32", got "i64"`},
+		},
+	}
+	for _, g := range golden {
+		_, err := ParseString("foo.ll", g.in)
+		if err == nil {
+			t.Errorf("expected error %q, got nil", g.want)
+			continue
+		}
+		got, ok := errors.Cause(err).(*Error)
+		if !ok {
+			t.Errorf("invalid error type; expected *asm.Error, got %T (%v)", errors.Cause(err), err)
+			continue
+		}
+		if *got != *g.want {
+			t.Errorf("error mismatch; expected %#v, got %#v", g.want, got)
+		}
+	}
+}
+
+func TestParseFileWithErrors(t *testing.T) {
+	// Two independent syntax errors, in the global variable @x and the function
+	// @g.
+	const path = "testdata/recover.ll"
+	m, errs := ParseFileWithErrors(path)
+	var lines []int
+	for _, err := range errs {
+		if err.Filename != path {
+			t.Errorf("filename mismatch of error %q; expected %q, got %q", err, path, err.Filename)
+		}
+		lines = append(lines, err.Line)
+	}
+	if got, want := fmt.Sprint(lines), "[1 11]"; got != want {
+		t.Errorf("error lines mismatch; expected %s, got %s (%v)", want, got, errs)
+	}
+	if m == nil {
+		t.Fatalf("expected partial module, got nil")
+	}
+	// Partial module.
+	var names []string
+	for _, f := range m.Funcs {
+		names = append(names, f.Name())
+	}
+	if got, want := strings.Join(names, " "), "f h"; got != want {
+		t.Errorf("functions mismatch of partial module; expected %q, got %q", want, got)
+	}
+	if len(m.Globals) != 0 {
+		t.Errorf("global count mismatch of partial module; expected 0, got %d", len(m.Globals))
+	}
+	// Semantic error of top-level entity referring to skipped top-level entity.
+	const in = "@x = global i32 7 8\n@y = global i32* @x\n@z = global i32 1\n"
+	m, errs = ParseStringWithErrors("foo.ll", in)
+	lines = nil
+	for _, err := range errs {
+		lines = append(lines, err.Line)
+	}
+	if got, want := fmt.Sprint(lines), "[1 2]"; got != want {
+		t.Errorf("error lines mismatch; expected %s, got %s (%v)", want, got, errs)
+	}
+	if m == nil || len(m.Globals) != 1 || m.Globals[0].Name() != "z" {
+		t.Errorf("globals mismatch of partial module; expected @z, got %v", m)
+	}
+	// Semantic errors in function bodies; an undefined local identifier in @f,
+	// and a redefined local identifier in @g (located at the function
+	// definition).
+	const in2 = `@x = global i32 7 8
+define i32 @f(i32 %a) {
+entry:
+	%b = add i32 %a, %c
+	ret i32 %b
+}
+define i32 @g(i32 %a) {
+entry:
+	%b = add i32 %a, 1
+	%b = add i32 %a, 2
+	ret i32 %b
+}
+define i32 @h() {
+entry:
+	ret i32 0
+}
+`
+	m, errs = ParseStringWithErrors("foo.ll", in2)
+	lines = nil
+	for _, err := range errs {
+		lines = append(lines, err.Line)
+	}
+	if got, want := fmt.Sprint(lines), "[1 4 7]"; got != want {
+		t.Errorf("error lines mismatch; expected %s, got %s (%v)", want, got, errs)
+	}
+	if m == nil || len(m.Funcs) != 1 || m.Funcs[0].Name() != "h" {
+		t.Errorf("functions mismatch of partial module; expected @h, got %v", m)
+	}
+}
+
+func TestValidateElemTypes(t *testing.T) {
+	vscale := types.NewScalableVector(4, types.I32)
+	golden := []struct {
+		in  types.Type
+		err string
+	}{
+		// Scalable vector type.
+		{in: vscale},
+		// Fixed array of fixed vectors.
+		{in: types.NewArray(2, types.NewVector(4, types.I32))},
+		// Pointer to scalable vector.
+		{in: types.NewPointer(vscale)},
+		// Fixed array of scalable vectors.
+		{
+			in:  types.NewArray(2, vscale),
+			err: "invalid element type of array type [2 x <vscale x 4 x i32>]; scalable vector type <vscale x 4 x i32> not allowed as array element",
+		},
+		// Struct of scalable vectors.
+		{
+			in:  types.NewStruct(types.I32, vscale),
+			err: "invalid field type of struct type { i32, <vscale x 4 x i32> }; scalable vector type <vscale x 4 x i32> not allowed as struct field",
+		},
+	}
+	for _, g := range golden {
+		err := validateElemTypes(g.in)
+		if len(g.err) == 0 {
+			if err != nil {
+				t.Errorf("unexpected error for type %s; %v", g.in, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("expected error containing %q for type %s, got nil", g.err, g.in)
+			continue
+		}
+		if !strings.Contains(err.Error(), g.err) {
+			t.Errorf("error mismatch; expected error containing %q, got %q", g.err, err.Error())
+		}
+	}
+}
+
+func TestParseStringHeaderOrder(t *testing.T) {
+	const in = "target triple = \"x86_64-unknown-linux-gnu\"\ntarget datalayout = \"e-m:e-i64:64-S128\"\nsource_filename = \"foo.c\"\n"
+	const want = "source_filename = \"foo.c\"\ntarget datalayout = \"e-m:e-i64:64-S128\"\ntarget triple = \"x86_64-unknown-linux-gnu\"\n"
+	m, err := ParseString("<stdin>", in)
+	if err != nil {
+		t.Fatalf("unable to parse %q; %+v", in, err)
+	}
+	got := m.String()
+	if got != want {
+		t.Errorf("module mismatch; expected %q, got %q", want, got)
+	}
+	// Re-parse module in canonical order.
+	m2, err := ParseString("<stdin>", got)
+	if err != nil {
+		t.Fatalf("unable to parse %q; %+v", got, err)
+	}
+	if m2.SourceFilename != m.SourceFilename || m2.DataLayout != m.DataLayout || m2.TargetTriple != m.TargetTriple {
+		t.Errorf("module header mismatch after re-parse; expected %q, got %q", got, m2.String())
+	}
+	if got2 := m2.String(); got2 != want {
+		t.Errorf("module mismatch after re-parse; expected %q, got %q", want, got2)
+	}
+}
+
+func TestParseStringSourceFilename(t *testing.T) {
+	const in = "; ModuleID = 'foo.c'\nsource_filename = \"C:\\5Csrc\\5Cmy \\22file\\22.c\"\ntarget triple = \"x86_64-pc-windows-msvc\"\n"
+	const want = "source_filename = \"C:\\5Csrc\\5Cmy \\22file\\22.c\"\ntarget triple = \"x86_64-pc-windows-msvc\"\n"
+	m, err := ParseString("<stdin>", in)
+	if err != nil {
+		t.Fatalf("unable to parse %q; %+v", in, err)
+	}
+	if want := `C:\src\my "file".c`; m.SourceFilename != want {
+		t.Errorf("source filename mismatch; expected %q, got %q", want, m.SourceFilename)
+	}
+	if got := m.String(); got != want {
+		t.Errorf("module mismatch; expected %q, got %q", want, got)
+	}
+}
+
+func TestParseStringModuleAsm(t *testing.T) {
+	const in = "module asm \".globl foo\"\nmodule asm \"foo: .ascii \\22bar\\22\"\n\ndeclare void @f()\n"
+	m, err := ParseString("<stdin>", in)
+	if err != nil {
+		t.Fatalf("unable to parse %q; %+v", in, err)
+	}
+	want := []string{".globl foo", `foo: .ascii "bar"`}
+	if len(m.ModuleAsms) != len(want) {
+		t.Fatalf("number of module-level inline assembly mismatch; expected %d, got %d", len(want), len(m.ModuleAsms))
+	}
+	for i := range want {
+		if want[i] != m.ModuleAsms[i] {
+			t.Errorf("module-level inline assembly %d mismatch; expected %q, got %q", i, want[i], m.ModuleAsms[i])
+		}
+	}
+	if got := m.String(); got != in {
+		t.Errorf("module mismatch; expected %q, got %q", in, got)
+	}
+}
+
+func TestParseStringInvalidConv(t *testing.T) {
+	golden := []struct {
+		content string
+		line    int
+	}{
+		// Integer truncation to larger type.
+		{content: "@x = global i32 trunc (i8 1 to i32)\n", line: 1},
+		// Bitcast between pointers in different address spaces.
+		{content: "\n@y = global i8* bitcast (i32 addrspace(1)* null to i8*)\n", line: 2},
+	}
+	for _, g := range golden {
+		_, err := ParseString("<stdin>", g.content)
+		if err == nil {
+			t.Errorf("%q: expected error for invalid conversion, got nil", g.content)
+			continue
+		}
+		e, ok := errors.Cause(err).(*Error)
+		if !ok {
+			t.Errorf("%q: invalid error type; expected *asm.Error, got %T", g.content, errors.Cause(err))
+			continue
+		}
+		if e.Line != g.line {
+			t.Errorf("%q: line mismatch; expected %d, got %d", g.content, g.line, e.Line)
+		}
+	}
+}
+
+func TestParseStringForwardRef(t *testing.T) {
+	golden := []struct {
+		in   string
+		want string
+		err  string
+	}{
+		// Phi instruction using a value of a basic block which appears later in
+		// the function body.
+		{
+			in:   "define i32 @f(i1 %cond) {\nentry:\n\tbr label %loop\n\nloop:\n\t%i = phi i32 [ 0, %entry ], [ %next, %latch ]\n\tbr i1 %cond, label %latch, label %exit\n\nexit:\n\tret i32 %i\n\nlatch:\n\t%next = add i32 %i, 1\n\tbr label %loop\n}\n",
+			want: "define i32 @f(i1 %cond) {\nentry:\n\tbr label %loop\n\nloop:\n\t%i = phi i32 [ 0, %entry ], [ %next, %latch ]\n\tbr i1 %cond, label %latch, label %exit\n\nexit:\n\tret i32 %i\n\nlatch:\n\t%next = add i32 %i, 1\n\tbr label %loop\n}\n",
+		},
+		// Unnamed local variables, used before being defined.
+		{
+			in:   "define i32 @f(i32 %0) {\n; <label>:1\n\tbr label %3\n\n; <label>:2\n\tret i32 %4\n\n; <label>:3\n\t%4 = add i32 %0, 1\n\tbr label %2\n}\n",
+			want: "define i32 @f(i32) {\n; <label>:1\n\tbr label %3\n\n; <label>:2\n\tret i32 %4\n\n; <label>:3\n\t%4 = add i32 %0, 1\n\tbr label %2\n}\n",
+		},
+		// Numeric local name and unnamed local variable with the same number.
+		{
+			in:   "define i32 @f(i32 %\"1\") {\n; <label>:0\n\t%1 = add i32 %\"1\", 1\n\tret i32 %1\n}\n",
+			want: "define i32 @f(i32 %\"1\") {\n; <label>:0\n\t%1 = add i32 %\"1\", 1\n\tret i32 %1\n}\n",
+		},
+		// Use of undefined local identifier.
+		{
+			in:  "define void @f() {\nentry:\n\t%x = add i32 %y, 1\n\tret void\n}\n",
+			err: `undefined local identifier "%y" in function "@f"`,
+		},
+		// Branch to undefined basic block.
+		{
+			in:  "define void @f() {\nentry:\n\tbr label %exit\n}\n",
+			err: `undefined local identifier "%exit" in function "@f"`,
+		},
+		// Phi instruction with undefined incoming value.
+		{
+			in:  "define i32 @f() {\nentry:\n\tbr label %exit\n\nexit:\n\t%x = phi i32 [ %y, %entry ]\n\tret i32 %x\n}\n",
+			err: `undefined local identifier "%y" in function "@f"`,
+		},
+	}
+	for _, g := range golden {
+		m, err := ParseString("<stdin>", g.in)
+		if len(g.err) > 0 {
+			if err == nil {
+				t.Errorf("expected error containing %q, got nil", g.err)
+				continue
+			}
+			if !strings.Contains(err.Error(), g.err) {
+				t.Errorf("error mismatch; expected error containing %q, got %q", g.err, err.Error())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unable to parse %q; %+v", g.in, err)
+			continue
+		}
+		got := m.String()
+		if got != g.want {
+			t.Errorf("module mismatch; expected %q, got %q", g.want, got)
+		}
+	}
+}
+
+func TestParseStringDINamespace(t *testing.T) {
+	const in = `!0 = !DIFile(filename: "foo.cpp", directory: "/tmp")
+!1 = !DINamespace(scope: null, name: "foo")
+!2 = !DINamespace(scope: !1, name: "bar", exportSymbols: true)
+!3 = !DIModule(scope: null, name: "baz", includePath: "/usr/include")
+!4 = !DIImportedEntity(tag: DW_TAG_imported_module, scope: !2, entity: !3, file: !0, line: 42)
+`
+	m, err := ParseString("<stdin>", in)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	if len(m.MetadataDefs) != 5 {
+		t.Fatalf("metadata definitions mismatch; expected 5, got %d", len(m.MetadataDefs))
+	}
+	// Namespace with parent namespace as scope.
+	ns, ok := m.MetadataDefs[2].Node.(*metadata.DINamespace)
+	if !ok {
+		t.Fatalf("invalid metadata node type; expected *metadata.DINamespace, got %T", m.MetadataDefs[2].Node)
+	}
+	if ns.Name != "bar" {
+		t.Errorf("namespace name mismatch; expected %q, got %q", "bar", ns.Name)
+	}
+	if !ns.ExportSymbols {
+		t.Errorf("namespace exportSymbols mismatch; expected true, got false")
+	}
+	scope, ok := ns.Scope.(*metadata.Def)
+	if !ok {
+		t.Fatalf("invalid namespace scope type; expected *metadata.Def, got %T", ns.Scope)
+	}
+	parent, ok := scope.Node.(*metadata.DINamespace)
+	if !ok {
+		t.Fatalf("invalid namespace scope node type; expected *metadata.DINamespace, got %T", scope.Node)
+	}
+	if parent.Name != "foo" {
+		t.Errorf("parent namespace name mismatch; expected %q, got %q", "foo", parent.Name)
+	}
+	// Module.
+	mod, ok := m.MetadataDefs[3].Node.(*metadata.DIModule)
+	if !ok {
+		t.Fatalf("invalid metadata node type; expected *metadata.DIModule, got %T", m.MetadataDefs[3].Node)
+	}
+	if mod.Name != "baz" || mod.IncludePath != "/usr/include" {
+		t.Errorf("module mismatch; expected name %q and include path %q, got %q and %q", "baz", "/usr/include", mod.Name, mod.IncludePath)
+	}
+	// Imported entity.
+	entity, ok := m.MetadataDefs[4].Node.(*metadata.DIImportedEntity)
+	if !ok {
+		t.Fatalf("invalid metadata node type; expected *metadata.DIImportedEntity, got %T", m.MetadataDefs[4].Node)
+	}
+	if entity.Line != 42 {
+		t.Errorf("imported entity line mismatch; expected 42, got %d", entity.Line)
+	}
+	if entity.Scope != m.MetadataDefs[2] {
+		t.Errorf("imported entity scope mismatch; expected %v, got %v", m.MetadataDefs[2], entity.Scope)
+	}
+}
+
+func TestParseStringDITemplateParameter(t *testing.T) {
+	const in = `!0 = !DIBasicType(name: "int", size: 32, encoding: DW_ATE_signed)
+!1 = !DITemplateTypeParameter(name: "T", type: !0)
+!2 = !DITemplateValueParameter(name: "N", type: !0, value: i32 3)
+!3 = !{!1, !2}
+!4 = !DICompositeType(tag: DW_TAG_structure_type, name: "S<int, 3>", templateParams: !3)
+`
+	m, err := ParseString("<stdin>", in)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	if len(m.MetadataDefs) != 5 {
+		t.Fatalf("metadata definitions mismatch; expected 5, got %d", len(m.MetadataDefs))
+	}
+	// Template parameters of composite type.
+	composite, ok := m.MetadataDefs[4].Node.(*metadata.DICompositeType)
+	if !ok {
+		t.Fatalf("invalid metadata node type; expected *metadata.DICompositeType, got %T", m.MetadataDefs[4].Node)
+	}
+	params, ok := composite.TemplateParams.(*metadata.Def)
+	if !ok {
+		t.Fatalf("invalid template parameters type; expected *metadata.Def, got %T", composite.TemplateParams)
+	}
+	tuple, ok := params.Node.(*metadata.Tuple)
+	if !ok {
+		t.Fatalf("invalid template parameters node type; expected *metadata.Tuple, got %T", params.Node)
+	}
+	if len(tuple.Fields) != 2 {
+		t.Fatalf("template parameters mismatch; expected 2, got %d", len(tuple.Fields))
+	}
+	// Template type parameter.
+	def, ok := tuple.Fields[0].(*metadata.Def)
+	if !ok {
+		t.Fatalf("invalid template parameter type; expected *metadata.Def, got %T", tuple.Fields[0])
+	}
+	param, ok := def.Node.(*metadata.DITemplateTypeParameter)
+	if !ok {
+		t.Fatalf("invalid metadata node type; expected *metadata.DITemplateTypeParameter, got %T", def.Node)
+	}
+	if param.Name != "T" {
+		t.Errorf("template type parameter name mismatch; expected %q, got %q", "T", param.Name)
+	}
+	typ, ok := param.Type.(*metadata.Def)
+	if !ok {
+		t.Fatalf("invalid template type parameter type; expected *metadata.Def, got %T", param.Type)
+	}
+	basic, ok := typ.Node.(*metadata.DIBasicType)
+	if !ok {
+		t.Fatalf("invalid metadata node type; expected *metadata.DIBasicType, got %T", typ.Node)
+	}
+	if basic.Name != "int" {
+		t.Errorf("template type parameter type name mismatch; expected %q, got %q", "int", basic.Name)
+	}
+	// Template value parameter.
+	def, ok = tuple.Fields[1].(*metadata.Def)
+	if !ok {
+		t.Fatalf("invalid template parameter type; expected *metadata.Def, got %T", tuple.Fields[1])
+	}
+	value, ok := def.Node.(*metadata.DITemplateValueParameter)
+	if !ok {
+		t.Fatalf("invalid metadata node type; expected *metadata.DITemplateValueParameter, got %T", def.Node)
+	}
+	if value.Name != "N" || value.Type != typ {
+		t.Errorf("template value parameter mismatch; expected name %q and type %v, got %q and %v", "N", typ, value.Name, value.Type)
+	}
+}
+
+func TestParseHeaderString(t *testing.T) {
+	const nfuncs = 100
+	content := largeModule(nfuncs, 200)
+	m, err := ParseHeaderString("<stdin>", content)
+	if err != nil {
+		t.Fatalf("unable to parse module header; %+v", err)
+	}
+	if len(m.Globals) != 1 {
+		t.Errorf("global count mismatch; expected 1, got %d", len(m.Globals))
+	}
+	if len(m.Funcs) != nfuncs+1 {
+		t.Fatalf("function count mismatch; expected %d, got %d", nfuncs+1, len(m.Funcs))
+	}
+	if m.Funcs[0].Lazy != nil {
+		t.Errorf("expected no lazy function body of function declaration %q", m.Funcs[0].Ident())
+	}
+	for _, f := range m.Funcs[1:] {
+		if f.Blocks != nil {
+			t.Errorf("expected nil basic blocks of %q, got %d basic blocks", f.Ident(), len(f.Blocks))
+		}
+		if f.Lazy == nil {
+			t.Errorf("expected lazy function body of %q, got nil", f.Ident())
+			continue
+		}
+		start, end := f.Lazy.SourceRange()
+		want := fmt.Sprintf("define i32 %s(i32 %%a) {", f.Ident())
+		if src := content[start:end]; !strings.HasPrefix(src, want) || !strings.HasSuffix(src, "}") {
+			t.Errorf("source range mismatch of %q; expected function definition, got `%s`", f.Ident(), src)
+		}
+	}
+}
+
+func BenchmarkParseString(b *testing.B) {
+	content := largeModule(100, 200)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseString("<stdin>", content); err != nil {
+			b.Fatalf("unable to parse module; %+v", err)
+		}
+	}
+}
+
+func BenchmarkParseHeaderString(b *testing.B) {
+	content := largeModule(100, 200)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseHeaderString("<stdin>", content); err != nil {
+			b.Fatalf("unable to parse module header; %+v", err)
+		}
+	}
+}
+
+// largeModule returns the LLVM IR assembly of a module with the given number of
+// function definitions, each with a body of the given number of instructions.
+func largeModule(nfuncs, ninsts int) string {
+	buf := &strings.Builder{}
+	buf.WriteString("@x = global i32 42\n\n")
+	buf.WriteString("declare i32 @g(i32)\n")
+	for i := 0; i < nfuncs; i++ {
+		fmt.Fprintf(buf, "\ndefine i32 @f%d(i32 %%a) {\n", i)
+		buf.WriteString("entry:\n")
+		buf.WriteString("\t%v0 = add i32 %a, 1\n")
+		for j := 1; j < ninsts; j++ {
+			fmt.Fprintf(buf, "\t%%v%d = mul i32 %%v%d, %d\n", j, j-1, j)
+		}
+		fmt.Fprintf(buf, "\t%%r = call i32 @g(i32 %%v%d)\n", ninsts-1)
+		buf.WriteString("\tret i32 %r\n")
+		buf.WriteString("}\n")
+	}
+	return buf.String()
+}
+
+func TestMaterialize(t *testing.T) {
+	const content = `%pair = type { i32, i32 }
+
+@x = global i32 42
+
+define i32 @f(i32 %a) {
+entry:
+	%b = add i32 %a, 1
+	ret i32 %b
+}
+
+define i32 @g(%pair* %p) {
+entry:
+	%q = getelementptr %pair, %pair* %p, i32 0, i32 1
+	%y = load i32, i32* %q
+	%z = load i32, i32* @x
+	%c = call i32 @f(i32 %y)
+	%r = mul i32 %z, %c
+	ret i32 %r
+}
+
+define void @h() {
+	ret void
+}
+`
+	want, err := ParseString("<stdin>", content)
+	if err != nil {
+		t.Fatalf("unable to parse module; %+v", err)
+	}
+	m, err := ParseHeaderString("<stdin>", content)
+	if err != nil {
+		t.Fatalf("unable to parse module header; %+v", err)
+	}
+	if len(m.Funcs) != 3 {
+		t.Fatalf("function count mismatch; expected 3, got %d", len(m.Funcs))
+	}
+	// Materialize @g.
+	g := m.Funcs[1]
+	if err := g.Materialize(); err != nil {
+		t.Fatalf("unable to materialize %q; %+v", g.Ident(), err)
+	}
+	if g.Lazy != nil {
+		t.Errorf("expected nil lazy function body of materialized function %q", g.Ident())
+	}
+	if got, want := g.Def(), want.Funcs[1].Def(); want != got {
+		t.Errorf("function mismatch; expected `%s`, got `%s`", want, got)
+	}
+	// Callee of @g refers to the function of the module.
+	call, ok := g.Blocks[0].Insts[3].(*ir.InstCall)
+	if !ok {
+		t.Fatalf("invalid instruction type; expected *ir.InstCall, got %T", g.Blocks[0].Insts[3])
+	}
+	if call.Callee != m.Funcs[0] {
+		t.Errorf("callee mismatch; expected %v, got %v", m.Funcs[0], call.Callee)
+	}
+	// Remaining functions are left unparsed.
+	for _, f := range []*ir.Function{m.Funcs[0], m.Funcs[2]} {
+		if f.Blocks != nil {
+			t.Errorf("expected nil basic blocks of %q, got %d basic blocks", f.Ident(), len(f.Blocks))
+		}
+		if f.Lazy == nil {
+			t.Errorf("expected lazy function body of %q, got nil", f.Ident())
+		}
+	}
+}
+
+func TestMaterializeError(t *testing.T) {
+	const content = `@addr = global i8* blockaddress(@g, %bar)
+
+define void @f(i32 %x) {
+entry:
+	store i8* blockaddress(@g, %bar), i8** @addr
+	%y = add i32 %x, %undefined
+	ret void
+}
+
+define void @g() {
+entry:
+	br label %bar
+
+bar:
+	ret void
+}
+`
+	m, err := ParseHeaderString("<stdin>", content)
+	if err != nil {
+		t.Fatalf("unable to parse module header; %+v", err)
+	}
+	f, g := m.Funcs[0], m.Funcs[1]
+	params := f.Params
+	gen := f.Lazy.(*lazyBody).gen
+	todo := len(gen.todo)
+	// Function left untouched on failed materialization.
+	if err := f.Materialize(); err == nil {
+		t.Fatalf("expected error materializing %q, got nil", f.Ident())
+	}
+	if f.Blocks != nil {
+		t.Errorf("expected nil basic blocks of %q, got %d basic blocks", f.Ident(), len(f.Blocks))
+	}
+	if f.Lazy == nil {
+		t.Errorf("expected lazy function body of %q, got nil", f.Ident())
+	}
+	if len(f.Params) != 1 || f.Params[0] != params[0] {
+		t.Errorf("function parameters mismatch of %q; expected %v, got %v", f.Ident(), params, f.Params)
+	}
+	if len(gen.todo) != todo {
+		t.Errorf("pending blockaddress constant count mismatch; expected %d, got %d", todo, len(gen.todo))
+	}
+	// Pending blockaddress constants are fixed on materialization of the
+	// referenced function.
+	if err := g.Materialize(); err != nil {
+		t.Fatalf("unable to materialize %q; %+v", g.Ident(), err)
+	}
+	if len(gen.todo) != 0 {
+		t.Errorf("expected no pending blockaddress constants, got %d", len(gen.todo))
+	}
+	c := m.Globals[0].Init.(*constant.BlockAddress)
+	if c.Block != g.Blocks[1] {
+		t.Errorf("basic block mismatch of blockaddress constant; expected %v, got %v", g.Blocks[1].Ident(), c.Block.Ident())
+	}
+}
+
+func TestMaterializeBlockAddress(t *testing.T) {
+	const content = `@addr = global i8* blockaddress(@f, %bar)
+
+define void @f() {
+entry:
+	%addr = load i8*, i8** @addr
+	indirectbr i8* %addr, [label %foo, label %bar]
+
+foo:
+	ret void
+
+bar:
+	ret void
+}
+`
+	m, err := ParseHeaderString("<stdin>", content)
+	if err != nil {
+		t.Fatalf("unable to parse module header; %+v", err)
+	}
+	c, ok := m.Globals[0].Init.(*constant.BlockAddress)
+	if !ok {
+		t.Fatalf("invalid initializer type; expected *constant.BlockAddress, got %T", m.Globals[0].Init)
+	}
+	f := m.Funcs[0]
+	if err := f.Materialize(); err != nil {
+		t.Fatalf("unable to materialize %q; %+v", f.Ident(), err)
+	}
+	// Basic block of blockaddress constant in module header refers to the
+	// basic block of the materialized function body.
+	if c.Block != f.Blocks[2] {
+		t.Errorf("basic block mismatch; expected %v, got %v", f.Blocks[2], c.Block)
+	}
+}
+
+func TestMaterializeBlockAddressCrossFunc(t *testing.T) {
+	const content = `define i8* @a() {
+entry:
+	ret i8* blockaddress(@b, %bb)
+}
+
+define void @b() {
+entry:
+	br label %bb
+
+bb:
+	ret void
+}
+`
+	m, err := ParseHeaderString("<stdin>", content)
+	if err != nil {
+		t.Fatalf("unable to parse module header; %+v", err)
+	}
+	a, b := m.Funcs[0], m.Funcs[1]
+	// Materialize @a before the function referred to by its blockaddress
+	// constant.
+	if err := a.Materialize(); err != nil {
+		t.Fatalf("unable to materialize %q; %+v", a.Ident(), err)
+	}
+	ret, ok := a.Blocks[0].Term.(*ir.TermRet)
+	if !ok {
+		t.Fatalf("invalid terminator type; expected *ir.TermRet, got %T", a.Blocks[0].Term)
+	}
+	c, ok := ret.X.(*constant.BlockAddress)
+	if !ok {
+		t.Fatalf("invalid return value type; expected *constant.BlockAddress, got %T", ret.X)
+	}
+	if b.Lazy == nil {
+		t.Fatalf("expected lazy function body of %q, got nil", b.Ident())
+	}
+	if err := b.Materialize(); err != nil {
+		t.Fatalf("unable to materialize %q; %+v", b.Ident(), err)
+	}
+	// Basic block of blockaddress constant in @a refers to the basic block of
+	// the materialized function body of @b.
+	if c.Block != b.Blocks[1] {
+		t.Errorf("basic block mismatch; expected %v, got %v", b.Blocks[1], c.Block)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	const content = `%pair = type { i32, i32 }
+%unused = type { i8 }
+
+$f = comdat any
+
+@p = global %pair zeroinitializer
+@unused = global i32 0
+
+define i32 @g(i32 %x) #0 {
+entry:
+	ret i32 %x
+}
+
+define i32 @f() #1 comdat {
+entry:
+	%elem = getelementptr %pair, %pair* @p, i32 0, i32 0
+	%x = load i32, i32* %elem, !tbaa !0
+	%y = call i32 @g(i32 %x)
+	ret i32 %y
+}
+
+attributes #0 = { noinline }
+attributes #1 = { nounwind }
+
+!0 = !{!1, !1, i64 0}
+!1 = !{!"int"}
+!2 = !{!"unused"}
+`
+	m, err := ParseHeaderString("<stdin>", content)
+	if err != nil {
+		t.Fatalf("unable to parse module header; %+v", err)
+	}
+	f, ok := m.Func("f")
+	if !ok {
+		t.Fatalf("unable to locate function %q", "f")
+	}
+	extracted, err := m.Extract([]*ir.Function{f})
+	if err != nil {
+		t.Fatalf("unable to extract function %q; %+v", f.Ident(), err)
+	}
+	// Parse extracted module standalone.
+	s := extracted.String()
+	got, err := ParseString("<extracted>", s)
+	if err != nil {
+		t.Fatalf("unable to parse extracted module %q; %+v", s, err)
+	}
+	if len(got.TypeDefs) != 1 || len(got.ComdatDefs) != 1 || len(got.Globals) != 1 || len(got.AttrGroupDefs) != 2 || len(got.MetadataDefs) != 2 {
+		t.Errorf("top-level entities mismatch of extracted module %q", s)
+	}
+	g, ok := got.Func("g")
+	if !ok {
+		t.Fatalf("unable to locate function %q in extracted module %q", "g", s)
+	}
+	if len(g.Blocks) != 0 {
+		t.Errorf("function %q of extracted module is not a declaration; %q", g.Ident(), g.Def())
+	}
+}
+
+func TestExtractUnnamed(t *testing.T) {
+	// Module with unnamed basic blocks and local variables, as constructed by
+	// the ir package; see ir.TestModuleExtract.
+	m := ir.NewModule()
+	pair := m.NewTypeDef("pair", types.NewStruct(types.I32, types.I32))
+	h := m.NewFunc("h", types.Void)
+	h.NewBlock("").NewRet(nil)
+	fp := m.NewGlobalDef("fp", h)
+	p := m.NewGlobalDef("p", constant.NewZeroInitializer(pair))
+	x := ir.NewParam("x", types.I32)
+	g := m.NewFunc("g", types.I32, x)
+	g.NewBlock("").NewRet(x)
+	f := m.NewFunc("f", types.I32)
+	entry := f.NewBlock("")
+	entry.NewLoad(fp)
+	zero := constant.NewInt(types.I32, 0)
+	elem := entry.NewGetElementPtr(p, zero, zero)
+	v := entry.NewLoad(elem)
+	entry.NewRet(entry.NewCall(g, v))
+	extracted, err := m.Extract([]*ir.Function{f})
+	if err != nil {
+		t.Fatalf("unable to extract function %q; %+v", f.Ident(), err)
+	}
+	// Parse extracted module standalone.
+	s := extracted.String()
+	got, err := ParseString("<extracted>", s)
+	if err != nil {
+		t.Fatalf("unable to parse extracted module %q; %+v", s, err)
+	}
+	if got.String() != s {
+		t.Errorf("extracted module mismatch; expected %q, got %q", s, got.String())
+	}
+	for _, name := range []string{"g", "h"} {
+		decl, ok := got.Func(name)
+		if !ok {
+			t.Errorf("unable to locate function %q in extracted module %q", name, s)
+			continue
+		}
+		if len(decl.Blocks) != 0 {
+			t.Errorf("function %q of extracted module is not a declaration; %q", decl.Ident(), decl.Def())
+		}
+	}
+}
+
+func TestParseStringUnsupported(t *testing.T) {
+	// LLVM IR constructs printed by the ir package but not yet supported by the
+	// grammar of the parser; see the package documentation. Unknown keywords
+	// must be reported as syntax errors, rather than skipped.
+	golden := []struct {
+		in   string
+		line int
+	}{
+		// Typed parameter attributes.
+		{in: "%T = type { i32 }\ndeclare void @f(%T* byval(%T))\n", line: 2},
+		{in: "%T = type { i32 }\ndeclare void @f(%T* sret(%T))\n", line: 2},
+		{in: "%T = type { i32 }\ndeclare void @f(%T* byref(%T))\n", line: 2},
+		{in: "%T = type { i32 }\ndeclare void @f(%T* preallocated(%T))\n", line: 2},
+		{in: "%T = type { i32 }\ndeclare void @f(%T* inalloca(%T))\n", line: 2},
+		// Poison values.
+		{in: "define i32 @f() {\n\tret i32 poison\n}\n", line: 2},
+		// callbr terminators.
+		{in: "define void @f() {\nentry:\n\tcallbr void asm \"\", \"\"() to label %a []\n\na:\n\tret void\n}\n", line: 3},
+		// dso_local_equivalent and no_cfi constants.
+		{in: "declare void @f()\n\n@g = global void ()* dso_local_equivalent @f\n", line: 3},
+		{in: "declare void @f()\n\n@g = global void ()* no_cfi @f\n", line: 3},
+	}
+	for _, g := range golden {
+		_, err := ParseString("<stdin>", g.in)
+		if err == nil {
+			t.Errorf("%q: expected syntax error, got nil", g.in)
+			continue
+		}
+		e, ok := errors.Cause(err).(*Error)
+		if !ok {
+			t.Errorf("%q: invalid error type; expected *Error, got %T", g.in, errors.Cause(err))
+			continue
+		}
+		if e.Msg != "syntax error" || e.Line != g.line {
+			t.Errorf("%q: error mismatch; expected syntax error at line %d, got %q at line %d", g.in, g.line, e.Msg, e.Line)
+		}
+	}
+}
+
+func TestParseStringInvalidLiterals(t *testing.T) {
+	// Literals out of range of 64-bit integers.
+	golden := []string{
+		// Attribute group ID.
+		"define void @f() #99999999999999999999 {\n\tret void\n}\n\nattributes #99999999999999999999 = { nounwind }\n",
+		// Metadata ID.
+		"!99999999999999999999999 = !{}\n",
+		// Unsigned integer literal.
+		"@x = global i32 0, align 99999999999999999999\n",
+		// Integer literal of specialized metadata field.
+		"!0 = !DISubrange(count: 1, lowerBound: 99999999999999999999)\n",
+	}
+	for _, content := range golden {
+		if _, err := ParseString("<stdin>", content); err == nil {
+			t.Errorf("%q: expected error for literal out of range, got nil", content)
+		}
+	}
+}
+
+func TestParseStringInvalidOperands(t *testing.T) {
+	// Operands of invalid type accepted by the grammar.
+	golden := []string{
+		// Floating-point operand of icmp.
+		"define i1 @f(double %x) {\nentry:\n\t%r = icmp eq double %x, %x\n\tret i1 %r\n}\n",
+		// Integer operand of fcmp.
+		"define i1 @f(i32 %x) {\nentry:\n\t%r = fcmp oeq i32 %x, %x\n\tret i1 %r\n}\n",
+		// Non-vector operand of extractelement.
+		"define i32 @f(i32 %x) {\nentry:\n\t%r = extractelement i32 %x, i32 0\n\tret i32 %r\n}\n",
+		// Struct index out of range in getelementptr.
+		"%t = type { i32 }\n\ndefine void @f(%t* %x) {\nentry:\n\t%r = getelementptr %t, %t* %x, i32 0, i32 5\n\tret void\n}\n",
+	}
+	for _, content := range golden {
+		if _, err := ParseString("<stdin>", content); err == nil {
+			t.Errorf("%q: expected error for invalid operand, got nil", content)
+		}
+	}
+}
@@ -41,10 +41,13 @@ func (gen *generator) irConstant(t types.Type, old ast.Constant) (constant.Const
 	case *ast.BlockAddressConst:
 		return gen.irBlockAddressConst(t, old)
 	case *ast.GlobalIdent:
-		ident := globalIdent(*old)
+		ident, err := globalIdent(*old)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
 		c, ok := gen.new.globals[ident]
 		if !ok {
-			return nil, errors.Errorf("unable to locate global identifier %q", ident.Ident())
+			return nil, errorf(old, "unable to locate global identifier %q", ident.Ident())
 		}
 		return c, nil
 	case ast.ConstantExpr:
@@ -73,12 +76,16 @@ func (gen *generator) irTypeConst(old ast.TypeConst) (constant.Constant, error)
 func (gen *generator) irBoolConst(t types.Type, old *ast.BoolConst) (*constant.Int, error) {
 	typ, ok := t.(*types.IntType)
 	if !ok {
-		return nil, errors.Errorf("invalid type of boolean constant; expected *types.IntType, got %T", t)
+		return nil, errorf(old, "invalid type of boolean constant; expected *types.IntType, got %T", t)
 	}
 	if !typ.Equal(types.I1) {
-		return nil, errors.Errorf("boolean type mismatch; expected %q, got %q", types.I1, typ)
+		return nil, errorf(old, "boolean type mismatch; expected %q, got %q", types.I1, typ)
+	}
+	x, err := boolLit(old.BoolLit())
+	if err != nil {
+		return nil, errors.WithStack(err)
 	}
-	return constant.NewBool(boolLit(old.BoolLit())), nil
+	return constant.NewBool(x), nil
 }
 
 // --- [ Integer constants ] ---------------------------------------------------
@@ -88,8 +95,7 @@ func (gen *generator) irBoolConst(t types.Type, old *ast.BoolConst) (*constant.I
 func (gen *generator) irIntConst(t types.Type, old *ast.IntConst) (*constant.Int, error) {
 	typ, ok := t.(*types.IntType)
 	if !ok {
-		line, col := old.LineColumn()
-		return nil, errors.Errorf("%d:%d: invalid type of integer constant; expected *types.IntType, got %T", line, col, t)
+		return nil, errorf(old, "invalid type of integer constant; expected *types.IntType, got %T", t)
 	}
 	s := old.IntLit().Text()
 	return constant.NewIntFromString(typ, s)
@@ -102,7 +108,7 @@ func (gen *generator) irIntConst(t types.Type, old *ast.IntConst) (*constant.Int
 func (gen *generator) irFloatConst(t types.Type, old *ast.FloatConst) (*constant.Float, error) {
 	typ, ok := t.(*types.FloatType)
 	if !ok {
-		return nil, errors.Errorf("invalid type of floating-point constant; expected *types.FloatType, got %T", t)
+		return nil, errorf(old, "invalid type of floating-point constant; expected *types.FloatType, got %T", t)
 	}
 	s := old.FloatLit().Text()
 	return constant.NewFloatFromString(typ, s)
@@ -115,7 +121,7 @@ func (gen *generator) irFloatConst(t types.Type, old *ast.FloatConst) (*constant
 func (gen *generator) irNullConst(t types.Type, old *ast.NullConst) (*constant.Null, error) {
 	typ, ok := t.(*types.PointerType)
 	if !ok {
-		return nil, errors.Errorf("invalid type of null pointer constant; expected *types.PointerType, got %T", t)
+		return nil, errorf(old, "invalid type of null pointer constant; expected *types.PointerType, got %T", t)
 	}
 	return constant.NewNull(typ), nil
 }
@@ -126,7 +132,7 @@ func (gen *generator) irNullConst(t types.Type, old *ast.NullConst) (*constant.N
 // token constant.
 func (gen *generator) irNoneConst(t types.Type, old *ast.NoneConst) (constant.Constant, error) {
 	if !t.Equal(types.Token) {
-		return nil, errors.Errorf("invalid type of none token constant; expected %q, got %q", types.Token, t)
+		return nil, errorf(old, "invalid type of none token constant; expected %q, got %q", types.Token, t)
 	}
 	return constant.None, nil
 }
@@ -138,7 +144,7 @@ func (gen *generator) irNoneConst(t types.Type, old *ast.NoneConst) (constant.Co
 func (gen *generator) irStructConst(t types.Type, old *ast.StructConst) (*constant.Struct, error) {
 	typ, ok := t.(*types.StructType)
 	if !ok {
-		return nil, errors.Errorf("invalid type of struct constant; expected *types.StructType, got %T", t)
+		return nil, errorf(old, "invalid type of struct constant; expected *types.StructType, got %T", t)
 	}
 	var fields []constant.Constant
 	if oldFields := old.Fields(); len(oldFields) > 0 {
@@ -165,13 +171,13 @@ func (gen *generator) irStructConst(t types.Type, old *ast.StructConst) (*consta
 func (gen *generator) irArrayConst(t types.Type, old *ast.ArrayConst) (*constant.Array, error) {
 	typ, ok := t.(*types.ArrayType)
 	if !ok {
-		return nil, errors.Errorf("invalid type of array constant; expected *types.ArrayType, got %T", t)
+		return nil, errorf(old, "invalid type of array constant; expected *types.ArrayType, got %T", t)
 	}
 	oldElems := old.Elems()
 	if len(oldElems) == 0 {
 		typ := types.NewArray(0, typ.ElemType)
 		if !t.Equal(typ) {
-			return nil, errors.Errorf("array type mismatch; expected %q, got %q", typ, t)
+			return nil, errorf(old, "array type mismatch; expected %q, got %q", typ, t)
 		}
 		return &constant.Array{Typ: typ}, nil
 	}
@@ -185,7 +191,7 @@ func (gen *generator) irArrayConst(t types.Type, old *ast.ArrayConst) (*constant
 	}
 	c := constant.NewArray(elems...)
 	if !t.Equal(c.Typ) {
-		return nil, errors.Errorf("array type mismatch; expected %q, got %q", c.Typ, t)
+		return nil, errorf(old, "array type mismatch; expected %q, got %q", c.Typ, t)
 	}
 	return c, nil
 }
@@ -196,7 +202,7 @@ func (gen *generator) irCharArrayConst(t types.Type, old *ast.CharArrayConst) (*
 	data := enc.Unquote(old.Val().Text())
 	c := constant.NewCharArray(data)
 	if !t.Equal(c.Typ) {
-		return nil, errors.Errorf("character array type mismatch; expected %q, got %q", c.Typ, t)
+		return nil, errorf(old, "character array type mismatch; expected %q, got %q", c.Typ, t)
 	}
 	return c, nil
 }
@@ -220,7 +226,7 @@ func (gen *generator) irVectorConst(t types.Type, old *ast.VectorConst) (*consta
 	}
 	c := constant.NewVector(elems...)
 	if !t.Equal(c.Typ) {
-		return nil, errors.Errorf("vector type mismatch; expected %q, got %q", c.Typ, t)
+		return nil, errorf(old, "vector type mismatch; expected %q, got %q", c.Typ, t)
 	}
 	return c, nil
 }
@@ -231,17 +237,23 @@ func (gen *generator) irVectorConst(t types.Type, old *ast.VectorConst) (*consta
 // equivalent IR blockaddress constant.
 func (gen *generator) irBlockAddressConst(t types.Type, old *ast.BlockAddressConst) (*constant.BlockAddress, error) {
 	// Function.
-	funcName := globalIdent(old.Func())
+	funcName, err := globalIdent(old.Func())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 	v, ok := gen.new.globals[funcName]
 	if !ok {
-		return nil, errors.Errorf("unable to locate global identifier %q", funcName)
+		return nil, errorf(old, "unable to locate global identifier %q", funcName.Ident())
 	}
 	f, ok := v.(*ir.Function)
 	if !ok {
-		return nil, errors.Errorf("invalid function type; expected *ir.Function, got %T", v)
+		return nil, errorf(old, "invalid function type; expected *ir.Function, got %T", v)
 	}
 	// Basic block.
-	blockIdent := localIdent(old.Block())
+	blockIdent, err := localIdent(old.Block())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 	// Add dummy basic block to track the name recorded by the AST. Resolve the
 	// proper basic block after translation of function bodies and assignment of
 	// local IDs.
@@ -251,7 +263,7 @@ func (gen *generator) irBlockAddressConst(t types.Type, old *ast.BlockAddressCon
 	c := constant.NewBlockAddress(f, block)
 	gen.todo = append(gen.todo, c)
 	if typ := c.Type(); !t.Equal(typ) {
-		return nil, errors.Errorf("blockaddress constant type mismatch; expected %q, got %q", typ, t)
+		return nil, errorf(old, "blockaddress constant type mismatch; expected %q, got %q", typ, t)
 	}
 	return c, nil
 }
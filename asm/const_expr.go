@@ -129,7 +129,7 @@ func (gen *generator) irAddExpr(t types.Type, old *ast.AddExpr) (*constant.ExprA
 	// (optional) Overflow flags.
 	expr.OverflowFlags = irOverflowFlags(old.OverflowFlags())
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -151,7 +151,7 @@ func (gen *generator) irFAddExpr(t types.Type, old *ast.FAddExpr) (*constant.Exp
 	}
 	expr := constant.NewFAdd(x, y)
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -175,7 +175,7 @@ func (gen *generator) irSubExpr(t types.Type, old *ast.SubExpr) (*constant.ExprS
 	// (optional) Overflow flags.
 	expr.OverflowFlags = irOverflowFlags(old.OverflowFlags())
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -197,7 +197,7 @@ func (gen *generator) irFSubExpr(t types.Type, old *ast.FSubExpr) (*constant.Exp
 	}
 	expr := constant.NewFSub(x, y)
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -221,7 +221,7 @@ func (gen *generator) irMulExpr(t types.Type, old *ast.MulExpr) (*constant.ExprM
 	// (optional) Overflow flags.
 	expr.OverflowFlags = irOverflowFlags(old.OverflowFlags())
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -243,7 +243,7 @@ func (gen *generator) irFMulExpr(t types.Type, old *ast.FMulExpr) (*constant.Exp
 	}
 	expr := constant.NewFMul(x, y)
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -267,7 +267,7 @@ func (gen *generator) irUDivExpr(t types.Type, old *ast.UDivExpr) (*constant.Exp
 	// (optional) Exact.
 	_, expr.Exact = old.Exact()
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -291,7 +291,7 @@ func (gen *generator) irSDivExpr(t types.Type, old *ast.SDivExpr) (*constant.Exp
 	// (optional) Exact.
 	_, expr.Exact = old.Exact()
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -313,7 +313,7 @@ func (gen *generator) irFDivExpr(t types.Type, old *ast.FDivExpr) (*constant.Exp
 	}
 	expr := constant.NewFDiv(x, y)
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -335,7 +335,7 @@ func (gen *generator) irURemExpr(t types.Type, old *ast.URemExpr) (*constant.Exp
 	}
 	expr := constant.NewURem(x, y)
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -357,7 +357,7 @@ func (gen *generator) irSRemExpr(t types.Type, old *ast.SRemExpr) (*constant.Exp
 	}
 	expr := constant.NewSRem(x, y)
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -379,7 +379,7 @@ func (gen *generator) irFRemExpr(t types.Type, old *ast.FRemExpr) (*constant.Exp
 	}
 	expr := constant.NewFRem(x, y)
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -405,7 +405,7 @@ func (gen *generator) irShlExpr(t types.Type, old *ast.ShlExpr) (*constant.ExprS
 	// (optional) Overflow flags.
 	expr.OverflowFlags = irOverflowFlags(old.OverflowFlags())
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -429,7 +429,7 @@ func (gen *generator) irLShrExpr(t types.Type, old *ast.LShrExpr) (*constant.Exp
 	// (optional) Exact.
 	_, expr.Exact = old.Exact()
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -453,7 +453,7 @@ func (gen *generator) irAShrExpr(t types.Type, old *ast.AShrExpr) (*constant.Exp
 	// (optional) Exact.
 	_, expr.Exact = old.Exact()
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -475,7 +475,7 @@ func (gen *generator) irAndExpr(t types.Type, old *ast.AndExpr) (*constant.ExprA
 	}
 	expr := constant.NewAnd(x, y)
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -497,7 +497,7 @@ func (gen *generator) irOrExpr(t types.Type, old *ast.OrExpr) (*constant.ExprOr,
 	}
 	expr := constant.NewOr(x, y)
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -519,7 +519,7 @@ func (gen *generator) irXorExpr(t types.Type, old *ast.XorExpr) (*constant.ExprX
 	}
 	expr := constant.NewXor(x, y)
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -543,7 +543,7 @@ func (gen *generator) irExtractElementExpr(t types.Type, old *ast.ExtractElement
 	}
 	expr := constant.NewExtractElement(x, index)
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -570,7 +570,7 @@ func (gen *generator) irInsertElementExpr(t types.Type, old *ast.InsertElementEx
 	}
 	expr := constant.NewInsertElement(x, elem, index)
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -597,7 +597,7 @@ func (gen *generator) irShuffleVectorExpr(t types.Type, old *ast.ShuffleVectorEx
 	}
 	expr := constant.NewShuffleVector(x, y, mask)
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -615,10 +615,13 @@ func (gen *generator) irExtractValueExpr(t types.Type, old *ast.ExtractValueExpr
 		return nil, errors.WithStack(err)
 	}
 	// Element indices.
-	indices := uintSlice(old.Indices())
+	indices, err := uintSlice(old.Indices())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 	expr := constant.NewExtractValue(x, indices...)
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -639,10 +642,13 @@ func (gen *generator) irInsertValueExpr(t types.Type, old *ast.InsertValueExpr)
 		return nil, errors.WithStack(err)
 	}
 	// Element indices.
-	indices := uintSlice(old.Indices())
+	indices, err := uintSlice(old.Indices())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 	expr := constant.NewInsertValue(x, elem, indices...)
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -680,10 +686,10 @@ func (gen *generator) irGetElementPtrExpr(t types.Type, old *ast.GetElementPtrEx
 	// (optional) In-bounds.
 	_, expr.InBounds = old.InBounds()
 	if !elemType.Equal(expr.ElemType) {
-		return nil, errors.Errorf("constant expression element type mismatch; expected %q, got %q", expr.ElemType, elemType)
+		return nil, errorf(old, "constant expression element type mismatch; expected %q, got %q", expr.ElemType, elemType)
 	}
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -719,9 +725,12 @@ func (gen *generator) irTruncExpr(t types.Type, old *ast.TruncExpr) (*constant.E
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if !constant.ValidConv("trunc", from.Type(), to) {
+		return nil, errorf(old, "invalid trunc expression; unable to convert from `%s` to `%s`", from.Type(), to)
+	}
 	expr := constant.NewTrunc(from, to)
 	if !t.Equal(expr.To) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.To, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.To, t)
 	}
 	return expr, nil
 }
@@ -741,9 +750,12 @@ func (gen *generator) irZExtExpr(t types.Type, old *ast.ZExtExpr) (*constant.Exp
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if !constant.ValidConv("zext", from.Type(), to) {
+		return nil, errorf(old, "invalid zext expression; unable to convert from `%s` to `%s`", from.Type(), to)
+	}
 	expr := constant.NewZExt(from, to)
 	if !t.Equal(expr.To) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.To, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.To, t)
 	}
 	return expr, nil
 }
@@ -763,9 +775,12 @@ func (gen *generator) irSExtExpr(t types.Type, old *ast.SExtExpr) (*constant.Exp
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if !constant.ValidConv("sext", from.Type(), to) {
+		return nil, errorf(old, "invalid sext expression; unable to convert from `%s` to `%s`", from.Type(), to)
+	}
 	expr := constant.NewSExt(from, to)
 	if !t.Equal(expr.To) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.To, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.To, t)
 	}
 	return expr, nil
 }
@@ -785,9 +800,12 @@ func (gen *generator) irFPTruncExpr(t types.Type, old *ast.FPTruncExpr) (*consta
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if !constant.ValidConv("fptrunc", from.Type(), to) {
+		return nil, errorf(old, "invalid fptrunc expression; unable to convert from `%s` to `%s`", from.Type(), to)
+	}
 	expr := constant.NewFPTrunc(from, to)
 	if !t.Equal(expr.To) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.To, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.To, t)
 	}
 	return expr, nil
 }
@@ -807,9 +825,12 @@ func (gen *generator) irFPExtExpr(t types.Type, old *ast.FPExtExpr) (*constant.E
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if !constant.ValidConv("fpext", from.Type(), to) {
+		return nil, errorf(old, "invalid fpext expression; unable to convert from `%s` to `%s`", from.Type(), to)
+	}
 	expr := constant.NewFPExt(from, to)
 	if !t.Equal(expr.To) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.To, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.To, t)
 	}
 	return expr, nil
 }
@@ -829,9 +850,12 @@ func (gen *generator) irFPToUIExpr(t types.Type, old *ast.FPToUIExpr) (*constant
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if !constant.ValidConv("fptoui", from.Type(), to) {
+		return nil, errorf(old, "invalid fptoui expression; unable to convert from `%s` to `%s`", from.Type(), to)
+	}
 	expr := constant.NewFPToUI(from, to)
 	if !t.Equal(expr.To) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.To, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.To, t)
 	}
 	return expr, nil
 }
@@ -851,9 +875,12 @@ func (gen *generator) irFPToSIExpr(t types.Type, old *ast.FPToSIExpr) (*constant
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if !constant.ValidConv("fptosi", from.Type(), to) {
+		return nil, errorf(old, "invalid fptosi expression; unable to convert from `%s` to `%s`", from.Type(), to)
+	}
 	expr := constant.NewFPToSI(from, to)
 	if !t.Equal(expr.To) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.To, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.To, t)
 	}
 	return expr, nil
 }
@@ -873,9 +900,12 @@ func (gen *generator) irUIToFPExpr(t types.Type, old *ast.UIToFPExpr) (*constant
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if !constant.ValidConv("uitofp", from.Type(), to) {
+		return nil, errorf(old, "invalid uitofp expression; unable to convert from `%s` to `%s`", from.Type(), to)
+	}
 	expr := constant.NewUIToFP(from, to)
 	if !t.Equal(expr.To) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.To, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.To, t)
 	}
 	return expr, nil
 }
@@ -895,9 +925,12 @@ func (gen *generator) irSIToFPExpr(t types.Type, old *ast.SIToFPExpr) (*constant
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if !constant.ValidConv("sitofp", from.Type(), to) {
+		return nil, errorf(old, "invalid sitofp expression; unable to convert from `%s` to `%s`", from.Type(), to)
+	}
 	expr := constant.NewSIToFP(from, to)
 	if !t.Equal(expr.To) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.To, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.To, t)
 	}
 	return expr, nil
 }
@@ -917,9 +950,12 @@ func (gen *generator) irPtrToIntExpr(t types.Type, old *ast.PtrToIntExpr) (*cons
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if !constant.ValidConv("ptrtoint", from.Type(), to) {
+		return nil, errorf(old, "invalid ptrtoint expression; unable to convert from `%s` to `%s`", from.Type(), to)
+	}
 	expr := constant.NewPtrToInt(from, to)
 	if !t.Equal(expr.To) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.To, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.To, t)
 	}
 	return expr, nil
 }
@@ -939,9 +975,12 @@ func (gen *generator) irIntToPtrExpr(t types.Type, old *ast.IntToPtrExpr) (*cons
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if !constant.ValidConv("inttoptr", from.Type(), to) {
+		return nil, errorf(old, "invalid inttoptr expression; unable to convert from `%s` to `%s`", from.Type(), to)
+	}
 	expr := constant.NewIntToPtr(from, to)
 	if !t.Equal(expr.To) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.To, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.To, t)
 	}
 	return expr, nil
 }
@@ -961,9 +1000,12 @@ func (gen *generator) irBitCastExpr(t types.Type, old *ast.BitCastExpr) (*consta
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if !constant.ValidConv("bitcast", from.Type(), to) {
+		return nil, errorf(old, "invalid bitcast expression; unable to convert from `%s` to `%s`", from.Type(), to)
+	}
 	expr := constant.NewBitCast(from, to)
 	if !t.Equal(expr.To) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.To, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.To, t)
 	}
 	return expr, nil
 }
@@ -983,9 +1025,12 @@ func (gen *generator) irAddrSpaceCastExpr(t types.Type, old *ast.AddrSpaceCastEx
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	if !constant.ValidConv("addrspacecast", from.Type(), to) {
+		return nil, errorf(old, "invalid addrspacecast expression; unable to convert from `%s` to `%s`", from.Type(), to)
+	}
 	expr := constant.NewAddrSpaceCast(from, to)
 	if !t.Equal(expr.To) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.To, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.To, t)
 	}
 	return expr, nil
 }
@@ -1011,7 +1056,7 @@ func (gen *generator) irICmpExpr(t types.Type, old *ast.ICmpExpr) (*constant.Exp
 	}
 	expr := constant.NewICmp(pred, x, y)
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -1035,7 +1080,7 @@ func (gen *generator) irFCmpExpr(t types.Type, old *ast.FCmpExpr) (*constant.Exp
 	}
 	expr := constant.NewFCmp(pred, x, y)
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
@@ -1062,7 +1107,7 @@ func (gen *generator) irSelectExpr(t types.Type, old *ast.SelectExpr) (*constant
 	}
 	expr := constant.NewSelect(cond, x, y)
 	if !t.Equal(expr.Typ) {
-		return nil, errors.Errorf("constant expression type mismatch; expected %q, got %q", expr.Typ, t)
+		return nil, errorf(old, "constant expression type mismatch; expected %q, got %q", expr.Typ, t)
 	}
 	return expr, nil
 }
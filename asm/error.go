@@ -0,0 +1,80 @@
+package asm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/llir/ll"
+	"github.com/llir/ll/ast"
+	"github.com/pkg/errors"
+)
+
+// === [ Errors ] ==============================================================
+
+// Error is an error encountered while parsing LLVM IR assembly, located at a
+// source position. The *Error of errors returned by the parser may be
+// retrieved using errors.Cause of the github.com/pkg/errors package.
+type Error struct {
+	// (optional) Path to the source file; or empty if not specified.
+	Filename string
+	// Line number of the source position, starting at 1.
+	Line int
+	// Column number of the source position in bytes, starting at 1.
+	Column int
+	// Error message.
+	Msg string
+}
+
+// Error returns the error message prefixed by the source position of the
+// error.
+func (e *Error) Error() string {
+	if len(e.Filename) > 0 {
+		return fmt.Sprintf("%s:%d:%d: %s", e.Filename, e.Line, e.Column, e.Msg)
+	}
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Msg)
+}
+
+// ### [ Helper functions ] ####################################################
+
+// errorf returns a new error located at the source position of the given AST
+// node.
+func errorf(n ast.LlvmNode, format string, args ...interface{}) error {
+	line, col := n.LlvmNode().LineColumn()
+	return errors.WithStack(&Error{Line: line, Column: col, Msg: fmt.Sprintf(format, args...)})
+}
+
+// locate returns err located at the source position of the given AST node, if
+// not already located at a source position.
+func locate(n ast.LlvmNode, err error) error {
+	if _, ok := errors.Cause(err).(*Error); ok {
+		return errors.WithStack(err)
+	}
+	return errorf(n, "%v", err)
+}
+
+// positionError returns err with the source position of syntax errors of the
+// parser, and the source filename of errors located at a source position. err
+// is returned unchanged if not located at a source position.
+func positionError(path, content string, err error) error {
+	switch e := errors.Cause(err).(type) {
+	case ll.SyntaxError:
+		return errors.WithStack(syntaxError(path, content, e))
+	case *ll.SyntaxError:
+		return errors.WithStack(syntaxError(path, content, *e))
+	case *Error:
+		e.Filename = path
+	}
+	return err
+}
+
+// syntaxError returns an error located at the source position of the given
+// syntax error of the parser.
+func syntaxError(path, content string, e ll.SyntaxError) *Error {
+	offset := e.Offset
+	if offset > len(content) {
+		offset = len(content)
+	}
+	// Column of offset within its line.
+	col := offset - strings.LastIndex(content[:offset], "\n")
+	return &Error{Filename: path, Line: e.Line, Column: col, Msg: "syntax error"}
+}
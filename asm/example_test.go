@@ -41,6 +41,7 @@ func Example() {
 	//             UnnamedAddr:           0x0,
 	//             ExternallyInitialized: false,
 	//             Section:               "",
+	//             Partition:             "",
 	//             Comdat:                (*ir.ComdatDef)(nil),
 	//             Align:                 0x0,
 	//             FuncAttrs:             nil,
@@ -80,6 +81,7 @@ func Example() {
 	//             UnnamedAddr:     0x0,
 	//             FuncAttrs:       nil,
 	//             Section:         "",
+	//             Partition:       "",
 	//             Comdat:          (*ir.ComdatDef)(nil),
 	//             GC:              "",
 	//             Prefix:          nil,
@@ -87,7 +89,9 @@ func Example() {
 	//             Personality:     nil,
 	//             UseListOrders:   nil,
 	//             Metadata:        nil,
+	//             Lazy:            nil,
 	//             mu:              sync.Mutex{},
+	//             preds:           {},
 	//         },
 	//         &ir.Function{
 	//             GlobalIdent: ir.GlobalIdent{GlobalName:"rand", GlobalID:0},
@@ -166,6 +170,7 @@ func Example() {
 	//                             FuncAttrs:      nil,
 	//                             OperandBundles: nil,
 	//                             Metadata:       nil,
+	//                             Parent:         &ir.BasicBlock{(CYCLIC REFERENCE)},
 	//                         },
 	//                     },
 	//                     Term: &ir.TermRet{
@@ -184,6 +189,7 @@ func Example() {
 	//                             FuncAttrs:      nil,
 	//                             OperandBundles: nil,
 	//                             Metadata:       nil,
+	//                             Parent:         &ir.BasicBlock{(CYCLIC REFERENCE)},
 	//                         },
 	//                         Metadata: nil,
 	//                     },
@@ -204,6 +210,7 @@ func Example() {
 	//             UnnamedAddr:     0x0,
 	//             FuncAttrs:       nil,
 	//             Section:         "",
+	//             Partition:       "",
 	//             Comdat:          (*ir.ComdatDef)(nil),
 	//             GC:              "",
 	//             Prefix:          nil,
@@ -211,20 +218,25 @@ func Example() {
 	//             Personality:     nil,
 	//             UseListOrders:   nil,
 	//             Metadata:        nil,
+	//             Lazy:            nil,
 	//             mu:              sync.Mutex{},
+	//             preds:           {},
 	//         },
 	//     },
-	//     SourceFilename:    "",
-	//     DataLayout:        "",
-	//     TargetTriple:      "",
-	//     ModuleAsms:        nil,
-	//     ComdatDefs:        nil,
-	//     Aliases:           nil,
-	//     IFuncs:            nil,
-	//     AttrGroupDefs:     nil,
-	//     NamedMetadataDefs: nil,
-	//     MetadataDefs:      nil,
-	//     UseListOrders:     nil,
-	//     UseListOrderBBs:   nil,
+	//     SourceFilename:         "",
+	//     DataLayout:             "",
+	//     TargetTriple:           "",
+	//     ModuleAsms:             nil,
+	//     ComdatDefs:             nil,
+	//     Aliases:                nil,
+	//     IFuncs:                 nil,
+	//     AttrGroupDefs:          nil,
+	//     NamedMetadataDefs:      nil,
+	//     MetadataDefs:           nil,
+	//     UseListOrders:          nil,
+	//     UseListOrderBBs:        nil,
+	//     EmitClangStyleComments: false,
+	//     funcIndex:              {},
+	//     globalIndex:            {},
 	// }
 }
@@ -0,0 +1,30 @@
+//go:build go1.18
+// +build go1.18
+
+package asm
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func FuzzParseBytes(f *testing.F) {
+	seeds := []string{
+		"testdata/inst_other.ll",
+		"testdata/terminator.ll",
+		"testdata/param_attrs.ll",
+	}
+	for _, seed := range seeds {
+		buf, err := ioutil.ReadFile(seed)
+		if err != nil {
+			f.Fatalf("unable to read file %q; %+v", seed, err)
+		}
+		f.Add(buf)
+	}
+	f.Add([]byte("attributes #99999999999999999999 = { nounwind }\n"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Ensure that parsing returns an error rather than panicking on
+		// malformed input.
+		ParseBytes("<fuzz>", data)
+	})
+}
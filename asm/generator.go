@@ -1,6 +1,8 @@
 package asm
 
 import (
+	"sync"
+
 	"github.com/llir/ll/ast"
 	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/constant"
@@ -18,11 +20,16 @@ type generator struct {
 	// index of IR top-level entities.
 	new newIndex
 
-	// TODO: add rw mutex to gen.todo for access to blockaddress constant.
+	// mu serializes the materialization of lazily loaded function bodies, which
+	// share the generator (e.g. gen.todo).
+	mu sync.Mutex
 
 	// Fix dummy basic blocks after translation of function bodies and assignment
 	// of local IDs.
 	todo []*constant.BlockAddress
+	// Skip function bodies; dummy basic blocks of blockaddress constants are
+	// fixed once the function body of the referenced function is materialized.
+	lazy bool
 }
 
 // newGenerator returns a new generator for translating an LLVM IR module from
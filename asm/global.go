@@ -68,7 +68,11 @@ func (gen *generator) newGlobal(ident ir.GlobalIdent, oldContentType ast.Type, o
 	typ := types.NewPointer(contentType)
 	// (optional) Address space.
 	if oldAddrSpace.IsValid() {
-		typ.AddrSpace = irAddrSpace(oldAddrSpace)
+		addrSpace, err := irAddrSpace(oldAddrSpace)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		typ.AddrSpace = addrSpace
 	}
 	return &ir.Global{GlobalIdent: ident, ContentType: contentType, Typ: typ}, nil
 }
@@ -105,7 +109,11 @@ func (gen *generator) newFunc(ident ir.GlobalIdent, hdr ast.FuncHeader) (*ir.Fun
 	typ := types.NewPointer(sig)
 	// (optional) Address space.
 	if n, ok := hdr.AddrSpace(); ok {
-		typ.AddrSpace = irAddrSpace(n)
+		addrSpace, err := irAddrSpace(n)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		typ.AddrSpace = addrSpace
 	}
 	return &ir.Function{GlobalIdent: ident, Sig: sig, Typ: typ}, nil
 }
@@ -148,6 +156,9 @@ func (gen *generator) translateGlobalEntities() error {
 
 	// 4b1. Translate AST global declarations and definitions, indirect symbol
 	//      definitions, and function declarations and definitions to IR.
+	//
+	// Errors without source position are located at the top-level entity, so
+	// that the top-level entity may be skipped on error recovery.
 	for ident, old := range gen.old.globals {
 		v, ok := gen.new.globals[ident]
 		if !ok {
@@ -160,7 +171,7 @@ func (gen *generator) translateGlobalEntities() error {
 				panic(fmt.Errorf("invalid global declaration type; expected *ir.Global, got %T", v))
 			}
 			if err := gen.irGlobal(new, old); err != nil {
-				return errors.WithStack(err)
+				return locate(old, err)
 			}
 		case *ast.IndirectSymbolDef:
 			kind := old.IndirectSymbolKind().Text()
@@ -171,7 +182,7 @@ func (gen *generator) translateGlobalEntities() error {
 					panic(fmt.Errorf("invalid alias definition type; expected *ir.Alias, got %T", v))
 				}
 				if err := gen.irAlias(new, old); err != nil {
-					return errors.WithStack(err)
+					return locate(old, err)
 				}
 			case "ifunc":
 				new, ok := v.(*ir.IFunc)
@@ -179,7 +190,7 @@ func (gen *generator) translateGlobalEntities() error {
 					panic(fmt.Errorf("invalid IFunc definition type; expected *ir.IFunc, got %T", v))
 				}
 				if err := gen.irIFunc(new, old); err != nil {
-					return errors.WithStack(err)
+					return locate(old, err)
 				}
 			default:
 				panic(fmt.Errorf("support for indirect symbol kind %q not yet implemented", kind))
@@ -190,7 +201,7 @@ func (gen *generator) translateGlobalEntities() error {
 				panic(fmt.Errorf("invalid function declaration type; expected *ir.Function, got %T", v))
 			}
 			if err := gen.irFuncDecl(new, old); err != nil {
-				return errors.WithStack(err)
+				return locate(old, err)
 			}
 		case *ast.FuncDef:
 			new, ok := v.(*ir.Function)
@@ -198,7 +209,7 @@ func (gen *generator) translateGlobalEntities() error {
 				panic(fmt.Errorf("invalid function definition type; expected *ir.Function, got %T", v))
 			}
 			if err := gen.irFuncDef(new, old); err != nil {
-				return errors.WithStack(err)
+				return locate(old, err)
 			}
 		default:
 			panic(fmt.Errorf("support for global variable, indirect symbol or function %T not yet implemented", old))
@@ -240,7 +251,11 @@ func (gen *generator) irGlobal(new *ir.Global, old *ast.GlobalDecl) error {
 	// (optional) Externally initialized.
 	_, new.ExternallyInitialized = old.ExternallyInitialized()
 	// Immutability of global variable (constant or global).
-	new.Immutable = irImmutable(old.Immutable())
+	immutable, err := irImmutable(old.Immutable())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	new.Immutable = immutable
 	// Content type: handled in newGlobalEntity.
 	// Initial value (only used in global variable definitions).
 	if n, ok := old.Init(); ok {
@@ -260,17 +275,25 @@ func (gen *generator) irGlobal(new *ir.Global, old *ast.GlobalDecl) error {
 		// comdat name.
 		name := new.Name()
 		if n, ok := n.Name(); ok {
-			name = comdatName(n)
+			comdat, err := comdatName(n)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			name = comdat
 		}
 		def, ok := gen.new.comdatDefs[name]
 		if !ok {
-			return errors.Errorf("unable to locate comdat identifier %q used in global declaration of %q", enc.Comdat(name), new.Ident())
+			return errorf(n, "unable to locate comdat identifier %q used in global declaration of %q", enc.Comdat(name), new.Ident())
 		}
 		new.Comdat = def
 	}
 	// (optional) Alignment.
 	if n, ok := old.Align(); ok {
-		new.Align = irAlign(n)
+		align, err := irAlign(n)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		new.Align = align
 	}
 	// (optional) Metadata.
 	md, err := gen.irMetadataAttachments(old.Metadata())
@@ -282,7 +305,10 @@ func (gen *generator) irGlobal(new *ir.Global, old *ast.GlobalDecl) error {
 	if oldFuncAttrs := old.FuncAttrs(); len(oldFuncAttrs) > 0 {
 		new.FuncAttrs = make([]ir.FuncAttribute, len(oldFuncAttrs))
 		for i, oldFuncAttr := range oldFuncAttrs {
-			funcAttr := gen.irFuncAttribute(oldFuncAttr)
+			funcAttr, err := gen.irFuncAttribute(oldFuncAttr)
+			if err != nil {
+				return errors.WithStack(err)
+			}
 			new.FuncAttrs[i] = funcAttr
 		}
 	}
@@ -449,13 +475,20 @@ func (gen *generator) irFuncHeader(new *ir.Function, old ast.FuncHeader) error {
 	}
 	// (optional) Calling convention.
 	if n, ok := old.CallingConv(); ok {
-		new.CallingConv = irCallingConv(n)
+		callingConv, err := irCallingConv(n)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		new.CallingConv = callingConv
 	}
 	// (optional) Return attributes.
 	if oldReturnAttrs := old.ReturnAttrs(); len(oldReturnAttrs) > 0 {
 		new.ReturnAttrs = make([]ir.ReturnAttribute, len(oldReturnAttrs))
 		for i, oldRetAttr := range oldReturnAttrs {
-			retAttr := irReturnAttribute(oldRetAttr)
+			retAttr, err := irReturnAttribute(oldRetAttr)
+			if err != nil {
+				return errors.WithStack(err)
+			}
 			new.ReturnAttrs[i] = retAttr
 		}
 	}
@@ -473,14 +506,20 @@ func (gen *generator) irFuncHeader(new *ir.Function, old ast.FuncHeader) error {
 			// Name.
 			param := ir.NewParam("", typ)
 			if n, ok := oldParam.Name(); ok {
-				ident := localIdent(n)
+				ident, err := localIdent(n)
+				if err != nil {
+					return errors.WithStack(err)
+				}
 				param.LocalIdent = ident
 			}
 			// (optional) Parameter attributes.
 			if oldParamAttrs := oldParam.Attrs(); len(oldParamAttrs) > 0 {
 				param.Attrs = make([]ir.ParamAttribute, len(oldParamAttrs))
 				for j, oldParamAttr := range oldParamAttrs {
-					paramAttr := irParamAttribute(oldParamAttr)
+					paramAttr, err := irParamAttribute(oldParamAttr)
+					if err != nil {
+						return errors.WithStack(err)
+					}
 					param.Attrs[j] = paramAttr
 				}
 			}
@@ -496,7 +535,10 @@ func (gen *generator) irFuncHeader(new *ir.Function, old ast.FuncHeader) error {
 	if oldFuncAttrs := old.FuncAttrs(); len(oldFuncAttrs) > 0 {
 		new.FuncAttrs = make([]ir.FuncAttribute, len(oldFuncAttrs))
 		for i, oldFuncAttr := range oldFuncAttrs {
-			funcAttr := gen.irFuncAttribute(oldFuncAttr)
+			funcAttr, err := gen.irFuncAttribute(oldFuncAttr)
+			if err != nil {
+				return errors.WithStack(err)
+			}
 			new.FuncAttrs[i] = funcAttr
 		}
 	}
@@ -511,11 +553,15 @@ func (gen *generator) irFuncHeader(new *ir.Function, old ast.FuncHeader) error {
 		// comdat name.
 		name := new.Name()
 		if n, ok := n.Name(); ok {
-			name = comdatName(n)
+			comdat, err := comdatName(n)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			name = comdat
 		}
 		def, ok := gen.new.comdatDefs[name]
 		if !ok {
-			return errors.Errorf("unable to locate comdat identifier %q used in function header of %q", enc.Comdat(name), new.Ident())
+			return errorf(n, "unable to locate comdat identifier %q used in function header of %q", enc.Comdat(name), new.Ident())
 		}
 		new.Comdat = def
 	}
@@ -23,117 +23,117 @@ import (
 
 // globalIdent returns the identifier (without '@' prefix) of the given global
 // identifier.
-func globalIdent(old ast.GlobalIdent) ir.GlobalIdent {
+func globalIdent(old ast.GlobalIdent) (ir.GlobalIdent, error) {
 	ident := old.Text()
 	const prefix = "@"
 	if !strings.HasPrefix(ident, prefix) {
-		panic(fmt.Errorf("invalid global identifier %q; missing '%s' prefix", ident, prefix))
+		return ir.GlobalIdent{}, errors.Errorf("invalid global identifier %q; missing '%s' prefix", ident, prefix)
 	}
 	ident = ident[len(prefix):]
 	if id, err := strconv.ParseInt(ident, 10, 64); err == nil {
-		return ir.GlobalIdent{GlobalID: id}
+		return ir.GlobalIdent{GlobalID: id}, nil
 	}
 	// Unquote after trying to parse as ID, since @"42" is recognized as named
 	// and not unnamed.
 	ident = unquote(ident)
-	return ir.GlobalIdent{GlobalName: ident}
+	return ir.GlobalIdent{GlobalName: ident}, nil
 }
 
 // --- [ Local identifiers ] ---------------------------------------------------
 
 // localIdent returns the identifier (without '%' prefix) of the given local
 // identifier.
-func localIdent(old ast.LocalIdent) ir.LocalIdent {
+func localIdent(old ast.LocalIdent) (ir.LocalIdent, error) {
 	ident := old.Text()
 	const prefix = "%"
 	if !strings.HasPrefix(ident, prefix) {
-		panic(fmt.Errorf("invalid local identifier %q; missing '%s' prefix", ident, prefix))
+		return ir.LocalIdent{}, errors.Errorf("invalid local identifier %q; missing '%s' prefix", ident, prefix)
 	}
 	ident = ident[len(prefix):]
 	if id, err := strconv.ParseInt(ident, 10, 64); err == nil {
-		return ir.LocalIdent{LocalID: id}
+		return ir.LocalIdent{LocalID: id}, nil
 	}
 	// Unquote after trying to parse as ID, since %"42" is recognized as named
 	// and not unnamed.
 	ident = unquote(ident)
-	return ir.LocalIdent{LocalName: ident}
+	return ir.LocalIdent{LocalName: ident}, nil
 }
 
 // --- [ Label identifiers ] ---------------------------------------------------
 
 // labelIdent returns the identifier (without ':' suffix) of the given label
 // identifier.
-func labelIdent(old ast.LabelIdent) ir.LocalIdent {
+func labelIdent(old ast.LabelIdent) (ir.LocalIdent, error) {
 	ident := old.Text()
 	const suffix = ":"
 	if !strings.HasSuffix(ident, suffix) {
-		panic(fmt.Errorf("invalid label identifier %q; missing '%s' suffix", ident, suffix))
+		return ir.LocalIdent{}, errors.Errorf("invalid label identifier %q; missing '%s' suffix", ident, suffix)
 	}
 	ident = ident[:len(ident)-len(suffix)]
 	// Note, label identifiers are always named if present (i.e. `42:` has the
 	// label name 42, not the ID 42).
 	ident = unquote(ident)
-	return ir.LocalIdent{LocalName: ident}
+	return ir.LocalIdent{LocalName: ident}, nil
 }
 
 // --- [ Attribute group identifiers ] -----------------------------------------
 
 // attrGroupID returns the ID (without '#' prefix) of the given attribute group
 // ID.
-func attrGroupID(old ast.AttrGroupID) int64 {
+func attrGroupID(old ast.AttrGroupID) (int64, error) {
 	text := old.Text()
 	const prefix = "#"
 	if !strings.HasPrefix(text, prefix) {
-		panic(fmt.Errorf("invalid attribute group ID %q; missing '%s' prefix", text, prefix))
+		return 0, errors.Errorf("invalid attribute group ID %q; missing '%s' prefix", text, prefix)
 	}
 	text = text[len(prefix):]
 	id, err := strconv.ParseInt(text, 10, 64)
 	if err != nil {
-		panic(fmt.Errorf("unable to parse attribute group ID %q; %v", text, err))
+		return 0, errors.Errorf("unable to parse attribute group ID %q; %v", text, err)
 	}
-	return id
+	return id, nil
 }
 
 // --- [ Comdat identifiers ] --------------------------------------------------
 
 // comdatName returns the name (without '%' prefix) of the given comdat name.
-func comdatName(old ast.ComdatName) string {
+func comdatName(old ast.ComdatName) (string, error) {
 	name := old.Text()
 	const prefix = "$"
 	if !strings.HasPrefix(name, prefix) {
-		panic(fmt.Errorf("invalid comdat name %q; missing '%s' prefix", name, prefix))
+		return "", errors.Errorf("invalid comdat name %q; missing '%s' prefix", name, prefix)
 	}
 	name = name[len(prefix):]
-	return unquote(name)
+	return unquote(name), nil
 }
 
 // --- [ Metadata identifiers ] ------------------------------------------------
 
 // metadataName returns the name (without '!' prefix) of the given metadata
 // name.
-func metadataName(old ast.MetadataName) string {
+func metadataName(old ast.MetadataName) (string, error) {
 	name := old.Text()
 	const prefix = "!"
 	if !strings.HasPrefix(name, prefix) {
-		panic(fmt.Errorf("invalid metadata name %q; missing '%s' prefix", name, prefix))
+		return "", errors.Errorf("invalid metadata name %q; missing '%s' prefix", name, prefix)
 	}
 	name = name[len(prefix):]
-	return string(enc.Unescape(name))
+	return string(enc.Unescape(name)), nil
 }
 
 // metadataID returns the ID (without '!' prefix) of the given metadata ID.
-func metadataID(old ast.MetadataID) int64 {
+func metadataID(old ast.MetadataID) (int64, error) {
 	text := old.Text()
 	const prefix = "!"
 	if !strings.HasPrefix(text, prefix) {
-		panic(fmt.Errorf("invalid metadata ID %q; missing '%s' prefix", text, prefix))
+		return 0, errors.Errorf("invalid metadata ID %q; missing '%s' prefix", text, prefix)
 	}
 	text = text[len(prefix):]
 	id, err := strconv.ParseInt(text, 10, 64)
 	if err != nil {
-		panic(fmt.Errorf("unable to parse metadata ID %q; %v", text, err))
+		return 0, errors.Errorf("unable to parse metadata ID %q; %v", text, err)
 	}
-	return id
+	return id, nil
 }
 
 // === [ Literals ] ============================================================
@@ -141,48 +141,51 @@ func metadataID(old ast.MetadataID) int64 {
 // --- [ Integer literals ] ----------------------------------------------------
 
 // boolLit returns the boolean value corresponding to the given boolean literal.
-func boolLit(old ast.BoolLit) bool {
+func boolLit(old ast.BoolLit) (bool, error) {
 	text := old.Text()
 	switch text {
 	case "true":
-		return true
+		return true, nil
 	case "false":
-		return false
+		return false, nil
 	default:
-		panic(fmt.Errorf(`invalid boolean literal; expected "true" or "false", got %q`, text))
+		return false, errors.Errorf(`invalid boolean literal; expected "true" or "false", got %q`, text)
 	}
 }
 
 // uintLit returns the unsigned integer value corresponding to the given
 // unsigned integer literal.
-func uintLit(old ast.UintLit) uint64 {
+func uintLit(old ast.UintLit) (uint64, error) {
 	text := old.Text()
 	x, err := strconv.ParseUint(text, 10, 64)
 	if err != nil {
-		panic(fmt.Errorf("unable to parse unsigned integer literal %q; %v", text, err))
+		return 0, errors.Errorf("unable to parse unsigned integer literal %q; %v", text, err)
 	}
-	return x
+	return x, nil
 }
 
 // uintSlice returns the slice of unsigned integer value corresponding to the given
 // unsigned integer slice.
-func uintSlice(olds []ast.UintLit) []uint64 {
+func uintSlice(olds []ast.UintLit) ([]uint64, error) {
 	xs := make([]uint64, len(olds))
 	for i, old := range olds {
-		x := uintLit(old)
+		x, err := uintLit(old)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
 		xs[i] = x
 	}
-	return xs
+	return xs, nil
 }
 
 // intLit returns the integer value corresponding to the given integer literal.
-func intLit(old ast.IntLit) int64 {
+func intLit(old ast.IntLit) (int64, error) {
 	text := old.Text()
 	x, err := strconv.ParseInt(text, 10, 64)
 	if err != nil {
-		panic(fmt.Errorf("unable to parse integer literal %q; %v", text, err))
+		return 0, errors.Errorf("unable to parse integer literal %q; %v", text, err)
 	}
-	return x
+	return x, nil
 }
 
 // --- [ String literals ] -----------------------------------------------------
@@ -196,13 +199,21 @@ func stringLit(old ast.StringLit) string {
 
 // irAddrSpace returns the IR address space corresponding to the given AST
 // address space.
-func irAddrSpace(old ast.AddrSpace) types.AddrSpace {
-	return types.AddrSpace(uintLit(old.N()))
+func irAddrSpace(old ast.AddrSpace) (types.AddrSpace, error) {
+	x, err := uintLit(old.N())
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return types.AddrSpace(x), nil
 }
 
 // irAlign returns the IR alignment corresponding to the given AST alignment.
-func irAlign(old ast.Align) ir.Align {
-	return ir.Align(uintLit(old.N()))
+func irAlign(old ast.Align) (ir.Align, error) {
+	x, err := uintLit(old.N())
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return ir.Align(x), nil
 }
 
 // irArg returns the IR argument corresponding to the given AST argument.
@@ -221,7 +232,10 @@ func (fgen *funcGen) irArg(old ast.Arg) (value.Value, error) {
 		if oldAttrs := old.Attrs(); len(oldAttrs) > 0 {
 			attrs := make([]ir.ParamAttribute, len(oldAttrs))
 			for i, oldAttr := range old.Attrs() {
-				attr := irParamAttribute(oldAttr)
+				attr, err := irParamAttribute(oldAttr)
+				if err != nil {
+					return nil, errors.WithStack(err)
+				}
 				attrs[i] = attr
 			}
 			return &ir.Arg{Attrs: attrs, Value: x}, nil
@@ -240,37 +254,35 @@ func (fgen *funcGen) irArg(old ast.Arg) (value.Value, error) {
 
 // irBasicBlock returns the IR basic block corresponding to the given AST label.
 func (fgen *funcGen) irBasicBlock(old ast.Label) (*ir.BasicBlock, error) {
-	ident := localIdent(old.Name())
-	v, ok := fgen.locals[ident]
-	if !ok {
-		return nil, errors.Errorf("unable to locate local identifier %q", ident.Ident())
-	}
-	block, ok := v.(*ir.BasicBlock)
-	if !ok {
-		return nil, errors.Errorf("invalid basic block type; expected *ir.BasicBlock, got %T", v)
+	block, err := fgen.block(old.Name())
+	if err != nil {
+		return nil, errors.WithStack(err)
 	}
 	return block, nil
 }
 
 // irCallingConv returns the IR calling convention corresponding to the given
 // AST calling convention.
-func irCallingConv(old ast.CallingConv) enum.CallingConv {
+func irCallingConv(old ast.CallingConv) (enum.CallingConv, error) {
 	switch old := old.(type) {
 	case *ast.CallingConvEnum:
-		return asmenum.CallingConvFromString(old.Text())
+		return asmenum.CallingConvFromString(old.Text()), nil
 	case *ast.CallingConvInt:
-		cc := uintLit(old.UintLit())
+		cc, err := uintLit(old.UintLit())
+		if err != nil {
+			return enum.CallingConvNone, errors.WithStack(err)
+		}
 		switch cc {
 		case 0:
 			// Note, C calling convention is defined as 0 in LLVM. To have the zero-value
 			// calling convention mean no calling convention, re-define C calling
 			// convention as 1, and use 0 for none.
-			return enum.CallingConvC
+			return enum.CallingConvC, nil
 		default:
-			return enum.CallingConv(cc)
+			return enum.CallingConv(cc), nil
 		}
 	default:
-		panic(fmt.Errorf("support for calling convention type %T not yet implemented", old))
+		return enum.CallingConvNone, errors.Errorf("support for calling convention type %T not yet implemented", old)
 	}
 }
 
@@ -327,10 +339,9 @@ func (fgen *funcGen) irExceptionScope(old ast.ExceptionScope) (ir.ExceptionScope
 	case *ast.NoneConst:
 		return constant.None, nil
 	case *ast.LocalIdent:
-		ident := localIdent(*old)
-		v, ok := fgen.locals[ident]
-		if !ok {
-			return nil, errors.Errorf("unable to locate local identifier %q", ident.Ident())
+		v, err := fgen.local(*old)
+		if err != nil {
+			return nil, errors.WithStack(err)
 		}
 		return v, nil
 	default:
@@ -354,17 +365,20 @@ func irFastMathFlags(olds []ast.FastMathFlag) []enum.FastMathFlag {
 
 // irFuncAttribute returns the IR function attribute corresponding to the given
 // AST function attribute.
-func (gen *generator) irFuncAttribute(old ast.FuncAttribute) ir.FuncAttribute {
+func (gen *generator) irFuncAttribute(old ast.FuncAttribute) (ir.FuncAttribute, error) {
 	switch old := old.(type) {
 	case *ast.AttrString:
-		return ir.AttrString(unquote(old.Text()))
+		return ir.AttrString(unquote(old.Text())), nil
 	case *ast.AttrPair:
 		return ir.AttrPair{
 			Key:   unquote(old.Key().Text()),
 			Value: unquote(old.Val().Text()),
-		}
+		}, nil
 	case *ast.AttrGroupID:
-		id := attrGroupID(*old)
+		id, err := attrGroupID(*old)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
 		def, ok := gen.new.attrGroupDefs[id]
 		if !ok {
 			// Attribute group definition for ID not found.
@@ -378,37 +392,49 @@ func (gen *generator) irFuncAttribute(old ast.FuncAttribute) ir.FuncAttribute {
 			def = &ir.AttrGroupDef{ID: id}
 			gen.new.attrGroupDefs[id] = def
 		}
-		return def
+		return def, nil
 	// TODO: add support for Align.
 	//case *ast.Align:
-	//	return ir.Align(uintLit(old.N()))
+	//	return ir.Align(uintLit(old.N())), nil
 	case *ast.AlignPair:
-		return ir.Align(uintLit(old.N()))
+		n, err := uintLit(old.N())
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return ir.Align(n), nil
 	case *ast.AlignStack:
-		return ir.AlignStack(uintLit(old.N()))
+		n, err := uintLit(old.N())
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return ir.AlignStack(n), nil
 	case *ast.AlignStackPair:
-		return ir.AlignStack(uintLit(old.N()))
+		n, err := uintLit(old.N())
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return ir.AlignStack(n), nil
 	case *ast.AllocSize:
 		// TODO: add support for AllocSize.
-		panic("support for function attribute AllocSize not yet implemented")
+		return nil, errors.New("support for function attribute AllocSize not yet implemented")
 	case *ast.FuncAttr:
-		return asmenum.FuncAttrFromString(old.Text())
+		return asmenum.FuncAttrFromString(old.Text()), nil
 	default:
-		panic(fmt.Errorf("support for function attribute %T not yet implemented", old))
+		return nil, errors.Errorf("support for function attribute %T not yet implemented", old)
 	}
 }
 
 // irImmutable returns the immutable boolean (constant or global) corresponding
 // to the given AST immutable.
-func irImmutable(old ast.Immutable) bool {
+func irImmutable(old ast.Immutable) (bool, error) {
 	text := old.Text()
 	switch text {
 	case "constant":
-		return true
+		return true, nil
 	case "global":
-		return false
+		return false, nil
 	default:
-		panic(fmt.Errorf("support for immutable %q not yet implemented", text))
+		return false, errors.Errorf("support for immutable %q not yet implemented", text)
 	}
 }
 
@@ -419,14 +445,9 @@ func (fgen *funcGen) irIncoming(xType types.Type, oldX ast.Value, oldPred ast.Lo
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	predIdent := localIdent(oldPred)
-	v, ok := fgen.locals[predIdent]
-	if !ok {
-		return nil, errors.Errorf("unable to locate local identifier %q", predIdent.Ident())
-	}
-	pred, ok := v.(*ir.BasicBlock)
-	if !ok {
-		return nil, errors.Errorf("invalid basic block type; expected *ir.BasicBlock, got %T", v)
+	pred, err := fgen.block(oldPred)
+	if err != nil {
+		return nil, errors.WithStack(err)
 	}
 	return ir.NewIncoming(x, pred), nil
 }
@@ -520,34 +541,46 @@ func irOverflowFlags(olds []ast.OverflowFlag) []enum.OverflowFlag {
 
 // irParamAttribute returns the IR parameter attribute corresponding to the given
 // AST parameter attribute.
-func irParamAttribute(old ast.ParamAttribute) ir.ParamAttribute {
+func irParamAttribute(old ast.ParamAttribute) (ir.ParamAttribute, error) {
 	switch old := old.(type) {
 	case *ast.AttrString:
-		return ir.AttrString(unquote(old.Text()))
+		return ir.AttrString(unquote(old.Text())), nil
 	case *ast.AttrPair:
 		return ir.AttrPair{
 			Key:   unquote(old.Key().Text()),
 			Value: unquote(old.Val().Text()),
-		}
+		}, nil
 	case *ast.Align:
-		return ir.Align(uintLit(old.N()))
+		n, err := uintLit(old.N())
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return ir.Align(n), nil
 	case *ast.Dereferenceable:
-		return ir.Dereferenceable{N: uintLit(old.N())}
+		n, err := uintLit(old.N())
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return ir.Dereferenceable{N: n}, nil
 	case *ast.DereferenceableOrNull:
+		n, err := uintLit(old.N())
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
 		return ir.Dereferenceable{
-			N:           uintLit(old.N()),
+			N:           n,
 			DerefOrNull: true,
-		}
+		}, nil
 	case *ast.ParamAttr:
-		return asmenum.ParamAttrFromString(old.Text())
+		return asmenum.ParamAttrFromString(old.Text()), nil
 	default:
-		panic(fmt.Errorf("support for parameter attribute %T not yet implemented", old))
+		return nil, errors.Errorf("support for parameter attribute %T not yet implemented", old)
 	}
 }
 
 // irReturnAttribute returns the IR return attribute corresponding to the given
 // AST return attribute.
-func irReturnAttribute(old ast.ReturnAttribute) ir.ReturnAttribute {
+func irReturnAttribute(old ast.ReturnAttribute) (ir.ReturnAttribute, error) {
 	switch old := old.(type) {
 	// TODO: add support for AttrString.
 	//case *ast.AttrString:
@@ -559,18 +592,30 @@ func irReturnAttribute(old ast.ReturnAttribute) ir.ReturnAttribute {
 	//		Value: unquote(old.Val().Text()),
 	//	}
 	case *ast.Align:
-		return ir.Align(uintLit(old.N()))
+		n, err := uintLit(old.N())
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return ir.Align(n), nil
 	case *ast.Dereferenceable:
-		return ir.Dereferenceable{N: uintLit(old.N())}
+		n, err := uintLit(old.N())
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return ir.Dereferenceable{N: n}, nil
 	case *ast.DereferenceableOrNull:
+		n, err := uintLit(old.N())
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
 		return ir.Dereferenceable{
-			N:           uintLit(old.N()),
+			N:           n,
 			DerefOrNull: true,
-		}
+		}, nil
 	case *ast.ReturnAttr:
-		return asmenum.ReturnAttrFromString(old.Text())
+		return asmenum.ReturnAttrFromString(old.Text()), nil
 	default:
-		panic(fmt.Errorf("support for return attribute %T not yet implemented", old))
+		return nil, errors.Errorf("support for return attribute %T not yet implemented", old)
 	}
 }
 
@@ -608,7 +653,10 @@ func (fgen *funcGen) irUseListOrder(old ast.UseListOrder) (*ir.UseListOrder, err
 		return nil, errors.WithStack(err)
 	}
 	// Indices.
-	indices := uintSlice(old.Indices())
+	indices, err := uintSlice(old.Indices())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 	useListOrder := &ir.UseListOrder{
 		Value:   val,
 		Indices: indices,
@@ -635,6 +683,28 @@ func text(n ast.LlvmNode) string {
 	return ""
 }
 
+// globalRedefinitionError returns an error reporting the redefinition of the
+// given global identifier, at the position of the new global declaration or
+// definition.
+func globalRedefinitionError(ident ir.GlobalIdent, new ast.LlvmNode) error {
+	return errorf(new, "redefinition of global '%s'", ident.Ident())
+}
+
+// equalAttrGroupDefs reports whether the given AST attribute group definitions
+// have identical function attributes.
+func equalAttrGroupDefs(a, b *ast.AttrGroupDef) bool {
+	aAttrs, bAttrs := a.FuncAttrs(), b.FuncAttrs()
+	if len(aAttrs) != len(bAttrs) {
+		return false
+	}
+	for i := range aAttrs {
+		if text(aAttrs[i]) != text(bAttrs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 // findBlock returns the basic block with the given local identifier in the
 // function.
 func findBlock(f *ir.Function, blockIdent ir.LocalIdent) (*ir.BasicBlock, error) {
@@ -16,7 +16,10 @@ func (fgen *funcGen) newInst(old ast.Instruction) (ir.Instruction, error) {
 	switch old := old.(type) {
 	// Value instructions.
 	case *ast.LocalDefInst:
-		ident := localIdent(old.Name())
+		ident, err := localIdent(old.Name())
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
 		return fgen.newValueInst(ident, old.Inst())
 	case ast.ValueInstruction:
 		unnamed := ir.LocalIdent{}
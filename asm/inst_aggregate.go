@@ -18,7 +18,10 @@ func (fgen *funcGen) newExtractValueInst(ident ir.LocalIdent, old *ast.ExtractVa
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	indices := uintSlice(old.Indices())
+	indices, err := uintSlice(old.Indices())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 	typ := aggregateElemType(xType, indices)
 	return &ir.InstExtractValue{LocalIdent: ident, Typ: typ}, nil
 }
@@ -51,7 +54,11 @@ func (fgen *funcGen) irExtractValueInst(new ir.Instruction, old *ast.ExtractValu
 	}
 	inst.X = x
 	// Element indices.
-	inst.Indices = uintSlice(old.Indices())
+	indices, err := uintSlice(old.Indices())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	inst.Indices = indices
 	// (optional) Metadata.
 	md, err := fgen.gen.irMetadataAttachments(old.Metadata())
 	if err != nil {
@@ -83,7 +90,11 @@ func (fgen *funcGen) irInsertValueInst(new ir.Instruction, old *ast.InsertValueI
 	}
 	inst.Elem = elem
 	// Element indices.
-	inst.Indices = uintSlice(old.Indices())
+	indices, err := uintSlice(old.Indices())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	inst.Indices = indices
 	// (optional) Metadata.
 	md, err := fgen.gen.irMetadataAttachments(old.Metadata())
 	if err != nil {
@@ -24,6 +24,16 @@ func (fgen *funcGen) newAllocaInst(ident ir.LocalIdent, old *ast.AllocaInst) (*i
 	inst := &ir.InstAlloca{LocalIdent: ident, ElemType: elemType}
 	// Cache inst.Typ.
 	inst.Type()
+	// (optional) Address space; stored in i.Typ. Recorded already at this stage
+	// since the result type of other instructions may depend on the result type
+	// of the alloca instruction.
+	if n, ok := old.AddrSpace(); ok {
+		addrSpace, err := irAddrSpace(n)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		inst.Typ.AddrSpace = addrSpace
+	}
 	return inst, nil
 }
 
@@ -57,7 +67,7 @@ func (fgen *funcGen) newAtomicRMWInst(ident ir.LocalIdent, old *ast.AtomicRMWIns
 	}
 	dt, ok := dstType.(*types.PointerType)
 	if !ok {
-		panic(fmt.Errorf("invalid pointer type; expected *types.PointerType, got %T", dstType))
+		return nil, errorf(old, "invalid pointer type; expected *types.PointerType, got %T", dstType)
 	}
 	return &ir.InstAtomicRMW{LocalIdent: ident, Typ: dt.ElemType}, nil
 }
@@ -70,7 +80,11 @@ func (fgen *funcGen) newGetElementPtrInst(ident ir.LocalIdent, old *ast.GetEleme
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	typ, err := fgen.gen.gepType(elemType, old.Indices())
+	srcType, err := fgen.gen.irType(old.Src().Typ())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	typ, err := fgen.gen.gepType(elemType, srcType, old.Indices())
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -108,12 +122,13 @@ func (fgen *funcGen) irAllocaInst(new ir.Instruction, old *ast.AllocaInst) error
 	_, inst.SwiftError = old.SwiftError()
 	// (optional) Alignment.
 	if n, ok := old.Align(); ok {
-		inst.Align = irAlign(n)
-	}
-	// (optional) Address space; stored in i.Typ.
-	if n, ok := old.AddrSpace(); ok {
-		inst.Typ.AddrSpace = irAddrSpace(n)
+		align, err := irAlign(n)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		inst.Align = align
 	}
+	// (optional) Address space; stored in i.Typ and recorded by newAllocaInst.
 	// (optional) Metadata.
 	md, err := fgen.gen.irMetadataAttachments(old.Metadata())
 	if err != nil {
@@ -152,7 +167,11 @@ func (fgen *funcGen) irLoadInst(new ir.Instruction, old *ast.LoadInst) error {
 	}
 	// (optional) Alignment.
 	if n, ok := old.Align(); ok {
-		inst.Align = irAlign(n)
+		align, err := irAlign(n)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		inst.Align = align
 	}
 	// (optional) Metadata.
 	md, err := fgen.gen.irMetadataAttachments(old.Metadata())
@@ -198,7 +217,11 @@ func (fgen *funcGen) irStoreInst(new ir.Instruction, old *ast.StoreInst) error {
 	}
 	// (optional) Alignment.
 	if n, ok := old.Align(); ok {
-		inst.Align = irAlign(n)
+		align, err := irAlign(n)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		inst.Align = align
 	}
 	// (optional) Metadata.
 	md, err := fgen.gen.irMetadataAttachments(old.Metadata())
@@ -363,8 +386,8 @@ func (fgen *funcGen) irGetElementPtrInst(new ir.Instruction, old *ast.GetElement
 
 // gepType returns the pointer type or vector of pointers type to the element at
 // the position in the type specified by the given indices, as calculated by the
-// getelementptr instruction.
-func (gen *generator) gepType(elemType types.Type, indices []ast.TypeValue) (types.Type, error) {
+// getelementptr instruction with the given source address type.
+func (gen *generator) gepType(elemType, srcType types.Type, indices []ast.TypeValue) (types.Type, error) {
 	e := elemType
 	for i, index := range indices {
 		if i == 0 {
@@ -377,7 +400,7 @@ func (gen *generator) gepType(elemType types.Type, indices []ast.TypeValue) (typ
 		switch t := e.(type) {
 		case *types.PointerType:
 			// ref: http://llvm.org/docs/GetElementPtr.html#what-is-dereferenced-by-gep
-			panic(fmt.Errorf("unable to index into element of pointer type `%v`; for more information, see http://llvm.org/docs/GetElementPtr.html#what-is-dereferenced-by-gep", elemType))
+			return nil, errorf(index, "unable to index into element of pointer type `%v`; for more information, see http://llvm.org/docs/GetElementPtr.html#what-is-dereferenced-by-gep", elemType)
 		case *types.VectorType:
 			e = t.ElemType
 		case *types.ArrayType:
@@ -387,7 +410,10 @@ func (gen *generator) gepType(elemType types.Type, indices []ast.TypeValue) (typ
 			case *ast.IntConst:
 				i, err := strconv.ParseInt(index.Text(), 10, 64)
 				if err != nil {
-					panic(fmt.Errorf("unable to parse integer %q; %v", index.Text(), err))
+					return nil, errorf(index, "unable to parse integer %q; %v", index.Text(), err)
+				}
+				if i < 0 || i >= int64(len(t.Fields)) {
+					return nil, errorf(index, "struct index %d out of range for struct type `%v` with %d fields", i, t, len(t.Fields))
 				}
 				e = t.Fields[i]
 			case *ast.VectorConst:
@@ -396,45 +422,55 @@ func (gen *generator) gepType(elemType types.Type, indices []ast.TypeValue) (typ
 				elem := elems[0].Val()
 				idx, ok := elem.(*ast.IntConst)
 				if !ok {
-					panic(fmt.Errorf("invalid index type for structure element; expected *ast.IntConst, got %T", elem))
+					return nil, errorf(index, "invalid index type for structure element; expected *ast.IntConst, got %T", elem)
 				}
 				i, err := strconv.ParseInt(idx.Text(), 10, 64)
 				if err != nil {
-					panic(fmt.Errorf("unable to parse integer %q; %v", idx.Text(), err))
+					return nil, errorf(index, "unable to parse integer %q; %v", idx.Text(), err)
 				}
 				// Sanity check. All vector elements must be integers, and must have
 				// the same value.
 				for _, elem := range elems {
 					idx, ok := elem.Val().(*ast.IntConst)
 					if !ok {
-						panic(fmt.Errorf("invalid index type for structure element; expected *ast.IntConst, got %T", elem.Val()))
+						return nil, errorf(index, "invalid index type for structure element; expected *ast.IntConst, got %T", elem.Val())
 					}
 					j, err := strconv.ParseInt(idx.Text(), 10, 64)
 					if err != nil {
-						panic(fmt.Errorf("unable to parse integer %q; %v", idx.Text(), err))
+						return nil, errorf(index, "unable to parse integer %q; %v", idx.Text(), err)
 					}
 					if i != j {
-						return nil, errors.Errorf("struct index mismatch; vector elements %d and %d differ", i, j)
+						return nil, errorf(index, "struct index mismatch; vector elements %d and %d differ", i, j)
 					}
 				}
+				if i < 0 || i >= int64(len(t.Fields)) {
+					return nil, errorf(index, "struct index %d out of range for struct type `%v` with %d fields", i, t, len(t.Fields))
+				}
 				e = t.Fields[i]
 			case *ast.ZeroInitializerConst:
+				if len(t.Fields) == 0 {
+					return nil, errorf(index, "struct index 0 out of range for struct type `%v` with 0 fields", t)
+				}
 				e = t.Fields[0]
 			default:
-				panic(fmt.Errorf("invalid index type for structure element; expected *ast.IntConst, *ast.VectorConst or *ast.ZeroInitializerConst, got %T", index))
+				return nil, errorf(index, "invalid index type for structure element; expected *ast.IntConst, *ast.VectorConst or *ast.ZeroInitializerConst, got %T", index)
 			}
 		default:
-			panic(fmt.Errorf("support for indexing element type %T not yet implemented", e))
+			return nil, errorf(index, "support for indexing element type %T not yet implemented", e)
 		}
 	}
-	// TODO: Validate how index vectors in gep are supposed to work.
+	// The result is a vector of pointers if the source address or any of the
+	// indices is a vector.
 	//
 	// Example from dir.ll:
 	//    %113 = getelementptr inbounds %struct.fileinfo, %struct.fileinfo* %96, <2 x i64> %110, !dbg !4736
 	//    %116 = bitcast i8** %115 to <2 x %struct.fileinfo*>*, !dbg !4738
 	//    store <2 x %struct.fileinfo*> %113, <2 x %struct.fileinfo*>* %116, align 8, !dbg !4738, !tbaa !1793
-	if len(indices) > 0 {
-		t, err := gen.irType(indices[0].Typ())
+	if t, ok := srcType.(*types.VectorType); ok {
+		return types.NewVector(t.Len, types.NewPointer(e)), nil
+	}
+	for _, index := range indices {
+		t, err := gen.irType(index.Typ())
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
@@ -27,7 +27,7 @@ func (fgen *funcGen) newICmpInst(ident ir.LocalIdent, old *ast.ICmpInst) (*ir.In
 	case *types.VectorType:
 		typ = types.NewVector(xType.Len, types.I1)
 	default:
-		panic(fmt.Errorf("invalid icmp operand type; expected *types.IntType, *types.PointerType or *types.VectorType, got %T", xType))
+		return nil, errorf(old, "invalid icmp operand type; expected *types.IntType, *types.PointerType or *types.VectorType, got %T", xType)
 	}
 	return &ir.InstICmp{LocalIdent: ident, Typ: typ}, nil
 }
@@ -46,7 +46,7 @@ func (fgen *funcGen) newFCmpInst(ident ir.LocalIdent, old *ast.FCmpInst) (*ir.In
 	case *types.VectorType:
 		typ = types.NewVector(xType.Len, types.I1)
 	default:
-		panic(fmt.Errorf("invalid fcmp operand type; expected *types.FloatType or *types.VectorType, got %T", xType))
+		return nil, errorf(old, "invalid fcmp operand type; expected *types.FloatType or *types.VectorType, got %T", xType)
 	}
 	return &ir.InstFCmp{LocalIdent: ident, Typ: typ}, nil
 }
@@ -292,25 +292,39 @@ func (fgen *funcGen) irCallInst(new ir.Instruction, old *ast.CallInst) error {
 	inst.FastMathFlags = irFastMathFlags(old.FastMathFlags())
 	// (optional) Calling convention.
 	if n, ok := old.CallingConv(); ok {
-		inst.CallingConv = irCallingConv(n)
+		callingConv, err := irCallingConv(n)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		inst.CallingConv = callingConv
 	}
 	// (optional) Return attributes.
 	if oldReturnAttrs := old.ReturnAttrs(); len(oldReturnAttrs) > 0 {
 		inst.ReturnAttrs = make([]ir.ReturnAttribute, len(oldReturnAttrs))
 		for i, oldRetAttr := range oldReturnAttrs {
-			retAttr := irReturnAttribute(oldRetAttr)
+			retAttr, err := irReturnAttribute(oldRetAttr)
+			if err != nil {
+				return errors.WithStack(err)
+			}
 			inst.ReturnAttrs[i] = retAttr
 		}
 	}
 	// (optional) Address space.
 	if n, ok := old.AddrSpace(); ok {
-		inst.AddrSpace = irAddrSpace(n)
+		addrSpace, err := irAddrSpace(n)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		inst.AddrSpace = addrSpace
 	}
 	// (optional) Function attributes.
 	if oldFuncAttrs := old.FuncAttrs(); len(oldFuncAttrs) > 0 {
 		inst.FuncAttrs = make([]ir.FuncAttribute, len(oldFuncAttrs))
 		for i, oldFuncAttr := range oldFuncAttrs {
-			funcAttr := fgen.gen.irFuncAttribute(oldFuncAttr)
+			funcAttr, err := fgen.gen.irFuncAttribute(oldFuncAttr)
+			if err != nil {
+				return errors.WithStack(err)
+			}
 			inst.FuncAttrs[i] = funcAttr
 		}
 	}
@@ -411,14 +425,13 @@ func (fgen *funcGen) irCatchPadInst(new ir.Instruction, old *ast.CatchPadInst) e
 		panic(fmt.Errorf("invalid IR instruction for AST instruction; expected *ir.InstCatchPad, got %T", new))
 	}
 	// Exception scope.
-	ident := localIdent(old.Scope())
-	v, ok := fgen.locals[ident]
-	if !ok {
-		return errors.Errorf("unable to locate local identifier %q", ident.Ident())
+	v, err := fgen.local(old.Scope())
+	if err != nil {
+		return errors.WithStack(err)
 	}
 	scope, ok := v.(*ir.TermCatchSwitch)
 	if !ok {
-		return errors.Errorf("invalid scope type; expected *ir.TermCatchSwitch, got %T", v)
+		return errorf(old, "invalid scope type; expected *ir.TermCatchSwitch, got %T", v)
 	}
 	inst.Scope = scope
 	// Exception arguments.
@@ -20,7 +20,7 @@ func (fgen *funcGen) newExtractElementInst(ident ir.LocalIdent, old *ast.Extract
 	}
 	xt, ok := xType.(*types.VectorType)
 	if !ok {
-		panic(fmt.Errorf("invalid vector type; expected *types.VectorType, got %T", xType))
+		return nil, errorf(old, "invalid vector type; expected *types.VectorType, got %T", xType)
 	}
 	return &ir.InstExtractElement{LocalIdent: ident, Typ: xt.ElemType}, nil
 }
@@ -34,7 +34,7 @@ func (fgen *funcGen) newInsertElementInst(ident ir.LocalIdent, old *ast.InsertEl
 	}
 	xt, ok := xType.(*types.VectorType)
 	if !ok {
-		panic(fmt.Errorf("invalid vector type; expected *types.VectorType, got %T", xType))
+		return nil, errorf(old, "invalid vector type; expected *types.VectorType, got %T", xType)
 	}
 	return &ir.InstInsertElement{LocalIdent: ident, Typ: xt}, nil
 }
@@ -48,7 +48,7 @@ func (fgen *funcGen) newShuffleVectorInst(ident ir.LocalIdent, old *ast.ShuffleV
 	}
 	xt, ok := xType.(*types.VectorType)
 	if !ok {
-		panic(fmt.Errorf("invalid vector type; expected *types.VectorType, got %T", xType))
+		return nil, errorf(old, "invalid vector type; expected *types.VectorType, got %T", xType)
 	}
 	maskType, err := fgen.gen.irType(old.Mask().Typ())
 	if err != nil {
@@ -56,7 +56,7 @@ func (fgen *funcGen) newShuffleVectorInst(ident ir.LocalIdent, old *ast.ShuffleV
 	}
 	mt, ok := maskType.(*types.VectorType)
 	if !ok {
-		panic(fmt.Errorf("invalid vector type; expected *types.VectorType, got %T", maskType))
+		return nil, errorf(old, "invalid vector type; expected *types.VectorType, got %T", maskType)
 	}
 	typ := types.NewVector(mt.Len, xt.ElemType)
 	return &ir.InstShuffleVector{LocalIdent: ident, Typ: typ}, nil
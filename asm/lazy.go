@@ -0,0 +1,365 @@
+package asm
+
+import (
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/llir/ll/ast"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
+)
+
+// ParseHeaderFile parses the given LLVM IR assembly file into an LLVM IR
+// module, skipping the bodies of function definitions.
+//
+// The module header (source filename, target specifiers, type definitions,
+// global variables, function signatures, attributes and metadata) is parsed
+// as usual, while function definitions are represented as declarations with a
+// lazily loaded function body, which may be loaded on demand using
+// Function.Materialize.
+func ParseHeaderFile(path string) (*ir.Module, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return ParseHeaderString(path, string(buf))
+}
+
+// ParseHeaderString parses the given LLVM IR assembly file into an LLVM IR
+// module, reading from content and skipping the bodies of function
+// definitions. An optional path to the source file may be specified for error
+// reporting.
+func ParseHeaderString(path, content string) (*ir.Module, error) {
+	scanStart := time.Now()
+	header, bodies := skipFuncBodies(content)
+	dbg.Println("skipping function bodies took:", time.Since(scanStart))
+	parseStart := time.Now()
+	tree, err := parseAST(path, header)
+	if err != nil {
+		return nil, errors.Wrapf(positionError(path, header, err), "unable to parse %q into an AST", path)
+	}
+	dbg.Println("parsing into AST took:", time.Since(parseStart))
+	root := ast.ToLlvmNode(tree.Root())
+	// Keep the generator to translate lazily loaded function bodies.
+	gen := newGenerator()
+	gen.lazy = true
+	m, err := gen.translate(root.(*ast.Module))
+	if err != nil {
+		return nil, errors.WithStack(positionError(path, header, err))
+	}
+	// Replace the placeholder bodies of function definitions with lazily loaded
+	// function bodies. Function definitions are stored in order of occurrence
+	// in the input.
+	i := 0
+	for _, f := range m.Funcs {
+		if len(f.Blocks) == 0 {
+			// Skip function declarations.
+			continue
+		}
+		if i >= len(bodies) {
+			return nil, errors.Errorf("unable to locate source range of function body of %q", f.Ident())
+		}
+		f.Blocks = nil
+		body := bodies[i]
+		body.path = path
+		body.gen = gen
+		f.Lazy = body
+		i++
+	}
+	if i != len(bodies) {
+		return nil, errors.Errorf("function body count mismatch; expected %d, got %d", len(bodies), i)
+	}
+	return m, nil
+}
+
+// lazyBody is a function body skipped by header-only parsing.
+type lazyBody struct {
+	// Path to the source file.
+	path string
+	// LLVM IR assembly source.
+	content string
+	// Start and end byte offsets of the function definition in the source.
+	start, end int
+	// Module generator of the header.
+	gen *generator
+}
+
+// Materialize parses the function body and stores the translated basic blocks
+// in f. The function is left untouched if materialization fails.
+//
+// Function bodies of a module share the generator of the module header, and
+// are therefore materialized one at a time; Materialize may be invoked
+// concurrently for distinct functions of the module.
+func (body *lazyBody) Materialize(f *ir.Function) error {
+	// Prepend line breaks to retain the line numbers of the source file.
+	content := strings.Repeat("\n", strings.Count(body.content[:body.start], "\n")) + body.Source()
+	tree, err := parseAST(body.path, content)
+	if err != nil {
+		return errors.Wrapf(err, "unable to parse function body of %q into an AST", f.Ident())
+	}
+	root := ast.ToLlvmNode(tree.Root()).(*ast.Module)
+	entities := root.TopLevelEntities()
+	if len(entities) != 1 {
+		return errors.Errorf("invalid number of top-level entities in function definition of %q; expected 1, got %d", f.Ident(), len(entities))
+	}
+	old, ok := entities[0].(*ast.FuncDef)
+	if !ok {
+		return errors.Errorf("invalid function definition of %q; expected *ast.FuncDef, got %T", f.Ident(), entities[0])
+	}
+	gen := body.gen
+	gen.mu.Lock()
+	defer gen.mu.Unlock()
+	// Restore the function and the blockaddress constants to fix if
+	// materialization fails.
+	orig := snapshotFunc(f)
+	todo := gen.todo
+	if err := gen.irFuncDef(f, old); err != nil {
+		restoreFunc(f, orig)
+		gen.todo = todo
+		return errors.WithStack(err)
+	}
+	// Fix basic block references in blockaddress constants. Constants referring
+	// to functions whose bodies have not yet been materialized are fixed once
+	// the bodies of those functions are materialized.
+	var pending []*constant.BlockAddress
+	fixed := make(map[*constant.BlockAddress]value.Named)
+	for _, c := range gen.todo {
+		if g, ok := c.Func.(*ir.Function); ok && g != f && g.Lazy != nil {
+			pending = append(pending, c)
+			continue
+		}
+		fixed[c] = c.Block
+		if err := fixBlockAddressConst(c); err != nil {
+			for c, block := range fixed {
+				c.Block = block
+			}
+			restoreFunc(f, orig)
+			gen.todo = todo
+			return errors.WithStack(err)
+		}
+	}
+	gen.todo = pending
+	return nil
+}
+
+// snapshotFunc returns a snapshot of the fields of the given function which are
+// set by the translation of its function definition.
+func snapshotFunc(f *ir.Function) *ir.Function {
+	return &ir.Function{
+		GlobalIdent:     f.GlobalIdent,
+		Sig:             f.Sig,
+		Params:          f.Params,
+		Blocks:          f.Blocks,
+		Typ:             f.Typ,
+		Linkage:         f.Linkage,
+		Preemption:      f.Preemption,
+		Visibility:      f.Visibility,
+		DLLStorageClass: f.DLLStorageClass,
+		CallingConv:     f.CallingConv,
+		ReturnAttrs:     f.ReturnAttrs,
+		UnnamedAddr:     f.UnnamedAddr,
+		FuncAttrs:       f.FuncAttrs,
+		Section:         f.Section,
+		Partition:       f.Partition,
+		Comdat:          f.Comdat,
+		GC:              f.GC,
+		Prefix:          f.Prefix,
+		Prologue:        f.Prologue,
+		Personality:     f.Personality,
+		UseListOrders:   f.UseListOrders,
+		Metadata:        f.Metadata,
+	}
+}
+
+// restoreFunc restores the fields of the given function from the snapshot
+// orig, as returned by snapshotFunc.
+func restoreFunc(f, orig *ir.Function) {
+	f.GlobalIdent = orig.GlobalIdent
+	f.Sig = orig.Sig
+	f.Params = orig.Params
+	f.Blocks = orig.Blocks
+	f.Typ = orig.Typ
+	f.Linkage = orig.Linkage
+	f.Preemption = orig.Preemption
+	f.Visibility = orig.Visibility
+	f.DLLStorageClass = orig.DLLStorageClass
+	f.CallingConv = orig.CallingConv
+	f.ReturnAttrs = orig.ReturnAttrs
+	f.UnnamedAddr = orig.UnnamedAddr
+	f.FuncAttrs = orig.FuncAttrs
+	f.Section = orig.Section
+	f.Partition = orig.Partition
+	f.Comdat = orig.Comdat
+	f.GC = orig.GC
+	f.Prefix = orig.Prefix
+	f.Prologue = orig.Prologue
+	f.Personality = orig.Personality
+	f.UseListOrders = orig.UseListOrders
+	f.Metadata = orig.Metadata
+	f.InvalidateCFG()
+}
+
+// SourceRange returns the start and end byte offsets of the function
+// definition in the LLVM IR assembly source.
+func (body *lazyBody) SourceRange() (start, end int) {
+	return body.start, body.end
+}
+
+// Source returns the LLVM IR assembly source of the function definition.
+func (body *lazyBody) Source() string {
+	return body.content[body.start:body.end]
+}
+
+// skipFuncBodies returns a copy of the given LLVM IR assembly source in which
+// the body of each function definition has been replaced by a placeholder
+// body, and the source ranges of the function definitions in order of
+// occurrence. Line breaks of skipped function bodies are retained, so that
+// line numbers of the header are left unchanged.
+func skipFuncBodies(content string) (string, []*lazyBody) {
+	var bodies []*lazyBody
+	buf := &strings.Builder{}
+	buf.Grow(len(content))
+	prev := 0
+	for pos := 0; pos < len(content); {
+		switch c := content[pos]; {
+		case c == ';':
+			pos = skipComment(content, pos)
+		case c == '"':
+			pos = skipString(content, pos)
+		case isDefine(content, pos):
+			lbrace, ok := findBodyStart(content, pos)
+			if !ok {
+				pos += len("define")
+				continue
+			}
+			rbrace, ok := findBodyEnd(content, lbrace)
+			if !ok {
+				pos += len("define")
+				continue
+			}
+			end := rbrace + 1
+			bodies = append(bodies, &lazyBody{content: content, start: pos, end: end})
+			// Placeholder body consisting of a single basic block.
+			buf.WriteString(content[prev : lbrace+1])
+			buf.WriteString("unreachable")
+			buf.WriteString(strings.Repeat("\n", strings.Count(content[lbrace:rbrace], "\n")))
+			buf.WriteString("}")
+			prev = end
+			pos = end
+		default:
+			pos++
+		}
+	}
+	buf.WriteString(content[prev:])
+	return buf.String(), bodies
+}
+
+// isDefine reports whether the 'define' keyword of a function definition is
+// located at the given position of the source.
+func isDefine(content string, pos int) bool {
+	const keyword = "define"
+	if !strings.HasPrefix(content[pos:], keyword) {
+		return false
+	}
+	if pos > 0 && !isSpace(content[pos-1]) {
+		return false
+	}
+	end := pos + len(keyword)
+	return end < len(content) && isSpace(content[end])
+}
+
+// findBodyStart returns the position of the left brace starting the function
+// body of the function definition at the given position of the source. The
+// left brace of a function body is the last token on its line.
+func findBodyStart(content string, pos int) (int, bool) {
+	depth := 0
+	for pos < len(content) {
+		switch c := content[pos]; c {
+		case ';':
+			pos = skipComment(content, pos)
+			continue
+		case '"':
+			pos = skipString(content, pos)
+			continue
+		case '(', '[', '<':
+			depth++
+		case ')', ']', '>':
+			depth--
+		case '{':
+			if depth == 0 && isLastOnLine(content, pos+1) {
+				return pos, true
+			}
+			depth++
+		case '}':
+			depth--
+		}
+		pos++
+	}
+	return 0, false
+}
+
+// findBodyEnd returns the position of the right brace matching the left brace
+// at the given position of the source.
+func findBodyEnd(content string, lbrace int) (int, bool) {
+	depth := 0
+	for pos := lbrace; pos < len(content); {
+		switch c := content[pos]; c {
+		case ';':
+			pos = skipComment(content, pos)
+			continue
+		case '"':
+			pos = skipString(content, pos)
+			continue
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return pos, true
+			}
+		}
+		pos++
+	}
+	return 0, false
+}
+
+// isLastOnLine reports whether the remainder of the line starting at the given
+// position of the source consists only of white space and comments.
+func isLastOnLine(content string, pos int) bool {
+	for ; pos < len(content); pos++ {
+		switch c := content[pos]; {
+		case c == '\n', c == ';':
+			return true
+		case !isSpace(c):
+			return false
+		}
+	}
+	return true
+}
+
+// skipComment returns the position following the comment at the given position
+// of the source.
+func skipComment(content string, pos int) int {
+	if i := strings.IndexByte(content[pos:], '\n'); i != -1 {
+		return pos + i
+	}
+	return len(content)
+}
+
+// skipString returns the position following the string literal at the given
+// position of the source.
+func skipString(content string, pos int) int {
+	// Note, double quotes are escaped as \22 within LLVM IR string literals.
+	if i := strings.IndexByte(content[pos+1:], '"'); i != -1 {
+		return pos + 1 + i + 1
+	}
+	return len(content)
+}
+
+// isSpace reports whether the given character is a white space character.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
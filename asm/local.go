@@ -20,6 +20,8 @@
 package asm
 
 import (
+	"strings"
+
 	"github.com/llir/ll/ast"
 	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/types"
@@ -108,7 +110,11 @@ func (fgen *funcGen) newLocals(oldBlocks []ast.BasicBlock) error {
 	for i, oldBlock := range oldBlocks {
 		block := &ir.BasicBlock{}
 		if n, ok := oldBlock.Name(); ok {
-			block.LocalIdent = labelIdent(n)
+			ident, err := labelIdent(n)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			block.LocalIdent = ident
 		}
 		if oldInsts := oldBlock.Insts(); len(oldInsts) > 0 {
 			block.Insts = make([]ir.Instruction, len(oldInsts))
@@ -117,6 +123,9 @@ func (fgen *funcGen) newLocals(oldBlocks []ast.BasicBlock) error {
 				if err != nil {
 					return errors.WithStack(err)
 				}
+				if call, ok := inst.(*ir.InstCall); ok {
+					call.Parent = block
+				}
 				block.Insts[j] = inst
 			}
 		}
@@ -152,7 +161,7 @@ func (fgen *funcGen) indexLocals() error {
 				// Skip non-value instructions.
 				continue
 			}
-			ident := ir.LocalIdent{LocalName: v.Name(), LocalID: v.ID()}
+			ident := localKey(v)
 			if err := fgen.addLocal(ident, v); err != nil {
 				return errors.WithStack(err)
 			}
@@ -163,7 +172,7 @@ func (fgen *funcGen) indexLocals() error {
 			// Skip non-value terminators.
 			continue
 		}
-		ident := ir.LocalIdent{LocalName: v.Name(), LocalID: v.ID()}
+		ident := localKey(v)
 		if err := fgen.addLocal(ident, v); err != nil {
 			return errors.WithStack(err)
 		}
@@ -173,6 +182,17 @@ func (fgen *funcGen) indexLocals() error {
 
 // ### [ Helper functions ] ####################################################
 
+// localKey returns the local identifier used as key of the given local variable
+// in the map of local variables of the function, as translated by localIdent.
+// Unnamed local variables are keyed by local ID, and named local variables by
+// unquoted name (e.g. %"42" has the name 42).
+func localKey(v local) ir.LocalIdent {
+	if v.IsUnnamed() {
+		return ir.LocalIdent{LocalID: v.ID()}
+	}
+	return ir.LocalIdent{LocalName: unquote(strings.TrimPrefix(v.Ident(), "%"))}
+}
+
 // addLocal adds the local variable with the given local identifier to the map
 // of local variables of the function.
 func (fgen *funcGen) addLocal(ident ir.LocalIdent, v value.Value) error {
@@ -182,3 +202,35 @@ func (fgen *funcGen) addLocal(ident ir.LocalIdent, v value.Value) error {
 	fgen.locals[ident] = v
 	return nil
 }
+
+// local returns the local variable (function parameter, basic block, or result
+// of instruction or terminator) of the function with the given AST local
+// identifier. All local variables of the function are created before
+// translating its instructions and terminators, and may thus be used before
+// being defined (e.g. by phi instructions and branch targets). An error is
+// returned if the local identifier is never defined in the function.
+func (fgen *funcGen) local(old ast.LocalIdent) (value.Value, error) {
+	ident, err := localIdent(old)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	v, ok := fgen.locals[ident]
+	if !ok {
+		return nil, errorf(old, "undefined local identifier %q in function %q", ident.Ident(), fgen.f.Ident())
+	}
+	return v, nil
+}
+
+// block returns the basic block of the function with the given AST local
+// identifier.
+func (fgen *funcGen) block(old ast.LocalIdent) (*ir.BasicBlock, error) {
+	v, err := fgen.local(old)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	block, ok := v.(*ir.BasicBlock)
+	if !ok {
+		return nil, errorf(old, "invalid basic block type of local identifier %q; expected *ir.BasicBlock, got %T", old.Text(), v)
+	}
+	return block, nil
+}
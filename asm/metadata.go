@@ -30,7 +30,10 @@ func (gen *generator) irMetadataAttachments(olds []ast.MetadataAttachment) ([]*m
 // the given AST metadata attachment.
 func (gen *generator) irMetadataAttachment(old ast.MetadataAttachment) (*metadata.Attachment, error) {
 	// Name.
-	name := metadataName(old.Name())
+	name, err := metadataName(old.Name())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 	// Node.
 	node, err := gen.irMDNode(old.MDNode())
 	if err != nil {
@@ -144,7 +147,10 @@ func (gen *generator) irMetadataNode(old ast.MetadataNode) (metadata.Node, error
 // metadataDefFromID returns the IR metadata definition associated with the
 // given AST metadata ID.
 func (gen *generator) metadataDefFromID(old ast.MetadataID) (*metadata.Def, error) {
-	id := metadataID(old)
+	id, err := metadataID(old)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 	node, ok := gen.new.metadataDefs[id]
 	if !ok {
 		return nil, errors.Errorf("unable to locate metadata ID %q", enc.MetadataID(id))
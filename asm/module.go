@@ -28,7 +28,10 @@ func (gen *generator) indexTopLevelEntities(old *ast.Module) error {
 			asm := unquote(entity.Asm().Text())
 			gen.m.ModuleAsms = append(gen.m.ModuleAsms, asm)
 		case *ast.TypeDef:
-			ident := localIdent(entity.Name())
+			ident, err := localIdent(entity.Name())
+			if err != nil {
+				return errors.WithStack(err)
+			}
 			name := getTypeName(ident)
 			if prev, ok := gen.old.typeDefs[name]; ok {
 				if _, ok := prev.Typ().(*ast.OpaqueType); !ok {
@@ -37,54 +40,83 @@ func (gen *generator) indexTopLevelEntities(old *ast.Module) error {
 			}
 			gen.old.typeDefs[name] = entity
 		case *ast.ComdatDef:
-			name := comdatName(entity.Name())
+			name, err := comdatName(entity.Name())
+			if err != nil {
+				return errors.WithStack(err)
+			}
 			if prev, ok := gen.old.comdatDefs[name]; ok {
 				return errors.Errorf("comdat name %q already present; prev `%s`, new `%s`", enc.Comdat(name), text(prev), text(entity))
 			}
 			gen.old.comdatDefs[name] = entity
 		case *ast.GlobalDecl:
-			ident := globalIdent(entity.Name())
-			if prev, ok := gen.old.globals[ident]; ok {
-				return errors.Errorf("global identifier %q already present; prev `%s`, new `%s`", ident.Ident(), text(prev), text(entity))
+			ident, err := globalIdent(entity.Name())
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if _, ok := gen.old.globals[ident]; ok {
+				return globalRedefinitionError(ident, entity)
 			}
 			gen.old.globals[ident] = entity
 			gen.old.globalOrder = append(gen.old.globalOrder, ident)
 		case *ast.IndirectSymbolDef:
-			ident := globalIdent(entity.Name())
-			if prev, ok := gen.old.globals[ident]; ok {
-				return errors.Errorf("global identifier %q already present; prev `%s`, new `%s`", ident.Ident(), text(prev), text(entity))
+			ident, err := globalIdent(entity.Name())
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if _, ok := gen.old.globals[ident]; ok {
+				return globalRedefinitionError(ident, entity)
 			}
 			gen.old.globals[ident] = entity
 			gen.old.globalOrder = append(gen.old.globalOrder, ident)
 		case *ast.FuncDecl:
-			ident := globalIdent(entity.Header().Name())
-			if prev, ok := gen.old.globals[ident]; ok {
-				return errors.Errorf("global identifier %q already present; prev `%s`, new `%s`", ident.Ident(), text(prev), text(entity))
+			ident, err := globalIdent(entity.Header().Name())
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if _, ok := gen.old.globals[ident]; ok {
+				return globalRedefinitionError(ident, entity)
 			}
 			gen.old.globals[ident] = entity
 			gen.old.globalOrder = append(gen.old.globalOrder, ident)
 		case *ast.FuncDef:
-			ident := globalIdent(entity.Header().Name())
-			if prev, ok := gen.old.globals[ident]; ok {
-				return errors.Errorf("global identifier %q already present; prev `%s`, new `%s`", ident.Ident(), text(prev), text(entity))
+			ident, err := globalIdent(entity.Header().Name())
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if _, ok := gen.old.globals[ident]; ok {
+				return globalRedefinitionError(ident, entity)
 			}
 			gen.old.globals[ident] = entity
 			gen.old.globalOrder = append(gen.old.globalOrder, ident)
 		case *ast.AttrGroupDef:
-			id := attrGroupID(entity.ID())
+			id, err := attrGroupID(entity.ID())
+			if err != nil {
+				return errors.WithStack(err)
+			}
 			if prev, ok := gen.old.attrGroupDefs[id]; ok {
-				return errors.Errorf("attribute group ID %q already present; prev `%s`, new `%s`", enc.AttrGroupID(id), text(prev), text(entity))
+				// Identical attribute group definitions are allowed to be
+				// repeated; keep the first as the canonical definition.
+				if equalAttrGroupDefs(prev, entity) {
+					continue
+				}
+				return errors.Errorf("attribute group ID %q already present with different attributes; prev `%s`, new `%s`", enc.AttrGroupID(id), text(prev), text(entity))
 			}
 			gen.old.attrGroupDefs[id] = entity
 		case *ast.NamedMetadataDef:
-			name := metadataName(entity.Name())
+			name, err := metadataName(entity.Name())
+			if err != nil {
+				return errors.WithStack(err)
+			}
 			if prev, ok := gen.old.namedMetadataDefs[name]; ok {
 				return errors.Errorf("metadata name %q already present; prev `%s`, new `%s`", enc.MetadataName(name), text(prev), text(entity))
 			}
 			gen.old.namedMetadataDefs[name] = entity
 			gen.old.namedMetadataDefOrder = append(gen.old.namedMetadataDefOrder, name)
 		case *ast.MetadataDef:
-			id := metadataID(entity.ID())
+			id, err := metadataID(entity.ID())
+			if err != nil {
+				return errors.WithStack(err)
+			}
 			if prev, ok := gen.old.metadataDefs[id]; ok {
 				return errors.Errorf("metadata ID %q already present; prev `%s`, new `%s`", enc.MetadataID(id), text(prev), text(entity))
 			}
@@ -195,7 +227,9 @@ func (gen *generator) translateTopLevelEntities() error {
 		return errors.WithStack(err)
 	}
 	// 4b2. Translate AST attribute group definitions to IR.
-	gen.translateAttrGroupDefs()
+	if err := gen.translateAttrGroupDefs(); err != nil {
+		return errors.WithStack(err)
+	}
 	// 4b3. Translate AST named metadata definitions to IR.
 	if err := gen.translateNamedMetadataDefs(); err != nil {
 		return errors.WithStack(err)
@@ -225,27 +259,34 @@ func (gen *generator) translateComdatDefs() {
 
 // translateAttrGroupDefs translates the AST attribute group definitions of the
 // given module to IR.
-func (gen *generator) translateAttrGroupDefs() {
+func (gen *generator) translateAttrGroupDefs() error {
 	// 4b2. Translate AST attribute group definitions to IR.
 	for id, old := range gen.old.attrGroupDefs {
 		new, ok := gen.new.attrGroupDefs[id]
 		if !ok {
 			panic(fmt.Errorf("unable to locate attribute group ID %q", enc.AttrGroupID(id)))
 		}
-		gen.irAttrGroupDef(new, old)
+		if err := gen.irAttrGroupDef(new, old); err != nil {
+			return errors.WithStack(err)
+		}
 	}
+	return nil
 }
 
 // irAttrGroupDef translates the AST attribute group definition to an equivalent
 // IR attribute group definition.
-func (gen *generator) irAttrGroupDef(new *ir.AttrGroupDef, old *ast.AttrGroupDef) {
+func (gen *generator) irAttrGroupDef(new *ir.AttrGroupDef, old *ast.AttrGroupDef) error {
 	if oldFuncAttrs := old.FuncAttrs(); len(oldFuncAttrs) > 0 {
 		new.FuncAttrs = make([]ir.FuncAttribute, len(oldFuncAttrs))
 		for i, oldFuncAttr := range oldFuncAttrs {
-			funcAttr := gen.irFuncAttribute(oldFuncAttr)
+			funcAttr, err := gen.irFuncAttribute(oldFuncAttr)
+			if err != nil {
+				return errors.WithStack(err)
+			}
 			new.FuncAttrs[i] = funcAttr
 		}
 	}
+	return nil
 }
 
 // --- [ Named metadata definitions ] ------------------------------------------
@@ -363,7 +404,10 @@ func (gen *generator) irUseListOrder(old *ast.UseListOrder) (*ir.UseListOrder, e
 		return nil, errors.WithStack(err)
 	}
 	// Indices.
-	indices := uintSlice(old.Indices())
+	indices, err := uintSlice(old.Indices())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 	useListOrder := &ir.UseListOrder{
 		Value:   c,
 		Indices: indices,
@@ -394,7 +438,10 @@ func (gen *generator) translateUseListOrderBBs() error {
 // to an equivalent IR basic block specific use-list order.
 func (gen *generator) irUseListOrderBB(old *ast.UseListOrderBB) (*ir.UseListOrderBB, error) {
 	// Function.
-	funcIdent := globalIdent(old.Func())
+	funcIdent, err := globalIdent(old.Func())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 	v, ok := gen.new.globals[funcIdent]
 	if !ok {
 		return nil, errors.Errorf("unable to locate global identifier %q", funcIdent.Ident())
@@ -404,13 +451,19 @@ func (gen *generator) irUseListOrderBB(old *ast.UseListOrderBB) (*ir.UseListOrde
 		return nil, errors.Errorf("invalid function type of %q; expected *ir.Function, got %T", funcIdent.Ident(), v)
 	}
 	// Basic block.
-	blockIdent := localIdent(old.Block())
+	blockIdent, err := localIdent(old.Block())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 	block, err := findBlock(f, blockIdent)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 	// Indices.
-	indices := uintSlice(old.Indices())
+	indices, err := uintSlice(old.Indices())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 	useListOrderBB := &ir.UseListOrderBB{
 		Func:    f,
 		Block:   block,
@@ -0,0 +1,162 @@
+package asm
+
+import (
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/llir/llvm/ir"
+	"github.com/pkg/errors"
+)
+
+// === [ Error recovery ] ======================================================
+
+// ParseFileWithErrors parses the given LLVM IR assembly file into an LLVM IR
+// module, recovering from errors. The errors encountered while parsing are
+// returned in order of source position, along with a partial module of the
+// top-level entities without errors.
+//
+// On error, parsing is resumed at the next top-level entity; i.e. at the next
+// line starting at column zero with a function definition or declaration
+// ("define" or "declare"), global identifier ('@'), metadata identifier ('!')
+// or attribute group definition ("attributes"). Top-level entities containing
+// errors, or referring to top-level entities containing errors, are omitted
+// from the partial module.
+func ParseFileWithErrors(path string) (*ir.Module, []*Error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, []*Error{{Filename: path, Msg: err.Error()}}
+	}
+	return ParseStringWithErrors(path, string(buf))
+}
+
+// ParseStringWithErrors parses the given LLVM IR assembly file into an LLVM IR
+// module, reading from content and recovering from errors. An optional path to
+// the source file may be specified for error reporting. See
+// ParseFileWithErrors for details on error recovery.
+func ParseStringWithErrors(path, content string) (*ir.Module, []*Error) {
+	entities := splitTopLevel(content)
+	var errs []*Error
+	// Skip top-level entities with syntax errors.
+	for _, entity := range entities {
+		if _, err := parseAST(path, entity.content); err != nil {
+			e := toError(path, entity.content, err)
+			if e.Line > 0 {
+				e.Line += entity.line - 1
+			}
+			errs = append(errs, e)
+			entity.skip = true
+		}
+	}
+	// Parse remaining top-level entities, skipping the top-level entity of each
+	// error until no errors remain.
+	var m *ir.Module
+	for {
+		content := joinTopLevel(entities)
+		var err error
+		m, err = ParseString(path, content)
+		if err == nil {
+			break
+		}
+		e := toError(path, content, err)
+		errs = append(errs, e)
+		entity := topLevelAt(entities, e.Line)
+		if entity == nil || entity.skip {
+			// Unable to recover from error.
+			m = nil
+			break
+		}
+		entity.skip = true
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Line < errs[j].Line
+	})
+	return m, errs
+}
+
+// ### [ Helper functions ] ####################################################
+
+// topLevel is the source of a top-level entity.
+type topLevel struct {
+	// Source of the top-level entity, including trailing lines up to the next
+	// top-level entity.
+	content string
+	// Line number of the first line of the top-level entity, starting at 1.
+	line int
+	// Number of lines of the top-level entity.
+	nlines int
+	// Skip the top-level entity, as it contains errors.
+	skip bool
+}
+
+// topLevelPrefixes specifies the prefixes of lines starting top-level entities
+// at which parsing is resumed on error.
+var topLevelPrefixes = []string{"define", "declare", "@", "!", "attributes"}
+
+// splitTopLevel splits the given LLVM IR assembly into top-level entities. Any
+// lines preceding the first top-level entity (e.g. source filename and type
+// definitions) are treated as a top-level entity of their own.
+func splitTopLevel(content string) []*topLevel {
+	var entities []*topLevel
+	cur := &topLevel{line: 1}
+	start := 0
+	lines := strings.SplitAfter(content, "\n")
+	for i, line := range lines {
+		if i > 0 && isTopLevelStart(line) {
+			cur.content = strings.Join(lines[start:i], "")
+			cur.nlines = i - start
+			entities = append(entities, cur)
+			cur = &topLevel{line: i + 1}
+			start = i
+		}
+	}
+	cur.content = strings.Join(lines[start:], "")
+	cur.nlines = len(lines) - start
+	return append(entities, cur)
+}
+
+// isTopLevelStart reports whether the given line starts a top-level entity.
+func isTopLevelStart(line string) bool {
+	for _, prefix := range topLevelPrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinTopLevel returns the LLVM IR assembly of the given top-level entities.
+// Skipped top-level entities are replaced by empty lines, to retain the source
+// positions of the remaining top-level entities.
+func joinTopLevel(entities []*topLevel) string {
+	buf := &strings.Builder{}
+	for _, entity := range entities {
+		if entity.skip {
+			buf.WriteString(strings.Repeat("\n", strings.Count(entity.content, "\n")))
+			continue
+		}
+		buf.WriteString(entity.content)
+	}
+	return buf.String()
+}
+
+// topLevelAt returns the top-level entity containing the given line; or nil if
+// not present.
+func topLevelAt(entities []*topLevel, line int) *topLevel {
+	for _, entity := range entities {
+		if entity.line <= line && line < entity.line+entity.nlines {
+			return entity
+		}
+	}
+	return nil
+}
+
+// toError returns the *Error of the given error encountered while parsing
+// content; or an error without source position if not located at a source
+// position.
+func toError(path, content string, err error) *Error {
+	if e, ok := errors.Cause(positionError(path, content, err)).(*Error); ok {
+		return e
+	}
+	return &Error{Filename: path, Msg: err.Error()}
+}
@@ -83,17 +83,37 @@ func (gen *generator) irDIBasicType(old *ast.DIBasicType) (*metadata.DIBasicType
 	for _, oldField := range old.Fields() {
 		switch oldField := oldField.(type) {
 		case *ast.TagField:
-			md.Tag = irDwarfTag(oldField.Tag())
+			tag, err := irDwarfTag(oldField.Tag())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Tag = tag
 		case *ast.NameField:
 			md.Name = stringLit(oldField.Name())
 		case *ast.SizeField:
-			md.Size = uintLit(oldField.Size())
+			size, err := uintLit(oldField.Size())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Size = size
 		case *ast.AlignField:
-			md.Align = uintLit(oldField.Align())
+			align, err := uintLit(oldField.Align())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Align = align
 		case *ast.EncodingField:
-			md.Encoding = irDwarfAttEncoding(oldField.Encoding())
+			encoding, err := irDwarfAttEncoding(oldField.Encoding())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Encoding = encoding
 		case *ast.FlagsField:
-			md.Flags = irDIFlags(oldField.Flags())
+			flags, err := irDIFlags(oldField.Flags())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Flags = flags
 		default:
 			panic(fmt.Errorf("support for DIBasicType field %T not yet implemented", old))
 		}
@@ -110,7 +130,11 @@ func (gen *generator) irDICompileUnit(old *ast.DICompileUnit) (*metadata.DICompi
 	for _, oldField := range old.Fields() {
 		switch oldField := oldField.(type) {
 		case *ast.LanguageField:
-			md.Language = irDwarfLang(oldField.Language())
+			language, err := irDwarfLang(oldField.Language())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Language = language
 		case *ast.FileField:
 			file, err := gen.irMDField(oldField.File())
 			if err != nil {
@@ -120,15 +144,27 @@ func (gen *generator) irDICompileUnit(old *ast.DICompileUnit) (*metadata.DICompi
 		case *ast.ProducerField:
 			md.Producer = stringLit(oldField.Producer())
 		case *ast.IsOptimizedField:
-			md.IsOptimized = boolLit(oldField.IsOptimized())
+			isOptimized, err := boolLit(oldField.IsOptimized())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.IsOptimized = isOptimized
 		case *ast.FlagsStringField:
 			md.Flags = stringLit(oldField.Flags())
 		case *ast.RuntimeVersionField:
-			md.RuntimeVersion = uintLit(oldField.RuntimeVersion())
+			runtimeVersion, err := uintLit(oldField.RuntimeVersion())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.RuntimeVersion = runtimeVersion
 		case *ast.SplitDebugFilenameField:
 			md.SplitDebugFilename = stringLit(oldField.SplitDebugFilename())
 		case *ast.EmissionKindField:
-			md.EmissionKind = irEmissionKind(oldField.EmissionKind())
+			emissionKind, err := irEmissionKind(oldField.EmissionKind())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.EmissionKind = emissionKind
 		case *ast.EnumsField:
 			enums, err := gen.irMDField(oldField.Enums())
 			if err != nil {
@@ -160,13 +196,29 @@ func (gen *generator) irDICompileUnit(old *ast.DICompileUnit) (*metadata.DICompi
 			}
 			md.Macros = macros
 		case *ast.DwoIdField:
-			md.DwoID = uintLit(oldField.DwoId())
+			dwoID, err := uintLit(oldField.DwoId())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.DwoID = dwoID
 		case *ast.SplitDebugInliningField:
-			md.SplitDebugInlining = boolLit(oldField.SplitDebugInlining())
+			splitDebugInlining, err := boolLit(oldField.SplitDebugInlining())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.SplitDebugInlining = splitDebugInlining
 		case *ast.DebugInfoForProfilingField:
-			md.DebugInfoForProfiling = boolLit(oldField.DebugInfoForProfiling())
+			debugInfoForProfiling, err := boolLit(oldField.DebugInfoForProfiling())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.DebugInfoForProfiling = debugInfoForProfiling
 		case *ast.NameTableKindField:
-			md.NameTableKind = irNameTableKind(oldField.NameTableKind())
+			nameTableKind, err := irNameTableKind(oldField.NameTableKind())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.NameTableKind = nameTableKind
 		default:
 			panic(fmt.Errorf("support for DICompileUnit field %T not yet implemented", old))
 		}
@@ -183,7 +235,11 @@ func (gen *generator) irDICompositeType(old *ast.DICompositeType) (*metadata.DIC
 	for _, oldField := range old.Fields() {
 		switch oldField := oldField.(type) {
 		case *ast.TagField:
-			md.Tag = irDwarfTag(oldField.Tag())
+			tag, err := irDwarfTag(oldField.Tag())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Tag = tag
 		case *ast.NameField:
 			md.Name = stringLit(oldField.Name())
 		case *ast.ScopeField:
@@ -199,7 +255,11 @@ func (gen *generator) irDICompositeType(old *ast.DICompositeType) (*metadata.DIC
 			}
 			md.File = file
 		case *ast.LineField:
-			md.Line = intLit(oldField.Line())
+			line, err := intLit(oldField.Line())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Line = line
 		case *ast.BaseTypeField:
 			baseType, err := gen.irMDField(oldField.BaseType())
 			if err != nil {
@@ -207,13 +267,29 @@ func (gen *generator) irDICompositeType(old *ast.DICompositeType) (*metadata.DIC
 			}
 			md.BaseType = baseType
 		case *ast.SizeField:
-			md.Size = uintLit(oldField.Size())
+			size, err := uintLit(oldField.Size())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Size = size
 		case *ast.AlignField:
-			md.Align = uintLit(oldField.Align())
+			align, err := uintLit(oldField.Align())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Align = align
 		case *ast.OffsetField:
-			md.Offset = uintLit(oldField.OffsetField())
+			offset, err := uintLit(oldField.OffsetField())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Offset = offset
 		case *ast.FlagsField:
-			md.Flags = irDIFlags(oldField.Flags())
+			flags, err := irDIFlags(oldField.Flags())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Flags = flags
 		case *ast.ElementsField:
 			elements, err := gen.irMDField(oldField.Elements())
 			if err != nil {
@@ -221,7 +297,11 @@ func (gen *generator) irDICompositeType(old *ast.DICompositeType) (*metadata.DIC
 			}
 			md.Elements = elements
 		case *ast.RuntimeLangField:
-			md.RuntimeLang = irDwarfLang(oldField.RuntimeLang())
+			runtimeLang, err := irDwarfLang(oldField.RuntimeLang())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.RuntimeLang = runtimeLang
 		case *ast.VtableHolderField:
 			vtableHolder, err := gen.irMDField(oldField.VtableHolder())
 			if err != nil {
@@ -258,7 +338,11 @@ func (gen *generator) irDIDerivedType(old *ast.DIDerivedType) (*metadata.DIDeriv
 	for _, oldField := range old.Fields() {
 		switch oldField := oldField.(type) {
 		case *ast.TagField:
-			md.Tag = irDwarfTag(oldField.Tag())
+			tag, err := irDwarfTag(oldField.Tag())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Tag = tag
 		case *ast.NameField:
 			md.Name = stringLit(oldField.Name())
 		case *ast.ScopeField:
@@ -274,7 +358,11 @@ func (gen *generator) irDIDerivedType(old *ast.DIDerivedType) (*metadata.DIDeriv
 			}
 			md.File = file
 		case *ast.LineField:
-			md.Line = intLit(oldField.Line())
+			line, err := intLit(oldField.Line())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Line = line
 		case *ast.BaseTypeField:
 			baseType, err := gen.irMDField(oldField.BaseType())
 			if err != nil {
@@ -282,14 +370,30 @@ func (gen *generator) irDIDerivedType(old *ast.DIDerivedType) (*metadata.DIDeriv
 			}
 			md.BaseType = baseType
 		case *ast.SizeField:
-			md.Size = uintLit(oldField.Size())
+			size, err := uintLit(oldField.Size())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Size = size
 		case *ast.AlignField:
-			md.Align = uintLit(oldField.Align())
+			align, err := uintLit(oldField.Align())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Align = align
 		case *ast.OffsetField:
 			// TODO: rename OffsetField method to Offset once https://github.com/inspirer/textmapper/issues/13 is resolved.
-			md.Offset = uintLit(oldField.OffsetField())
+			offset, err := uintLit(oldField.OffsetField())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Offset = offset
 		case *ast.FlagsField:
-			md.Flags = irDIFlags(oldField.Flags())
+			flags, err := irDIFlags(oldField.Flags())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Flags = flags
 		case *ast.ExtraDataField:
 			extraData, err := gen.irMDField(oldField.ExtraData())
 			if err != nil {
@@ -297,7 +401,11 @@ func (gen *generator) irDIDerivedType(old *ast.DIDerivedType) (*metadata.DIDeriv
 			}
 			md.ExtraData = extraData
 		case *ast.DwarfAddressSpaceField:
-			md.DwarfAddressSpace = uintLit(oldField.DwarfAddressSpace())
+			dwarfAddressSpace, err := uintLit(oldField.DwarfAddressSpace())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.DwarfAddressSpace = dwarfAddressSpace
 		default:
 			panic(fmt.Errorf("support for DIDerivedType field %T not yet implemented", old))
 		}
@@ -314,7 +422,11 @@ func (gen *generator) irDIEnumerator(old *ast.DIEnumerator) (*metadata.DIEnumera
 	isUnsigned := false
 	for _, oldField := range old.Fields() {
 		if oldField, ok := oldField.(*ast.IsUnsignedField); ok {
-			isUnsigned = boolLit(oldField.IsUnsigned())
+			unsigned, err := boolLit(oldField.IsUnsigned())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			isUnsigned = unsigned
 			break
 		}
 	}
@@ -331,10 +443,18 @@ func (gen *generator) irDIEnumerator(old *ast.DIEnumerator) (*metadata.DIEnumera
 				}
 				md.Value = int64(x)
 			} else {
-				md.Value = intLit(oldField.Value())
+				value, err := intLit(oldField.Value())
+				if err != nil {
+					return nil, errors.WithStack(err)
+				}
+				md.Value = value
 			}
 		case *ast.IsUnsignedField:
-			md.IsUnsigned = boolLit(oldField.IsUnsigned())
+			isUnsigned, err := boolLit(oldField.IsUnsigned())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.IsUnsigned = isUnsigned
 		default:
 			panic(fmt.Errorf("support for DIEnumerator field %T not yet implemented", old))
 		}
@@ -363,7 +483,11 @@ func (gen *generator) irDIExpression(old *ast.DIExpression) (*metadata.DIExpress
 func (gen *generator) irDIExpressionField(old ast.DIExpressionField) (metadata.DIExpressionField, error) {
 	switch old := old.(type) {
 	case *ast.UintLit:
-		return metadata.UintLit(uintLit(*old)), nil
+		x, err := uintLit(*old)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return metadata.UintLit(x), nil
 	case *ast.DwarfOp:
 		return asmenum.DwarfOpFromString(old.Text()), nil
 	default:
@@ -421,7 +545,11 @@ func (gen *generator) irDIGlobalVariable(old *ast.DIGlobalVariable) (*metadata.D
 			}
 			md.File = file
 		case *ast.LineField:
-			md.Line = intLit(oldField.Line())
+			line, err := intLit(oldField.Line())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Line = line
 		case *ast.TypeField:
 			typ, err := gen.irMDField(oldField.Typ())
 			if err != nil {
@@ -429,9 +557,17 @@ func (gen *generator) irDIGlobalVariable(old *ast.DIGlobalVariable) (*metadata.D
 			}
 			md.Type = typ
 		case *ast.IsLocalField:
-			md.IsLocal = boolLit(oldField.IsLocal())
+			isLocal, err := boolLit(oldField.IsLocal())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.IsLocal = isLocal
 		case *ast.IsDefinitionField:
-			md.IsDefinition = boolLit(oldField.IsDefinition())
+			isDefinition, err := boolLit(oldField.IsDefinition())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.IsDefinition = isDefinition
 		case *ast.TemplateParamsField:
 			templateParams, err := gen.irMDField(oldField.TemplateParams())
 			if err != nil {
@@ -445,7 +581,11 @@ func (gen *generator) irDIGlobalVariable(old *ast.DIGlobalVariable) (*metadata.D
 			}
 			md.Declaration = declaration
 		case *ast.AlignField:
-			md.Align = uintLit(oldField.Align())
+			align, err := uintLit(oldField.Align())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Align = align
 		default:
 			panic(fmt.Errorf("support for DIGlobalVariable field %T not yet implemented", old))
 		}
@@ -490,7 +630,11 @@ func (gen *generator) irDIImportedEntity(old *ast.DIImportedEntity) (*metadata.D
 	for _, oldField := range old.Fields() {
 		switch oldField := oldField.(type) {
 		case *ast.TagField:
-			md.Tag = irDwarfTag(oldField.Tag())
+			tag, err := irDwarfTag(oldField.Tag())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Tag = tag
 		case *ast.ScopeField:
 			scope, err := gen.irMDField(oldField.Scope())
 			if err != nil {
@@ -510,7 +654,11 @@ func (gen *generator) irDIImportedEntity(old *ast.DIImportedEntity) (*metadata.D
 			}
 			md.File = file
 		case *ast.LineField:
-			md.Line = intLit(oldField.Line())
+			line, err := intLit(oldField.Line())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Line = line
 		case *ast.NameField:
 			md.Name = stringLit(oldField.Name())
 		default:
@@ -543,7 +691,11 @@ func (gen *generator) irDILabel(old *ast.DILabel) (*metadata.DILabel, error) {
 			}
 			md.File = file
 		case *ast.LineField:
-			md.Line = intLit(oldField.Line())
+			line, err := intLit(oldField.Line())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Line = line
 		default:
 			panic(fmt.Errorf("support for DILabel field %T not yet implemented", old))
 		}
@@ -572,9 +724,17 @@ func (gen *generator) irDILexicalBlock(old *ast.DILexicalBlock) (*metadata.DILex
 			}
 			md.File = file
 		case *ast.LineField:
-			md.Line = intLit(oldField.Line())
+			line, err := intLit(oldField.Line())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Line = line
 		case *ast.ColumnField:
-			md.Column = intLit(oldField.Column())
+			column, err := intLit(oldField.Column())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Column = column
 		default:
 			panic(fmt.Errorf("support for DILexicalBlock field %T not yet implemented", old))
 		}
@@ -604,7 +764,11 @@ func (gen *generator) irDILexicalBlockFile(old *ast.DILexicalBlockFile) (*metada
 			}
 			md.File = file
 		case *ast.DiscriminatorIntField:
-			md.Discriminator = uintLit(oldField.Discriminator())
+			discriminator, err := uintLit(oldField.Discriminator())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Discriminator = discriminator
 		default:
 			panic(fmt.Errorf("support for DILexicalBlockFile field %T not yet implemented", old))
 		}
@@ -623,7 +787,11 @@ func (gen *generator) irDILocalVariable(old *ast.DILocalVariable) (*metadata.DIL
 		case *ast.NameField:
 			md.Name = stringLit(oldField.Name())
 		case *ast.ArgField:
-			md.Arg = uintLit(oldField.Arg())
+			arg, err := uintLit(oldField.Arg())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Arg = arg
 		case *ast.ScopeField:
 			scope, err := gen.irMDField(oldField.Scope())
 			if err != nil {
@@ -637,7 +805,11 @@ func (gen *generator) irDILocalVariable(old *ast.DILocalVariable) (*metadata.DIL
 			}
 			md.File = file
 		case *ast.LineField:
-			md.Line = intLit(oldField.Line())
+			line, err := intLit(oldField.Line())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Line = line
 		case *ast.TypeField:
 			typ, err := gen.irMDField(oldField.Typ())
 			if err != nil {
@@ -645,9 +817,17 @@ func (gen *generator) irDILocalVariable(old *ast.DILocalVariable) (*metadata.DIL
 			}
 			md.Type = typ
 		case *ast.FlagsField:
-			md.Flags = irDIFlags(oldField.Flags())
+			flags, err := irDIFlags(oldField.Flags())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Flags = flags
 		case *ast.AlignField:
-			md.Align = uintLit(oldField.Align())
+			align, err := uintLit(oldField.Align())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Align = align
 		default:
 			panic(fmt.Errorf("support for DILocalVariable field %T not yet implemented", old))
 		}
@@ -664,9 +844,17 @@ func (gen *generator) irDILocation(old *ast.DILocation) (*metadata.DILocation, e
 	for _, oldField := range old.Fields() {
 		switch oldField := oldField.(type) {
 		case *ast.LineField:
-			md.Line = intLit(oldField.Line())
+			line, err := intLit(oldField.Line())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Line = line
 		case *ast.ColumnField:
-			md.Column = intLit(oldField.Column())
+			column, err := intLit(oldField.Column())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Column = column
 		case *ast.ScopeField:
 			scope, err := gen.irMDField(oldField.Scope())
 			if err != nil {
@@ -680,7 +868,11 @@ func (gen *generator) irDILocation(old *ast.DILocation) (*metadata.DILocation, e
 			}
 			md.InlinedAt = inlinedAt
 		case *ast.IsImplicitCodeField:
-			md.IsImplicitCode = boolLit(oldField.IsImplicitCode())
+			isImplicitCode, err := boolLit(oldField.IsImplicitCode())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.IsImplicitCode = isImplicitCode
 		default:
 			panic(fmt.Errorf("support for DILocation field %T not yet implemented", old))
 		}
@@ -697,9 +889,17 @@ func (gen *generator) irDIMacro(old *ast.DIMacro) (*metadata.DIMacro, error) {
 	for _, oldField := range old.Fields() {
 		switch oldField := oldField.(type) {
 		case *ast.TypeMacinfoField:
-			md.Type = irDwarfMacinfo(oldField.Typ())
+			typ, err := irDwarfMacinfo(oldField.Typ())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Type = typ
 		case *ast.LineField:
-			md.Line = intLit(oldField.Line())
+			line, err := intLit(oldField.Line())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Line = line
 		case *ast.NameField:
 			md.Name = stringLit(oldField.Name())
 		case *ast.ValueStringField:
@@ -720,9 +920,17 @@ func (gen *generator) irDIMacroFile(old *ast.DIMacroFile) (*metadata.DIMacroFile
 	for _, oldField := range old.Fields() {
 		switch oldField := oldField.(type) {
 		case *ast.TypeMacinfoField:
-			md.Type = irDwarfMacinfo(oldField.Typ())
+			typ, err := irDwarfMacinfo(oldField.Typ())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Type = typ
 		case *ast.LineField:
-			md.Line = intLit(oldField.Line())
+			line, err := intLit(oldField.Line())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Line = line
 		case *ast.FileField:
 			file, err := gen.irMDField(oldField.File())
 			if err != nil {
@@ -788,7 +996,11 @@ func (gen *generator) irDINamespace(old *ast.DINamespace) (*metadata.DINamespace
 		case *ast.NameField:
 			md.Name = stringLit(oldField.Name())
 		case *ast.ExportSymbolsField:
-			md.ExportSymbols = boolLit(oldField.ExportSymbols())
+			exportSymbols, err := boolLit(oldField.ExportSymbols())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.ExportSymbols = exportSymbols
 		default:
 			panic(fmt.Errorf("support for DINamespace field %T not yet implemented", old))
 		}
@@ -813,13 +1025,21 @@ func (gen *generator) irDIObjCProperty(old *ast.DIObjCProperty) (*metadata.DIObj
 			}
 			md.File = file
 		case *ast.LineField:
-			md.Line = intLit(oldField.Line())
+			line, err := intLit(oldField.Line())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Line = line
 		case *ast.SetterField:
 			md.Setter = stringLit(oldField.Setter())
 		case *ast.GetterField:
 			md.Getter = stringLit(oldField.Getter())
 		case *ast.AttributesField:
-			md.Attributes = uintLit(oldField.Attributes())
+			attributes, err := uintLit(oldField.Attributes())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Attributes = attributes
 		case *ast.TypeField:
 			typ, err := gen.irMDField(oldField.Typ())
 			if err != nil {
@@ -858,7 +1078,11 @@ func (gen *generator) irDISubprogram(old *ast.DISubprogram) (*metadata.DISubprog
 			}
 			md.File = file
 		case *ast.LineField:
-			md.Line = intLit(oldField.Line())
+			line, err := intLit(oldField.Line())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Line = line
 		case *ast.TypeField:
 			typ, err := gen.irMDField(oldField.Typ())
 			if err != nil {
@@ -866,11 +1090,23 @@ func (gen *generator) irDISubprogram(old *ast.DISubprogram) (*metadata.DISubprog
 			}
 			md.Type = typ
 		case *ast.IsLocalField:
-			md.IsLocal = boolLit(oldField.IsLocal())
+			isLocal, err := boolLit(oldField.IsLocal())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.IsLocal = isLocal
 		case *ast.IsDefinitionField:
-			md.IsDefinition = boolLit(oldField.IsDefinition())
+			isDefinition, err := boolLit(oldField.IsDefinition())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.IsDefinition = isDefinition
 		case *ast.ScopeLineField:
-			md.ScopeLine = intLit(oldField.ScopeLine())
+			scopeLine, err := intLit(oldField.ScopeLine())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.ScopeLine = scopeLine
 		case *ast.ContainingTypeField:
 			containingType, err := gen.irMDField(oldField.ContainingType())
 			if err != nil {
@@ -878,15 +1114,35 @@ func (gen *generator) irDISubprogram(old *ast.DISubprogram) (*metadata.DISubprog
 			}
 			md.ContainingType = containingType
 		case *ast.VirtualityField:
-			md.Virtuality = irDwarfVirtuality(oldField.Virtuality())
+			virtuality, err := irDwarfVirtuality(oldField.Virtuality())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Virtuality = virtuality
 		case *ast.VirtualIndexField:
-			md.VirtualIndex = uintLit(oldField.VirtualIndex())
+			virtualIndex, err := uintLit(oldField.VirtualIndex())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.VirtualIndex = virtualIndex
 		case *ast.ThisAdjustmentField:
-			md.ThisAdjustment = intLit(oldField.ThisAdjustment())
+			thisAdjustment, err := intLit(oldField.ThisAdjustment())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.ThisAdjustment = thisAdjustment
 		case *ast.FlagsField:
-			md.Flags = irDIFlags(oldField.Flags())
+			flags, err := irDIFlags(oldField.Flags())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Flags = flags
 		case *ast.IsOptimizedField:
-			md.IsOptimized = boolLit(oldField.IsOptimized())
+			isOptimized, err := boolLit(oldField.IsOptimized())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.IsOptimized = isOptimized
 		case *ast.UnitField:
 			unit, err := gen.irMDField(oldField.Unit())
 			if err != nil {
@@ -939,7 +1195,11 @@ func (gen *generator) irDISubrange(old *ast.DISubrange) (*metadata.DISubrange, e
 			}
 			md.Count = count
 		case *ast.LowerBoundField:
-			md.LowerBound = intLit(oldField.LowerBound())
+			lowerBound, err := intLit(oldField.LowerBound())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.LowerBound = lowerBound
 		default:
 			panic(fmt.Errorf("support for DISubrange field %T not yet implemented", old))
 		}
@@ -956,9 +1216,17 @@ func (gen *generator) irDISubroutineType(old *ast.DISubroutineType) (*metadata.D
 	for _, oldField := range old.Fields() {
 		switch oldField := oldField.(type) {
 		case *ast.FlagsField:
-			md.Flags = irDIFlags(oldField.Flags())
+			flags, err := irDIFlags(oldField.Flags())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Flags = flags
 		case *ast.CCField:
-			md.CC = irDwarfCC(oldField.CC())
+			cc, err := irDwarfCC(oldField.CC())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.CC = cc
 		case *ast.TypesField:
 			ts, err := gen.irMDField(oldField.Types())
 			if err != nil {
@@ -1006,7 +1274,11 @@ func (gen *generator) irDITemplateValueParameter(old *ast.DITemplateValueParamet
 	for _, oldField := range old.Fields() {
 		switch oldField := oldField.(type) {
 		case *ast.TagField:
-			md.Tag = irDwarfTag(oldField.Tag())
+			tag, err := irDwarfTag(oldField.Tag())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Tag = tag
 		case *ast.NameField:
 			md.Name = stringLit(oldField.Name())
 		case *ast.TypeField:
@@ -1037,7 +1309,11 @@ func (gen *generator) irGenericDINode(old *ast.GenericDINode) (*metadata.Generic
 	for _, oldField := range old.Fields() {
 		switch oldField := oldField.(type) {
 		case *ast.TagField:
-			md.Tag = irDwarfTag(oldField.Tag())
+			tag, err := irDwarfTag(oldField.Tag())
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			md.Tag = tag
 		case *ast.HeaderField:
 			md.Header = stringLit(oldField.Header())
 		case *ast.OperandsField:
@@ -1064,7 +1340,11 @@ func (gen *generator) irMDFieldOrInt(old ast.MDFieldOrInt) (metadata.FieldOrInt,
 	case ast.MDField:
 		return gen.irMDField(old)
 	case *ast.IntLit:
-		return metadata.IntLit(intLit(*old)), nil
+		x, err := intLit(*old)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return metadata.IntLit(x), nil
 	default:
 		panic(fmt.Errorf("support for metadata field %T not yet implemented", old))
 	}
@@ -1072,127 +1352,166 @@ func (gen *generator) irMDFieldOrInt(old ast.MDFieldOrInt) (metadata.FieldOrInt,
 
 // irDIFlags returns the IR debug info flags corresponding to the given AST
 // debug info flags.
-func irDIFlags(old ast.DIFlags) enum.DIFlag {
+func irDIFlags(old ast.DIFlags) (enum.DIFlag, error) {
 	var flags enum.DIFlag
 	for _, oldFlag := range old.Flags() {
-		flag := irDIFlag(oldFlag)
+		flag, err := irDIFlag(oldFlag)
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
 		flags |= flag
 	}
-	return flags
+	return flags, nil
 }
 
 // irDIFlag returns the IR debug info flag corresponding to the given AST debug
 // info flag.
-func irDIFlag(old ast.DIFlag) enum.DIFlag {
+func irDIFlag(old ast.DIFlag) (enum.DIFlag, error) {
 	switch old := old.(type) {
 	case *ast.DIFlagEnum:
-		return asmenum.DIFlagFromString(old.Text())
+		return asmenum.DIFlagFromString(old.Text()), nil
 	case *ast.DIFlagInt:
-		return enum.DIFlag(uintLit(old.UintLit()))
+		x, err := uintLit(old.UintLit())
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		return enum.DIFlag(x), nil
 	default:
-		panic(fmt.Errorf("support for debug info flag %T not yet implemented", old))
+		return 0, errors.Errorf("support for debug info flag %T not yet implemented", old)
 	}
 }
 
 // irDwarfAttEncoding returns the IR Dwarf attribute encoding corresponding to
 // the given AST Dwarf attribute encoding.
-func irDwarfAttEncoding(old ast.DwarfAttEncoding) enum.DwarfAttEncoding {
+func irDwarfAttEncoding(old ast.DwarfAttEncoding) (enum.DwarfAttEncoding, error) {
 	switch old := old.(type) {
 	case *ast.DwarfAttEncodingEnum:
-		return asmenum.DwarfAttEncodingFromString(old.Text())
+		return asmenum.DwarfAttEncodingFromString(old.Text()), nil
 	case *ast.DwarfAttEncodingInt:
-		return enum.DwarfAttEncoding(uintLit(old.UintLit()))
+		x, err := uintLit(old.UintLit())
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		return enum.DwarfAttEncoding(x), nil
 	default:
-		panic(fmt.Errorf("support for Dwarf attribute encoding %T not yet implemented", old))
+		return 0, errors.Errorf("support for Dwarf attribute encoding %T not yet implemented", old)
 	}
 }
 
 // irDwarfCC returns the IR Dwarf calling convention corresponding to the given
 // AST Dwarf calling convention.
-func irDwarfCC(old ast.DwarfCC) enum.DwarfCC {
+func irDwarfCC(old ast.DwarfCC) (enum.DwarfCC, error) {
 	switch old := old.(type) {
 	case *ast.DwarfCCEnum:
-		return asmenum.DwarfCCFromString(old.Text())
+		return asmenum.DwarfCCFromString(old.Text()), nil
 	case *ast.DwarfCCInt:
-		return enum.DwarfCC(uintLit(old.UintLit()))
+		x, err := uintLit(old.UintLit())
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		return enum.DwarfCC(x), nil
 	default:
-		panic(fmt.Errorf("support for Dwarf calling convention %T not yet implemented", old))
+		return 0, errors.Errorf("support for Dwarf calling convention %T not yet implemented", old)
 	}
 }
 
 // irDwarfLang returns the IR Dwarf language corresponding to the given AST
 // Dwarf language.
-func irDwarfLang(old ast.DwarfLang) enum.DwarfLang {
+func irDwarfLang(old ast.DwarfLang) (enum.DwarfLang, error) {
 	switch old := old.(type) {
 	case *ast.DwarfLangEnum:
-		return asmenum.DwarfLangFromString(old.Text())
+		return asmenum.DwarfLangFromString(old.Text()), nil
 	case *ast.DwarfLangInt:
-		return enum.DwarfLang(uintLit(old.UintLit()))
+		x, err := uintLit(old.UintLit())
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		return enum.DwarfLang(x), nil
 	default:
-		panic(fmt.Errorf("support for Dwarf language %T not yet implemented", old))
+		return 0, errors.Errorf("support for Dwarf language %T not yet implemented", old)
 	}
 }
 
 // irDwarfMacinfo returns the IR Dwarf Macinfo corresponding to the given AST
 // Dwarf Macinfo.
-func irDwarfMacinfo(old ast.DwarfMacinfo) enum.DwarfMacinfo {
+func irDwarfMacinfo(old ast.DwarfMacinfo) (enum.DwarfMacinfo, error) {
 	switch old := old.(type) {
 	case *ast.DwarfMacinfoEnum:
-		return asmenum.DwarfMacinfoFromString(old.Text())
+		return asmenum.DwarfMacinfoFromString(old.Text()), nil
 	case *ast.DwarfMacinfoInt:
-		return enum.DwarfMacinfo(uintLit(old.UintLit()))
+		x, err := uintLit(old.UintLit())
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		return enum.DwarfMacinfo(x), nil
 	default:
-		panic(fmt.Errorf("support for Dwarf Macinfo %T not yet implemented", old))
+		return 0, errors.Errorf("support for Dwarf Macinfo %T not yet implemented", old)
 	}
 }
 
 // irDwarfTag returns the IR Dwarf tag corresponding to the given AST Dwarf tag.
-func irDwarfTag(old ast.DwarfTag) enum.DwarfTag {
+func irDwarfTag(old ast.DwarfTag) (enum.DwarfTag, error) {
 	switch old := old.(type) {
 	case *ast.DwarfTagEnum:
-		return asmenum.DwarfTagFromString(old.Text())
+		return asmenum.DwarfTagFromString(old.Text()), nil
 	case *ast.DwarfTagInt:
-		return enum.DwarfTag(uintLit(old.UintLit()))
+		x, err := uintLit(old.UintLit())
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		return enum.DwarfTag(x), nil
 	default:
-		panic(fmt.Errorf("support for Dwarf tag %T not yet implemented", old))
+		return 0, errors.Errorf("support for Dwarf tag %T not yet implemented", old)
 	}
 }
 
 // irDwarfVirtuality returns the IR Dwarf virtuality corresponding to the given
 // AST Dwarf virtuality.
-func irDwarfVirtuality(old ast.DwarfVirtuality) enum.DwarfVirtuality {
+func irDwarfVirtuality(old ast.DwarfVirtuality) (enum.DwarfVirtuality, error) {
 	switch old := old.(type) {
 	case *ast.DwarfVirtualityEnum:
-		return asmenum.DwarfVirtualityFromString(old.Text())
+		return asmenum.DwarfVirtualityFromString(old.Text()), nil
 	case *ast.DwarfVirtualityInt:
-		return enum.DwarfVirtuality(uintLit(old.UintLit()))
+		x, err := uintLit(old.UintLit())
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		return enum.DwarfVirtuality(x), nil
 	default:
-		panic(fmt.Errorf("support for Dwarf virtuality %T not yet implemented", old))
+		return 0, errors.Errorf("support for Dwarf virtuality %T not yet implemented", old)
 	}
 }
 
 // irEmissionKind returns the IR emission kind corresponding to the given AST
 // emission kind.
-func irEmissionKind(old ast.EmissionKind) enum.EmissionKind {
+func irEmissionKind(old ast.EmissionKind) (enum.EmissionKind, error) {
 	switch old := old.(type) {
 	case *ast.EmissionKindEnum:
-		return asmenum.EmissionKindFromString(old.Text())
+		return asmenum.EmissionKindFromString(old.Text()), nil
 	case *ast.EmissionKindInt:
-		return enum.EmissionKind(uintLit(old.UintLit()))
+		x, err := uintLit(old.UintLit())
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		return enum.EmissionKind(x), nil
 	default:
-		panic(fmt.Errorf("support for emission kind %T not yet implemented", old))
+		return 0, errors.Errorf("support for emission kind %T not yet implemented", old)
 	}
 }
 
 // irNameTableKind returns the IR name table kind corresponding to the given AST
 // name table kind.
-func irNameTableKind(old ast.NameTableKind) enum.NameTableKind {
+func irNameTableKind(old ast.NameTableKind) (enum.NameTableKind, error) {
 	switch old := old.(type) {
 	case *ast.NameTableKindEnum:
-		return asmenum.NameTableKindFromString(old.Text())
+		return asmenum.NameTableKindFromString(old.Text()), nil
 	case *ast.NameTableKindInt:
-		return enum.NameTableKind(uintLit(old.UintLit()))
+		x, err := uintLit(old.UintLit())
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		return enum.NameTableKind(x), nil
 	default:
-		panic(fmt.Errorf("support for name table kind %T not yet implemented", old))
+		return 0, errors.Errorf("support for name table kind %T not yet implemented", old)
 	}
 }
@@ -18,7 +18,10 @@ func (fgen *funcGen) newTerm(old ast.Terminator) (ir.Terminator, error) {
 	switch old := old.(type) {
 	// Value terminators.
 	case *ast.LocalDefTerm:
-		ident := localIdent(old.Name())
+		ident, err := localIdent(old.Name())
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
 		return fgen.newValueTerm(ident, old.Term())
 	case ast.ValueTerminator:
 		unnamed := ir.LocalIdent{}
@@ -143,6 +146,9 @@ func (fgen *funcGen) irRetTerm(new ir.Terminator, old *ast.RetTerm) error {
 	if err != nil {
 		return errors.WithStack(err)
 	}
+	if retType := fgen.f.Sig.RetType; !typ.Equal(retType) {
+		return errorf(old, "return type mismatch of function %q; expected %q, got %q", fgen.f.Ident(), retType, typ)
+	}
 	// Check if non-void return.
 	if n, ok := old.X(); ok {
 		// Return value.
@@ -358,25 +364,39 @@ func (fgen *funcGen) irInvokeTerm(new ir.Terminator, old *ast.InvokeTerm) error
 	term.Exception = exception
 	// (optional) Calling convention.
 	if n, ok := old.CallingConv(); ok {
-		term.CallingConv = irCallingConv(n)
+		callingConv, err := irCallingConv(n)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		term.CallingConv = callingConv
 	}
 	// (optional) Return attributes.
 	if oldReturnAttrs := old.ReturnAttrs(); len(oldReturnAttrs) > 0 {
 		term.ReturnAttrs = make([]ir.ReturnAttribute, len(oldReturnAttrs))
 		for i, oldRetAttr := range oldReturnAttrs {
-			retAttr := irReturnAttribute(oldRetAttr)
+			retAttr, err := irReturnAttribute(oldRetAttr)
+			if err != nil {
+				return errors.WithStack(err)
+			}
 			term.ReturnAttrs[i] = retAttr
 		}
 	}
 	// (optional) Address space.
 	if n, ok := old.AddrSpace(); ok {
-		term.AddrSpace = irAddrSpace(n)
+		addrSpace, err := irAddrSpace(n)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		term.AddrSpace = addrSpace
 	}
 	// (optional) Function attributes.
 	if oldFuncAttrs := old.FuncAttrs(); len(oldFuncAttrs) > 0 {
 		term.FuncAttrs = make([]ir.FuncAttribute, len(oldFuncAttrs))
 		for i, oldFuncAttr := range oldFuncAttrs {
-			funcAttr := fgen.gen.irFuncAttribute(oldFuncAttr)
+			funcAttr, err := fgen.gen.irFuncAttribute(oldFuncAttr)
+			if err != nil {
+				return errors.WithStack(err)
+			}
 			term.FuncAttrs[i] = funcAttr
 		}
 	}
@@ -481,7 +501,7 @@ func (fgen *funcGen) irCatchRetTerm(new ir.Terminator, old *ast.CatchRetTerm) er
 	}
 	catchpad, ok := v.(*ir.InstCatchPad)
 	if !ok {
-		return errors.Errorf("invalid catchpad type; expected *ir.InstCatchPad, got %T", v)
+		return errorf(old, "invalid catchpad type; expected *ir.InstCatchPad, got %T", v)
 	}
 	term.From = catchpad
 	// Target basic block to transfer control flow to.
@@ -515,7 +535,7 @@ func (fgen *funcGen) irCleanupRetTerm(new ir.Terminator, old *ast.CleanupRetTerm
 	}
 	cleanuppad, ok := v.(*ir.InstCleanupPad)
 	if !ok {
-		return errors.Errorf("invalid cleanuppad type; expected *ir.InstCleanupPad, got %T", v)
+		return errorf(old, "invalid cleanuppad type; expected *ir.InstCleanupPad, got %T", v)
 	}
 	term.From = cleanuppad
 	// Unwind target.
@@ -0,0 +1,13 @@
+@addr = global i8* blockaddress(@f, %bar)
+
+define void @f() {
+entry:
+	%addr = load i8*, i8** @addr
+	indirectbr i8* %addr, [label %foo, label %bar]
+
+foo:
+	ret void
+
+bar:
+	ret void
+}
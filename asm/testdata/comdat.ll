@@ -0,0 +1,21 @@
+$bar = comdat largest
+$corge = comdat exactmatch
+$foo = comdat any
+$quux = comdat samesize
+$qux = comdat noduplicates
+
+@foo = linkonce_odr global i32 0, comdat
+@baz = weak global i32 1, comdat($bar)
+@qux = global i32 2, comdat
+@quux = linkonce_odr global i64 3, comdat
+@corge = linkonce_odr global i32 4, comdat
+
+define linkonce_odr void @f() comdat($bar) {
+entry:
+	ret void
+}
+
+define linkonce_odr void @g() comdat($quux) {
+entry:
+	ret void
+}
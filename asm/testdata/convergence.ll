@@ -0,0 +1,26 @@
+define void @f(i32 %n) {
+entry:
+	%entry.token = call token @llvm.experimental.convergence.entry()
+	br label %loop
+
+loop:
+	%i = phi i32 [ 0, %entry ], [ %next, %loop ]
+	%loop.token = call token @llvm.experimental.convergence.loop() [ "convergencectrl"(token %entry.token) ]
+	call void @g(i32 %i) [ "convergencectrl"(token %loop.token) ]
+	%next = add i32 %i, 1
+	%cond = icmp slt i32 %next, %n
+	br i1 %cond, label %loop, label %exit
+
+exit:
+	%anchor.token = call token @llvm.experimental.convergence.anchor()
+	call void @g(i32 %n) [ "convergencectrl"(token %anchor.token) ]
+	ret void
+}
+
+declare void @g(i32 %x)
+
+declare token @llvm.experimental.convergence.entry()
+
+declare token @llvm.experimental.convergence.anchor()
+
+declare token @llvm.experimental.convergence.loop()
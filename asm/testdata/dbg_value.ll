@@ -0,0 +1,10 @@
+define void @f(i32 %x) {
+entry:
+	call void @llvm.dbg.value(metadata i32 %x, metadata !0, metadata !DIExpression())
+	call void @llvm.dbg.value(metadata i32 42, metadata !0, metadata !DIExpression(DW_OP_plus_uconst, 1))
+	ret void
+}
+
+declare void @llvm.dbg.value(metadata, metadata, metadata)
+
+!0 = !{!"x"}
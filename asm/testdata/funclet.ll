@@ -0,0 +1,24 @@
+declare i32 @__CxxFrameHandler3(...)
+
+declare void @g()
+
+define void @f() personality i8* bitcast (i32 (...)* @__CxxFrameHandler3 to i8*) {
+entry:
+	invoke void @g()
+		to label %exit unwind label %dispatch
+
+dispatch:
+	%cs = catchswitch within none [label %handler] unwind to caller
+
+handler:
+	%cp = catchpad within %cs [i8* null, i32 64, i8* null]
+	invoke void @g() [ "funclet"(token %cp) ]
+		to label %exit unwind label %cleanup
+
+cleanup:
+	%cl = cleanuppad within %cp []
+	cleanupret from %cl unwind to caller
+
+exit:
+	ret void
+}
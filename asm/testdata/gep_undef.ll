@@ -0,0 +1,7 @@
+@p = global i8* getelementptr (i8, i8* undef, i64 1)
+
+define i8* @f() {
+entry:
+	%x = getelementptr i8, i8* undef, i64 1
+	ret i8* %x
+}
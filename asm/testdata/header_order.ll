@@ -0,0 +1,8 @@
+target triple = "x86_64-unknown-linux-gnu"
+target datalayout = "e-m:e-i64:64-f80:128-n8:16:32:64-S128"
+source_filename = "foo.c"
+
+define void @f() {
+entry:
+	ret void
+}
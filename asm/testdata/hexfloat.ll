@@ -1,2 +1,8 @@
 @a = global half 0xH4400
 @b = global half 0xH2E66
+@c = global x86_fp80 0xK4000C90FDAA22168C235
+@d = global x86_fp80 0xK3FFF8000000000000000
+@e = global fp128 0xL00000000000000013FFF000000000000
+@f = global fp128 0xL00000000000000003FFF000000000000
+@g = global ppc_fp128 0xM3FF00000000000003C90000000000000
+@h = global ppc_fp128 0xM3FF00000000000000000000000000000
@@ -11,3 +11,12 @@ define void @f() {
 	%4 = getelementptr [4 x i8], [4 x i8]* @s, i64 0, i64 0
 	ret void
 }
+
+define void @g() {
+; <label>:0
+	%1 = alloca i32, addrspace(5)
+	%2 = alloca inalloca i32, align 4
+	store i32 42, i32 addrspace(5)* %1
+	store i32 42, i32* %2
+	ret void
+}
@@ -0,0 +1,33 @@
+define i32 @f(i32* %p, i32 %n) {
+entry:
+	%x = load i32, i32* %p, !tbaa !0, !range !4
+	%cond = icmp slt i32 %x, %n
+	br i1 %cond, label %loop, label %exit, !prof !5
+
+loop:
+	%i = phi i32 [ 0, %entry ], [ %next, %loop ]
+	%next = add i32 %i, 1
+	store i32 %next, i32* %p, !tbaa !0
+	%done = icmp sge i32 %next, %n
+	br i1 %done, label %exit, label %loop, !prof !6, !llvm.loop !7
+
+exit:
+	%y = phi i32 [ %x, %entry ], [ %next, %loop ]
+	switch i32 %y, label %ret [
+		i32 0, label %ret
+	], !prof !9
+
+ret:
+	ret i32 %y
+}
+
+!0 = !{!1, !1, i64 0}
+!1 = !{!"int", !2, i64 0}
+!2 = !{!"omnipotent char", !3, i64 0}
+!3 = !{!"Simple C/C++ TBAA"}
+!4 = !{i32 0, i32 100}
+!5 = !{!"branch_weights", i32 1, i32 2000}
+!6 = !{!"branch_weights", i32 1, i32 99}
+!7 = distinct !{!7, !8}
+!8 = !{!"llvm.loop.mustprogress"}
+!9 = !{!"branch_weights", i32 1, i32 1}
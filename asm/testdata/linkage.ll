@@ -0,0 +1,23 @@
+@appending = appending global [1 x i32] [i32 0]
+@available_externally = available_externally global i32 0
+@common = common global i32 0
+@internal = internal global i32 0
+@linkonce = linkonce global i32 0
+@linkonce_odr = linkonce_odr global i32 0
+@private = private global i32 0
+@weak = weak global i32 0
+@weak_odr = weak_odr global i32 0
+@external = external global i32
+@extern_weak = extern_weak global i32
+
+@appending_alias = appending alias [1 x i32], [1 x i32]* @appending
+@available_externally_alias = available_externally alias i32, i32* @available_externally
+@common_alias = common alias i32, i32* @common
+@internal_alias = internal alias i32, i32* @internal
+@linkonce_alias = linkonce alias i32, i32* @linkonce
+@linkonce_odr_alias = linkonce_odr alias i32, i32* @linkonce_odr
+@private_alias = private alias i32, i32* @private
+@weak_alias = weak alias i32, i32* @weak
+@weak_odr_alias = weak_odr alias i32, i32* @weak_odr
+@external_alias = external alias i32, i32* @external
+@extern_weak_alias = extern_weak alias i32, i32* @extern_weak
@@ -0,0 +1,23 @@
+define void @f(i32 %n) {
+entry:
+	br label %loop
+
+loop:
+	%i = phi i32 [ 0, %entry ], [ %next, %loop ]
+	%next = add i32 %i, 1
+	%cond = icmp slt i32 %next, %n
+	br i1 %cond, label %loop, label %exit, !llvm.loop !0
+
+exit:
+	ret void
+}
+
+!0 = distinct !{!0, !1, !2, !3, !4, !5, !6}
+!1 = !{!"llvm.loop.distribute.enable", i1 true}
+!2 = !{!"llvm.loop.interleave.count", i32 4}
+!3 = !{!"llvm.loop.isvectorized", i32 1}
+!4 = !{!"llvm.loop.licm_versioning.disable"}
+!5 = !{!"llvm.loop.vectorize.followup_all", !7}
+!6 = !{!"llvm.loop.unroll.runtime.disable"}
+!7 = distinct !{!7, !8}
+!8 = !{!"llvm.loop.mustprogress"}
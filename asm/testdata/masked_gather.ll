@@ -0,0 +1,11 @@
+define <4 x i32> @f(<4 x i32*> %ptrs, <4 x i64> %idx, <4 x i1> %mask) {
+entry:
+	%p = getelementptr i32, <4 x i32*> %ptrs, <4 x i64> %idx
+	%x = call <4 x i32> @llvm.masked.gather.v4i32.v4p0i32(<4 x i32*> %p, i32 4, <4 x i1> %mask, <4 x i32> undef)
+	call void @llvm.masked.scatter.v4i32.v4p0i32(<4 x i32> %x, <4 x i32*> %ptrs, i32 4, <4 x i1> %mask)
+	ret <4 x i32> %x
+}
+
+declare <4 x i32> @llvm.masked.gather.v4i32.v4p0i32(<4 x i32*> %ptrs, i32 %align, <4 x i1> %mask, <4 x i32> %passthru)
+
+declare void @llvm.masked.scatter.v4i32.v4p0i32(<4 x i32> %val, <4 x i32*> %ptrs, i32 %align, <4 x i1> %mask)
@@ -0,0 +1,8 @@
+%struct.foo = type { i32, i64 }
+
+define void @f(%struct.foo* noalias sret %ret, %struct.foo* byval align 8 %x, i8* nonnull dereferenceable(16) %p, i8 signext %n) {
+; <label>:0
+	ret void
+}
+
+declare void @g(%struct.foo* sret, %struct.foo* byval align 8, i8* nonnull)
@@ -0,0 +1,17 @@
+; 1 + 2^-54
+@a = global ppc_fp128 0xM3FF00000000000003C90000000000000
+; Smallest denormal high-order double.
+@b = global ppc_fp128 0xM00000000000000010000000000000000
+; 1 plus smallest denormal low-order double.
+@c = global ppc_fp128 0xM3FF00000000000000000000000000001
+; Largest denormal high-order double.
+@d = global ppc_fp128 0xM000FFFFFFFFFFFFF0000000000000000
+; Negative zero.
+@e = global ppc_fp128 0xM80000000000000000000000000000000
+; Positive and negative infinity.
+@f = global ppc_fp128 0xM7FF00000000000000000000000000000
+@g = global ppc_fp128 0xMFFF00000000000000000000000000000
+; -pi
+@h = global ppc_fp128 0xMC00921FB54442D18BCA1A62633145C07
+; Quiet NaN.
+@i = global ppc_fp128 0xM7FF80000000000000000000000000000
@@ -0,0 +1,18 @@
+@x = global i32 7 8
+
+define i32 @f() {
+entry:
+	ret i32 0
+}
+
+define i32 @g() {
+entry:
+	%y = add i32 1,
+	ret i32 %y
+}
+
+define i32 @h() {
+entry:
+	%z = call i32 @f()
+	ret i32 %z
+}
@@ -0,0 +1,16 @@
+define { i32, i32 } @pair(i32 %a, i32 %b) {
+entry:
+	%x = insertvalue { i32, i32 } undef, i32 %a, 0
+	%y = insertvalue { i32, i32 } %x, i32 %b, 1
+	ret { i32, i32 } %y
+}
+
+define [2 x i8] @array() {
+entry:
+	ret [2 x i8] zeroinitializer
+}
+
+define <4 x float> @vector(<4 x float> %v) {
+entry:
+	ret <4 x float> %v
+}
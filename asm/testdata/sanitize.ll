@@ -0,0 +1,9 @@
+define void @asan() sanitize_address shadowcallstack {
+entry:
+	ret void
+}
+
+define void @fuzz() optforfuzzing sanitize_hwaddress sanitize_memory sanitize_thread {
+entry:
+	ret void
+}
@@ -0,0 +1,19 @@
+declare i32 @g(i32)
+
+declare i8* @h()
+
+define i32 @f(i32 %x) {
+; <label>:0
+	%1 = call i32 @g(i32 %x)
+	%2 = tail call i32 @g(i32 %1)
+	%3 = notail call i32 @g(i32 %2)
+	%4 = musttail call i32 @g(i32 %3)
+	ret i32 %4
+}
+
+define i32* @p() {
+; <label>:0
+	%1 = musttail call i8* @h()
+	%2 = bitcast i8* %1 to i32*
+	ret i32* %2
+}
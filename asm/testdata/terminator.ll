@@ -8,3 +8,15 @@ foo:
 bar:
 	ret void
 }
+
+define void @g(i1 %cond) {
+entry:
+	%addr = select i1 %cond, i8* blockaddress(@g, %foo), i8* blockaddress(@g, %bar)
+	indirectbr i8* %addr, [label %foo, label %bar]
+
+foo:
+	ret void
+
+bar:
+	ret void
+}
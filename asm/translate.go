@@ -101,6 +101,12 @@ import (
 // translate translates the given AST module into an equivalent IR module.
 func translate(old *ast.Module) (*ir.Module, error) {
 	gen := newGenerator()
+	return gen.translate(old)
+}
+
+// translate translates the given AST module into an equivalent IR module,
+// keeping track of top-level entities in gen.
+func (gen *generator) translate(old *ast.Module) (*ir.Module, error) {
 	// 1. Index AST top-level entities.
 	indexStart := time.Now()
 	if err := gen.indexTopLevelEntities(old); err != nil {
@@ -145,9 +151,11 @@ func translate(old *ast.Module) (*ir.Module, error) {
 		return nil, errors.WithStack(err)
 	}
 	// 7. Fix basic block references in blockaddress constants.
-	for _, c := range gen.todo {
-		if err := fixBlockAddressConst(c); err != nil {
-			return nil, errors.WithStack(err)
+	if !gen.lazy {
+		for _, c := range gen.todo {
+			if err := fixBlockAddressConst(c); err != nil {
+				return nil, errors.WithStack(err)
+			}
 		}
 	}
 	// 8. Add IR top-level declarations and definitions to the IR module in order
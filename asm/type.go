@@ -24,7 +24,11 @@ func (gen *generator) resolveTypeDefs() error {
 		return errors.WithStack(err)
 	}
 	// 2b. Translate AST type definitions to IR.
-	return gen.translateTypeDefs()
+	if err := gen.translateTypeDefs(); err != nil {
+		return errors.WithStack(err)
+	}
+	// 2c. Validate element types of IR type definitions.
+	return gen.validateTypeDefs()
 }
 
 // === [ Create and index IR ] =================================================
@@ -107,9 +111,16 @@ func newType(typeName string, old ast.LlvmNode, index map[string]*ast.TypeDef, t
 			return nil, errors.Errorf("invalid named type; self-referential with type name(s) %s", strings.Join(names, ", "))
 		}
 		track[typeName] = true
-		newIdent := localIdent(old.Name())
+		newIdent, err := localIdent(old.Name())
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
 		newName := getTypeName(newIdent)
-		newTyp := index[newName].Typ()
+		def, ok := index[newName]
+		if !ok {
+			return nil, errorf(old, "unable to locate type definition of named type %q", enc.Local(newName))
+		}
+		newTyp := def.Typ()
 		return newType(newName, newTyp, index, track)
 	default:
 		panic(fmt.Errorf("support for type %T not yet implemented", old))
@@ -131,6 +142,20 @@ func (gen *generator) translateTypeDefs() error {
 	return nil
 }
 
+// validateTypeDefs validates the element types of the IR type definitions of the
+// given module. Element types are validated once all type definitions have been
+// translated, as named element types are only skeleton IR types while type
+// definitions are being translated.
+func (gen *generator) validateTypeDefs() error {
+	// 2c. Validate element types of IR type definitions.
+	for _, t := range gen.new.typeDefs {
+		if err := validateElemTypes(t); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
 // irTypeDef translates the AST type into an equivalent IR type. A new IR type
 // correspoding to the AST type is created if t is nil, otherwise the body of t
 // is populated. Named types are resolved through gen.new.typeDefs.
@@ -307,7 +332,11 @@ func (gen *generator) irPointerType(t types.Type, old *ast.PointerType) (types.T
 	typ.ElemType = elemType
 	// Address space.
 	if n, ok := old.AddrSpace(); ok {
-		typ.AddrSpace = irAddrSpace(n)
+		addrSpace, err := irAddrSpace(n)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		typ.AddrSpace = addrSpace
 	}
 	return typ, nil
 }
@@ -325,7 +354,11 @@ func (gen *generator) irVectorType(t types.Type, old *ast.VectorType) (types.Typ
 		panic(fmt.Errorf("invalid IR type for AST vector type; expected *types.VectorType, got %T", t))
 	}
 	// Vector length.
-	typ.Len = uintLit(old.Len())
+	n, err := uintLit(old.Len())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	typ.Len = n
 	// Element type.
 	elem, err := gen.irType(old.Elem())
 	if err != nil {
@@ -396,13 +429,20 @@ func (gen *generator) irArrayType(t types.Type, old *ast.ArrayType) (types.Type,
 		panic(fmt.Errorf("invalid IR type for AST array type; expected *types.ArrayType, got %T", t))
 	}
 	// Array length.
-	typ.Len = uintLit(old.Len())
+	n, err := uintLit(old.Len())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	typ.Len = n
 	// Element type.
 	elem, err := gen.irType(old.Elem())
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 	typ.ElemType = elem
+	if err := validateElemTypes(typ); err != nil {
+		return nil, errors.WithStack(err)
+	}
 	return typ, nil
 }
 
@@ -445,6 +485,9 @@ func (gen *generator) irStructType(t types.Type, old *ast.StructType) (types.Typ
 			typ.Fields[i] = field
 		}
 	}
+	if err := validateElemTypes(typ); err != nil {
+		return nil, errors.WithStack(err)
+	}
 	// struct body now present.
 	typ.Opaque = false
 	return typ, nil
@@ -473,6 +516,9 @@ func (gen *generator) irPackedStructType(t types.Type, old *ast.PackedStructType
 			typ.Fields[i] = field
 		}
 	}
+	if err := validateElemTypes(typ); err != nil {
+		return nil, errors.WithStack(err)
+	}
 	// struct body now present.
 	typ.Opaque = false
 	return typ, nil
@@ -484,7 +530,10 @@ func (gen *generator) irPackedStructType(t types.Type, old *ast.PackedStructType
 func (gen *generator) irNamedType(t types.Type, old *ast.NamedType) (types.Type, error) {
 	// TODO: make use of t?
 	// Resolve named type.
-	ident := localIdent(old.Name())
+	ident, err := localIdent(old.Name())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 	name := getTypeName(ident)
 	typ, ok := gen.new.typeDefs[name]
 	if !ok {
@@ -512,3 +561,22 @@ func getTypeName(ident ir.LocalIdent) string {
 	}
 	return ident.LocalName
 }
+
+// validateElemTypes validates the element types of the given array or struct
+// type. Scalable vector types may not be used as array elements or struct
+// fields.
+func validateElemTypes(t types.Type) error {
+	switch t := t.(type) {
+	case *types.ArrayType:
+		if types.IsScalableVector(t.ElemType) {
+			return errors.Errorf("invalid element type of array type %s; scalable vector type %s not allowed as array element", t, t.ElemType)
+		}
+	case *types.StructType:
+		for _, field := range t.Fields {
+			if types.IsScalableVector(field) {
+				return errors.Errorf("invalid field type of struct type %s; scalable vector type %s not allowed as struct field", t, field)
+			}
+		}
+	}
+	return nil
+}
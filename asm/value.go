@@ -13,17 +13,19 @@ import (
 func (fgen *funcGen) irValue(typ types.Type, old ast.Value) (value.Value, error) {
 	switch old := old.(type) {
 	case *ast.GlobalIdent:
-		ident := globalIdent(*old)
+		ident, err := globalIdent(*old)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
 		v, ok := fgen.gen.new.globals[ident]
 		if !ok {
-			return nil, errors.Errorf("unable to locate global identifier %q", ident.Ident())
+			return nil, errorf(old, "unable to locate global identifier %q", ident.Ident())
 		}
 		return v, nil
 	case *ast.LocalIdent:
-		ident := localIdent(*old)
-		v, ok := fgen.locals[ident]
-		if !ok {
-			return nil, errors.Errorf("unable to locate local identifier %q of %q", ident.Ident(), fgen.f.Ident())
+		v, err := fgen.local(*old)
+		if err != nil {
+			return nil, errors.WithStack(err)
 		}
 		return v, nil
 	case *ast.InlineAsm:
@@ -6,6 +6,7 @@ import (
 
 	"github.com/llir/llvm/internal/enc"
 	"github.com/llir/llvm/ir/types"
+	"github.com/pkg/errors"
 )
 
 // === [ Basic blocks ] ========================================================
@@ -60,3 +61,108 @@ func (block *BasicBlock) Def() string {
 	fmt.Fprintf(buf, "\t%s", block.Term.Def())
 	return buf.String()
 }
+
+// Succs returns the successor basic blocks of the basic block, as derived from
+// its terminator.
+func (block *BasicBlock) Succs() []*BasicBlock {
+	if block.Term == nil {
+		return nil
+	}
+	return block.Term.Succs()
+}
+
+// Preds returns the predecessor basic blocks of the basic block within its
+// parent function. Each predecessor is listed once, in order of occurrence in
+// the function body.
+//
+// The predecessors are cached by the parent function; invoke
+// Parent.InvalidateCFG after modifying the control flow of the function.
+func (block *BasicBlock) Preds() []*BasicBlock {
+	if block.Parent == nil {
+		panic(fmt.Errorf("unable to locate predecessors of basic block %q; parent function not set", block.Ident()))
+	}
+	return block.Parent.predsOf(block)
+}
+
+// SplitAt splits the basic block at the given instruction, and returns the new
+// basic block. The instruction, the instructions following it and the
+// terminator are moved to a new unnamed basic block, which is inserted after
+// the basic block in its parent function. The basic block is terminated by an
+// unconditional branch to the new basic block, and the incoming values of phi
+// instructions in the successors of the new basic block are updated to refer
+// to the new basic block as predecessor.
+//
+// The IDs of unnamed local variables of the parent function are reset, and
+// assigned anew on output.
+func (block *BasicBlock) SplitAt(inst Instruction) (*BasicBlock, error) {
+	if block.Parent == nil {
+		return nil, errors.Errorf("unable to split basic block %q; parent function not set", block.Ident())
+	}
+	if _, ok := inst.(*InstPhi); ok {
+		return nil, errors.Errorf("unable to split basic block %q at phi instruction", block.Ident())
+	}
+	index := -1
+	for i, v := range block.Insts {
+		if v == inst {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return nil, errors.Errorf("unable to locate instruction in basic block %q", block.Ident())
+	}
+	f := block.Parent
+	pos := -1
+	for i, b := range f.Blocks {
+		if b == block {
+			pos = i
+			break
+		}
+	}
+	if pos == -1 {
+		return nil, errors.Errorf("unable to locate basic block %q in parent function %q", block.Ident(), f.Ident())
+	}
+	// Move instructions and terminator to the new basic block.
+	newBlock := NewBlock("")
+	newBlock.Parent = f
+	newBlock.Insts = append(newBlock.Insts, block.Insts[index:]...)
+	newBlock.Term = block.Term
+	setCallParents(newBlock)
+	for i := index; i < len(block.Insts); i++ {
+		block.Insts[i] = nil
+	}
+	block.Insts = block.Insts[:index]
+	block.Term = NewBr(newBlock)
+	f.Blocks = append(f.Blocks, nil)
+	copy(f.Blocks[pos+2:], f.Blocks[pos+1:])
+	f.Blocks[pos+1] = newBlock
+	// Update predecessors of incoming values of phi instructions in successors.
+	for _, succ := range newBlock.Succs() {
+		for _, inst := range succ.Insts {
+			phi, ok := inst.(*InstPhi)
+			if !ok {
+				continue
+			}
+			for _, inc := range phi.Incs {
+				if inc.Pred == block {
+					inc.Pred = newBlock
+				}
+			}
+		}
+	}
+	f.InvalidateCFG()
+	resetLocalIDs(f)
+	return newBlock, nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// setCallParents sets the parent basic block of the call instructions of the
+// given basic block.
+func setCallParents(block *BasicBlock) {
+	for _, inst := range block.Insts {
+		if call, ok := inst.(*InstCall); ok {
+			call.Parent = block
+		}
+	}
+}
@@ -18,6 +18,15 @@ func (block *BasicBlock) NewAlloca(elemType types.Type) *InstAlloca {
 	return inst
 }
 
+// NewAllocaInAddrSpace appends a new alloca instruction to the basic block
+// based on the given element type, allocating memory in the given address
+// space.
+func (block *BasicBlock) NewAllocaInAddrSpace(elemType types.Type, addrSpace types.AddrSpace) *InstAlloca {
+	inst := NewAllocaInAddrSpace(elemType, addrSpace)
+	block.Insts = append(block.Insts, inst)
+	return inst
+}
+
 // ~~~ [ load ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // NewLoad appends a new load instruction to the basic block based on the given
@@ -44,7 +44,7 @@ func (block *BasicBlock) NewPhi(incs ...*Incoming) *InstPhi {
 // NewSelect appends a new select instruction to the basic block based on the
 // given selection condition and operands.
 func (block *BasicBlock) NewSelect(cond, x, y value.Value) *InstSelect {
-	inst := NewSelect(cond, x, x)
+	inst := NewSelect(cond, x, y)
 	block.Insts = append(block.Insts, inst)
 	return inst
 }
@@ -57,6 +57,7 @@ func (block *BasicBlock) NewSelect(cond, x, y value.Value) *InstSelect {
 // TODO: specify the set of underlying types of callee.
 func (block *BasicBlock) NewCall(callee value.Value, args ...value.Value) *InstCall {
 	inst := NewCall(callee, args...)
+	inst.Parent = block
 	block.Insts = append(block.Insts, inst)
 	return inst
 }
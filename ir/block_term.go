@@ -1,7 +1,6 @@
 package ir
 
 import (
-	"github.com/llir/llvm/ir/constant"
 	"github.com/llir/llvm/ir/value"
 )
 
@@ -53,8 +52,8 @@ func (block *BasicBlock) NewSwitch(x value.Value, targetDefault *BasicBlock, cas
 
 // NewIndirectBr sets the terminator of the basic block to a new indirectbr
 // terminator based on the given target address (derived from a blockaddress
-// constant) and set of valid target basic blocks.
-func (block *BasicBlock) NewIndirectBr(addr constant.Constant, validTargets ...*BasicBlock) *TermIndirectBr {
+// constant of type i8*) and set of valid target basic blocks.
+func (block *BasicBlock) NewIndirectBr(addr value.Value, validTargets ...*BasicBlock) *TermIndirectBr {
 	term := NewIndirectBr(addr, validTargets...)
 	block.Term = term
 	return term
@@ -73,6 +72,19 @@ func (block *BasicBlock) NewInvoke(invokee value.Value, args []value.Value, norm
 	return term
 }
 
+// ~~~ [ callbr ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// NewCallBr sets the terminator of the basic block to a new callbr terminator
+// based on the given callee, function arguments and control flow return points
+// for normal and other execution.
+//
+// TODO: specify the set of underlying types of callee.
+func (block *BasicBlock) NewCallBr(callee value.Value, args []value.Value, normal *BasicBlock, others ...*BasicBlock) *TermCallBr {
+	term := NewCallBr(callee, args, normal, others...)
+	block.Term = term
+	return term
+}
+
 // ~~~ [ resume ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // NewResume sets the terminator of the basic block to a new resume terminator
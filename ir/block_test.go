@@ -0,0 +1,155 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestBlockSuccsPreds(t *testing.T) {
+	// Diamond control flow graph.
+	//
+	//      entry
+	//      /   \
+	//   left   right
+	//      \   /
+	//      exit
+	diamond := NewFunc("diamond", types.Void, NewParam("cond", types.I1))
+	entry := diamond.NewBlock("entry")
+	left := diamond.NewBlock("left")
+	right := diamond.NewBlock("right")
+	exit := diamond.NewBlock("exit")
+	entry.NewCondBr(diamond.Params[0], left, right)
+	left.NewBr(exit)
+	right.NewBr(exit)
+	exit.NewRet(nil)
+	// Loop control flow graph.
+	//
+	//   head <--+
+	//   /  \    |
+	// done body-+
+	loop := NewFunc("loop", types.Void, NewParam("cond", types.I1))
+	head := loop.NewBlock("head")
+	body := loop.NewBlock("body")
+	done := loop.NewBlock("done")
+	head.NewCondBr(loop.Params[0], body, done)
+	body.NewBr(head)
+	done.NewRet(nil)
+	golden := []struct {
+		block *BasicBlock
+		succs []*BasicBlock
+		preds []*BasicBlock
+	}{
+		{block: entry, succs: []*BasicBlock{left, right}, preds: nil},
+		{block: left, succs: []*BasicBlock{exit}, preds: []*BasicBlock{entry}},
+		{block: right, succs: []*BasicBlock{exit}, preds: []*BasicBlock{entry}},
+		{block: exit, succs: nil, preds: []*BasicBlock{left, right}},
+		{block: head, succs: []*BasicBlock{body, done}, preds: []*BasicBlock{body}},
+		{block: body, succs: []*BasicBlock{head}, preds: []*BasicBlock{head}},
+		{block: done, succs: nil, preds: []*BasicBlock{head}},
+	}
+	for _, g := range golden {
+		if got := g.block.Succs(); !equalBlocks(g.succs, got) {
+			t.Errorf("successors mismatch of %q; expected %v, got %v", g.block.Ident(), g.succs, got)
+		}
+		if got := g.block.Preds(); !equalBlocks(g.preds, got) {
+			t.Errorf("predecessors mismatch of %q; expected %v, got %v", g.block.Ident(), g.preds, got)
+		}
+	}
+	// Modify control flow and invalidate cached control flow graph.
+	left.Term.(*TermBr).Target = right
+	right.NewRet(nil)
+	diamond.InvalidateCFG()
+	if got, want := right.Preds(), []*BasicBlock{entry, left}; !equalBlocks(want, got) {
+		t.Errorf("predecessors mismatch of %q; expected %v, got %v", right.Ident(), want, got)
+	}
+	if got := exit.Preds(); len(got) != 0 {
+		t.Errorf("predecessors mismatch of %q; expected none, got %v", exit.Ident(), got)
+	}
+	if got, want := left.Succs(), []*BasicBlock{right}; !equalBlocks(want, got) {
+		t.Errorf("successors mismatch of %q; expected %v, got %v", left.Ident(), want, got)
+	}
+}
+
+// equalBlocks reports whether the given basic block slices are equal.
+func equalBlocks(a, b []*BasicBlock) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSplitAt(t *testing.T) {
+	n := NewParam("n", types.I32)
+	f := NewFunc("f", types.I32, n)
+	entry := f.NewBlock("entry")
+	loop := f.NewBlock("loop")
+	exit := f.NewBlock("exit")
+	entry.NewBr(loop)
+	i := loop.NewPhi(NewIncoming(constant.NewInt(types.I32, 0), entry))
+	i.SetName("i")
+	sum := loop.NewPhi(NewIncoming(constant.NewInt(types.I32, 0), entry))
+	sum.SetName("sum")
+	nextSum := loop.NewAdd(sum, i)
+	nextSum.SetName("next_sum")
+	next := loop.NewAdd(i, constant.NewInt(types.I32, 1))
+	next.SetName("next")
+	i.Incs = append(i.Incs, NewIncoming(next, loop))
+	sum.Incs = append(sum.Incs, NewIncoming(nextSum, loop))
+	cond := loop.NewICmp(enum.IPredSLT, next, n)
+	cond.SetName("cond")
+	loop.NewCondBr(cond, loop, exit)
+	exit.NewRet(nextSum)
+	// Preds are cached before splitting.
+	if got := len(exit.Preds()); got != 1 {
+		t.Fatalf("predecessors mismatch; expected 1, got %d", got)
+	}
+	tail, err := loop.SplitAt(next)
+	if err != nil {
+		t.Fatalf("unable to split basic block; %v", err)
+	}
+	want := `define i32 @f(i32 %n) {
+entry:
+	br label %loop
+
+loop:
+	%i = phi i32 [ 0, %entry ], [ %next, %0 ]
+	%sum = phi i32 [ 0, %entry ], [ %next_sum, %0 ]
+	%next_sum = add i32 %sum, %i
+	br label %0
+
+; <label>:0
+	%next = add i32 %i, 1
+	%cond = icmp slt i32 %next, %n
+	br i1 %cond, label %loop, label %exit
+
+exit:
+	ret i32 %next_sum
+}`
+	if got := f.Def(); got != want {
+		t.Errorf("function mismatch; expected `%s`, got `%s`", want, got)
+	}
+	if got := exit.Preds(); len(got) != 1 || got[0] != tail {
+		t.Errorf("predecessors of exit mismatch; expected [%s], got %v", tail.Ident(), got)
+	}
+	if got := tail.Preds(); len(got) != 1 || got[0] != loop {
+		t.Errorf("predecessors of split block mismatch; expected [%%loop], got %v", got)
+	}
+	if got := loop.Preds(); len(got) != 2 || got[0] != entry || got[1] != tail {
+		t.Errorf("predecessors of loop mismatch; expected [%%entry %s], got %v", tail.Ident(), got)
+	}
+	// Split at phi instruction and instruction not in basic block.
+	if _, err := loop.SplitAt(i); err == nil {
+		t.Errorf("expected error for split at phi instruction, got nil")
+	}
+	if _, err := loop.SplitAt(next); err == nil {
+		t.Errorf("expected error for instruction not in basic block, got nil")
+	}
+}
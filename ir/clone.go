@@ -0,0 +1,274 @@
+package ir
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Instruction cloning ] =================================================
+
+// CloneInstruction returns a copy of the given instruction, which is not yet
+// part of any basic block. The operands of the copy are initially shared with
+// the original instruction; use RemapInstruction to rewire them. The copy is
+// unnamed, and metadata attachments are shared with the original instruction.
+func CloneInstruction(inst Instruction) Instruction {
+	var clone Instruction
+	switch inst := inst.(type) {
+	// Binary instructions.
+	case *InstAdd:
+		c := *inst
+		clone = &c
+	case *InstFAdd:
+		c := *inst
+		clone = &c
+	case *InstSub:
+		c := *inst
+		clone = &c
+	case *InstFSub:
+		c := *inst
+		clone = &c
+	case *InstMul:
+		c := *inst
+		clone = &c
+	case *InstFMul:
+		c := *inst
+		clone = &c
+	case *InstUDiv:
+		c := *inst
+		clone = &c
+	case *InstSDiv:
+		c := *inst
+		clone = &c
+	case *InstFDiv:
+		c := *inst
+		clone = &c
+	case *InstURem:
+		c := *inst
+		clone = &c
+	case *InstSRem:
+		c := *inst
+		clone = &c
+	case *InstFRem:
+		c := *inst
+		clone = &c
+	// Bitwise instructions.
+	case *InstShl:
+		c := *inst
+		clone = &c
+	case *InstLShr:
+		c := *inst
+		clone = &c
+	case *InstAShr:
+		c := *inst
+		clone = &c
+	case *InstAnd:
+		c := *inst
+		clone = &c
+	case *InstOr:
+		c := *inst
+		clone = &c
+	case *InstXor:
+		c := *inst
+		clone = &c
+	// Vector instructions.
+	case *InstExtractElement:
+		c := *inst
+		clone = &c
+	case *InstInsertElement:
+		c := *inst
+		clone = &c
+	case *InstShuffleVector:
+		c := *inst
+		clone = &c
+	// Aggregate instructions.
+	case *InstExtractValue:
+		c := *inst
+		clone = &c
+	case *InstInsertValue:
+		c := *inst
+		clone = &c
+	// Memory instructions.
+	case *InstAlloca:
+		c := *inst
+		clone = &c
+	case *InstLoad:
+		c := *inst
+		clone = &c
+	case *InstStore:
+		c := *inst
+		clone = &c
+	case *InstFence:
+		c := *inst
+		clone = &c
+	case *InstCmpXchg:
+		c := *inst
+		clone = &c
+	case *InstAtomicRMW:
+		c := *inst
+		clone = &c
+	case *InstGetElementPtr:
+		c := *inst
+		c.Indices = append([]value.Value(nil), inst.Indices...)
+		clone = &c
+	// Conversion instructions.
+	case *InstTrunc:
+		c := *inst
+		clone = &c
+	case *InstZExt:
+		c := *inst
+		clone = &c
+	case *InstSExt:
+		c := *inst
+		clone = &c
+	case *InstFPTrunc:
+		c := *inst
+		clone = &c
+	case *InstFPExt:
+		c := *inst
+		clone = &c
+	case *InstFPToUI:
+		c := *inst
+		clone = &c
+	case *InstFPToSI:
+		c := *inst
+		clone = &c
+	case *InstUIToFP:
+		c := *inst
+		clone = &c
+	case *InstSIToFP:
+		c := *inst
+		clone = &c
+	case *InstPtrToInt:
+		c := *inst
+		clone = &c
+	case *InstIntToPtr:
+		c := *inst
+		clone = &c
+	case *InstBitCast:
+		c := *inst
+		clone = &c
+	case *InstAddrSpaceCast:
+		c := *inst
+		clone = &c
+	// Other instructions.
+	case *InstICmp:
+		c := *inst
+		clone = &c
+	case *InstFCmp:
+		c := *inst
+		clone = &c
+	case *InstPhi:
+		c := *inst
+		c.Incs = make([]*Incoming, len(inst.Incs))
+		for i, inc := range inst.Incs {
+			c.Incs[i] = NewIncoming(inc.X, inc.Pred)
+		}
+		clone = &c
+	case *InstSelect:
+		c := *inst
+		clone = &c
+	case *InstCall:
+		c := *inst
+		c.Args = cloneArgs(inst.Args)
+		c.OperandBundles = cloneOperandBundles(inst.OperandBundles)
+		c.Parent = nil
+		clone = &c
+	case *InstVAArg:
+		c := *inst
+		clone = &c
+	case *InstLandingPad:
+		c := *inst
+		c.Clauses = make([]*Clause, len(inst.Clauses))
+		for i, clause := range inst.Clauses {
+			c.Clauses[i] = NewClause(clause.Type, clause.X)
+		}
+		clone = &c
+	case *InstCatchPad:
+		c := *inst
+		c.Args = cloneArgs(inst.Args)
+		clone = &c
+	case *InstCleanupPad:
+		c := *inst
+		c.Args = cloneArgs(inst.Args)
+		clone = &c
+	default:
+		panic(fmt.Errorf("support for instruction %T not yet implemented", inst))
+	}
+	if n, ok := clone.(local); ok {
+		n.SetName("")
+		n.SetID(0)
+	}
+	return clone
+}
+
+// RemapInstruction replaces the operands of the given instruction or
+// terminator based on the given value map, mapping from old to new value.
+// Function arguments are remapped by their argument value. The predecessor
+// basic blocks of incoming values of phi instructions are remapped if mapped
+// to basic blocks, and exception pad operands are remapped if mapped to
+// exception pads.
+func RemapInstruction(inst interface{}, valueMap map[value.Value]value.Value) {
+	for _, op := range operands(inst) {
+		if new, ok := valueMap[*op]; ok {
+			*op = new
+		}
+	}
+	switch inst := inst.(type) {
+	case *InstPhi:
+		for _, inc := range inst.Incs {
+			if new, ok := valueMap[inc.Pred].(*BasicBlock); ok {
+				inc.Pred = new
+			}
+		}
+	case *InstCatchPad:
+		if new, ok := valueMap[inst.Scope].(*TermCatchSwitch); ok {
+			inst.Scope = new
+		}
+	case *InstCleanupPad:
+		if new, ok := valueMap[inst.Scope].(ExceptionScope); ok {
+			inst.Scope = new
+		}
+	case *TermCatchSwitch:
+		if new, ok := valueMap[inst.Scope].(ExceptionScope); ok {
+			inst.Scope = new
+		}
+	case *TermCatchRet:
+		if new, ok := valueMap[inst.From].(*InstCatchPad); ok {
+			inst.From = new
+		}
+	case *TermCleanupRet:
+		if new, ok := valueMap[inst.From].(*InstCleanupPad); ok {
+			inst.From = new
+		}
+	}
+}
+
+// ### [ Helper functions ] ####################################################
+
+// cloneArgs returns a copy of the given function arguments. Arguments with
+// parameter attributes are copied, so that their argument values may be
+// remapped independently.
+func cloneArgs(args []value.Value) []value.Value {
+	clone := make([]value.Value, len(args))
+	for i, arg := range args {
+		if a, ok := arg.(*Arg); ok {
+			c := *a
+			arg = &c
+		}
+		clone[i] = arg
+	}
+	return clone
+}
+
+// cloneOperandBundles returns a copy of the given operand bundles.
+func cloneOperandBundles(bundles []*OperandBundle) []*OperandBundle {
+	if bundles == nil {
+		return nil
+	}
+	clone := make([]*OperandBundle, len(bundles))
+	for i, bundle := range bundles {
+		clone[i] = NewOperandBundle(bundle.Tag, append([]value.Value(nil), bundle.Inputs...)...)
+	}
+	return clone
+}
@@ -0,0 +1,41 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestCloneInstruction(t *testing.T) {
+	arr := types.NewArray(4, types.I32)
+	p := NewParam("p", types.NewPointer(arr))
+	q := NewParam("q", types.NewPointer(arr))
+	i := NewParam("i", types.I64)
+	f := NewFunc("f", types.Void, p, q, i)
+	entry := f.NewBlock("entry")
+	gep := entry.NewGetElementPtr(p, constant.NewInt(types.I64, 0), i)
+	gep.SetName("x")
+	clone := CloneInstruction(gep).(*InstGetElementPtr)
+	if clone == gep {
+		t.Fatalf("expected copy of instruction, got original")
+	}
+	RemapInstruction(clone, map[value.Value]value.Value{p: q})
+	entry.Insts = append(entry.Insts, clone)
+	entry.NewRet(nil)
+	want := `define void @f([4 x i32]* %p, [4 x i32]* %q, i64 %i) {
+entry:
+	%x = getelementptr [4 x i32], [4 x i32]* %p, i64 0, i64 %i
+	%0 = getelementptr [4 x i32], [4 x i32]* %q, i64 0, i64 %i
+	ret void
+}`
+	if got := f.Def(); got != want {
+		t.Errorf("function mismatch; expected `%s`, got `%s`", want, got)
+	}
+	// Remapping the indices of the copy leaves the original untouched.
+	RemapInstruction(clone, map[value.Value]value.Value{i: constant.NewInt(types.I64, 1)})
+	if got, want := gep.Indices[1], value.Value(i); got != want {
+		t.Errorf("index of original mismatch; expected %v, got %v", want, got)
+	}
+}
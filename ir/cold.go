@@ -0,0 +1,214 @@
+package ir
+
+import (
+	"strings"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Cold code ] ===========================================================
+
+// coldRatio is the ratio between the total branch weight of a terminator and
+// the branch weight of an edge, at or above which the edge is considered
+// unlikely to be taken. The branch weights of llvm.expect as lowered by LLVM
+// (2000:1) are above the ratio.
+const coldRatio = 1000
+
+// IsCold reports whether the function is rarely called, as indicated by the
+// cold function attribute.
+func (f *Function) IsCold() bool {
+	return hasColdAttr(f.FuncAttrs)
+}
+
+// IsCold reports whether the basic block is unlikely to be executed.
+//
+// A basic block is cold if its parent function is cold, if it contains a call
+// to a cold function or a call site with the cold function attribute, or if
+// every edge from its predecessor basic blocks is unlikely to be taken. An edge
+// is unlikely to be taken if its share of the !prof branch weights of the
+// terminator is at most 1/1000, or if the branching condition of a conditional
+// br terminator is expected by llvm.expect to take the other edge.
+func (block *BasicBlock) IsCold() bool {
+	if block.Parent != nil && block.Parent.IsCold() {
+		return true
+	}
+	for _, inst := range block.Insts {
+		if call, ok := inst.(*InstCall); ok && isColdCall(call) {
+			return true
+		}
+	}
+	if block.Parent == nil {
+		return false
+	}
+	hasPreds := false
+	for _, pred := range block.Parent.Blocks {
+		if pred.Term == nil || !isSucc(pred.Term, block) {
+			continue
+		}
+		hasPreds = true
+		if !isUnlikelyEdge(pred.Term, block) {
+			return false
+		}
+	}
+	return hasPreds
+}
+
+// ### [ Helper functions ] ####################################################
+
+// hasColdAttr reports whether the given function attributes contain the cold
+// attribute.
+func hasColdAttr(attrs []FuncAttribute) bool {
+	for _, attr := range attrs {
+		switch attr := attr.(type) {
+		case enum.FuncAttr:
+			if attr == enum.FuncAttrCold {
+				return true
+			}
+		case *AttrGroupDef:
+			if hasColdAttr(attr.FuncAttrs) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isColdCall reports whether the given call instruction has the cold function
+// attribute or calls a cold function.
+func isColdCall(call *InstCall) bool {
+	if hasColdAttr(call.FuncAttrs) {
+		return true
+	}
+	callee, ok := call.Callee.(*Function)
+	return ok && callee.IsCold()
+}
+
+// isSucc reports whether the given basic block is a successor of the
+// terminator.
+func isSucc(term Terminator, block *BasicBlock) bool {
+	for _, succ := range term.Succs() {
+		if succ == block {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnlikelyEdge reports whether the edges from the given terminator to the
+// successor basic block are unlikely to be taken.
+func isUnlikelyEdge(term Terminator, succ *BasicBlock) bool {
+	succs := term.Succs()
+	if weights := branchWeights(term); len(weights) == len(succs) {
+		var total, weight uint64
+		for i, w := range weights {
+			total += w
+			if succs[i] == succ {
+				weight += w
+			}
+		}
+		return total > 0 && weight*coldRatio <= total
+	}
+	if term, ok := term.(*TermCondBr); ok && term.TargetTrue != term.TargetFalse {
+		if expected, ok := expectedCond(term.Cond); ok {
+			if succ == term.TargetTrue {
+				return !expected
+			}
+			return expected
+		}
+	}
+	return false
+}
+
+// branchWeights returns the !prof branch weights of the given terminator, in
+// order of successors; or nil if not present.
+func branchWeights(term Terminator) []uint64 {
+	var mds []*metadata.Attachment
+	switch term := term.(type) {
+	case *TermCondBr:
+		mds = term.Metadata
+	case *TermSwitch:
+		mds = term.Metadata
+	case *TermIndirectBr:
+		mds = term.Metadata
+	case *TermInvoke:
+		mds = term.Metadata
+	case *TermCallBr:
+		mds = term.Metadata
+	}
+	for _, md := range mds {
+		if md.Name != "prof" {
+			continue
+		}
+		tuple, ok := metadataTuple(md.Node)
+		if !ok || len(tuple.Fields) < 2 {
+			return nil
+		}
+		if kind, ok := tuple.Fields[0].(*metadata.String); !ok || kind.Value != "branch_weights" {
+			return nil
+		}
+		var weights []uint64
+		for _, field := range tuple.Fields[1:] {
+			w, ok := field.(*constant.Int)
+			if !ok || w.X.Sign() < 0 || !w.X.IsUint64() {
+				return nil
+			}
+			weights = append(weights, w.X.Uint64())
+		}
+		return weights
+	}
+	return nil
+}
+
+// expectedCond returns the expected value of the given branching condition, as
+// specified by llvm.expect, and a boolean indicating whether an expected value
+// was specified. The branching condition is either the result of llvm.expect,
+// or an eq or ne integer comparison of the result of llvm.expect and an
+// integer constant.
+func expectedCond(cond value.Value) (bool, bool) {
+	switch cond := cond.(type) {
+	case *InstCall:
+		if expected, ok := expectedValue(cond); ok {
+			return expected.X.Sign() != 0, true
+		}
+	case *InstICmp:
+		if cond.Pred != enum.IPredEQ && cond.Pred != enum.IPredNE {
+			return false, false
+		}
+		x, y := cond.X, cond.Y
+		if _, ok := x.(*constant.Int); ok {
+			x, y = y, x
+		}
+		call, ok := x.(*InstCall)
+		if !ok {
+			return false, false
+		}
+		c, ok := y.(*constant.Int)
+		if !ok {
+			return false, false
+		}
+		if expected, ok := expectedValue(call); ok {
+			eq := expected.X.Cmp(c.X) == 0
+			return eq == (cond.Pred == enum.IPredEQ), true
+		}
+	}
+	return false, false
+}
+
+// expectedValue returns the expected value of the given call to llvm.expect,
+// and a boolean indicating whether the call is to llvm.expect with an integer
+// constant expected value.
+func expectedValue(call *InstCall) (*constant.Int, bool) {
+	callee, ok := call.Callee.(*Function)
+	if !ok || !strings.HasPrefix(callee.Name(), "llvm.expect.") || len(call.Args) < 2 {
+		return nil, false
+	}
+	arg := call.Args[1]
+	if a, ok := arg.(*Arg); ok {
+		arg = a.Value
+	}
+	expected, ok := arg.(*constant.Int)
+	return expected, ok
+}
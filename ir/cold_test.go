@@ -0,0 +1,59 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestIsCold(t *testing.T) {
+	m := NewModule()
+	abort := m.NewFunc("abort", types.Void)
+	abort.FuncAttrs = append(abort.FuncAttrs, enum.FuncAttrCold)
+	expect := m.NewFunc("llvm.expect.i64", types.I64, NewParam("x", types.I64), NewParam("expected", types.I64))
+	f := m.NewFunc("f", types.Void, NewParam("x", types.I64), NewParam("cond", types.I1))
+	entry := f.NewBlock("entry")
+	// Branch hint lowered from llvm.expect.
+	likely := f.NewBlock("likely")
+	unlikely := f.NewBlock("unlikely")
+	br := entry.NewCondBr(f.Params[1], likely, unlikely)
+	weights := &metadata.Tuple{Fields: []metadata.Field{
+		&metadata.String{Value: "branch_weights"},
+		constant.NewInt(types.I32, 2000),
+		constant.NewInt(types.I32, 1),
+	}}
+	br.Metadata = append(br.Metadata, &metadata.Attachment{Name: "prof", Node: weights})
+	// Branch hint using llvm.expect.
+	e := likely.NewCall(expect, f.Params[0], constant.NewInt(types.I64, 0))
+	cond := likely.NewICmp(enum.IPredNE, e, constant.NewInt(types.I64, 0))
+	fail := f.NewBlock("error")
+	exit := f.NewBlock("exit")
+	likely.NewCondBr(cond, fail, exit)
+	// Call to cold function.
+	unlikely.NewCall(abort)
+	unlikely.NewUnreachable()
+	fail.NewBr(exit)
+	exit.NewRet(nil)
+	golden := []struct {
+		block *BasicBlock
+		want  bool
+	}{
+		{block: entry, want: false},
+		{block: likely, want: false},
+		{block: unlikely, want: true},
+		{block: fail, want: true},
+		// exit is reached both from a cold and a hot basic block.
+		{block: exit, want: false},
+	}
+	for _, g := range golden {
+		if got := g.block.IsCold(); g.want != got {
+			t.Errorf("cold mismatch of basic block %s; expected %v, got %v", g.block.Ident(), g.want, got)
+		}
+	}
+	if !abort.IsCold() || f.IsCold() {
+		t.Errorf("cold function mismatch; expected @abort cold and @f not cold")
+	}
+}
@@ -81,6 +81,14 @@ func NewCharArrayFromString(s string) *CharArray {
 	return NewCharArray([]byte(s))
 }
 
+// NewCString returns a new character array constant based on the given UTF-8
+// string contents, followed by a terminating NUL character.
+func NewCString(s string) *CharArray {
+	x := make([]byte, len(s)+1)
+	copy(x, s)
+	return NewCharArray(x)
+}
+
 // String returns the LLVM syntax representation of the constant as a type-value
 // pair.
 func (c *CharArray) String() string {
@@ -92,7 +100,9 @@ func (c *CharArray) Type() types.Type {
 	return c.Typ
 }
 
-// Ident returns the identifier associated with the constant.
+// Ident returns the identifier associated with the constant. Bytes outside of
+// the printable ASCII range, double quotes and backslashes are printed as
+// hexadecimal escape sequences (\XX), as done by LLVM.
 func (c *CharArray) Ident() string {
 	// 'c' Val=StringLit
 	return "c" + enc.Quote(c.X)
@@ -2,7 +2,6 @@ package constant
 
 import (
 	"fmt"
-	"log"
 	"math"
 	"math/big"
 	"strconv"
@@ -57,7 +56,6 @@ func NewFloat(typ *types.FloatType, x float64) *Float {
 //         0xM[0-9A-Fa-f]{32} // HexPPC128
 //         0xH[0-9A-Fa-f]{4}  // HexHalf
 func NewFloatFromString(typ *types.FloatType, s string) (*Float, error) {
-	// TODO: implement NewFloatFromString. return 0 for now.
 	if strings.HasPrefix(s, "0x") {
 		switch {
 		case strings.HasPrefix(s, "0xK"):
@@ -76,9 +74,56 @@ func NewFloatFromString(typ *types.FloatType, s string) (*Float, error) {
 			x, nan := f.Big()
 			return &Float{Typ: typ, X: x, NaN: nan}, nil
 		case strings.HasPrefix(s, "0xL"):
-			//s = s[len("0xL"):]
+			// Low 64 bits followed by high 64 bits.
+			hex := s[len("0xL"):]
+			if len(hex) != 32 {
+				return nil, errors.Errorf("invalid length of fp128 hexadecimal floating-point literal %q; expected 32 hexadecimal digits, got %d", s, len(hex))
+			}
+			lo, err := strconv.ParseUint(hex[:16], 16, 64)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			hi, err := strconv.ParseUint(hex[16:], 16, 64)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			bits := new(big.Int).Lsh(new(big.Int).SetUint64(hi), 64)
+			bits.Or(bits, new(big.Int).SetUint64(lo))
+			x, nan := ieeeFromBits(bits, fp128ExpBits, fp128MantBits)
+			return &Float{Typ: typ, X: x, NaN: nan}, nil
 		case strings.HasPrefix(s, "0xM"):
-			//s = s[len("0xM"):]
+			// High-order double followed by low-order double.
+			hex := s[len("0xM"):]
+			if len(hex) != 32 {
+				return nil, errors.Errorf("invalid length of ppc_fp128 hexadecimal floating-point literal %q; expected 32 hexadecimal digits, got %d", s, len(hex))
+			}
+			hi, err := strconv.ParseUint(hex[:16], 16, 64)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			lo, err := strconv.ParseUint(hex[16:], 16, 64)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			fhi, flo := math.Float64frombits(hi), math.Float64frombits(lo)
+			if math.IsNaN(fhi) {
+				f := &Float{Typ: typ, X: &big.Float{}, NaN: true}
+				// Store sign of NaN.
+				if math.Signbit(fhi) {
+					f.X.SetFloat64(-1)
+				}
+				return f, nil
+			}
+			x := big.NewFloat(fhi)
+			// Only add a non-zero low-order double, as the sum of -0 and +0 is
+			// +0.
+			if !math.IsInf(fhi, 0) && flo != 0 {
+				// The sum of two doubles is exactly representable using the
+				// maximum span of double exponents.
+				x.SetPrec(ppcFP128Prec)
+				x.Add(x, big.NewFloat(flo))
+			}
+			return &Float{Typ: typ, X: x}, nil
 		case strings.HasPrefix(s, "0xH"):
 			hex := s[len("0xH"):]
 			bits, err := strconv.ParseUint(hex, 16, 16)
@@ -153,9 +198,6 @@ func NewFloatFromString(typ *types.FloatType, s string) (*Float, error) {
 				panic(fmt.Errorf("support for hexadecimal floating-point literal %q of kind %v not yet implemented", s, typ.Kind))
 			}
 		}
-		log.Printf("constant.NewFloatFromString(%q): not yet implemented", s)
-		return NewFloat(typ, 0), nil
-		//panic(fmt.Errorf("support for hexadecimal floating-point constant %q not yet implemented", s))
 	}
 	switch typ.Kind {
 	case types.FloatKindHalf:
@@ -191,6 +233,21 @@ func NewFloatFromString(typ *types.FloatType, s string) (*Float, error) {
 			X:   x,
 		}
 		return c, nil
+	case types.FloatKindX86_FP80, types.FloatKindFP128, types.FloatKindPPC_FP128:
+		precision := map[types.FloatKind]uint{
+			types.FloatKindX86_FP80:  64,
+			types.FloatKindFP128:     fp128MantBits + 1,
+			types.FloatKindPPC_FP128: 106,
+		}[typ.Kind]
+		x, _, err := big.ParseFloat(s, 10, precision, big.ToNearestEven)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		c := &Float{
+			Typ: typ,
+			X:   x,
+		}
+		return c, nil
 	default:
 		panic(fmt.Errorf("support for floating-point kind %v not yet implemented", typ.Kind))
 	}
@@ -210,27 +267,28 @@ func (c *Float) Type() types.Type {
 // Ident returns the identifier associated with the constant.
 func (c *Float) Ident() string {
 	// FloatLit
-	// TODO: add support for hexadecimal format.
-	// TODO: add support for NaN, +-Inf.
 
+	// Use the shortest decimal literal if it round-trips to the same bits, and
+	// the exact hexadecimal format otherwise.
+	if s, ok := c.shortestDecimal(); ok {
+		return s
+	}
 	switch c.Typ.Kind {
 	case types.FloatKindHalf:
-		if c.NaN || c.X.IsInf() || !float.IsExact16(c.X) {
-			var bits uint16
-			if c.NaN {
-				if c.X.Signbit() {
-					bits = binary16.NegNaN.Bits()
-				} else {
-					bits = binary16.NaN.Bits()
-				}
+		var bits uint16
+		if c.NaN {
+			if c.X.Signbit() {
+				bits = binary16.NegNaN.Bits()
 			} else {
-				f, acc := binary16.NewFromBig(c.X)
-				// TODO: check acc.
-				_ = acc
-				bits = f.Bits()
+				bits = binary16.NaN.Bits()
 			}
-			return fmt.Sprintf("0xH%04X", bits)
+		} else {
+			f, acc := binary16.NewFromBig(c.X)
+			// TODO: check acc.
+			_ = acc
+			bits = f.Bits()
 		}
+		return fmt.Sprintf("0xH%04X", bits)
 	case types.FloatKindFloat:
 		// ref: https://groups.google.com/d/msg/llvm-dev/IlqV3TbSk6M/27dAggZOMb0J
 		//
@@ -241,52 +299,51 @@ func (c *Float) Ident() string {
 		// double.  A double has 52 bits of significand, so this means that the
 		// last 29 bits of significand will always be ignored.  As an
 		// error-detection measure, the IR parser requires them to be zero.
-		if c.NaN || c.X.IsInf() || !float.IsExact32(c.X) {
-			// Single precision.
-			//
-			//     1 bit:  sign
-			//     8 bits: exponent
-			//    23 bits: mantissa
-			//
-			//    bias: 127
-			var bits32 uint32
-			if c.NaN {
-				// TODO: handle sign bit.
-				bits32 = 0xFFFFFFFF
-			} else {
-				f, _ := c.X.Float32()
-				bits32 = math.Float32bits(f)
-			}
-			// 0b10000000000000000000000000000000
-			sign := uint64(bits32 & 0x80000000 >> 31)
-			// 0b01111111100000000000000000000000
-			const bias32 = 127
-			exp32 := (bits32 & 0x7F800000 >> 23)
-			// 0b00000000011111111111111111111111
-			mant := uint64(bits32 & 0x7FFFFF)
-			// Double precision.
-			//
-			//     1 bit:  sign
-			//    11 bits: exponent
-			//    52 bits: mantissa
-			//
-			//    bias: 1023
-			var bits64 uint64
-			bits64 |= sign << 63
-			const bias64 = 1023
-			var exp64 uint64
-			if exp32 == 0xFF {
-				// Keep every bit set in the exponent if such was the case for
-				// float32.
-				exp64 = 0x7FF
-			} else {
-				exp := uint64(exp32 - bias32)
-				exp64 = exp + bias64
-			}
-			bits64 |= exp64 << 52
-			bits64 |= mant << (52 - 23)
-			return fmt.Sprintf("0x%016X", bits64)
+		//
+		// Single precision.
+		//
+		//     1 bit:  sign
+		//     8 bits: exponent
+		//    23 bits: mantissa
+		//
+		//    bias: 127
+		var bits32 uint32
+		if c.NaN {
+			// TODO: handle sign bit.
+			bits32 = 0xFFFFFFFF
+		} else {
+			f, _ := c.X.Float32()
+			bits32 = math.Float32bits(f)
 		}
+		// 0b10000000000000000000000000000000
+		sign := uint64(bits32 & 0x80000000 >> 31)
+		// 0b01111111100000000000000000000000
+		const bias32 = 127
+		exp32 := (bits32 & 0x7F800000 >> 23)
+		// 0b00000000011111111111111111111111
+		mant := uint64(bits32 & 0x7FFFFF)
+		// Double precision.
+		//
+		//     1 bit:  sign
+		//    11 bits: exponent
+		//    52 bits: mantissa
+		//
+		//    bias: 1023
+		var bits64 uint64
+		bits64 |= sign << 63
+		const bias64 = 1023
+		var exp64 uint64
+		if exp32 == 0xFF {
+			// Keep every bit set in the exponent if such was the case for
+			// float32.
+			exp64 = 0x7FF
+		} else {
+			exp := uint64(exp32 - bias32)
+			exp64 = exp + bias64
+		}
+		bits64 |= exp64 << 52
+		bits64 |= mant << (52 - 23)
+		return fmt.Sprintf("0x%016X", bits64)
 	case types.FloatKindDouble:
 		if c.NaN {
 			f := math.NaN()
@@ -296,14 +353,12 @@ func (c *Float) Ident() string {
 			bits := math.Float64bits(f)
 			return fmt.Sprintf("0x%X", bits)
 		}
-		if c.X.IsInf() || !float.IsExact64(c.X) {
-			f, _ := c.X.Float64()
-			bits := math.Float64bits(f)
-			// Note, to match Clang output we do not zero-pad the hexadecimal
-			// output.
-			return fmt.Sprintf("0x%X", bits)
-			//return fmt.Sprintf("0x%016X", bits)
-		}
+		f, _ := c.X.Float64()
+		bits := math.Float64bits(f)
+		// Note, to match Clang output we do not zero-pad the hexadecimal
+		// output.
+		return fmt.Sprintf("0x%X", bits)
+		//return fmt.Sprintf("0x%016X", bits)
 	case types.FloatKindX86_FP80:
 		// TODO: handle NaN.
 		f, acc := float80x86.NewFromBig(c.X)
@@ -311,14 +366,83 @@ func (c *Float) Ident() string {
 		_ = acc
 		se, m := f.Bits()
 		return fmt.Sprintf("0xK%04X%016X", se, m)
-		//case types.FloatKindFP128:
-		//case types.FloatKindPPCFP128:
+	case types.FloatKindFP128:
+		var bits *big.Int
+		if c.NaN {
+			// Quiet NaN; all exponent bits and the most significant mantissa
+			// bit set.
+			bits = new(big.Int).Lsh(big.NewInt(1<<fp128ExpBits-1), fp128MantBits)
+			bits.SetBit(bits, fp128MantBits-1, 1)
+			if c.X.Signbit() {
+				bits.SetBit(bits, fp128ExpBits+fp128MantBits, 1)
+			}
+		} else {
+			bits = ieeeBits(c.X, fp128ExpBits, fp128MantBits)
+		}
+		mask := new(big.Int).SetUint64(math.MaxUint64)
+		lo := new(big.Int).And(bits, mask).Uint64()
+		hi := new(big.Int).Rsh(bits, 64).Uint64()
+		// Low 64 bits followed by high 64 bits.
+		return fmt.Sprintf("0xL%016X%016X", lo, hi)
+	case types.FloatKindPPC_FP128:
+		var hi, lo float64
+		if c.NaN {
+			// Quiet NaN; all exponent bits and the most significant mantissa
+			// bit set.
+			hi = math.Float64frombits(0x7FF8000000000000)
+			if c.X.Signbit() {
+				hi = math.Copysign(hi, -1)
+			}
+		} else {
+			// A ppc_fp128 value is the sum of a high-order double and a
+			// low-order double.
+			hi, _ = c.X.Float64()
+			if !math.IsInf(hi, 0) {
+				rest := new(big.Float).SetPrec(ppcFP128Prec).Sub(c.X, big.NewFloat(hi))
+				lo, _ = rest.Float64()
+			}
+		}
+		return fmt.Sprintf("0xM%016X%016X", math.Float64bits(hi), math.Float64bits(lo))
+	default:
+		panic(fmt.Errorf("support for floating-point kind %v not yet implemented", c.Typ.Kind))
 	}
+}
 
+// shortestDecimal returns the shortest decimal floating-point literal of the
+// constant, and a boolean indicating whether the literal round-trips to the
+// same bits.
+//
+// Decimal floating-point literals are parsed as double precision values by
+// LLVM, and subsequently converted to the floating-point type of the constant;
+// thus the value has to be exactly representable both as a double and in the
+// floating-point type.
+func (c *Float) shortestDecimal() (string, bool) {
+	if c.NaN || c.X.IsInf() {
+		return "", false
+	}
+	f, acc := c.X.Float64()
+	if acc != big.Exact {
+		return "", false
+	}
+	switch c.Typ.Kind {
+	case types.FloatKindHalf:
+		// Only use decimal literals of half precision values if they are also
+		// the shortest decimal representation at half precision.
+		if !float.IsExact16(c.X) {
+			return "", false
+		}
+	case types.FloatKindFloat:
+		if float64(float32(f)) != f {
+			return "", false
+		}
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if g, err := strconv.ParseFloat(s, 64); err != nil || math.Float64bits(g) != math.Float64bits(f) {
+		return "", false
+	}
 	// Insert decimal point if not present.
 	//    3e4 -> 3.0e4
 	//    42  -> 42.0
-	s := c.X.Text('g', -1)
 	if !strings.ContainsRune(s, '.') {
 		if pos := strings.IndexByte(s, 'e'); pos != -1 {
 			s = s[:pos] + ".0" + s[pos:]
@@ -326,5 +450,111 @@ func (c *Float) Ident() string {
 			s += ".0"
 		}
 	}
-	return s
+	return s, true
+}
+
+// ### [ Helper functions ] ####################################################
+
+const (
+	// Number of exponent bits of the fp128 type.
+	fp128ExpBits = 15
+	// Number of explicitly stored mantissa bits of the fp128 type.
+	fp128MantBits = 112
+	// Precision in bits sufficient to exactly represent the sum of the two
+	// doubles of a ppc_fp128 value.
+	ppcFP128Prec = 2200
+)
+
+// ieeeBits returns the bit representation of the given finite or infinite
+// floating-point value in an IEEE 754 binary interchange format with the
+// specified number of exponent and (explicitly stored) mantissa bits, rounding
+// to nearest even.
+func ieeeBits(x *big.Float, expBits, mantBits uint) *big.Int {
+	bits := new(big.Int)
+	if x.Signbit() {
+		bits.SetBit(bits, int(expBits+mantBits), 1)
+	}
+	expMask := new(big.Int).Lsh(big.NewInt(1<<expBits-1), mantBits)
+	if x.IsInf() {
+		return bits.Or(bits, expMask)
+	}
+	if x.Sign() == 0 {
+		return bits
+	}
+	bias := 1<<(expBits-1) - 1
+	emin := 1 - bias
+	abs := new(big.Float).Abs(x)
+	// abs = m * 2^exp, where 1 <= m < 2.
+	exp := abs.MantExp(nil) - 1
+	if exp >= emin {
+		// Normal value.
+		r := new(big.Float).SetPrec(mantBits + 1).SetMode(big.ToNearestEven).Set(abs)
+		exp = r.MantExp(nil) - 1
+		if exp > bias {
+			// Overflow to infinity.
+			return bits.Or(bits, expMask)
+		}
+		mant, _ := new(big.Float).SetMantExp(r, int(mantBits)-exp).Int(nil)
+		// Clear implicit leading bit.
+		mant.SetBit(mant, int(mantBits), 0)
+		bits.Or(bits, new(big.Int).Lsh(big.NewInt(int64(exp+bias)), mantBits))
+		return bits.Or(bits, mant)
+	}
+	// Subnormal value; scale to an integer multiple of the smallest subnormal.
+	scaled := new(big.Float).SetMantExp(abs, int(mantBits)-emin)
+	n := scaled.MantExp(nil)
+	var mant *big.Int
+	switch {
+	case n <= 0:
+		// Less than one; round to nearest even of zero and one.
+		if scaled.Cmp(big.NewFloat(0.5)) > 0 {
+			mant = big.NewInt(1)
+		} else {
+			mant = new(big.Int)
+		}
+	default:
+		r := new(big.Float).SetPrec(uint(n)).SetMode(big.ToNearestEven).Set(scaled)
+		mant, _ = r.Int(nil)
+	}
+	// Note, rounding up to the smallest normal value correctly carries into the
+	// exponent bits.
+	return bits.Or(bits, mant)
+}
+
+// ieeeFromBits returns the floating-point value of the given bit representation
+// in an IEEE 754 binary interchange format with the specified number of
+// exponent and (explicitly stored) mantissa bits, and a boolean indicating
+// whether the value is NaN. The sign of NaN values is stored in the returned
+// floating-point value.
+func ieeeFromBits(bits *big.Int, expBits, mantBits uint) (*big.Float, bool) {
+	neg := bits.Bit(int(expBits+mantBits)) == 1
+	expField := new(big.Int).Rsh(bits, mantBits)
+	expField.And(expField, big.NewInt(1<<expBits-1))
+	exp := int(expField.Int64())
+	mant := new(big.Int).And(bits, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), mantBits), big.NewInt(1)))
+	if exp == 1<<expBits-1 {
+		if mant.Sign() != 0 {
+			x := &big.Float{}
+			// Store sign of NaN.
+			if neg {
+				x.SetFloat64(-1)
+			}
+			return x, true
+		}
+		return new(big.Float).SetInf(neg), false
+	}
+	bias := 1<<(expBits-1) - 1
+	if exp == 0 {
+		// Subnormal value.
+		exp = 1
+	} else {
+		// Set implicit leading bit.
+		mant.SetBit(mant, int(mantBits), 1)
+	}
+	x := new(big.Float).SetPrec(mantBits + 1).SetInt(mant)
+	x.SetMantExp(x, exp-bias-int(mantBits))
+	if neg {
+		x.Neg(x)
+	}
+	return x, false
 }
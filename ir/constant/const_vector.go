@@ -56,3 +56,90 @@ func (c *Vector) Ident() string {
 	buf.WriteString(">")
 	return buf.String()
 }
+
+// ~~~ [ Splat vectors ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// NewSplat returns a new vector constant of the given length, with all
+// elements equal to elem.
+func NewSplat(length uint64, elem Constant) *Vector {
+	elems := make([]Constant, length)
+	for i := range elems {
+		elems[i] = elem
+	}
+	return &Vector{Typ: types.NewVector(length, elem.Type()), Elems: elems}
+}
+
+// NewScalableSplat returns a new scalable vector constant of the given minimum
+// length, with all elements equal to elem. Scalable vector splats are
+// represented by shufflevector expressions with a zeroinitializer mask of an
+// insertelement expression of elem at index 0 into an undef vector.
+func NewScalableSplat(minLength uint64, elem Constant) *ExprShuffleVector {
+	typ := types.NewVector(minLength, elem.Type())
+	typ.Scalable = true
+	maskType := types.NewVector(minLength, types.I32)
+	maskType.Scalable = true
+	x := NewInsertElement(NewUndef(typ), elem, NewInt(types.I32, 0))
+	return NewShuffleVector(x, NewUndef(typ), NewZeroInitializer(maskType))
+}
+
+// IsSplat reports whether the given constant is a vector with all elements
+// equal, and returns the element if so. Vector constants, zeroinitializer,
+// undef and poison vectors, and shufflevector expressions with a
+// zeroinitializer mask of an insertelement expression at index 0 (as created
+// by NewScalableSplat) are recognized.
+func IsSplat(c Constant) (Constant, bool) {
+	t, ok := c.Type().(*types.VectorType)
+	if !ok {
+		return nil, false
+	}
+	switch c := c.(type) {
+	case *Vector:
+		if len(c.Elems) == 0 {
+			return nil, false
+		}
+		elem := c.Elems[0]
+		for _, e := range c.Elems[1:] {
+			if e.String() != elem.String() {
+				return nil, false
+			}
+		}
+		return elem, true
+	case *ZeroInitializer:
+		return NewZeroInitializer(t.ElemType), true
+	case *Undef:
+		return NewUndef(t.ElemType), true
+	case *Poison:
+		return NewPoison(t.ElemType), true
+	case *ExprShuffleVector:
+		if !isZeroMask(c.Mask) {
+			return nil, false
+		}
+		x, ok := c.X.(*ExprInsertElement)
+		if !ok {
+			return nil, false
+		}
+		if index, ok := x.Index.(*Int); !ok || index.X.Sign() != 0 {
+			return nil, false
+		}
+		return x.Elem, true
+	default:
+		return nil, false
+	}
+}
+
+// isZeroMask reports whether the given shuffle mask has all elements zero.
+func isZeroMask(mask Constant) bool {
+	switch mask := mask.(type) {
+	case *ZeroInitializer:
+		return true
+	case *Vector:
+		for _, elem := range mask.Elems {
+			if index, ok := elem.(*Int); !ok || index.X.Sign() != 0 {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
@@ -36,3 +36,46 @@ func (c *ZeroInitializer) Ident() string {
 	// 'zeroinitializer'
 	return "zeroinitializer"
 }
+
+// Expand returns the explicit constant of the zeroinitializer; i.e. integer and
+// floating-point zero constants, null pointers, and aggregate constants of
+// expanded zero elements. The zeroinitializer itself is returned for types
+// without an explicit zero constant (e.g. scalable vectors and tokens).
+func (c *ZeroInitializer) Expand() Constant {
+	switch t := c.Typ.(type) {
+	case *types.IntType:
+		return NewInt(t, 0)
+	case *types.FloatType:
+		return NewFloat(t, 0)
+	case *types.PointerType:
+		return NewNull(t)
+	case *types.VectorType:
+		if t.Scalable {
+			return c
+		}
+		elem := NewZeroInitializer(t.ElemType).Expand()
+		elems := make([]Constant, t.Len)
+		for i := range elems {
+			elems[i] = elem
+		}
+		return &Vector{Typ: t, Elems: elems}
+	case *types.ArrayType:
+		elem := NewZeroInitializer(t.ElemType).Expand()
+		elems := make([]Constant, t.Len)
+		for i := range elems {
+			elems[i] = elem
+		}
+		return &Array{Typ: t, Elems: elems}
+	case *types.StructType:
+		if t.Opaque {
+			return c
+		}
+		fields := make([]Constant, len(t.Fields))
+		for i, field := range t.Fields {
+			fields[i] = NewZeroInitializer(field).Expand()
+		}
+		return &Struct{Typ: t, Fields: fields}
+	default:
+		return c
+	}
+}
@@ -58,12 +58,25 @@ var (
 //
 //    *constant.Undef   // https://godoc.org/github.com/llir/llvm/ir/constant#Undef
 //
+// Poison values
+//
+// https://llvm.org/docs/LangRef.html#poison-values
+//
+//    *constant.Poison   // https://godoc.org/github.com/llir/llvm/ir/constant#Poison
+//
 // Addresses of basic blocks
 //
 // https://llvm.org/docs/LangRef.html#addresses-of-basic-blocks
 //
 //    *constant.BlockAddress   // https://godoc.org/github.com/llir/llvm/ir/constant#BlockAddress
 //
+// Function address wrappers
+//
+// https://llvm.org/docs/LangRef.html#dso-local-equivalent
+//
+//    *constant.DSOLocalEquivalent   // https://godoc.org/github.com/llir/llvm/ir/constant#DSOLocalEquivalent
+//    *constant.NoCFI                // https://godoc.org/github.com/llir/llvm/ir/constant#NoCFI
+//
 // Constant expressions
 //
 // https://llvm.org/docs/LangRef.html#constant-expressions
@@ -1,5 +1,15 @@
 package constant
 
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
 // Assert that each constant implements the constant.Constant interface.
 var (
 	// Constant expressions.
@@ -16,7 +26,10 @@ var (
 	_ Constant = (*Vector)(nil)
 	_ Constant = (*ZeroInitializer)(nil)
 	_ Constant = (*Undef)(nil)
+	_ Constant = (*Poison)(nil)
 	_ Constant = (*BlockAddress)(nil)
+	_ Constant = (*DSOLocalEquivalent)(nil)
+	_ Constant = (*NoCFI)(nil)
 )
 
 // Assert that each constant expression implements the constant.Expression interface.
@@ -69,3 +82,314 @@ var (
 	_ Expression = (*ExprFCmp)(nil)
 	_ Expression = (*ExprSelect)(nil)
 )
+
+func TestConvExpr(t *testing.T) {
+	i8Ptr := types.NewPointer(types.I8)
+	i8PtrAS1 := types.NewPointer(types.I8)
+	i8PtrAS1.AddrSpace = 1
+	x := NewInt(types.I32, 257)
+	y := NewFloat(types.Double, 1.5)
+	null := NewNull(i8Ptr)
+	v := NewUndef(types.NewVector(2, types.I32))
+	golden := []struct {
+		in   func() Expression
+		want string
+	}{
+		{in: func() Expression { return NewTrunc(x, types.I8) }, want: "i8 trunc (i32 257 to i8)"},
+		{in: func() Expression { return NewZExt(x, types.I64) }, want: "i64 zext (i32 257 to i64)"},
+		{in: func() Expression { return NewSExt(x, types.I64) }, want: "i64 sext (i32 257 to i64)"},
+		{in: func() Expression { return NewFPTrunc(y, types.Float) }, want: "float fptrunc (double 1.5 to float)"},
+		{in: func() Expression { return NewFPExt(y, types.FP128) }, want: "fp128 fpext (double 1.5 to fp128)"},
+		{in: func() Expression { return NewFPToUI(y, types.I32) }, want: "i32 fptoui (double 1.5 to i32)"},
+		{in: func() Expression { return NewFPToSI(y, types.I32) }, want: "i32 fptosi (double 1.5 to i32)"},
+		{in: func() Expression { return NewUIToFP(x, types.Double) }, want: "double uitofp (i32 257 to double)"},
+		{in: func() Expression { return NewSIToFP(x, types.Double) }, want: "double sitofp (i32 257 to double)"},
+		{in: func() Expression { return NewPtrToInt(null, types.I64) }, want: "i64 ptrtoint (i8* null to i64)"},
+		{in: func() Expression { return NewIntToPtr(x, i8Ptr) }, want: "i8* inttoptr (i32 257 to i8*)"},
+		{in: func() Expression { return NewBitCast(null, types.NewPointer(types.I32)) }, want: "i32* bitcast (i8* null to i32*)"},
+		{in: func() Expression { return NewBitCast(v, types.I64) }, want: "i64 bitcast (<2 x i32> undef to i64)"},
+		{in: func() Expression { return NewAddrSpaceCast(null, i8PtrAS1) }, want: "i8 addrspace(1)* addrspacecast (i8* null to i8 addrspace(1)*)"},
+		{in: func() Expression { return NewTrunc(v, types.NewVector(2, types.I8)) }, want: "<2 x i8> trunc (<2 x i32> undef to <2 x i8>)"},
+		// Invalid conversions.
+		{in: func() Expression { return NewTrunc(x, types.I64) }},
+		{in: func() Expression { return NewZExt(x, types.I8) }},
+		{in: func() Expression { return NewSExt(y, types.I64) }},
+		{in: func() Expression { return NewFPTrunc(y, types.FP128) }},
+		{in: func() Expression { return NewFPExt(y, types.Float) }},
+		{in: func() Expression { return NewFPToUI(x, types.I32) }},
+		{in: func() Expression { return NewSIToFP(y, types.Double) }},
+		{in: func() Expression { return NewPtrToInt(x, types.I64) }},
+		{in: func() Expression { return NewIntToPtr(null, i8Ptr) }},
+		{in: func() Expression { return NewBitCast(x, types.I64) }},
+		{in: func() Expression { return NewBitCast(null, i8PtrAS1) }},
+		{in: func() Expression { return NewBitCast(x, i8Ptr) }},
+		{in: func() Expression { return NewAddrSpaceCast(null, types.NewPointer(types.I32)) }},
+		{in: func() Expression { return NewTrunc(v, types.NewVector(4, types.I8)) }},
+	}
+	for _, g := range golden {
+		got, ok := tryConv(g.in)
+		if g.want == "" {
+			if ok {
+				t.Errorf("expected invalid conversion, got %q", got)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("unexpected invalid conversion; expected %q", g.want)
+			continue
+		}
+		if got != g.want {
+			t.Errorf("expression mismatch; expected %q, got %q", g.want, got)
+		}
+	}
+}
+
+// tryConv returns the string representation of the constant expression
+// returned by f, and a boolean indicating whether f returned without panicking.
+func tryConv(f func() Expression) (s string, ok bool) {
+	defer func() {
+		if e := recover(); e != nil {
+			ok = false
+		}
+	}()
+	return f().String(), true
+}
+
+func TestAsIntAsFloat(t *testing.T) {
+	golden := []struct {
+		in       value.Value
+		constant bool
+		intOk    bool
+		i        int64
+		floatOk  bool
+		f        float64
+	}{
+		{in: NewInt(types.I32, -42), constant: true, intOk: true, i: -42},
+		{in: &Int{Typ: types.I64, X: new(big.Int).SetUint64(math.MaxUint64)}, constant: true, intOk: true, i: -1},
+		{in: NewFloat(types.Double, 2.5), constant: true, floatOk: true, f: 2.5},
+		{in: NewVector(NewInt(types.I8, 3), NewInt(types.I8, 3)), constant: true, intOk: true, i: 3},
+		{in: NewVector(NewInt(types.I8, 3), NewInt(types.I8, 4)), constant: true},
+		{in: NewVector(NewFloat(types.Float, 1), NewFloat(types.Float, 1)), constant: true, floatOk: true, f: 1},
+		{in: NewNull(types.NewPointer(types.I8)), constant: true},
+		{in: NewUndef(types.I32), constant: true},
+		{in: NewPoison(types.I32), constant: true},
+		{in: &nonConstant{}},
+	}
+	for _, g := range golden {
+		if got := value.IsConstant(g.in); got != g.constant {
+			t.Errorf("%v: constant mismatch; expected %v, got %v", g.in, g.constant, got)
+		}
+		i, ok := AsInt(g.in)
+		if ok != g.intOk || i != g.i {
+			t.Errorf("%v: integer mismatch; expected (%d, %v), got (%d, %v)", g.in, g.i, g.intOk, i, ok)
+		}
+		f, ok := AsFloat(g.in)
+		if ok != g.floatOk || f != g.f {
+			t.Errorf("%v: floating-point mismatch; expected (%v, %v), got (%v, %v)", g.in, g.f, g.floatOk, f, ok)
+		}
+	}
+}
+
+// nonConstant is a non-constant value used for testing.
+type nonConstant struct{}
+
+func (*nonConstant) String() string   { return "i32 %x" }
+func (*nonConstant) Type() types.Type { return types.I32 }
+func (*nonConstant) Ident() string    { return "%x" }
+
+func TestFloatIdent(t *testing.T) {
+	golden := []struct {
+		typ  *types.FloatType
+		in   string
+		want string
+	}{
+		// float
+		{typ: types.Float, in: "1.5", want: "1.5"},
+		{typ: types.Float, in: "0x3FB99999A0000000", want: "0.10000000149011612"},
+		// double
+		{typ: types.Double, in: "0.1", want: "0.1"},
+		{typ: types.Double, in: "0x3FB999999999999A", want: "0.1"},
+		{typ: types.Double, in: "0x7FF0000000000000", want: "0x7FF0000000000000"},
+		// x86_fp80
+		{typ: types.X86_FP80, in: "2.5", want: "2.5"},
+		{typ: types.X86_FP80, in: "0xK3FFF8000000000000000", want: "1.0"},
+		{typ: types.X86_FP80, in: "0xK4000C90FDAA22168C235", want: "0xK4000C90FDAA22168C235"},
+		// fp128
+		{typ: types.FP128, in: "1.5", want: "1.5"},
+		{typ: types.FP128, in: "0xL00000000000000003FFF000000000000", want: "1.0"},
+		{typ: types.FP128, in: "0xL0000000000000000BFFF000000000000", want: "-1.0"},
+		{typ: types.FP128, in: "0xL00000000000000013FFF000000000000", want: "0xL00000000000000013FFF000000000000"},
+		{typ: types.FP128, in: "0xL00000000000000007FFF000000000000", want: "0xL00000000000000007FFF000000000000"},
+		{typ: types.FP128, in: "0xL00000000000000010000000000000000", want: "0xL00000000000000010000000000000000"},
+		{typ: types.FP128, in: "0xL00000000000000007FFF800000000000", want: "0xL00000000000000007FFF800000000000"},
+		// ppc_fp128
+		{typ: types.PPC_FP128, in: "0xM3FF00000000000000000000000000000", want: "1.0"},
+		{typ: types.PPC_FP128, in: "0xM3FF00000000000003C90000000000000", want: "0xM3FF00000000000003C90000000000000"},
+		{typ: types.PPC_FP128, in: "0xM7FF00000000000000000000000000000", want: "0xM7FF00000000000000000000000000000"},
+		{typ: types.PPC_FP128, in: "0xM80000000000000000000000000000000", want: "-0.0"},
+		{typ: types.PPC_FP128, in: "0xM3FF00000000000000000000000000001", want: "0xM3FF00000000000000000000000000001"},
+		{typ: types.PPC_FP128, in: "0xMC00921FB54442D18BCA1A62633145C07", want: "0xMC00921FB54442D18BCA1A62633145C07"},
+		{typ: types.PPC_FP128, in: "0xM7FF80000000000000000000000000000", want: "0xM7FF80000000000000000000000000000"},
+	}
+	for _, g := range golden {
+		c, err := NewFloatFromString(g.typ, g.in)
+		if err != nil {
+			t.Errorf("unable to parse %s %s; %v", g.typ, g.in, err)
+			continue
+		}
+		if got := c.Ident(); g.want != got {
+			t.Errorf("%s %s: floating-point literal mismatch; expected %q, got %q", g.typ, g.in, g.want, got)
+		}
+	}
+}
+
+func TestGetElementPtrSimplify(t *testing.T) {
+	one := NewInt(types.I64, 1)
+	golden := []struct {
+		in   *ExprGetElementPtr
+		want string
+	}{
+		// Poison source address.
+		{in: NewGetElementPtr(NewPoison(types.I8Ptr), one), want: "i8* poison"},
+		// Poison index.
+		{in: NewGetElementPtr(NewNull(types.I8Ptr), NewPoison(types.I64)), want: "i8* poison"},
+		// Undefined source address.
+		{in: NewGetElementPtr(NewUndef(types.I8Ptr), one), want: "i8* undef"},
+		// Vector of pointers source address.
+		{in: NewGetElementPtr(NewPoison(types.NewVector(2, types.I8Ptr)), one), want: "<2 x i8*> poison"},
+		// Not folded.
+		{in: NewGetElementPtr(NewNull(types.I8Ptr), one), want: "i8* getelementptr (i8, i8* null, i64 1)"},
+	}
+	for _, g := range golden {
+		if got := g.in.Simplify().String(); got != g.want {
+			t.Errorf("simplified constant mismatch of %q; expected %q, got %q", g.in.Ident(), g.want, got)
+		}
+	}
+}
+
+// global is a global variable used for testing.
+type global struct {
+	name string
+	typ  *types.PointerType
+}
+
+func (g *global) String() string   { return fmt.Sprintf("%s %s", g.typ, g.Ident()) }
+func (g *global) Type() types.Type { return g.typ }
+func (g *global) Ident() string    { return "@" + g.name }
+func (*global) IsConstant()        {}
+
+func TestFoldGetElementPtr(t *testing.T) {
+	str := &global{name: "str", typ: types.NewPointer(NewCString("hello world\n").Type())}
+	zero := NewInt(types.I64, 0)
+	one := NewInt(types.I64, 1)
+	inbounds := func(e *ExprGetElementPtr) *ExprGetElementPtr {
+		e.InBounds = true
+		return e
+	}
+	golden := []struct {
+		in   Constant
+		want string
+	}{
+		// Zero index folded to source address.
+		{in: NewGetElementPtr(str, zero), want: "[13 x i8]* @str"},
+		{in: inbounds(NewGetElementPtr(str, zero)), want: "[13 x i8]* @str"},
+		// Zero indices folded to bitcast of source address.
+		{in: NewGetElementPtr(str, zero, zero), want: "i8* bitcast ([13 x i8]* @str to i8*)"},
+		// Nested getelementptr expressions merged.
+		{in: inbounds(NewGetElementPtr(inbounds(NewGetElementPtr(str, zero, one)), zero)), want: "i8* getelementptr inbounds ([13 x i8], [13 x i8]* @str, i64 0, i64 1)"},
+		{in: NewGetElementPtr(inbounds(NewGetElementPtr(str, one)), zero, one), want: "i8* getelementptr ([13 x i8], [13 x i8]* @str, i64 1, i64 1)"},
+		// Not folded; non-zero index.
+		{in: inbounds(NewGetElementPtr(str, zero, one)), want: "i8* getelementptr inbounds ([13 x i8], [13 x i8]* @str, i64 0, i64 1)"},
+		// Not folded; non-constant index.
+		{in: NewGetElementPtr(str, zero, NewPtrToInt(NewNull(types.I8Ptr), types.I64)), want: "i8* getelementptr ([13 x i8], [13 x i8]* @str, i64 0, i64 ptrtoint (i8* null to i64))"},
+		// Poison index.
+		{in: NewGetElementPtr(str, zero, NewPoison(types.I64)), want: "i8* poison"},
+		// Not a getelementptr expression.
+		{in: one, want: "i64 1"},
+	}
+	for _, g := range golden {
+		if got := Fold(g.in).String(); got != g.want {
+			t.Errorf("folded constant mismatch of %q; expected %q, got %q", g.in.Ident(), g.want, got)
+		}
+	}
+}
+
+func TestCharArray(t *testing.T) {
+	golden := []struct {
+		in   *CharArray
+		want string
+	}{
+		{in: NewCharArrayFromString("hello"), want: `[5 x i8] c"hello"`},
+		{in: NewCString("hello world\n"), want: `[13 x i8] c"hello world\0A\00"`},
+		// Embedded quotes and backslashes.
+		{in: NewCharArrayFromString(`say "hi"`), want: `[8 x i8] c"say \22hi\22"`},
+		{in: NewCharArrayFromString(`C:\dir`), want: `[6 x i8] c"C:\5Cdir"`},
+		// Control characters.
+		{in: NewCharArrayFromString("a\tb\r\n\x7F"), want: `[6 x i8] c"a\09b\0D\0A\7F"`},
+		// High bytes.
+		{in: NewCharArrayFromString("\xFF\x80世"), want: `[5 x i8] c"\FF\80\E4\B8\96"`},
+		// Empty string.
+		{in: NewCString(""), want: `[1 x i8] c"\00"`},
+	}
+	for _, g := range golden {
+		if got := g.in.String(); g.want != got {
+			t.Errorf("character array mismatch; expected %q, got %q", g.want, got)
+		}
+	}
+}
+
+func TestSplat(t *testing.T) {
+	one := NewInt(types.I32, 1)
+	half := NewFloat(types.Float, 0.5)
+	golden := []struct {
+		in       Constant
+		want     string
+		wantElem string // empty if not a splat
+	}{
+		{in: NewSplat(4, one), want: "<4 x i32> <i32 1, i32 1, i32 1, i32 1>", wantElem: "i32 1"},
+		{in: NewSplat(2, half), want: "<2 x float> <float 0.5, float 0.5>", wantElem: "float 0.5"},
+		{in: NewScalableSplat(4, one), want: "<vscale x 4 x i32> shufflevector (<vscale x 4 x i32> insertelement (<vscale x 4 x i32> undef, i32 1, i32 0), <vscale x 4 x i32> undef, <vscale x 4 x i32> zeroinitializer)", wantElem: "i32 1"},
+		{in: NewZeroInitializer(types.NewVector(4, types.I32)), want: "<4 x i32> zeroinitializer", wantElem: "i32 zeroinitializer"},
+		{in: NewPoison(types.NewVector(4, types.I32)), want: "<4 x i32> poison", wantElem: "i32 poison"},
+		// Not splats.
+		{in: NewVector(one, NewInt(types.I32, 2)), want: "<2 x i32> <i32 1, i32 2>"},
+		{in: NewZeroInitializer(types.NewArray(4, types.I32)), want: "[4 x i32] zeroinitializer"},
+		{in: one, want: "i32 1"},
+	}
+	for _, g := range golden {
+		if got := g.in.String(); got != g.want {
+			t.Errorf("constant mismatch; expected %q, got %q", g.want, got)
+		}
+		elem, ok := IsSplat(g.in)
+		switch {
+		case ok != (len(g.wantElem) > 0):
+			t.Errorf("splat mismatch of %q; expected %t, got %t", g.want, len(g.wantElem) > 0, ok)
+		case ok && elem.String() != g.wantElem:
+			t.Errorf("splat element mismatch of %q; expected %q, got %q", g.want, g.wantElem, elem.String())
+		}
+	}
+}
+
+func TestZeroInitializerExpand(t *testing.T) {
+	// Struct of arrays.
+	st := types.NewStruct(types.NewArray(2, types.I32), types.NewArray(1, types.NewVector(2, types.Float)), types.NewArray(0, types.I8), types.I8Ptr)
+	golden := []struct {
+		t    types.Type
+		want string
+	}{
+		{t: types.I32, want: "i32 0"},
+		{t: types.Double, want: "double 0.0"},
+		{t: types.I8Ptr, want: "i8* null"},
+		{t: st, want: "{ [2 x i32], [1 x <2 x float>], [0 x i8], i8* } { [2 x i32] [i32 0, i32 0], [1 x <2 x float>] [<2 x float> <float 0.0, float 0.0>], [0 x i8] [], i8* null }"},
+		{t: &types.VectorType{Len: 2, ElemType: types.I32, Scalable: true}, want: "<vscale x 2 x i32> zeroinitializer"},
+	}
+	for _, g := range golden {
+		c := NewZeroInitializer(g.t)
+		if got, want := c.String(), g.t.String()+" zeroinitializer"; got != want {
+			t.Errorf("zeroinitializer mismatch; expected %q, got %q", want, got)
+		}
+		if got := c.Expand().String(); got != g.want {
+			t.Errorf("expanded zeroinitializer mismatch of type %s; expected %q, got %q", g.t, g.want, got)
+		}
+	}
+}
@@ -21,6 +21,8 @@ type ExprTrunc struct {
 // NewTrunc returns a new trunc expression based on the given source value and
 // target type.
 func NewTrunc(from Constant, to types.Type) *ExprTrunc {
+	// Validate source and target types.
+	checkConv("trunc", from.Type(), to)
 	e := &ExprTrunc{From: from, To: to}
 	// Compute type.
 	e.Type()
@@ -63,6 +65,8 @@ type ExprZExt struct {
 // NewZExt returns a new zext expression based on the given source value and
 // target type.
 func NewZExt(from Constant, to types.Type) *ExprZExt {
+	// Validate source and target types.
+	checkConv("zext", from.Type(), to)
 	e := &ExprZExt{From: from, To: to}
 	// Compute type.
 	e.Type()
@@ -105,6 +109,8 @@ type ExprSExt struct {
 // NewSExt returns a new sext expression based on the given source value and
 // target type.
 func NewSExt(from Constant, to types.Type) *ExprSExt {
+	// Validate source and target types.
+	checkConv("sext", from.Type(), to)
 	e := &ExprSExt{From: from, To: to}
 	// Compute type.
 	e.Type()
@@ -147,6 +153,8 @@ type ExprFPTrunc struct {
 // NewFPTrunc returns a new fptrunc expression based on the given source value
 // and target type.
 func NewFPTrunc(from Constant, to types.Type) *ExprFPTrunc {
+	// Validate source and target types.
+	checkConv("fptrunc", from.Type(), to)
 	e := &ExprFPTrunc{From: from, To: to}
 	// Compute type.
 	e.Type()
@@ -189,6 +197,8 @@ type ExprFPExt struct {
 // NewFPExt returns a new fpext expression based on the given source value and
 // target type.
 func NewFPExt(from Constant, to types.Type) *ExprFPExt {
+	// Validate source and target types.
+	checkConv("fpext", from.Type(), to)
 	e := &ExprFPExt{From: from, To: to}
 	// Compute type.
 	e.Type()
@@ -231,6 +241,8 @@ type ExprFPToUI struct {
 // NewFPToUI returns a new fptoui expression based on the given source value and
 // target type.
 func NewFPToUI(from Constant, to types.Type) *ExprFPToUI {
+	// Validate source and target types.
+	checkConv("fptoui", from.Type(), to)
 	e := &ExprFPToUI{From: from, To: to}
 	// Compute type.
 	e.Type()
@@ -273,6 +285,8 @@ type ExprFPToSI struct {
 // NewFPToSI returns a new fptosi expression based on the given source value and
 // target type.
 func NewFPToSI(from Constant, to types.Type) *ExprFPToSI {
+	// Validate source and target types.
+	checkConv("fptosi", from.Type(), to)
 	e := &ExprFPToSI{From: from, To: to}
 	// Compute type.
 	e.Type()
@@ -315,6 +329,8 @@ type ExprUIToFP struct {
 // NewUIToFP returns a new uitofp expression based on the given source value and
 // target type.
 func NewUIToFP(from Constant, to types.Type) *ExprUIToFP {
+	// Validate source and target types.
+	checkConv("uitofp", from.Type(), to)
 	e := &ExprUIToFP{From: from, To: to}
 	// Compute type.
 	e.Type()
@@ -357,6 +373,8 @@ type ExprSIToFP struct {
 // NewSIToFP returns a new sitofp expression based on the given source value and
 // target type.
 func NewSIToFP(from Constant, to types.Type) *ExprSIToFP {
+	// Validate source and target types.
+	checkConv("sitofp", from.Type(), to)
 	e := &ExprSIToFP{From: from, To: to}
 	// Compute type.
 	e.Type()
@@ -399,6 +417,8 @@ type ExprPtrToInt struct {
 // NewPtrToInt returns a new ptrtoint expression based on the given source value
 // and target type.
 func NewPtrToInt(from Constant, to types.Type) *ExprPtrToInt {
+	// Validate source and target types.
+	checkConv("ptrtoint", from.Type(), to)
 	e := &ExprPtrToInt{From: from, To: to}
 	// Compute type.
 	e.Type()
@@ -441,6 +461,8 @@ type ExprIntToPtr struct {
 // NewIntToPtr returns a new inttoptr expression based on the given source value
 // and target type.
 func NewIntToPtr(from Constant, to types.Type) *ExprIntToPtr {
+	// Validate source and target types.
+	checkConv("inttoptr", from.Type(), to)
 	e := &ExprIntToPtr{From: from, To: to}
 	// Compute type.
 	e.Type()
@@ -483,6 +505,8 @@ type ExprBitCast struct {
 // NewBitCast returns a new bitcast expression based on the given source value
 // and target type.
 func NewBitCast(from Constant, to types.Type) *ExprBitCast {
+	// Validate source and target types.
+	checkConv("bitcast", from.Type(), to)
 	e := &ExprBitCast{From: from, To: to}
 	// Compute type.
 	e.Type()
@@ -525,6 +549,8 @@ type ExprAddrSpaceCast struct {
 // NewAddrSpaceCast returns a new addrspacecast expression based on the given
 // source value and target type.
 func NewAddrSpaceCast(from Constant, to types.Type) *ExprAddrSpaceCast {
+	// Validate source and target types.
+	checkConv("addrspacecast", from.Type(), to)
 	e := &ExprAddrSpaceCast{From: from, To: to}
 	// Compute type.
 	e.Type()
@@ -553,3 +579,132 @@ func (e *ExprAddrSpaceCast) Ident() string {
 func (e *ExprAddrSpaceCast) Simplify() Constant {
 	panic("not yet implemented")
 }
+
+// --- [ Conversion validation ] -----------------------------------------------
+
+// ValidConv reports whether a value of the source type may be converted to the
+// target type using the given conversion operation; e.g. "trunc" or "bitcast".
+func ValidConv(op string, from, to types.Type) bool {
+	if op == "bitcast" {
+		fromPtr, fromIsPtr := ptrElem(from)
+		toPtr, toIsPtr := ptrElem(to)
+		switch {
+		case fromIsPtr && toIsPtr:
+			return vectorLen(from) == vectorLen(to) && fromPtr.AddrSpace == toPtr.AddrSpace
+		case fromIsPtr || toIsPtr:
+			return false
+		}
+		size := bitSize(from)
+		return size != 0 && size == bitSize(to)
+	}
+	// Non-bitcast conversions operate element-wise on vectors of equal length.
+	if vectorLen(from) != vectorLen(to) {
+		return false
+	}
+	from, to = scalarType(from), scalarType(to)
+	switch op {
+	case "trunc", "zext", "sext":
+		fromInt, ok := from.(*types.IntType)
+		if !ok {
+			return false
+		}
+		toInt, ok := to.(*types.IntType)
+		if !ok {
+			return false
+		}
+		if op == "trunc" {
+			return fromInt.BitSize > toInt.BitSize
+		}
+		return fromInt.BitSize < toInt.BitSize
+	case "fptrunc", "fpext":
+		if !types.IsFloat(from) || !types.IsFloat(to) {
+			return false
+		}
+		if op == "fptrunc" {
+			return bitSize(from) > bitSize(to)
+		}
+		return bitSize(from) < bitSize(to)
+	case "fptoui", "fptosi":
+		return types.IsFloat(from) && types.IsInt(to)
+	case "uitofp", "sitofp":
+		return types.IsInt(from) && types.IsFloat(to)
+	case "ptrtoint":
+		return types.IsPointer(from) && types.IsInt(to)
+	case "inttoptr":
+		return types.IsInt(from) && types.IsPointer(to)
+	case "addrspacecast":
+		fromPtr, ok := from.(*types.PointerType)
+		if !ok {
+			return false
+		}
+		toPtr, ok := to.(*types.PointerType)
+		if !ok {
+			return false
+		}
+		return fromPtr.AddrSpace != toPtr.AddrSpace
+	default:
+		panic(fmt.Errorf("support for conversion operation %q not yet implemented", op))
+	}
+}
+
+// ### [ Helper functions ] ####################################################
+
+// checkConv panics if a value of the source type cannot be converted to the
+// target type using the given conversion operation.
+func checkConv(op string, from, to types.Type) {
+	if !ValidConv(op, from, to) {
+		panic(fmt.Errorf("invalid %s expression; unable to convert from `%s` to `%s`", op, from, to))
+	}
+}
+
+// ptrElem returns the pointer type of the given pointer or vector of pointers
+// type, and a boolean indicating if the type was a pointer type.
+func ptrElem(t types.Type) (*types.PointerType, bool) {
+	ptr, ok := scalarType(t).(*types.PointerType)
+	return ptr, ok
+}
+
+// scalarType returns the element type of the given vector type, or the type
+// itself if not a vector type.
+func scalarType(t types.Type) types.Type {
+	if t, ok := t.(*types.VectorType); ok {
+		return t.ElemType
+	}
+	return t
+}
+
+// vectorLen returns the length of the given vector type; or 0 if not a vector
+// type.
+func vectorLen(t types.Type) uint64 {
+	if t, ok := t.(*types.VectorType); ok {
+		return t.Len
+	}
+	return 0
+}
+
+// bitSize returns the size in bits of the given first-class non-pointer type;
+// or 0 if unknown.
+func bitSize(t types.Type) uint64 {
+	switch t := t.(type) {
+	case *types.IntType:
+		return t.BitSize
+	case *types.FloatType:
+		switch t.Kind {
+		case types.FloatKindHalf:
+			return 16
+		case types.FloatKindFloat:
+			return 32
+		case types.FloatKindDouble:
+			return 64
+		case types.FloatKindX86_FP80:
+			return 80
+		case types.FloatKindFP128, types.FloatKindPPC_FP128:
+			return 128
+		}
+	case *types.MMXType:
+		return 64
+	case *types.VectorType:
+		return t.Len * bitSize(t.ElemType)
+	}
+	return 0
+}
@@ -63,7 +63,7 @@ func (e *ExprGetElementPtr) Type() types.Type {
 	}
 	// Cache type if not present.
 	if e.Typ == nil {
-		e.Typ = gepType(e.ElemType, e.Indices)
+		e.Typ = gepType(e.ElemType, e.Src.Type(), e.Indices)
 	}
 	return e.Typ
 }
@@ -87,8 +87,26 @@ func (e *ExprGetElementPtr) Ident() string {
 
 // Simplify returns an equivalent (and potentially simplified) constant to the
 // constant expression.
+//
+// The getelementptr expression is folded to a poison value if the source
+// address or any of the indices is a poison value, and to an undefined value if
+// the source address is undefined.
 func (e *ExprGetElementPtr) Simplify() Constant {
-	panic("not yet implemented")
+	if _, ok := e.Src.(*Poison); ok {
+		return NewPoison(e.Type())
+	}
+	for _, index := range e.Indices {
+		if idx, ok := index.(*Index); ok {
+			index = idx.Constant
+		}
+		if _, ok := index.(*Poison); ok {
+			return NewPoison(e.Type())
+		}
+	}
+	if _, ok := e.Src.(*Undef); ok {
+		return NewUndef(e.Type())
+	}
+	return e
 }
 
 // ___ [ gep indices ] _________________________________________________________
@@ -124,8 +142,8 @@ func (index *Index) String() string {
 
 // gepType returns the pointer type or vector of pointers type to the element at
 // the position in the type specified by the given indices, as calculated by the
-// getelementptr instruction.
-func gepType(elemType types.Type, indices []Constant) types.Type {
+// getelementptr instruction with the given source address type.
+func gepType(elemType, srcType types.Type, indices []Constant) types.Type {
 	e := elemType
 	for i, index := range indices {
 		// unpack inrange indices.
@@ -180,14 +198,17 @@ func gepType(elemType types.Type, indices []Constant) types.Type {
 			panic(fmt.Errorf("support for indexing element type %T not yet implemented", e))
 		}
 	}
-	// TODO: Validate how index vectors in gep are supposed to work.
+	// The result is a vector of pointers if the source address or any of the
+	// indices is a vector.
 	//
 	// Example from dir.ll:
 	//    %113 = getelementptr inbounds %struct.fileinfo, %struct.fileinfo* %96, <2 x i64> %110, !dbg !4736
 	//    %116 = bitcast i8** %115 to <2 x %struct.fileinfo*>*, !dbg !4738
 	//    store <2 x %struct.fileinfo*> %113, <2 x %struct.fileinfo*>* %116, align 8, !dbg !4738, !tbaa !1793
-	if len(indices) > 0 {
-		index := indices[0]
+	if t, ok := srcType.(*types.VectorType); ok {
+		return types.NewVector(t.Len, types.NewPointer(e))
+	}
+	for _, index := range indices {
 		// unpack inrange index.
 		if idx, ok := index.(*Index); ok {
 			index = idx.Constant
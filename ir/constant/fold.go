@@ -0,0 +1,83 @@
+package constant
+
+import (
+	"github.com/llir/llvm/ir/types"
+)
+
+// === [ Constant folding ] ====================================================
+
+// Fold returns an equivalent (and potentially folded) constant to the given
+// constant.
+//
+// Getelementptr expressions with constant integer indices are folded as
+// follows. A getelementptr expression of which the source address is in turn a
+// getelementptr expression is merged into one if the first index is zero; the
+// merged expression is inbounds only if both were inbounds. A getelementptr
+// expression of which all indices are zero is folded to its source address,
+// which is bit-identical; a bitcast of the source address is used if the types
+// differ. Getelementptr expressions with non-constant or inrange indices, or
+// operating on vectors of pointers, are not folded.
+//
+// Constants other than getelementptr expressions are returned as is.
+func Fold(c Constant) Constant {
+	e, ok := c.(*ExprGetElementPtr)
+	if !ok {
+		return c
+	}
+	if c := e.Simplify(); c != e {
+		return c
+	}
+	if !foldableGEP(e) {
+		return e
+	}
+	src := Fold(e.Src)
+	// Merge nested getelementptr expressions.
+	if inner, ok := src.(*ExprGetElementPtr); ok && foldableGEP(inner) && len(e.Indices) > 0 && isZeroIndex(e.Indices[0]) {
+		indices := append(append([]Constant(nil), inner.Indices...), e.Indices[1:]...)
+		merged := &ExprGetElementPtr{ElemType: inner.ElemType, Src: inner.Src, Indices: indices, InBounds: inner.InBounds && e.InBounds}
+		// Compute type.
+		merged.Type()
+		return Fold(merged)
+	}
+	// Fold getelementptr expressions with zero offset to their source address.
+	for _, index := range e.Indices {
+		if isZeroIndex(index) {
+			continue
+		}
+		if src == e.Src {
+			return e
+		}
+		return &ExprGetElementPtr{ElemType: e.ElemType, Src: src, Indices: e.Indices, Typ: e.Typ, InBounds: e.InBounds}
+	}
+	if src.Type().Equal(e.Type()) {
+		return src
+	}
+	return NewBitCast(src, e.Type())
+}
+
+// ### [ Helper functions ] ####################################################
+
+// foldableGEP reports whether the given getelementptr expression may be folded;
+// i.e. it has constant integer indices without inrange, and both the source
+// address and the result are pointers (not vectors of pointers).
+func foldableGEP(e *ExprGetElementPtr) bool {
+	if _, ok := e.Src.Type().(*types.PointerType); !ok {
+		return false
+	}
+	if _, ok := e.Type().(*types.PointerType); !ok {
+		return false
+	}
+	for _, index := range e.Indices {
+		if _, ok := index.(*Int); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// isZeroIndex reports whether the given getelementptr index is a constant zero
+// integer.
+func isZeroIndex(index Constant) bool {
+	i, ok := index.(*Int)
+	return ok && i.X.Sign() == 0
+}
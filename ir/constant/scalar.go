@@ -0,0 +1,69 @@
+package constant
+
+import (
+	"math"
+
+	"github.com/llir/llvm/ir/value"
+)
+
+// AsInt returns the integer value of the given value, and a boolean indicating
+// whether the value is an integer constant. Vector constants of integers are
+// treated as integer constants if all elements are equal (i.e. splats).
+//
+// Null and undef constants have no integer value, and are reported as not
+// being integer constants. Integer constants which do not fit in 64 bits are
+// also reported as not being integer constants.
+func AsInt(v value.Value) (int64, bool) {
+	switch c := splat(v).(type) {
+	case *Int:
+		if c.X.IsInt64() {
+			return c.X.Int64(), true
+		}
+		// Unsigned representation of i64 constant (e.g. 0xFFFFFFFFFFFFFFFF).
+		if c.Typ.BitSize <= 64 && c.X.IsUint64() {
+			return int64(c.X.Uint64()), true
+		}
+	}
+	return 0, false
+}
+
+// AsFloat returns the floating-point value of the given value, and a boolean
+// indicating whether the value is a floating-point constant. Vector constants
+// of floating-point values are treated as floating-point constants if all
+// elements are equal (i.e. splats).
+//
+// Null and undef constants have no floating-point value, and are reported as
+// not being floating-point constants.
+func AsFloat(v value.Value) (float64, bool) {
+	switch c := splat(v).(type) {
+	case *Float:
+		if c.NaN {
+			return math.NaN(), true
+		}
+		x, _ := c.X.Float64()
+		return x, true
+	}
+	return 0, false
+}
+
+// ### [ Helper functions ] ####################################################
+
+// splat returns the element of the given vector constant if all elements are
+// equal; or the value itself if not a vector constant. A nil value is returned
+// if the elements of the vector constant differ.
+func splat(v value.Value) value.Value {
+	vec, ok := v.(*Vector)
+	if !ok {
+		return v
+	}
+	if len(vec.Elems) == 0 {
+		return nil
+	}
+	elem := vec.Elems[0]
+	for _, e := range vec.Elems[1:] {
+		if e.Ident() != elem.Ident() {
+			return nil
+		}
+	}
+	return elem
+}
@@ -42,10 +42,22 @@ func (*ZeroInitializer) IsConstant() {}
 // constant.Constant interface.
 func (*Undef) IsConstant() {}
 
+// IsConstant ensures that only constants can be assigned to the
+// constant.Constant interface.
+func (*Poison) IsConstant() {}
+
 // IsConstant ensures that only constants can be assigned to the
 // constant.Constant interface.
 func (*BlockAddress) IsConstant() {}
 
+// IsConstant ensures that only constants can be assigned to the
+// constant.Constant interface.
+func (*DSOLocalEquivalent) IsConstant() {}
+
+// IsConstant ensures that only constants can be assigned to the
+// constant.Constant interface.
+func (*NoCFI) IsConstant() {}
+
 // --- [ Binary expressions ] --------------------------------------------------
 
 // IsConstant ensures that only constants can be assigned to the
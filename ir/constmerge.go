@@ -0,0 +1,77 @@
+package ir
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+)
+
+// === [ Constant merging ] ====================================================
+
+// MergeConstantGlobals merges the constant global variables of the given module
+// which have identical content types and initializers into one, and redirects
+// uses of the merged global variables to the remaining one (mirroring the
+// -constmerge pass of LLVM).
+//
+// Only global variable definitions with private or internal linkage and the
+// unnamed_addr attribute are merged, as the addresses of such global variables
+// are not significant; e.g. string literals.
+func MergeConstantGlobals(m *Module) {
+	// Locate global variables to merge.
+	canon := make(map[string]*Global)
+	repl := make(map[constant.Constant]constant.Constant)
+	globals := m.Globals[:0]
+	for _, g := range m.Globals {
+		if !isMergeable(g) {
+			globals = append(globals, g)
+			continue
+		}
+		key := mergeKey(g)
+		if prev, ok := canon[key]; ok {
+			if g.Align > prev.Align {
+				prev.Align = g.Align
+			}
+			repl[g] = prev
+			continue
+		}
+		canon[key] = g
+		globals = append(globals, g)
+	}
+	for i := len(globals); i < len(m.Globals); i++ {
+		m.Globals[i] = nil
+	}
+	m.Globals = globals
+	if len(repl) == 0 {
+		return
+	}
+	// Redirect uses of merged global variables.
+	rewriteConstantUses(m, func(c constant.Constant) (constant.Constant, bool) {
+		new, ok := repl[c]
+		return new, ok
+	})
+}
+
+// ### [ Helper functions ] ####################################################
+
+// isMergeable reports whether the given global variable may be merged with
+// other global variables of identical contents.
+func isMergeable(g *Global) bool {
+	if !g.Immutable || g.Init == nil {
+		return false
+	}
+	switch g.Linkage {
+	case enum.LinkagePrivate, enum.LinkageInternal:
+		// valid linkage.
+	default:
+		return false
+	}
+	return g.UnnamedAddr == enum.UnnamedAddrUnnamedAddr
+}
+
+// mergeKey returns a key of the given global variable, which is identical for
+// global variables that may be merged.
+func mergeKey(g *Global) string {
+	// The pointer type includes the address space of the global variable.
+	return fmt.Sprintf("%s|%s %s|%s|%d|%t", g.Type(), g.ContentType, g.Init, g.Section, g.TLSModel, g.ExternallyInitialized)
+}
@@ -0,0 +1,50 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestMergeConstantGlobals(t *testing.T) {
+	m := NewModule()
+	var strs []*Global
+	for _, name := range []string{".str", ".str.1"} {
+		g := m.NewGlobalDef(name, constant.NewCharArrayFromString("hello\x00"))
+		g.Immutable = true
+		g.Linkage = enum.LinkagePrivate
+		g.UnnamedAddr = enum.UnnamedAddrUnnamedAddr
+		strs = append(strs, g)
+	}
+	zero := constant.NewInt(types.I64, 0)
+	m.NewGlobalDef("ptr", constant.NewGetElementPtr(strs[1], zero, zero))
+	puts := m.NewFunc("puts", types.I32, NewParam("", types.I8Ptr))
+	f := m.NewFunc("main", types.I32)
+	entry := f.NewBlock("")
+	entry.NewCall(puts, constant.NewGetElementPtr(strs[0], zero, zero))
+	s := entry.NewGetElementPtr(strs[1], zero, zero)
+	entry.NewCall(puts, s)
+	entry.NewRet(constant.NewInt(types.I32, 0))
+	MergeConstantGlobals(m)
+	if len(m.Globals) != 2 {
+		t.Fatalf("number of global variables mismatch; expected 2, got %d", len(m.Globals))
+	}
+	want := `@.str = private unnamed_addr constant [6 x i8] c"hello\00"
+@ptr = global i8* getelementptr ([6 x i8], [6 x i8]* @.str, i64 0, i64 0)
+
+declare i32 @puts(i8*)
+
+define i32 @main() {
+; <label>:0
+	%1 = call i32 @puts(i8* getelementptr ([6 x i8], [6 x i8]* @.str, i64 0, i64 0))
+	%2 = getelementptr [6 x i8], [6 x i8]* @.str, i64 0, i64 0
+	%3 = call i32 @puts(i8* %2)
+	ret i32 0
+}
+`
+	if got := m.String(); want != got {
+		t.Errorf("module mismatch; expected `%v`, got `%v`", want, got)
+	}
+}
@@ -0,0 +1,630 @@
+package ir
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/pkg/errors"
+)
+
+// === [ Data layout ] =========================================================
+
+// DataLayout is a parsed target data layout, which specifies how data is laid
+// out in memory.
+//
+// ref: https://llvm.org/docs/LangRef.html#data-layout
+type DataLayout struct {
+	// Big-endian byte order.
+	BigEndian bool
+	// Natural stack alignment in bytes; or 0 if unspecified.
+	StackAlign uint64
+	// Name mangling mode (e.g. "e" for ELF mangling); or empty if unspecified.
+	Mangling string
+	// Native integer widths in bits.
+	NativeIntWidths []uint64
+	// Pointer sizes and alignments, keyed by address space.
+	Pointers map[types.AddrSpace]PointerSpec
+	// Alignments of integer, floating-point and vector types, keyed by bit
+	// size.
+	Ints, Floats, Vectors map[uint64]AlignSpec
+	// Alignment of aggregate types.
+	Aggregate AlignSpec
+	// Alignment of function pointers in bytes; or 0 if unspecified.
+	FuncPtrAlign uint64
+	// Function pointer alignment is a multiple of the function alignment,
+	// rather than independent of it.
+	FuncPtrAlignMultiple bool
+	// Address space of program memory, allocas and global variables.
+	ProgramAddrSpace, AllocaAddrSpace, GlobalsAddrSpace types.AddrSpace
+	// Address spaces of non-integral pointers.
+	NonIntegralAddrSpaces []types.AddrSpace
+
+	// Specifications in order of occurrence; used to re-emit the data layout
+	// string.
+	specs []layoutSpec
+}
+
+// layoutSpec is a data layout specification as it occurs in the data layout
+// string.
+type layoutSpec struct {
+	// Specification prefix (e.g. "e", "p270", "i64").
+	prefix string
+	// Original text of the specification (e.g. "i1:8:8").
+	text string
+}
+
+// AlignSpec is an ABI and preferred alignment in bytes.
+type AlignSpec struct {
+	// ABI alignment in bytes.
+	ABI uint64
+	// Preferred alignment in bytes.
+	Pref uint64
+}
+
+// PointerSpec is the size and alignment of pointers in an address space.
+type PointerSpec struct {
+	// Pointer size in bits.
+	Size uint64
+	// Pointer alignment.
+	Align AlignSpec
+	// Size in bits of indices used for address calculation.
+	IndexSize uint64
+}
+
+// ParseDataLayout parses the given data layout string (e.g. the contents of the
+// target datalayout of a module). Alignments and sizes not specified by the
+// data layout string use the defaults of LLVM.
+func ParseDataLayout(s string) (*DataLayout, error) {
+	dl := &DataLayout{
+		Ints: map[uint64]AlignSpec{
+			1:  {ABI: 1, Pref: 1},
+			8:  {ABI: 1, Pref: 1},
+			16: {ABI: 2, Pref: 2},
+			32: {ABI: 4, Pref: 4},
+			64: {ABI: 4, Pref: 8},
+		},
+		Floats: map[uint64]AlignSpec{
+			16:  {ABI: 2, Pref: 2},
+			32:  {ABI: 4, Pref: 4},
+			64:  {ABI: 8, Pref: 8},
+			128: {ABI: 16, Pref: 16},
+		},
+		Vectors: map[uint64]AlignSpec{
+			64:  {ABI: 8, Pref: 8},
+			128: {ABI: 16, Pref: 16},
+		},
+		Aggregate: AlignSpec{ABI: 0, Pref: 8},
+		Pointers: map[types.AddrSpace]PointerSpec{
+			0: {Size: 64, Align: AlignSpec{ABI: 8, Pref: 8}, IndexSize: 64},
+		},
+	}
+	if len(s) == 0 {
+		return dl, nil
+	}
+	for _, spec := range strings.Split(s, "-") {
+		if err := dl.parseSpec(spec); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return dl, nil
+}
+
+// String returns the data layout string of the data layout. Specifications are
+// emitted in the order they were parsed, using their original text unless the
+// fields they specify have since been changed. Changed specifications omit
+// preferred alignments and pointer index sizes when equal to the ABI alignment
+// and pointer size respectively.
+func (dl *DataLayout) String() string {
+	specs := make([]string, len(dl.specs))
+	for i, spec := range dl.specs {
+		specs[i] = dl.specText(spec)
+	}
+	return strings.Join(specs, "-")
+}
+
+// Sizeof returns the allocation size in bytes of the given type; i.e. the
+// offset in bytes between successive objects of the type, including alignment
+// padding.
+func (dl *DataLayout) Sizeof(t types.Type) uint64 {
+	return alignTo(dl.storeSize(t), dl.Alignof(t))
+}
+
+// Alignof returns the ABI alignment in bytes of the given type.
+func (dl *DataLayout) Alignof(t types.Type) uint64 {
+	switch t := t.(type) {
+	case *types.IntType:
+		return dl.intAlign(t.BitSize)
+	case *types.FloatType:
+		if spec, ok := dl.Floats[dl.sizeInBits(t)]; ok {
+			return spec.ABI
+		}
+		return powerOf2Ceil(dl.storeSize(t))
+	case *types.MMXType:
+		if spec, ok := dl.Vectors[64]; ok {
+			return spec.ABI
+		}
+		return 8
+	case *types.PointerType:
+		return dl.pointer(t.AddrSpace).Align.ABI
+	case *types.VectorType:
+		if spec, ok := dl.Vectors[dl.sizeInBits(t)]; ok {
+			return spec.ABI
+		}
+		// Use natural alignment of vector types without explicit alignment.
+		return powerOf2Ceil(dl.storeSize(t))
+	case *types.ArrayType:
+		return dl.Alignof(t.ElemType)
+	case *types.StructType:
+		if t.Packed {
+			return 1
+		}
+		_, align := dl.structLayout(t)
+		if dl.Aggregate.ABI > align {
+			return dl.Aggregate.ABI
+		}
+		return align
+	default:
+		panic(fmt.Errorf("unable to compute alignment of unsized type %v", t))
+	}
+}
+
+// Offsetof returns the offset in bytes of the specified field of the given
+// struct type.
+func (dl *DataLayout) Offsetof(t *types.StructType, field int) uint64 {
+	if field < 0 || field >= len(t.Fields) {
+		panic(fmt.Errorf("invalid field index %d of struct type %v with %d fields", field, t, len(t.Fields)))
+	}
+	offsets, _ := dl.structLayout(t)
+	return offsets[field]
+}
+
+// === [ Getelementptr offsets ] ===============================================
+
+// GEPByteOffset returns the offset in bytes of the address computed by the
+// given getelementptr instruction relative to its source address, based on the
+// element layouts of the data layout. An error is returned if any index of the
+// instruction is not a constant integer.
+func GEPByteOffset(gep *InstGetElementPtr, dl *DataLayout) (int64, error) {
+	offset := int64(0)
+	t := gep.ElemType
+	for i, index := range gep.Indices {
+		idx, ok := index.(*constant.Int)
+		if !ok || !idx.X.IsInt64() {
+			return 0, errors.Errorf("invalid index %v of getelementptr instruction %q; expected constant integer", index, gep.Ident())
+		}
+		n := idx.X.Int64()
+		// The first index steps through the source address.
+		if i == 0 {
+			offset += n * int64(dl.Sizeof(t))
+			continue
+		}
+		switch typ := t.(type) {
+		case *types.StructType:
+			if n < 0 || n >= int64(len(typ.Fields)) {
+				return 0, errors.Errorf("invalid field index %d of struct type %v in getelementptr instruction %q", n, typ, gep.Ident())
+			}
+			offset += int64(dl.Offsetof(typ, int(n)))
+			t = typ.Fields[n]
+		case *types.ArrayType:
+			t = typ.ElemType
+			offset += n * int64(dl.Sizeof(t))
+		case *types.VectorType:
+			t = typ.ElemType
+			offset += n * int64(dl.Sizeof(t))
+		default:
+			return 0, errors.Errorf("invalid index into non-aggregate type %v of getelementptr instruction %q", t, gep.Ident())
+		}
+	}
+	return offset, nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// parseSpec parses the given data layout specification.
+func (dl *DataLayout) parseSpec(spec string) error {
+	if len(spec) == 0 {
+		return errors.Errorf("invalid empty data layout specification")
+	}
+	// Specification prefix (e.g. "p270" or "i64"), recorded along with the
+	// original text to re-emit the data layout string.
+	prefix := spec
+	if i := strings.IndexByte(spec, ':'); i != -1 {
+		prefix = spec[:i]
+	}
+	switch kind, rest := spec[0], spec[1:]; {
+	case kind == 'e' && len(rest) == 0:
+		dl.BigEndian = false
+	case kind == 'E' && len(rest) == 0:
+		dl.BigEndian = true
+	case kind == 'S':
+		n, err := parseBits(spec, rest)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		dl.StackAlign = n / 8
+	case kind == 'm':
+		// m:<mangling>
+		if len(rest) < 2 || rest[0] != ':' {
+			return errors.Errorf("invalid mangling data layout specification %q; expected mangling mode", spec)
+		}
+		dl.Mangling = rest[1:]
+		prefix = "m"
+	case kind == 'p':
+		// p[n]:<size>:<abi>[:<pref>][:<idx>]
+		fields := strings.Split(rest, ":")
+		addrSpace, err := parseAddrSpace(spec, fields[0])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if len(fields) < 3 || len(fields) > 5 {
+			return errors.Errorf("invalid pointer data layout specification %q; expected size and alignment", spec)
+		}
+		size, err := parseBits(spec, fields[1])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		align, err := parseAlign(spec, fields[2:])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		indexSize := size
+		if len(fields) == 5 {
+			if indexSize, err = parseBits(spec, fields[4]); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		dl.Pointers[addrSpace] = PointerSpec{Size: size, Align: align, IndexSize: indexSize}
+	case kind == 'i', kind == 'f', kind == 'v':
+		// i<size>:<abi>[:<pref>]
+		fields := strings.Split(rest, ":")
+		if len(fields) < 2 || len(fields) > 3 {
+			return errors.Errorf("invalid data layout specification %q; expected size and alignment", spec)
+		}
+		size, err := parseBits(spec, fields[0])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		align, err := parseAlign(spec, fields[1:])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		switch kind {
+		case 'i':
+			dl.Ints[size] = align
+		case 'f':
+			dl.Floats[size] = align
+		case 'v':
+			dl.Vectors[size] = align
+		}
+	case kind == 'a':
+		// a[0]:<abi>[:<pref>]
+		fields := strings.Split(rest, ":")
+		if len(fields) < 2 || len(fields) > 3 || (fields[0] != "" && fields[0] != "0") {
+			return errors.Errorf("invalid aggregate data layout specification %q; expected alignment", spec)
+		}
+		align, err := parseAlign(spec, fields[1:])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		dl.Aggregate = align
+	case strings.HasPrefix(spec, "ni:"):
+		// ni:<address space>[:<address space>]...
+		dl.NonIntegralAddrSpaces = nil
+		for _, field := range strings.Split(spec[len("ni:"):], ":") {
+			addrSpace, err := parseAddrSpace(spec, field)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			dl.NonIntegralAddrSpaces = append(dl.NonIntegralAddrSpaces, addrSpace)
+		}
+	case kind == 'n':
+		// n<size>[:<size>]...
+		dl.NativeIntWidths = nil
+		for _, field := range strings.Split(rest, ":") {
+			n, err := parseBits(spec, field)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			dl.NativeIntWidths = append(dl.NativeIntWidths, n)
+		}
+		prefix = "n"
+	case kind == 'F':
+		// F<type><abi>
+		if len(rest) < 2 || (rest[0] != 'i' && rest[0] != 'n') {
+			return errors.Errorf("invalid function pointer alignment data layout specification %q; expected i or n", spec)
+		}
+		align, err := parseAlign(spec, []string{rest[1:]})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		dl.FuncPtrAlign = align.ABI
+		dl.FuncPtrAlignMultiple = rest[0] == 'n'
+		prefix = "F"
+	case kind == 'P', kind == 'A', kind == 'G':
+		// P<address space>
+		addrSpace, err := parseAddrSpace(spec, rest)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		switch kind {
+		case 'P':
+			dl.ProgramAddrSpace = addrSpace
+		case 'A':
+			dl.AllocaAddrSpace = addrSpace
+		case 'G':
+			dl.GlobalsAddrSpace = addrSpace
+		}
+		prefix = string(kind)
+	case kind == 's':
+		// s[<size>]:<abi>[:<pref>]
+		//
+		// Deprecated stack object alignment; ignored by LLVM, but accepted to
+		// load data layout strings of older LLVM IR assembly files.
+		prefix = "s"
+	default:
+		return errors.Errorf("invalid data layout specification %q; unknown specifier %q", spec, kind)
+	}
+	dl.specs = append(dl.specs, layoutSpec{prefix: prefix, text: spec})
+	return nil
+}
+
+// specText returns the text of the given data layout specification; the
+// original text if the fields specified are unchanged since parsing, and the
+// data layout specification of the current fields otherwise.
+func (dl *DataLayout) specText(spec layoutSpec) string {
+	if spec.prefix == "s" {
+		// Deprecated specifications have no fields.
+		return spec.text
+	}
+	// Parsing of the empty data layout string never fails.
+	orig, _ := ParseDataLayout("")
+	s := dl.specString(spec.prefix)
+	if err := orig.parseSpec(spec.text); err == nil && orig.specString(spec.prefix) == s {
+		return spec.text
+	}
+	return s
+}
+
+// specString returns the data layout specification with the given prefix.
+func (dl *DataLayout) specString(prefix string) string {
+	switch kind, rest := prefix[0], prefix[1:]; {
+	case kind == 'e', kind == 'E':
+		if dl.BigEndian {
+			return "E"
+		}
+		return "e"
+	case kind == 'S':
+		return fmt.Sprintf("S%d", dl.StackAlign*8)
+	case kind == 'm':
+		return fmt.Sprintf("m:%s", dl.Mangling)
+	case kind == 'p':
+		addrSpace, _ := strconv.ParseUint(rest, 10, 24)
+		spec := dl.pointer(types.AddrSpace(addrSpace))
+		s := fmt.Sprintf("%s:%d:%s", prefix, spec.Size, spec.Align.bits(spec.IndexSize != spec.Size))
+		if spec.IndexSize != spec.Size {
+			s += fmt.Sprintf(":%d", spec.IndexSize)
+		}
+		return s
+	case kind == 'i', kind == 'f', kind == 'v':
+		size, _ := strconv.ParseUint(rest, 10, 64)
+		var align AlignSpec
+		switch kind {
+		case 'i':
+			align = dl.Ints[size]
+		case 'f':
+			align = dl.Floats[size]
+		case 'v':
+			align = dl.Vectors[size]
+		}
+		return fmt.Sprintf("%s:%s", prefix, align.bits(false))
+	case kind == 'a':
+		return fmt.Sprintf("%s:%s", prefix, dl.Aggregate.bits(false))
+	case prefix == "ni":
+		buf := &strings.Builder{}
+		buf.WriteString("ni")
+		for _, addrSpace := range dl.NonIntegralAddrSpaces {
+			fmt.Fprintf(buf, ":%d", uint64(addrSpace))
+		}
+		return buf.String()
+	case kind == 'n':
+		widths := make([]string, len(dl.NativeIntWidths))
+		for i, n := range dl.NativeIntWidths {
+			widths[i] = strconv.FormatUint(n, 10)
+		}
+		return "n" + strings.Join(widths, ":")
+	case kind == 'F':
+		if dl.FuncPtrAlignMultiple {
+			return fmt.Sprintf("Fn%d", dl.FuncPtrAlign*8)
+		}
+		return fmt.Sprintf("Fi%d", dl.FuncPtrAlign*8)
+	case kind == 'P':
+		return fmt.Sprintf("P%d", uint64(dl.ProgramAddrSpace))
+	case kind == 'A':
+		return fmt.Sprintf("A%d", uint64(dl.AllocaAddrSpace))
+	case kind == 'G':
+		return fmt.Sprintf("G%d", uint64(dl.GlobalsAddrSpace))
+	default:
+		panic(fmt.Errorf("support for data layout specification %q not yet implemented", prefix))
+	}
+}
+
+// pointer returns the size and alignment of pointers in the given address
+// space. Address spaces without explicit specification use that of the default
+// address space.
+func (dl *DataLayout) pointer(addrSpace types.AddrSpace) PointerSpec {
+	if spec, ok := dl.Pointers[addrSpace]; ok {
+		return spec
+	}
+	return dl.Pointers[0]
+}
+
+// intAlign returns the ABI alignment in bytes of integers with the given bit
+// size. Integer types without explicit alignment use the alignment of the next
+// larger integer type; or of the largest integer type if none is larger.
+func (dl *DataLayout) intAlign(bitSize uint64) uint64 {
+	if spec, ok := dl.Ints[bitSize]; ok {
+		return spec.ABI
+	}
+	var larger, largest uint64
+	for size := range dl.Ints {
+		if size > bitSize && (larger == 0 || size < larger) {
+			larger = size
+		}
+		if size > largest {
+			largest = size
+		}
+	}
+	if larger != 0 {
+		return dl.Ints[larger].ABI
+	}
+	return dl.Ints[largest].ABI
+}
+
+// sizeInBits returns the size in bits of the given type.
+func (dl *DataLayout) sizeInBits(t types.Type) uint64 {
+	switch t := t.(type) {
+	case *types.IntType:
+		return t.BitSize
+	case *types.FloatType:
+		switch t.Kind {
+		case types.FloatKindHalf:
+			return 16
+		case types.FloatKindFloat:
+			return 32
+		case types.FloatKindDouble:
+			return 64
+		case types.FloatKindX86_FP80:
+			return 80
+		case types.FloatKindFP128, types.FloatKindPPC_FP128:
+			return 128
+		default:
+			panic(fmt.Errorf("support for floating-point kind %v not yet implemented", t.Kind))
+		}
+	case *types.MMXType:
+		return 64
+	case *types.PointerType:
+		return dl.pointer(t.AddrSpace).Size
+	case *types.VectorType:
+		return t.Len * dl.sizeInBits(t.ElemType)
+	case *types.ArrayType:
+		return t.Len * dl.Sizeof(t.ElemType) * 8
+	case *types.StructType:
+		return dl.structSize(t) * 8
+	default:
+		panic(fmt.Errorf("unable to compute size of unsized type %v", t))
+	}
+}
+
+// storeSize returns the number of bytes overwritten by a store of the given
+// type.
+func (dl *DataLayout) storeSize(t types.Type) uint64 {
+	return (dl.sizeInBits(t) + 7) / 8
+}
+
+// structLayout returns the field offsets in bytes and the alignment in bytes of
+// the fields of the given struct type.
+func (dl *DataLayout) structLayout(t *types.StructType) (offsets []uint64, align uint64) {
+	if t.Opaque {
+		panic(fmt.Errorf("unable to compute layout of opaque struct type %v", t))
+	}
+	align = 1
+	offset := uint64(0)
+	for _, field := range t.Fields {
+		fieldAlign := uint64(1)
+		if !t.Packed {
+			fieldAlign = dl.Alignof(field)
+		}
+		if fieldAlign > align {
+			align = fieldAlign
+		}
+		offset = alignTo(offset, fieldAlign)
+		offsets = append(offsets, offset)
+		offset += dl.Sizeof(field)
+	}
+	return offsets, align
+}
+
+// structSize returns the size in bytes of the given struct type, including tail
+// padding.
+func (dl *DataLayout) structSize(t *types.StructType) uint64 {
+	offsets, align := dl.structLayout(t)
+	if len(offsets) == 0 {
+		return 0
+	}
+	last := len(t.Fields) - 1
+	size := offsets[last] + dl.Sizeof(t.Fields[last])
+	return alignTo(size, align)
+}
+
+// parseBits parses the given size in bits of the data layout specification.
+func parseBits(spec, s string) (uint64, error) {
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("invalid size of data layout specification %q; %v", spec, err)
+	}
+	return n, nil
+}
+
+// parseAlign parses the given ABI and optional preferred alignments in bits of
+// the data layout specification. Any trailing fields (e.g. index sizes of
+// pointer specifications) are ignored.
+func parseAlign(spec string, fields []string) (AlignSpec, error) {
+	abi, err := parseBits(spec, fields[0])
+	if err != nil {
+		return AlignSpec{}, errors.WithStack(err)
+	}
+	pref := abi
+	if len(fields) > 1 {
+		if pref, err = parseBits(spec, fields[1]); err != nil {
+			return AlignSpec{}, errors.WithStack(err)
+		}
+	}
+	if abi%8 != 0 || pref%8 != 0 {
+		return AlignSpec{}, errors.Errorf("invalid alignment of data layout specification %q; expected multiple of 8 bits", spec)
+	}
+	return AlignSpec{ABI: abi / 8, Pref: pref / 8}, nil
+}
+
+// parseAddrSpace parses the given address space of the data layout
+// specification; an empty string denotes the default address space.
+func parseAddrSpace(spec, s string) (types.AddrSpace, error) {
+	if len(s) == 0 {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 24)
+	if err != nil {
+		return 0, errors.Errorf("invalid address space of data layout specification %q; %v", spec, err)
+	}
+	return types.AddrSpace(n), nil
+}
+
+// bits returns the ABI and preferred alignment in bits, in the form abi[:pref].
+// The preferred alignment is omitted if equal to the ABI alignment, unless
+// forced.
+func (a AlignSpec) bits(forcePref bool) string {
+	if a.Pref != a.ABI || forcePref {
+		return fmt.Sprintf("%d:%d", a.ABI*8, a.Pref*8)
+	}
+	return strconv.FormatUint(a.ABI*8, 10)
+}
+
+// alignTo returns n rounded up to the nearest multiple of align.
+func alignTo(n, align uint64) uint64 {
+	if align == 0 {
+		return n
+	}
+	return (n + align - 1) / align * align
+}
+
+// powerOf2Ceil returns the smallest power of two which is greater than or equal
+// to n.
+func powerOf2Ceil(n uint64) uint64 {
+	p := uint64(1)
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
@@ -0,0 +1,262 @@
+package ir
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestDataLayout(t *testing.T) {
+	const (
+		x86_64 = "e-m:e-i64:64-f80:128-n8:16:32:64-S128"
+		i386   = "e-m:e-p:32:32-f64:32:64-f80:32-n8:16:32-S128"
+		amdgpu = "e-p:64:64-p1:64:64-p2:32:32-p3:32:32-p4:64:64-p5:32:32-p6:32:32-i64:64-v16:16-v24:32-v32:32-v48:64-v96:128-v192:256-v256:256-v512:512-v1024:1024-v2048:2048-n32:64-S32-A5"
+	)
+	i8Ptr := types.NewPointer(types.I8)
+	golden := []struct {
+		layout string
+		t      types.Type
+		size   uint64
+		align  uint64
+	}{
+		// x86-64.
+		{layout: x86_64, t: types.I1, size: 1, align: 1},
+		{layout: x86_64, t: types.I32, size: 4, align: 4},
+		{layout: x86_64, t: types.I64, size: 8, align: 8},
+		{layout: x86_64, t: types.NewInt(24), size: 4, align: 4},
+		{layout: x86_64, t: types.NewInt(128), size: 16, align: 8},
+		{layout: x86_64, t: types.Double, size: 8, align: 8},
+		{layout: x86_64, t: types.X86_FP80, size: 16, align: 16},
+		{layout: x86_64, t: i8Ptr, size: 8, align: 8},
+		{layout: x86_64, t: types.NewStruct(types.I8, types.I32), size: 8, align: 4},
+		{layout: x86_64, t: &types.StructType{Packed: true, Fields: []types.Type{types.I8, types.I32}}, size: 5, align: 1},
+		{layout: x86_64, t: types.NewStruct(types.I8, types.Double, types.I8), size: 24, align: 8},
+		{layout: x86_64, t: types.NewStruct(), size: 0, align: 1},
+		{layout: x86_64, t: types.NewArray(3, types.I16), size: 6, align: 2},
+		{layout: x86_64, t: types.NewArray(2, types.NewStruct(types.I32, types.I8)), size: 16, align: 4},
+		{layout: x86_64, t: types.NewVector(4, types.Float), size: 16, align: 16},
+		{layout: x86_64, t: types.NewVector(3, types.Float), size: 16, align: 16},
+		{layout: x86_64, t: types.NewVector(2, types.I32), size: 8, align: 8},
+		// i386.
+		{layout: i386, t: types.I64, size: 8, align: 4},
+		{layout: i386, t: types.Double, size: 8, align: 4},
+		{layout: i386, t: types.X86_FP80, size: 12, align: 4},
+		{layout: i386, t: i8Ptr, size: 4, align: 4},
+		{layout: i386, t: types.NewStruct(types.I8, types.I64), size: 12, align: 4},
+		{layout: i386, t: types.NewStruct(types.I8, i8Ptr), size: 8, align: 4},
+		// AMDGPU; address-space-specific pointer sizes.
+		{layout: amdgpu, t: i8Ptr, size: 8, align: 8},
+		{layout: amdgpu, t: &types.PointerType{ElemType: types.I8, AddrSpace: 1}, size: 8, align: 8},
+		{layout: amdgpu, t: &types.PointerType{ElemType: types.I8, AddrSpace: 3}, size: 4, align: 4},
+		{layout: amdgpu, t: types.NewStruct(&types.PointerType{ElemType: types.I8, AddrSpace: 5}, i8Ptr), size: 16, align: 8},
+		{layout: amdgpu, t: types.NewVector(3, types.I32), size: 16, align: 16},
+		// Default data layout.
+		{layout: "", t: types.I64, size: 8, align: 4},
+		{layout: "", t: i8Ptr, size: 8, align: 8},
+	}
+	for _, g := range golden {
+		dl, err := ParseDataLayout(g.layout)
+		if err != nil {
+			t.Errorf("unable to parse data layout %q; %v", g.layout, err)
+			continue
+		}
+		if size := dl.Sizeof(g.t); size != g.size {
+			t.Errorf("size mismatch of %s in data layout %q; expected %d, got %d", g.t, g.layout, g.size, size)
+		}
+		if align := dl.Alignof(g.t); align != g.align {
+			t.Errorf("alignment mismatch of %s in data layout %q; expected %d, got %d", g.t, g.layout, g.align, align)
+		}
+	}
+	// Struct field offsets.
+	dl, err := ParseDataLayout(x86_64)
+	if err != nil {
+		t.Fatalf("unable to parse data layout %q; %v", x86_64, err)
+	}
+	st := types.NewStruct(types.I8, types.Double, types.I16, types.NewVector(4, types.Float))
+	var offsets []string
+	for i := range st.Fields {
+		offsets = append(offsets, fmt.Sprint(dl.Offsetof(st, i)))
+	}
+	if got, want := strings.Join(offsets, " "), "0 8 16 32"; got != want {
+		t.Errorf("field offsets mismatch of %s; expected %q, got %q", st, want, got)
+	}
+	// Struct field offsets and padded size with default alignment.
+	for _, g := range []struct {
+		st      *types.StructType
+		offsets string
+		size    uint64
+	}{
+		{st: types.NewStruct(types.I8, types.I32, types.I8), offsets: "0 4 8", size: 12},
+		{st: &types.StructType{Packed: true, Fields: []types.Type{types.I8, types.I32, types.I8}}, offsets: "0 1 5", size: 6},
+	} {
+		var offsets []string
+		for i := range g.st.Fields {
+			offsets = append(offsets, fmt.Sprint(dl.Offsetof(g.st, i)))
+		}
+		if got := strings.Join(offsets, " "); got != g.offsets {
+			t.Errorf("field offsets mismatch of %s; expected %q, got %q", g.st, g.offsets, got)
+		}
+		if size := dl.Sizeof(g.st); size != g.size {
+			t.Errorf("size mismatch of %s; expected %d, got %d", g.st, g.size, size)
+		}
+	}
+	// Byte order.
+	if dl.BigEndian {
+		t.Errorf("byte order mismatch of data layout %q; expected little-endian", x86_64)
+	}
+	if dl, err := ParseDataLayout("E-m:e-i64:64-n32:64"); err != nil || !dl.BigEndian {
+		t.Errorf("byte order mismatch of big-endian data layout; %v", err)
+	}
+	// Invalid data layouts.
+	for _, layout := range []string{"e-i64", "e-p:64", "e-x", "e-i32:33"} {
+		if _, err := ParseDataLayout(layout); err == nil {
+			t.Errorf("expected error for invalid data layout %q, got nil", layout)
+		}
+	}
+}
+
+func TestDataLayoutString(t *testing.T) {
+	golden := []string{
+		// Empty data layout.
+		"",
+		// x86_64 Linux.
+		"e-m:e-i64:64-f80:128-n8:16:32:64-S128",
+		// x86_64 Linux with address spaces of mixed pointer sizes.
+		"e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128",
+		// i386 Windows.
+		"e-m:x-p:32:32-i64:64-f80:32-n8:16:32-a:0:32-S32",
+		// AArch64 macOS.
+		"e-m:o-i64:64-i128:128-n32:64-S128",
+		// ARM with function pointer alignment.
+		"e-m:e-p:32:32-Fi8-i64:64-v128:64:128-a:0:32-n32-S64",
+		// PowerPC big-endian.
+		"E-m:e-i64:64-n32:64",
+		// AMDGPU with pointer index sizes and non-integral address spaces.
+		"e-p:64:64-p1:64:64-p2:32:32-p3:32:32-p4:64:64-p5:32:32-p6:32:32-i64:64-v16:16-v24:32-v32:32-v48:64-v96:128-v192:256-v256:256-v512:512-v1024:1024-v2048:2048-n32:64-S32-A5-ni:7",
+		// WebAssembly.
+		"e-m:e-p:32:32-i64:64-n32:64-S128",
+		// Pointer with preferred alignment and index size.
+		"e-p:64:64:64:32-P1-G1",
+		// Legacy x86_64 macOS (clang 3.x), with explicit preferred alignments
+		// and deprecated stack object alignment.
+		"e-p:64:64:64-i1:8:8-i8:8:8-i16:16:16-i32:32:32-i64:64:64-f32:32:32-f64:64:64-v64:64:64-v128:128:128-a0:0:64-s0:64:64-f80:128:128-n8:16:32:64-S128",
+	}
+	for _, s := range golden {
+		dl, err := ParseDataLayout(s)
+		if err != nil {
+			t.Errorf("unable to parse data layout %q; %v", s, err)
+			continue
+		}
+		if got := dl.String(); got != s {
+			t.Errorf("data layout mismatch; expected %q, got %q", s, got)
+		}
+	}
+	// Changed fields.
+	dl, err := ParseDataLayout("e-i1:8:8-i64:64:64-s0:64:64-S128")
+	if err != nil {
+		t.Fatalf("unable to parse data layout; %v", err)
+	}
+	dl.Ints[64] = AlignSpec{ABI: 4, Pref: 8}
+	dl.StackAlign = 8
+	if got, want := dl.String(), "e-i1:8:8-i64:32:64-s0:64:64-S64"; got != want {
+		t.Errorf("data layout mismatch; expected %q, got %q", want, got)
+	}
+	// Structured fields.
+	dl, err = ParseDataLayout("e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128")
+	if err != nil {
+		t.Fatalf("unable to parse data layout; %v", err)
+	}
+	if dl.Mangling != "e" {
+		t.Errorf("mangling mode mismatch; expected %q, got %q", "e", dl.Mangling)
+	}
+	if got, want := fmt.Sprint(dl.NativeIntWidths), "[8 16 32 64]"; got != want {
+		t.Errorf("native integer widths mismatch; expected %q, got %q", want, got)
+	}
+	if got, want := dl.Pointers[270], (PointerSpec{Size: 32, Align: AlignSpec{ABI: 4, Pref: 4}, IndexSize: 32}); got != want {
+		t.Errorf("pointer specification mismatch of address space 270; expected %+v, got %+v", want, got)
+	}
+	if got, want := dl.Ints[64], (AlignSpec{ABI: 8, Pref: 8}); got != want {
+		t.Errorf("alignment mismatch of i64; expected %+v, got %+v", want, got)
+	}
+	if dl.StackAlign != 16 {
+		t.Errorf("stack alignment mismatch; expected 16, got %d", dl.StackAlign)
+	}
+	dl, err = ParseDataLayout("e-p:64:64:64:32-P1-G1-A5-ni:7:8-Fn32")
+	if err != nil {
+		t.Fatalf("unable to parse data layout; %v", err)
+	}
+	if dl.Pointers[0].IndexSize != 32 || dl.ProgramAddrSpace != 1 || dl.GlobalsAddrSpace != 1 || dl.AllocaAddrSpace != 5 {
+		t.Errorf("address space mismatch of data layout %q", dl)
+	}
+	if got, want := fmt.Sprint(dl.NonIntegralAddrSpaces), "[addrspace(7) addrspace(8)]"; got != want {
+		t.Errorf("non-integral address spaces mismatch; expected %q, got %q", want, got)
+	}
+	if dl.FuncPtrAlign != 4 || !dl.FuncPtrAlignMultiple {
+		t.Errorf("function pointer alignment mismatch of data layout %q", dl)
+	}
+}
+
+func TestGEPByteOffset(t *testing.T) {
+	dl, err := ParseDataLayout("e-m:e-i64:64-f80:128-n8:16:32:64-S128")
+	if err != nil {
+		t.Fatalf("unable to parse data layout; %v", err)
+	}
+	// inner = {i8, [3 x i32]}; size 16, array at offset 4.
+	inner := types.NewStruct(types.I8, types.NewArray(3, types.I32))
+	// outer = {i16, inner, double}; size 32, inner at offset 4, double at
+	// offset 24.
+	outer := types.NewStruct(types.I16, inner, types.Double)
+	packed := &types.StructType{Packed: true, Fields: []types.Type{types.I8, types.I32}}
+	vec := types.NewVector(4, types.I16)
+	i32 := func(x int64) value.Value {
+		return constant.NewInt(types.I32, x)
+	}
+	x := NewParam("x", types.I32)
+	golden := []struct {
+		elemType types.Type
+		indices  []value.Value
+		want     int64
+		err      bool
+	}{
+		// Nested struct and array indexing.
+		{elemType: outer, indices: []value.Value{i32(1), i32(1), i32(1), i32(2)}, want: 32 + 4 + 4 + 2*4},
+		{elemType: outer, indices: []value.Value{i32(0), i32(2)}, want: 24},
+		{elemType: outer, indices: []value.Value{i32(0), i32(1), i32(0)}, want: 4},
+		// Negative indices.
+		{elemType: types.I32, indices: []value.Value{i32(-2)}, want: -8},
+		// Packed struct.
+		{elemType: packed, indices: []value.Value{i32(1), i32(1)}, want: 5 + 1},
+		// Vector elements.
+		{elemType: vec, indices: []value.Value{i32(0), i32(3)}, want: 6},
+		// No indices.
+		{elemType: outer, want: 0},
+		// Non-constant index.
+		{elemType: outer, indices: []value.Value{i32(0), i32(1), i32(1), x}, err: true},
+		// Invalid field index.
+		{elemType: outer, indices: []value.Value{i32(0), i32(3)}, err: true},
+		// Index into non-aggregate type.
+		{elemType: types.I32, indices: []value.Value{i32(0), i32(1)}, err: true},
+	}
+	for _, g := range golden {
+		src := NewParam("p", types.NewPointer(g.elemType))
+		gep := &InstGetElementPtr{ElemType: g.elemType, Src: src, Indices: g.indices}
+		got, err := GEPByteOffset(gep, dl)
+		if g.err {
+			if err == nil {
+				t.Errorf("expected error for indices %v into %v, got nil", g.indices, g.elemType)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unable to compute offset of indices %v into %v; %v", g.indices, g.elemType, err)
+			continue
+		}
+		if got != g.want {
+			t.Errorf("offset mismatch of indices %v into %v; expected %d, got %d", g.indices, g.elemType, g.want, got)
+		}
+	}
+}
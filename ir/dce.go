@@ -0,0 +1,91 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Dead code elimination ] ===============================================
+
+// EliminateDeadCode removes the instructions of the given function whose
+// results are unused and which have no side effects. Instructions made dead by
+// the removal of other instructions are removed as well.
+//
+// Terminators, stores, fences, atomic and volatile operations, and calls
+// (except to functions which only read memory, as indicated by the readnone
+// and readonly function attributes) are never removed.
+func EliminateDeadCode(f *Function) {
+	for {
+		// Locate used values.
+		used := make(map[value.Value]bool)
+		for _, block := range f.Blocks {
+			for _, inst := range block.Insts {
+				for _, op := range operands(inst) {
+					used[*op] = true
+				}
+			}
+			if block.Term != nil {
+				for _, op := range operands(block.Term) {
+					used[*op] = true
+				}
+			}
+		}
+		// Remove dead instructions.
+		changed := false
+		for _, block := range f.Blocks {
+			insts := block.Insts[:0]
+			for _, inst := range block.Insts {
+				if v, ok := inst.(value.Value); ok && !used[v] && isRemovable(inst) {
+					changed = true
+					continue
+				}
+				insts = append(insts, inst)
+			}
+			block.Insts = insts
+		}
+		if !changed {
+			return
+		}
+	}
+}
+
+// ### [ Helper functions ] ####################################################
+
+// isRemovable reports whether the given instruction has no side effects, and
+// may thus be removed if its result is unused.
+func isRemovable(inst Instruction) bool {
+	switch inst := inst.(type) {
+	case *InstStore, *InstFence, *InstCmpXchg, *InstAtomicRMW, *InstVAArg, *InstLandingPad, *InstCatchPad, *InstCleanupPad:
+		return false
+	case *InstLoad:
+		return !inst.Atomic && !inst.Volatile
+	case *InstCall:
+		if onlyReadsMemory(inst.FuncAttrs) {
+			return true
+		}
+		if callee, ok := inst.Callee.(*Function); ok {
+			return onlyReadsMemory(callee.FuncAttrs)
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// onlyReadsMemory reports whether the given function attributes contain the
+// readnone or readonly attribute.
+func onlyReadsMemory(attrs []FuncAttribute) bool {
+	for _, attr := range attrs {
+		switch attr := attr.(type) {
+		case enum.FuncAttr:
+			if attr == enum.FuncAttrReadNone || attr == enum.FuncAttrReadOnly {
+				return true
+			}
+		case *AttrGroupDef:
+			if onlyReadsMemory(attr.FuncAttrs) {
+				return true
+			}
+		}
+	}
+	return false
+}
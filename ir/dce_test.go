@@ -0,0 +1,59 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestEliminateDeadCode(t *testing.T) {
+	g := NewFunc("g", types.I32)
+	h := NewFunc("h", types.I32)
+	h.FuncAttrs = append(h.FuncAttrs, enum.FuncAttrReadNone)
+	f := NewFunc("f", types.I32, NewParam("x", types.I32))
+	x := f.Params[0]
+	entry := f.NewBlock("")
+	one := constant.NewInt(types.I32, 1)
+	// Chain of unused instructions.
+	a := entry.NewAdd(x, one)
+	b := entry.NewAdd(a, one)
+	entry.NewAdd(b, one)
+	// Unused call with side effects.
+	entry.NewCall(g)
+	// Unused call without side effects.
+	entry.NewCall(h)
+	entry.NewRet(entry.NewMul(x, x))
+	EliminateDeadCode(f)
+	want := `define i32 @f(i32 %x) {
+; <label>:0
+	%1 = call i32 @g()
+	%2 = mul i32 %x, %x
+	ret i32 %2
+}`
+	if got := f.Def(); want != got {
+		t.Errorf("function mismatch after dead code elimination; expected `%v`, got `%v`", want, got)
+	}
+}
+
+func TestEliminateDeadCodeArg(t *testing.T) {
+	usei := NewFunc("usei", types.Void, NewParam("", types.I32))
+	f := NewFunc("f", types.Void, NewParam("y", types.I32))
+	entry := f.NewBlock("entry")
+	// Value only used as argument with parameter attributes.
+	x := entry.NewAdd(f.Params[0], constant.NewInt(types.I32, 1))
+	x.SetName("x")
+	entry.NewCall(usei, NewArg(x, enum.ParamAttrSignExt))
+	entry.NewRet(nil)
+	EliminateDeadCode(f)
+	want := `define void @f(i32 %y) {
+entry:
+	%x = add i32 %y, 1
+	call void @usei(i32 signext %x)
+	ret void
+}`
+	if got := f.Def(); want != got {
+		t.Errorf("function mismatch after dead code elimination; expected `%v`, got `%v`", want, got)
+	}
+}
@@ -0,0 +1,164 @@
+package ir
+
+// === [ Dominator tree ] ======================================================
+
+// DomTree is the dominator tree of a function.
+//
+// A basic block A dominates a basic block B if every path from the entry block
+// of the function to B passes through A. Basic blocks which are unreachable
+// from the entry block are not part of the dominator tree.
+type DomTree struct {
+	// Function of the dominator tree.
+	Func *Function
+
+	// Immediate dominator of each reachable basic block; the entry block maps
+	// to nil.
+	idoms map[*BasicBlock]*BasicBlock
+	// Reverse postorder index of each reachable basic block.
+	order map[*BasicBlock]int
+	// Dominance frontier of each reachable basic block.
+	frontiers map[*BasicBlock][]*BasicBlock
+}
+
+// ComputeDominatorTree computes the dominator tree of the given function.
+//
+// The dominator tree is computed using the iterative algorithm of Cooper,
+// Harvey and Kennedy.
+//
+// ref: K. D. Cooper, T. J. Harvey and K. Kennedy, "A Simple, Fast Dominance
+// Algorithm", 2001.
+func ComputeDominatorTree(f *Function) *DomTree {
+	t := &DomTree{
+		Func:      f,
+		idoms:     make(map[*BasicBlock]*BasicBlock),
+		order:     make(map[*BasicBlock]int),
+		frontiers: make(map[*BasicBlock][]*BasicBlock),
+	}
+	if len(f.Blocks) == 0 {
+		return t
+	}
+	// Order reachable basic blocks in reverse postorder.
+	blocks := reversePostorder(f.Blocks[0])
+	for i, block := range blocks {
+		t.order[block] = i
+	}
+	// Compute immediate dominators.
+	entry := blocks[0]
+	t.idoms[entry] = entry
+	for changed := true; changed; {
+		changed = false
+		for _, block := range blocks[1:] {
+			var idom *BasicBlock
+			for _, pred := range f.predsOf(block) {
+				if _, ok := t.idoms[pred]; !ok {
+					// Skip unreachable and unprocessed predecessors.
+					continue
+				}
+				if idom == nil {
+					idom = pred
+					continue
+				}
+				idom = t.intersect(pred, idom)
+			}
+			if t.idoms[block] != idom {
+				t.idoms[block] = idom
+				changed = true
+			}
+		}
+	}
+	t.idoms[entry] = nil
+	// Compute dominance frontiers.
+	for _, block := range blocks {
+		preds := f.predsOf(block)
+		if len(preds) < 2 {
+			continue
+		}
+		for _, pred := range preds {
+			if !t.reachable(pred) {
+				continue
+			}
+			for runner := pred; runner != nil && runner != t.idoms[block]; runner = t.idoms[runner] {
+				if !containsBlock(t.frontiers[runner], block) {
+					t.frontiers[runner] = append(t.frontiers[runner], block)
+				}
+			}
+		}
+	}
+	return t
+}
+
+// IDom returns the immediate dominator of the given basic block. The entry
+// block and basic blocks unreachable from the entry block have no immediate
+// dominator, in which case nil is returned.
+func (t *DomTree) IDom(block *BasicBlock) *BasicBlock {
+	return t.idoms[block]
+}
+
+// Dominates reports whether the basic block a dominates the basic block b.
+// Every reachable basic block dominates itself. Basic blocks unreachable from
+// the entry block neither dominate nor are dominated by other basic blocks.
+func (t *DomTree) Dominates(a, b *BasicBlock) bool {
+	if !t.reachable(a) || !t.reachable(b) {
+		return false
+	}
+	for ; b != nil; b = t.idoms[b] {
+		if b == a {
+			return true
+		}
+	}
+	return false
+}
+
+// DominanceFrontier returns the dominance frontier of the given basic block;
+// the set of basic blocks B such that the given basic block dominates a
+// predecessor of B but does not strictly dominate B. The dominance frontier of
+// basic blocks unreachable from the entry block is empty.
+func (t *DomTree) DominanceFrontier(block *BasicBlock) []*BasicBlock {
+	return t.frontiers[block]
+}
+
+// reachable reports whether the given basic block is reachable from the entry
+// block.
+func (t *DomTree) reachable(block *BasicBlock) bool {
+	_, ok := t.order[block]
+	return ok
+}
+
+// intersect returns the nearest common dominator of the given basic blocks,
+// based on the immediate dominators computed so far.
+func (t *DomTree) intersect(a, b *BasicBlock) *BasicBlock {
+	for a != b {
+		for t.order[a] > t.order[b] {
+			a = t.idoms[a]
+		}
+		for t.order[b] > t.order[a] {
+			b = t.idoms[b]
+		}
+	}
+	return a
+}
+
+// ### [ Helper functions ] ####################################################
+
+// reversePostorder returns the basic blocks reachable from the given entry
+// block in reverse postorder.
+func reversePostorder(entry *BasicBlock) []*BasicBlock {
+	var postorder []*BasicBlock
+	visited := make(map[*BasicBlock]bool)
+	var visit func(block *BasicBlock)
+	visit = func(block *BasicBlock) {
+		visited[block] = true
+		for _, succ := range block.Succs() {
+			if !visited[succ] {
+				visit(succ)
+			}
+		}
+		postorder = append(postorder, block)
+	}
+	visit(entry)
+	blocks := make([]*BasicBlock, len(postorder))
+	for i, block := range postorder {
+		blocks[len(postorder)-1-i] = block
+	}
+	return blocks
+}
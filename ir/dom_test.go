@@ -0,0 +1,77 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestDomTree(t *testing.T) {
+	// Control flow graph with a loop and an unreachable basic block.
+	//
+	//        entry
+	//        /   \
+	//       a     b
+	//        \   /
+	//          c <-- unreachable
+	//         / ^
+	//        d -+
+	//        |
+	//       exit
+	f := NewFunc("f", types.Void, NewParam("cond", types.I1))
+	cond := f.Params[0]
+	entry := f.NewBlock("entry")
+	a := f.NewBlock("a")
+	b := f.NewBlock("b")
+	c := f.NewBlock("c")
+	d := f.NewBlock("d")
+	exit := f.NewBlock("exit")
+	unreachable := f.NewBlock("unreachable")
+	entry.NewCondBr(cond, a, b)
+	a.NewBr(c)
+	b.NewBr(c)
+	c.NewBr(d)
+	d.NewCondBr(cond, c, exit)
+	exit.NewRet(nil)
+	unreachable.NewBr(c)
+	dt := ComputeDominatorTree(f)
+	golden := []struct {
+		block    *BasicBlock
+		idom     *BasicBlock
+		frontier []*BasicBlock
+	}{
+		{block: entry, idom: nil, frontier: nil},
+		{block: a, idom: entry, frontier: []*BasicBlock{c}},
+		{block: b, idom: entry, frontier: []*BasicBlock{c}},
+		{block: c, idom: entry, frontier: []*BasicBlock{c}},
+		{block: d, idom: c, frontier: []*BasicBlock{c}},
+		{block: exit, idom: d, frontier: nil},
+		{block: unreachable, idom: nil, frontier: nil},
+	}
+	for _, g := range golden {
+		if got := dt.IDom(g.block); g.idom != got {
+			t.Errorf("immediate dominator mismatch of %q; expected %v, got %v", g.block.Ident(), g.idom, got)
+		}
+		if got := dt.DominanceFrontier(g.block); !equalBlocks(g.frontier, got) {
+			t.Errorf("dominance frontier mismatch of %q; expected %v, got %v", g.block.Ident(), g.frontier, got)
+		}
+	}
+	doms := []struct {
+		a, b *BasicBlock
+		want bool
+	}{
+		{a: entry, b: entry, want: true},
+		{a: entry, b: exit, want: true},
+		{a: c, b: exit, want: true},
+		{a: a, b: c, want: false},
+		{a: d, b: c, want: false},
+		{a: exit, b: entry, want: false},
+		{a: entry, b: unreachable, want: false},
+		{a: unreachable, b: c, want: false},
+	}
+	for _, g := range doms {
+		if got := dt.Dominates(g.a, g.b); g.want != got {
+			t.Errorf("dominance mismatch of %q over %q; expected %v, got %v", g.a.Ident(), g.b.Ident(), g.want, got)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package ir
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// === [ Control flow graph in DOT format ] ====================================
+
+// WriteCFGDot writes the control flow graph of the given function to w in the
+// DOT format of GraphViz. Each basic block is represented by a node labelled
+// with the basic block name and its instructions, and each successor of the
+// terminator of a basic block by an edge. The edges of conditional branch
+// terminators are labelled true and false, and the edges of switch terminators
+// are labelled with their case values (or default).
+//
+// Unnamed local variables are assigned IDs before output.
+func WriteCFGDot(w io.Writer, f *Function) error {
+	if err := f.AssignIDs(); err != nil {
+		return errors.WithStack(err)
+	}
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "digraph %s {\n", dotQuote(f.Ident()))
+	buf.WriteString("\tnode [shape=box fontname=monospace];\n")
+	for _, block := range f.Blocks {
+		label := &strings.Builder{}
+		fmt.Fprintf(label, "%s:\n", block.Ident())
+		for _, inst := range block.Insts {
+			fmt.Fprintf(label, "\t%s\n", inst.Def())
+		}
+		if block.Term != nil {
+			fmt.Fprintf(label, "\t%s\n", block.Term.Def())
+		}
+		fmt.Fprintf(buf, "\t%s [label=%s];\n", dotQuote(block.Ident()), dotLabel(label.String()))
+	}
+	for _, block := range f.Blocks {
+		for _, edge := range dotEdges(block) {
+			fmt.Fprintf(buf, "\t%s -> %s", dotQuote(block.Ident()), dotQuote(edge.target.Ident()))
+			if len(edge.label) > 0 {
+				fmt.Fprintf(buf, " [label=%s]", dotQuote(edge.label))
+			}
+			buf.WriteString(";\n")
+		}
+	}
+	buf.WriteString("}\n")
+	if _, err := io.WriteString(w, buf.String()); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// dotEdge is a labelled control flow edge to a target basic block.
+type dotEdge struct {
+	// Target basic block.
+	target *BasicBlock
+	// (optional) Edge label.
+	label string
+}
+
+// dotEdges returns the outgoing control flow edges of the given basic block.
+func dotEdges(block *BasicBlock) []dotEdge {
+	switch term := block.Term.(type) {
+	case nil:
+		return nil
+	case *TermCondBr:
+		return []dotEdge{{target: term.TargetTrue, label: "true"}, {target: term.TargetFalse, label: "false"}}
+	case *TermSwitch:
+		edges := []dotEdge{{target: term.TargetDefault, label: "default"}}
+		for _, c := range term.Cases {
+			edges = append(edges, dotEdge{target: c.Target, label: c.X.Ident()})
+		}
+		return edges
+	default:
+		var edges []dotEdge
+		for _, succ := range term.Succs() {
+			edges = append(edges, dotEdge{target: succ})
+		}
+		return edges
+	}
+}
+
+// dotQuote returns the given string as a quoted DOT string.
+func dotQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	return `"` + s + `"`
+}
+
+// dotLabel returns the given multi-line string as a quoted DOT label, with
+// left-justified lines.
+func dotLabel(s string) string {
+	s = strings.Replace(s, "\t", "  ", -1)
+	s = dotQuote(s)
+	return strings.Replace(s, "\n", `\l`, -1)
+}
@@ -0,0 +1,55 @@
+package ir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestWriteCFGDot(t *testing.T) {
+	// Diamond control flow graph, with a switch in one arm.
+	m := NewModule()
+	x := NewParam("x", types.I32)
+	f := m.NewFunc("f", types.I32, x)
+	entry := f.NewBlock("entry")
+	left := f.NewBlock("left")
+	right := f.NewBlock("right")
+	exit := f.NewBlock("exit")
+	cond := entry.NewICmp(enum.IPredEQ, x, constant.NewInt(types.I32, 0))
+	entry.NewCondBr(cond, left, right)
+	left.NewBr(exit)
+	right.NewSwitch(x, exit, NewCase(constant.NewInt(types.I32, 1), left), NewCase(constant.NewInt(types.I32, 2), exit))
+	phi := exit.NewPhi(NewIncoming(constant.NewInt(types.I32, 1), left), NewIncoming(x, right))
+	exit.NewRet(phi)
+	buf := &strings.Builder{}
+	if err := WriteCFGDot(buf, f); err != nil {
+		t.Fatalf("unable to write control flow graph; %+v", err)
+	}
+	got := buf.String()
+	if !strings.HasPrefix(got, "digraph \"@f\" {\n") {
+		t.Errorf("graph header mismatch; got %q", got)
+	}
+	want := []string{
+		"\t\"%entry\" [label=\"%entry:\\l  %0 = icmp eq i32 %x, 0\\l  br i1 %0, label %left, label %right\\l\"];\n",
+		"\t\"%left\" [label=",
+		"\t\"%right\" [label=",
+		"\t\"%exit\" [label=\"%exit:\\l  %1 = phi i32 [ 1, %left ], [ %x, %right ]\\l  ret i32 %1\\l\"];\n",
+		"\t\"%entry\" -> \"%left\" [label=\"true\"];\n",
+		"\t\"%entry\" -> \"%right\" [label=\"false\"];\n",
+		"\t\"%left\" -> \"%exit\";\n",
+		"\t\"%right\" -> \"%exit\" [label=\"default\"];\n",
+		"\t\"%right\" -> \"%left\" [label=\"1\"];\n",
+		"\t\"%right\" -> \"%exit\" [label=\"2\"];\n",
+	}
+	for _, w := range want {
+		if !strings.Contains(got, w) {
+			t.Errorf("unable to locate %q in DOT output; got\n%s", w, got)
+		}
+	}
+	if n := strings.Count(got, " -> "); n != 6 {
+		t.Errorf("edge count mismatch; expected 6, got %d", n)
+	}
+}
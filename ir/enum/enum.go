@@ -695,6 +695,7 @@ const (
 	FuncAttrNoRedZone                                   // noredzone
 	FuncAttrNoReturn                                    // noreturn
 	FuncAttrNoUnwind                                    // nounwind
+	FuncAttrOptForFuzzing                               // optforfuzzing
 	FuncAttrOptNone                                     // optnone
 	FuncAttrOptSize                                     // optsize
 	FuncAttrReadNone                                    // readnone
@@ -705,6 +706,7 @@ const (
 	FuncAttrSanitizeHWAddress                           // sanitize_hwaddress
 	FuncAttrSanitizeMemory                              // sanitize_memory
 	FuncAttrSanitizeThread                              // sanitize_thread
+	FuncAttrShadowCallStack                             // shadowcallstack
 	FuncAttrSpeculatable                                // speculatable
 	FuncAttrSSP                                         // ssp
 	FuncAttrSSPReq                                      // sspreq
@@ -769,6 +771,78 @@ const (
 	NameTableKindNone    NameTableKind = 2 // None
 )
 
+//go:generate stringer -linecomment -type Opcode
+
+// Opcode is an instruction opcode.
+type Opcode uint8
+
+// Instruction opcodes.
+const (
+	OpcodeAdd            Opcode = iota + 1 // add
+	OpcodeAddrSpaceCast                    // addrspacecast
+	OpcodeAlloca                           // alloca
+	OpcodeAnd                              // and
+	OpcodeAShr                             // ashr
+	OpcodeAtomicRMW                        // atomicrmw
+	OpcodeBitCast                          // bitcast
+	OpcodeBr                               // br
+	OpcodeCall                             // call
+	OpcodeCallBr                           // callbr
+	OpcodeCatchPad                         // catchpad
+	OpcodeCatchRet                         // catchret
+	OpcodeCatchSwitch                      // catchswitch
+	OpcodeCleanupPad                       // cleanuppad
+	OpcodeCleanupRet                       // cleanupret
+	OpcodeCmpXchg                          // cmpxchg
+	OpcodeExtractElement                   // extractelement
+	OpcodeExtractValue                     // extractvalue
+	OpcodeFAdd                             // fadd
+	OpcodeFCmp                             // fcmp
+	OpcodeFDiv                             // fdiv
+	OpcodeFence                            // fence
+	OpcodeFMul                             // fmul
+	OpcodeFPExt                            // fpext
+	OpcodeFPToSI                           // fptosi
+	OpcodeFPToUI                           // fptoui
+	OpcodeFPTrunc                          // fptrunc
+	OpcodeFRem                             // frem
+	OpcodeFSub                             // fsub
+	OpcodeGetElementPtr                    // getelementptr
+	OpcodeICmp                             // icmp
+	OpcodeIndirectBr                       // indirectbr
+	OpcodeInsertElement                    // insertelement
+	OpcodeInsertValue                      // insertvalue
+	OpcodeIntToPtr                         // inttoptr
+	OpcodeInvoke                           // invoke
+	OpcodeLandingPad                       // landingpad
+	OpcodeLoad                             // load
+	OpcodeLShr                             // lshr
+	OpcodeMul                              // mul
+	OpcodeOr                               // or
+	OpcodePhi                              // phi
+	OpcodePtrToInt                         // ptrtoint
+	OpcodeResume                           // resume
+	OpcodeRet                              // ret
+	OpcodeSDiv                             // sdiv
+	OpcodeSelect                           // select
+	OpcodeSExt                             // sext
+	OpcodeShl                              // shl
+	OpcodeShuffleVector                    // shufflevector
+	OpcodeSIToFP                           // sitofp
+	OpcodeSRem                             // srem
+	OpcodeStore                            // store
+	OpcodeSub                              // sub
+	OpcodeSwitch                           // switch
+	OpcodeTrunc                            // trunc
+	OpcodeUDiv                             // udiv
+	OpcodeUIToFP                           // uitofp
+	OpcodeUnreachable                      // unreachable
+	OpcodeURem                             // urem
+	OpcodeVAArg                            // va_arg
+	OpcodeXor                              // xor
+	OpcodeZExt                             // zext
+)
+
 //go:generate stringer -linecomment -type OverflowFlag
 
 // OverflowFlag is an integer overflow flag.
@@ -845,6 +919,19 @@ const (
 	SelectionKindSameSize                          // samesize
 )
 
+//go:generate stringer -linecomment -type SymbolKind
+
+// SymbolKind is the kind of a global symbol of a module.
+type SymbolKind uint8
+
+// Global symbol kinds.
+const (
+	SymbolKindGlobal SymbolKind = iota // global
+	SymbolKindFunc                     // function
+	SymbolKindAlias                    // alias
+	SymbolKindIFunc                    // ifunc
+)
+
 //go:generate stringer -linecomment -type Tail
 
 // Tail is a tail call attribute.
@@ -0,0 +1,104 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/value"
+)
+
+// CommonFastMathFlags returns the fast-math flags common to all floating-point
+// instructions of the expression tree rooted at the given instruction; i.e.
+// the intersection of the fast-math flags of the root instruction and its
+// floating-point operand chain. The common flags determine which
+// transformations (e.g. reassociation) are legal for the expression tree as a
+// whole.
+//
+// The expression tree consists of fadd, fsub, fmul, fdiv, frem and fcmp
+// instructions; any other operand is treated as a leaf of the tree. The fast
+// flag is expanded into the set of flags it implies, and is only present in
+// the result if every instruction of the expression tree has all of them.
+// Flags are returned in order of the enum.FastMathFlag enumeration.
+func CommonFastMathFlags(inst Instruction) []enum.FastMathFlag {
+	root, ok := inst.(value.Value)
+	if !ok {
+		return nil
+	}
+	if _, _, ok := fastMathFlagsOf(root); !ok {
+		return nil
+	}
+	var common uint = fastMathAll
+	visited := make(map[value.Value]bool)
+	var visit func(v value.Value)
+	visit = func(v value.Value) {
+		if visited[v] {
+			return
+		}
+		visited[v] = true
+		flags, ops, ok := fastMathFlagsOf(v)
+		if !ok {
+			// Leaf of expression tree.
+			return
+		}
+		common &= fastMathMask(flags)
+		for _, op := range ops {
+			visit(op)
+		}
+	}
+	visit(root)
+	return fastMathFlags(common)
+}
+
+// ### [ Helper functions ] ####################################################
+
+// fastMathFlagsOf returns the fast-math flags and floating-point operands of
+// the given value, and a boolean indicating whether the value is a
+// floating-point instruction of an expression tree.
+func fastMathFlagsOf(v value.Value) ([]enum.FastMathFlag, []value.Value, bool) {
+	switch inst := v.(type) {
+	case *InstFAdd:
+		return inst.FastMathFlags, []value.Value{inst.X, inst.Y}, true
+	case *InstFSub:
+		return inst.FastMathFlags, []value.Value{inst.X, inst.Y}, true
+	case *InstFMul:
+		return inst.FastMathFlags, []value.Value{inst.X, inst.Y}, true
+	case *InstFDiv:
+		return inst.FastMathFlags, []value.Value{inst.X, inst.Y}, true
+	case *InstFRem:
+		return inst.FastMathFlags, []value.Value{inst.X, inst.Y}, true
+	case *InstFCmp:
+		return inst.FastMathFlags, []value.Value{inst.X, inst.Y}, true
+	default:
+		return nil, nil, false
+	}
+}
+
+// fastMathAll is the bitmask of fast-math flags implied by the fast flag.
+const fastMathAll = 1<<enum.FastMathFlagAFn | 1<<enum.FastMathFlagARcp | 1<<enum.FastMathFlagContract | 1<<enum.FastMathFlagNInf | 1<<enum.FastMathFlagNNaN | 1<<enum.FastMathFlagNSZ | 1<<enum.FastMathFlagReassoc
+
+// fastMathMask returns the bitmask of the given fast-math flags, with the fast
+// flag expanded into the set of flags it implies.
+func fastMathMask(flags []enum.FastMathFlag) uint {
+	var mask uint
+	for _, flag := range flags {
+		if flag == enum.FastMathFlagFast {
+			mask |= fastMathAll
+			continue
+		}
+		mask |= 1 << flag
+	}
+	return mask
+}
+
+// fastMathFlags returns the fast-math flags of the given bitmask, with the set
+// of flags implied by the fast flag collapsed into the fast flag.
+func fastMathFlags(mask uint) []enum.FastMathFlag {
+	if mask == fastMathAll {
+		return []enum.FastMathFlag{enum.FastMathFlagFast}
+	}
+	var flags []enum.FastMathFlag
+	for flag := enum.FastMathFlagAFn; flag <= enum.FastMathFlagReassoc; flag++ {
+		if mask&(1<<flag) != 0 {
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
@@ -0,0 +1,52 @@
+package ir
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestCommonFastMathFlags(t *testing.T) {
+	f := NewFunc("f", types.Double, NewParam("a", types.Double), NewParam("b", types.Double), NewParam("n", types.I32))
+	a, b, n := f.Params[0], f.Params[1], f.Params[2]
+	entry := f.NewBlock("")
+	// Leaf of expression tree.
+	conv := entry.NewSIToFP(n, types.Double)
+	// (a * b) + conv
+	mul := entry.NewFMul(a, b)
+	mul.FastMathFlags = []enum.FastMathFlag{enum.FastMathFlagReassoc, enum.FastMathFlagNSZ}
+	add := entry.NewFAdd(mul, conv)
+	add.FastMathFlags = []enum.FastMathFlag{enum.FastMathFlagFast}
+	// ((a * b) + conv) - a
+	sub := entry.NewFSub(add, a)
+	sub.FastMathFlags = []enum.FastMathFlag{enum.FastMathFlagNNaN, enum.FastMathFlagNSZ, enum.FastMathFlagReassoc}
+	// a / b
+	div := entry.NewFDiv(a, b)
+	div.FastMathFlags = []enum.FastMathFlag{enum.FastMathFlagFast}
+	// (a / b) + (a / b)
+	add2 := entry.NewFAdd(div, div)
+	add2.FastMathFlags = []enum.FastMathFlag{enum.FastMathFlagFast}
+	// ((a / b) + (a / b)) * b
+	mul2 := entry.NewFMul(add2, b)
+	entry.NewRet(sub)
+	golden := []struct {
+		inst Instruction
+		want []enum.FastMathFlag
+	}{
+		{inst: mul, want: []enum.FastMathFlag{enum.FastMathFlagNSZ, enum.FastMathFlagReassoc}},
+		{inst: add, want: []enum.FastMathFlag{enum.FastMathFlagNSZ, enum.FastMathFlagReassoc}},
+		{inst: sub, want: []enum.FastMathFlag{enum.FastMathFlagNSZ, enum.FastMathFlagReassoc}},
+		{inst: add2, want: []enum.FastMathFlag{enum.FastMathFlagFast}},
+		{inst: mul2, want: nil},
+		{inst: conv, want: nil},
+	}
+	for _, g := range golden {
+		got := CommonFastMathFlags(g.inst)
+		if fmt.Sprint(g.want) != fmt.Sprint(got) {
+			t.Errorf("fast-math flags mismatch of %q; expected %v, got %v", g.inst.(value.Named).Ident(), g.want, got)
+		}
+	}
+}
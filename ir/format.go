@@ -0,0 +1,27 @@
+package ir
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir/value"
+)
+
+// Format returns the LLVM syntax representation of the given instruction,
+// terminator or value, without requiring the enclosing function or module.
+//
+// Instructions and terminators are formatted as by their Def method; e.g.
+// "%x = load i32, i32* %p". Other values are formatted as type-value pairs;
+// e.g. "i32 42". Unnamed local values are formatted using their current local
+// IDs, which are assigned when the enclosing function is formatted.
+func Format(v interface{}) string {
+	switch v := v.(type) {
+	case Instruction:
+		return v.Def()
+	case Terminator:
+		return v.Def()
+	case value.Value:
+		return v.String()
+	default:
+		panic(fmt.Errorf("support for formatting %T not yet implemented", v))
+	}
+}
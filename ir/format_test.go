@@ -0,0 +1,31 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestFormat(t *testing.T) {
+	p := NewParam("p", types.NewPointer(types.NewArray(4, types.I32)))
+	gep := NewGetElementPtr(p, constant.NewInt(types.I64, 0), constant.NewInt(types.I64, 2))
+	gep.SetName("elem")
+	load := NewLoad(gep)
+	load.SetName("x")
+	golden := []struct {
+		v    interface{}
+		want string
+	}{
+		{v: gep, want: "%elem = getelementptr [4 x i32], [4 x i32]* %p, i64 0, i64 2"},
+		{v: load, want: "%x = load i32, i32* %elem"},
+		{v: NewRet(load), want: "ret i32 %x"},
+		{v: p, want: "[4 x i32]* %p"},
+		{v: constant.NewInt(types.I32, 42), want: "i32 42"},
+	}
+	for _, g := range golden {
+		if got := Format(g.v); g.want != got {
+			t.Errorf("formatted value mismatch; expected %q, got %q", g.want, got)
+		}
+	}
+}
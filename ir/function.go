@@ -54,6 +54,8 @@ type Function struct {
 	FuncAttrs []FuncAttribute
 	// (optional) Section name; empty if not present.
 	Section string
+	// (optional) Partition name; empty if not present.
+	Partition string
 	// (optional) Comdat; nil if not present.
 	Comdat *ComdatDef
 	// (optional) Garbage collection; empty if not present.
@@ -68,9 +70,43 @@ type Function struct {
 	UseListOrders []*UseListOrder
 	// (optional) Metadata.
 	Metadata []*metadata.Attachment
+	// (optional) Lazily loaded function body of function definitions skipped
+	// during parsing; nil if not present.
+	Lazy LazyBody
 
-	// mu prevents races on AssignIDs.
+	// mu prevents races on AssignIDs and on computing the predecessors of basic
+	// blocks.
 	mu sync.Mutex
+	// preds maps from basic block to predecessor basic blocks; computed on first
+	// invocation of BasicBlock.Preds and reset by InvalidateCFG.
+	preds map[*BasicBlock][]*BasicBlock
+}
+
+// LazyBody is a function body which was skipped during parsing, and which may
+// be loaded on demand.
+type LazyBody interface {
+	// SourceRange returns the start and end byte offsets of the function
+	// definition in the LLVM IR assembly source.
+	SourceRange() (start, end int)
+	// Materialize parses the function body and stores the translated basic
+	// blocks in f.
+	Materialize(f *Function) error
+}
+
+// Materialize loads the lazily loaded function body of the function, if
+// present. The function body is left untouched if materialization fails.
+//
+// Materialize is not safe for concurrent use on the same function, nor
+// concurrently with other uses of the function.
+func (f *Function) Materialize() error {
+	if f.Lazy == nil {
+		return nil
+	}
+	if err := f.Lazy.Materialize(f); err != nil {
+		return errors.WithStack(err)
+	}
+	f.Lazy = nil
+	return nil
 }
 
 // NewFunc returns a new function based on the given function name, return type
@@ -142,6 +178,81 @@ func (f *Function) Def() string {
 	return buf.String()
 }
 
+// SetSection sets the section of the function, and returns the function.
+func (f *Function) SetSection(section string) *Function {
+	f.Section = section
+	return f
+}
+
+// SetPartition sets the partition of the function, and returns the function.
+func (f *Function) SetPartition(partition string) *Function {
+	f.Partition = partition
+	return f
+}
+
+// SetComdat sets the comdat of the function, and returns the function.
+func (f *Function) SetComdat(comdat *ComdatDef) *Function {
+	f.Comdat = comdat
+	return f
+}
+
+// SetPrefix sets the prefix data of the function, which is placed immediately
+// before the function entry point, and returns the function.
+func (f *Function) SetPrefix(prefix constant.Constant) *Function {
+	f.Prefix = prefix
+	return f
+}
+
+// SetPrologue sets the prologue data of the function, which is placed at the
+// function entry point before the function body, and returns the function.
+func (f *Function) SetPrologue(prologue constant.Constant) *Function {
+	f.Prologue = prologue
+	return f
+}
+
+// SetPersonality sets the personality routine used for exception handling by
+// the function, and returns the function.
+func (f *Function) SetPersonality(personality constant.Constant) *Function {
+	f.Personality = personality
+	return f
+}
+
+// InvalidateCFG invalidates the cached control flow graph of the function; i.e.
+// the successors of terminators and the predecessors of basic blocks.
+// InvalidateCFG should be invoked after modifying the control flow of the
+// function.
+func (f *Function) InvalidateCFG() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.preds = nil
+	for _, block := range f.Blocks {
+		resetSuccs(block.Term)
+	}
+}
+
+// predsOf returns the predecessor basic blocks of the given basic block of the
+// function. The predecessors of each basic block are computed by a single scan
+// of the function body on first invocation, and cached until InvalidateCFG is
+// invoked.
+func (f *Function) predsOf(block *BasicBlock) []*BasicBlock {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.preds == nil {
+		f.preds = make(map[*BasicBlock][]*BasicBlock)
+		for _, pred := range f.Blocks {
+			if pred.Term == nil {
+				continue
+			}
+			for _, succ := range pred.Term.Succs() {
+				if !containsBlock(f.preds[succ], pred) {
+					f.preds[succ] = append(f.preds[succ], pred)
+				}
+			}
+		}
+	}
+	return f.preds[block]
+}
+
 // AssignIDs assigns IDs to unnamed local variables.
 func (f *Function) AssignIDs() error {
 	if len(f.Blocks) == 0 {
@@ -203,14 +314,83 @@ func (f *Function) AssignIDs() error {
 	return nil
 }
 
+// UniquifyNames renames the parameters, basic blocks and local variables of the
+// function whose names are already used by a preceding parameter, basic block
+// or local variable of the function, and reports whether any were renamed. If
+// numbered is set, duplicates are made unnamed, and are assigned IDs by
+// AssignIDs; otherwise, duplicates are renamed by appending a numeric suffix
+// (e.g. %tmp.1).
+//
+// Uses of renamed values refer to the values themselves, and thus use the new
+// names once renamed.
+func (f *Function) UniquifyNames(numbered bool) bool {
+	locals := funcLocals(f)
+	used := make(map[string]bool)
+	for _, n := range locals {
+		used[n.Name()] = true
+	}
+	seen := make(map[string]bool)
+	renamed := false
+	for _, n := range locals {
+		name := n.Name()
+		if !seen[name] {
+			seen[name] = true
+			continue
+		}
+		renamed = true
+		if numbered {
+			n.SetName("")
+			continue
+		}
+		if s, err := strconv.Unquote(name); err == nil {
+			// Numeric name; e.g. "42".
+			name = s
+		}
+		n.SetName(uniqueName(name, used))
+	}
+	if renamed && numbered {
+		resetLocalIDs(f)
+	}
+	return renamed
+}
+
 // ### [ Helper functions ] ####################################################
 
+// funcLocals returns the named parameters, basic blocks and local variables of
+// the given function, in order of occurrence.
+func funcLocals(f *Function) []local {
+	var locals []local
+	for _, param := range f.Params {
+		locals = appendLocal(locals, param)
+	}
+	for _, block := range f.Blocks {
+		locals = appendLocal(locals, block)
+		for _, inst := range block.Insts {
+			locals = appendLocal(locals, inst)
+		}
+		if block.Term != nil {
+			locals = appendLocal(locals, block.Term)
+		}
+	}
+	return locals
+}
+
+// appendLocal appends v to locals if v is a named local variable (i.e. a
+// parameter, basic block or result of a non-void instruction or terminator).
+func appendLocal(locals []local, v interface{}) []local {
+	if n, ok := v.(local); ok && !n.IsUnnamed() && !isVoidValue(n) {
+		return append(locals, n)
+	}
+	return locals
+}
+
 // headerString returns the string representation of the function header.
 func headerString(f *Function) string {
 	// (Linkage | ExternLinkage)? Preemptionopt Visibilityopt DLLStorageClassopt
 	// CallingConvopt ReturnAttrs=ReturnAttribute* RetType=Type Name=GlobalIdent
 	// '(' Params ')' UnnamedAddropt AddrSpaceopt FuncAttrs=FuncAttribute*
-	// Sectionopt Comdatopt GCopt Prefixopt Prologueopt Personalityopt
+	// Sectionopt Partitionopt Comdatopt GCopt Prefixopt Prologueopt
+	// Personalityopt
 	buf := &strings.Builder{}
 	if f.Preemption != enum.PreemptionNone {
 		fmt.Fprintf(buf, " %s", f.Preemption)
@@ -251,6 +431,9 @@ func headerString(f *Function) string {
 	if len(f.Section) > 0 {
 		fmt.Fprintf(buf, " section %s", quote(f.Section))
 	}
+	if len(f.Partition) > 0 {
+		fmt.Fprintf(buf, " partition %s", quote(f.Partition))
+	}
 	if f.Comdat != nil {
 		if f.Comdat.Name == f.Name() {
 			buf.WriteString(" comdat")
@@ -295,10 +478,10 @@ func bodyString(body *Function) string {
 }
 
 // isVoidValue reports whether the given named value is a non-value (i.e. a call
-// instruction or invoke terminator with void-return type).
+// instruction, invoke terminator or callbr terminator with void-return type).
 func isVoidValue(n value.Named) bool {
 	switch n.(type) {
-	case *InstCall, *TermInvoke:
+	case *InstCall, *TermInvoke, *TermCallBr:
 		return n.Type().Equal(types.Void)
 	}
 	return false
@@ -0,0 +1,131 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestFuncPrefixPrologue(t *testing.T) {
+	m := NewModule()
+	f := m.NewFunc("f", types.Void)
+	// Prefix data holding a struct constant of runtime metadata.
+	prefix := constant.NewStruct(constant.NewInt(types.I32, 1), constant.NewInt(types.I64, 42))
+	// Prologue data holding a jump over 8 bytes (x86 jmp rel8).
+	prologue := constant.NewArray(constant.NewInt(types.I8, -0x15), constant.NewInt(types.I8, 0x08))
+	f.SetPrefix(prefix).SetPrologue(prologue)
+	f.NewBlock("").NewRet(nil)
+	if f.Prefix != prefix || f.Prologue != prologue {
+		t.Errorf("prefix and prologue mismatch; expected %v and %v, got %v and %v", prefix, prologue, f.Prefix, f.Prologue)
+	}
+	const want = "define void @f() prefix { i32, i64 } { i32 1, i64 42 } prologue [2 x i8] [i8 -21, i8 8] {\n; <label>:0\n\tret void\n}"
+	if got := f.Def(); got != want {
+		t.Errorf("function mismatch; expected %q, got %q", want, got)
+	}
+	// Declarations with prefix data.
+	g := m.NewFunc("g", types.Void).SetPrefix(constant.NewInt(types.I32, 7))
+	if got, want := g.Def(), "declare void @g() prefix i32 7"; got != want {
+		t.Errorf("function mismatch; expected %q, got %q", want, got)
+	}
+}
+
+func TestFuncPersonality(t *testing.T) {
+	m := NewModule()
+	i8Ptr := types.NewPointer(types.I8)
+	personality := m.NewFunc("__gxx_personality_v0", types.I32)
+	personality.Sig.Variadic = true
+	g := m.NewFunc("g", types.Void)
+	f := m.NewFunc("f", types.Void)
+	f.SetPersonality(constant.NewBitCast(personality, i8Ptr))
+	entry := f.NewBlock("entry")
+	ok := f.NewBlock("ok")
+	lpad := f.NewBlock("lpad")
+	entry.NewInvoke(g, nil, ok, lpad)
+	ok.NewRet(nil)
+	lp := lpad.NewLandingPad(types.NewStruct(i8Ptr, types.I32))
+	lp.Cleanup = true
+	lpad.NewResume(lp)
+	const want = `define void @f() personality i8* bitcast (i32 (...)* @__gxx_personality_v0 to i8*) {
+entry:
+	invoke void @g()
+		to label %ok unwind label %lpad
+
+ok:
+	ret void
+
+lpad:
+	%0 = landingpad { i8*, i32 }
+		cleanup
+	resume { i8*, i32 } %0
+}`
+	if got := f.Def(); got != want {
+		t.Errorf("function mismatch; expected %q, got %q", want, got)
+	}
+	// Personality routine retained by extraction.
+	extracted, err := m.Extract([]*Function{f})
+	if err != nil {
+		t.Fatalf("unable to extract function %q; %+v", f.Ident(), err)
+	}
+	if _, ok := extracted.Func(personality.Name()); !ok {
+		t.Errorf("personality routine %q not present in extracted module", personality.Ident())
+	}
+	if got, ok := extracted.Func(f.Name()); !ok || got.Personality != f.Personality {
+		t.Errorf("personality of %q not retained by extraction", f.Ident())
+	}
+}
+
+func TestUniquifyNames(t *testing.T) {
+	golden := []struct {
+		numbered bool
+		want     string
+	}{
+		{
+			numbered: false,
+			want: `define i32 @f(i32 %x) {
+; <label>:0
+	%tmp = add i32 %x, 1
+	%tmp.1 = mul i32 %tmp, 2
+	br label %x.1
+
+x.1:
+	%1 = sub i32 %tmp.1, %tmp
+	ret i32 %1
+}`,
+		},
+		{
+			numbered: true,
+			want: `define i32 @f(i32 %x) {
+; <label>:0
+	%tmp = add i32 %x, 1
+	%1 = mul i32 %tmp, 2
+	br label %2
+
+; <label>:2
+	%3 = sub i32 %1, %tmp
+	ret i32 %3
+}`,
+		},
+	}
+	for _, g := range golden {
+		x := NewParam("x", types.I32)
+		f := NewFunc("f", types.I32, x)
+		entry := f.NewBlock("")
+		tmp1 := entry.NewAdd(x, constant.NewInt(types.I32, 1))
+		tmp1.SetName("tmp")
+		tmp2 := entry.NewMul(tmp1, constant.NewInt(types.I32, 2))
+		tmp2.SetName("tmp")
+		exit := f.NewBlock("x")
+		entry.NewBr(exit)
+		exit.NewRet(exit.NewSub(tmp2, tmp1))
+		if !f.UniquifyNames(g.numbered) {
+			t.Errorf("expected duplicate names to be renamed")
+		}
+		if got := f.Def(); got != g.want {
+			t.Errorf("function mismatch (numbered=%t); expected\n%s\ngot\n%s", g.numbered, g.want, got)
+		}
+		if f.UniquifyNames(g.numbered) {
+			t.Errorf("unexpected renaming of unique names")
+		}
+	}
+}
@@ -45,6 +45,8 @@ type Global struct {
 	ExternallyInitialized bool
 	// (optional) Section name; empty if not present.
 	Section string
+	// (optional) Partition name; empty if not present.
+	Partition string
 	// (optional) Comdat; nil if not present.
 	Comdat *ComdatDef
 	// (optional) Alignment; zero if not present.
@@ -98,15 +100,15 @@ func (g *Global) Def() string {
 	//    Name=GlobalIdent '=' ExternLinkage Preemptionopt Visibilityopt
 	//    DLLStorageClassopt ThreadLocalopt UnnamedAddropt AddrSpaceopt
 	//    ExternallyInitializedopt Immutable ContentType=Type (',' Section)? (','
-	//    Comdat)? (',' Alignment)? Metadata=(',' MetadataAttachment)+?
-	//    FuncAttrs=(',' FuncAttribute)+?
+	//    Partition)? (',' Comdat)? (',' Alignment)? Metadata=(','
+	//    MetadataAttachment)+? FuncAttrs=(',' FuncAttribute)+?
 	//
 	// Global definition.
 	//
 	//    Name=GlobalIdent '=' Linkageopt Preemptionopt Visibilityopt
 	//    DLLStorageClassopt ThreadLocalopt UnnamedAddropt AddrSpaceopt
 	//    ExternallyInitializedopt Immutable ContentType=Type Init=Constant (','
-	//    Section)? (',' Comdat)? (',' Alignment)? Metadata=(','
+	//    Section)? (',' Partition)? (',' Comdat)? (',' Alignment)? Metadata=(','
 	//    MetadataAttachment)+? FuncAttrs=(',' FuncAttribute)+?
 	buf := &strings.Builder{}
 	fmt.Fprintf(buf, "%s =", g.Ident())
@@ -149,6 +151,9 @@ func (g *Global) Def() string {
 	if g.Section != "" {
 		fmt.Fprintf(buf, ", section %s", quote(g.Section))
 	}
+	if g.Partition != "" {
+		fmt.Fprintf(buf, ", partition %s", quote(g.Partition))
+	}
 	if g.Comdat != nil {
 		if g.Comdat.Name == g.Name() {
 			buf.WriteString(", comdat")
@@ -167,3 +172,52 @@ func (g *Global) Def() string {
 	}
 	return buf.String()
 }
+
+// SetLinkage sets the linkage of the global variable, and returns the global
+// variable.
+func (g *Global) SetLinkage(linkage enum.Linkage) *Global {
+	g.Linkage = linkage
+	return g
+}
+
+// SetThreadLocalMode sets the thread local storage model of the global
+// variable, and returns the global variable.
+func (g *Global) SetThreadLocalMode(tlsModel enum.TLSModel) *Global {
+	g.TLSModel = tlsModel
+	return g
+}
+
+// SetUnnamedAddr sets the unnamed_addr or local_unnamed_addr attribute of the
+// global variable, and returns the global variable.
+func (g *Global) SetUnnamedAddr(unnamedAddr enum.UnnamedAddr) *Global {
+	g.UnnamedAddr = unnamedAddr
+	return g
+}
+
+// SetAlign sets the alignment of the global variable, and returns the global
+// variable.
+func (g *Global) SetAlign(align Align) *Global {
+	g.Align = align
+	return g
+}
+
+// SetSection sets the section of the global variable, and returns the global
+// variable.
+func (g *Global) SetSection(section string) *Global {
+	g.Section = section
+	return g
+}
+
+// SetPartition sets the partition of the global variable, and returns the
+// global variable.
+func (g *Global) SetPartition(partition string) *Global {
+	g.Partition = partition
+	return g
+}
+
+// SetComdat sets the comdat of the global variable, and returns the global
+// variable.
+func (g *Global) SetComdat(comdat *ComdatDef) *Global {
+	g.Comdat = comdat
+	return g
+}
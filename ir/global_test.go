@@ -0,0 +1,41 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestGlobalSetters(t *testing.T) {
+	m := NewModule()
+	msg := m.NewGlobalDef("msg", constant.NewCharArrayFromString("hello world\n\x00")).
+		SetLinkage(enum.LinkagePrivate).
+		SetUnnamedAddr(enum.UnnamedAddrUnnamedAddr).
+		SetAlign(1)
+	msg.Immutable = true
+	m.NewGlobalDecl("counter", types.I32).
+		SetLinkage(enum.LinkageExternal).
+		SetThreadLocalMode(enum.TLSModelInitialExec).
+		SetSection(".tbss")
+	want := `@msg = private unnamed_addr constant [13 x i8] c"hello world\0A\00", align 1
+@counter = external thread_local(initialexec) global i32, section ".tbss"
+`
+	if got := m.String(); want != got {
+		t.Errorf("module mismatch; expected `%v`, got `%v`", want, got)
+	}
+}
+
+func TestSectionPartition(t *testing.T) {
+	g := NewGlobalDef("x", constant.NewInt(types.I32, 1))
+	g.SetSection(`.data."x"`).SetPartition("part")
+	if got, want := g.Def(), `@x = global i32 1, section ".data.\22x\22", partition "part"`; got != want {
+		t.Errorf("global mismatch; expected %q, got %q", want, got)
+	}
+	f := NewFunc("f", types.Void)
+	f.SetSection(`.text\hot`).SetPartition("part")
+	if got, want := f.Def(), `declare void @f() section ".text\5Chot" partition "part"`; got != want {
+		t.Errorf("function mismatch; expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,289 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
+)
+
+// === [ Unused function elimination ] =========================================
+
+// RemoveUnusedFunctions removes the functions of the given module which are
+// not reachable from the given root functions, together with the global
+// variables, aliases, IFuncs and metadata definitions made unused by their
+// removal.
+//
+// Functions, global variables, aliases and IFuncs which are externally visible
+// by linkage are treated as additional roots; i.e. only definitions with
+// private, internal, linkonce, linkonce_odr or available_externally linkage,
+// and declarations, are removed. Named metadata definitions, and members of
+// comdats with reachable members, are treated as reachable.
+//
+// Reachability is based on references rather than on direct calls; a function
+// referenced by a reachable entity (e.g. by storing its address, or by a block
+// address) is kept, as it may be invoked indirectly.
+//
+// Metadata definitions are retained if the metadata of the module contains
+// specialized metadata nodes (e.g. debug information), as references between
+// specialized metadata nodes are not tracked.
+func RemoveUnusedFunctions(m *Module, roots []*Function) error {
+	r := newReachability(m)
+	for _, f := range roots {
+		if !containsFunc(m.Funcs, f) {
+			return errors.Errorf("unable to locate root function %q in module", f.Ident())
+		}
+		r.markLive(f)
+	}
+	for _, g := range m.Globals {
+		if !isDiscardable(g.Linkage, g.Init == nil) {
+			r.markLive(g)
+		}
+	}
+	for _, f := range m.Funcs {
+		if !isDiscardable(f.Linkage, len(f.Blocks) == 0 && f.Lazy == nil) {
+			r.markLive(f)
+		}
+	}
+	for _, alias := range m.Aliases {
+		if !isDiscardable(alias.Linkage, false) {
+			r.markLive(alias)
+		}
+	}
+	for _, ifunc := range m.IFuncs {
+		if !isDiscardable(ifunc.Linkage, false) {
+			r.markLive(ifunc)
+		}
+	}
+	for _, named := range m.NamedMetadataDefs {
+		for _, node := range named.Nodes {
+			r.addMetadata(node)
+		}
+	}
+	if err := r.propagate(); err != nil {
+		return errors.WithStack(err)
+	}
+	// Remove unreachable entities.
+	globals := m.Globals[:0]
+	for _, g := range m.Globals {
+		if r.live[g] {
+			globals = append(globals, g)
+		}
+	}
+	m.Globals = globals
+	funcs := m.Funcs[:0]
+	for _, f := range m.Funcs {
+		if r.live[f] {
+			funcs = append(funcs, f)
+		}
+	}
+	m.Funcs = funcs
+	aliases := m.Aliases[:0]
+	for _, alias := range m.Aliases {
+		if r.live[alias] {
+			aliases = append(aliases, alias)
+		}
+	}
+	m.Aliases = aliases
+	ifuncs := m.IFuncs[:0]
+	for _, ifunc := range m.IFuncs {
+		if r.live[ifunc] {
+			ifuncs = append(ifuncs, ifunc)
+		}
+	}
+	m.IFuncs = ifuncs
+	if !r.allMetadata {
+		mds := m.MetadataDefs[:0]
+		for _, md := range m.MetadataDefs {
+			if r.mds[md] {
+				mds = append(mds, md)
+			}
+		}
+		m.MetadataDefs = mds
+	}
+	return nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// reachability tracks the top-level entities of a module reachable from a set
+// of roots.
+type reachability struct {
+	// Reachable functions, global variables, aliases and IFuncs.
+	live map[value.Value]bool
+	// Reachable entities whose references have yet to be visited.
+	worklist []value.Value
+	// Comdat members, keyed by comdat.
+	comdats map[*ComdatDef][]value.Value
+	// Reachable metadata definitions.
+	mds map[*metadata.Def]bool
+	// Retain all metadata definitions; set if specialized metadata nodes are
+	// reachable.
+	allMetadata bool
+}
+
+// newReachability returns a new reachability tracker of the given module.
+func newReachability(m *Module) *reachability {
+	r := &reachability{
+		live:    make(map[value.Value]bool),
+		comdats: make(map[*ComdatDef][]value.Value),
+		mds:     make(map[*metadata.Def]bool),
+	}
+	for _, g := range m.Globals {
+		if g.Comdat != nil {
+			r.comdats[g.Comdat] = append(r.comdats[g.Comdat], g)
+		}
+	}
+	for _, f := range m.Funcs {
+		if f.Comdat != nil {
+			r.comdats[f.Comdat] = append(r.comdats[f.Comdat], f)
+		}
+	}
+	return r
+}
+
+// markLive marks the given function, global variable, alias or IFunc as
+// reachable, together with the other members of its comdat.
+func (r *reachability) markLive(v value.Value) {
+	if r.live[v] {
+		return
+	}
+	r.live[v] = true
+	r.worklist = append(r.worklist, v)
+	var comdat *ComdatDef
+	switch v := v.(type) {
+	case *Global:
+		comdat = v.Comdat
+	case *Function:
+		comdat = v.Comdat
+	}
+	if comdat != nil {
+		for _, member := range r.comdats[comdat] {
+			r.markLive(member)
+		}
+	}
+}
+
+// propagate visits the references of reachable entities until no more entities
+// are made reachable.
+func (r *reachability) propagate() error {
+	for len(r.worklist) > 0 {
+		v := r.worklist[len(r.worklist)-1]
+		r.worklist = r.worklist[:len(r.worklist)-1]
+		switch v := v.(type) {
+		case *Global:
+			if v.Init != nil {
+				r.addValue(v.Init)
+			}
+			for _, md := range v.Metadata {
+				r.addMetadata(md.Node)
+			}
+		case *Function:
+			if err := v.Materialize(); err != nil {
+				return errors.WithStack(err)
+			}
+			r.addFunc(v)
+		case *Alias:
+			r.addValue(v.Aliasee)
+		case *IFunc:
+			r.addValue(v.Resolver)
+		}
+	}
+	return nil
+}
+
+// addFunc adds the entities referenced by the given function.
+func (r *reachability) addFunc(f *Function) {
+	for _, c := range []constant.Constant{f.Prefix, f.Prologue, f.Personality} {
+		if c != nil {
+			r.addValue(c)
+		}
+	}
+	for _, md := range f.Metadata {
+		r.addMetadata(md.Node)
+	}
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			r.addInst(inst)
+		}
+		if block.Term != nil {
+			r.addInst(block.Term)
+		}
+		if term, ok := block.Term.(*TermSwitch); ok {
+			for _, c := range term.Cases {
+				r.addValue(c.X)
+			}
+		}
+	}
+}
+
+// addInst adds the entities referenced by the given instruction or terminator.
+func (r *reachability) addInst(inst interface{}) {
+	for _, op := range operands(inst) {
+		if *op != nil {
+			r.addValue(*op)
+		}
+	}
+	for _, md := range instMetadata(inst) {
+		r.addMetadata(md.Node)
+	}
+}
+
+// addValue adds the entities referenced by the given value.
+func (r *reachability) addValue(v value.Value) {
+	switch v := v.(type) {
+	case *Arg:
+		r.addValue(v.Value)
+	case *metadata.Value:
+		r.addMetadata(v.Value)
+	case *Global, *Function, *Alias, *IFunc:
+		r.markLive(v)
+	case constant.Constant:
+		for _, op := range constantOperands(v) {
+			if *op != nil {
+				r.addValue(*op)
+			}
+		}
+	}
+}
+
+// addMetadata adds the metadata definitions and values referenced by the given
+// metadata.
+func (r *reachability) addMetadata(md metadata.Field) {
+	switch md := md.(type) {
+	case nil, *metadata.NullLit, *metadata.String, *metadata.DIExpression:
+		// Metadata without references.
+	case *metadata.Def:
+		if r.mds[md] {
+			return
+		}
+		r.mds[md] = true
+		r.addMetadata(md.Node)
+	case *metadata.Tuple:
+		for _, field := range md.Fields {
+			r.addMetadata(field)
+		}
+	case *metadata.Value:
+		r.addMetadata(md.Value)
+	case value.Value:
+		r.addValue(md)
+	default:
+		// Specialized metadata node.
+		r.allMetadata = true
+	}
+}
+
+// isDiscardable reports whether a definition or declaration with the given
+// linkage may be removed if unreferenced.
+func isDiscardable(linkage enum.Linkage, decl bool) bool {
+	if decl {
+		return true
+	}
+	switch linkage {
+	case enum.LinkagePrivate, enum.LinkageInternal, enum.LinkageLinkOnce, enum.LinkageLinkOnceODR, enum.LinkageAvailableExternally:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,70 @@
+package ir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestRemoveUnusedFunctions(t *testing.T) {
+	m := NewModule()
+	// Private global variable referenced only by unused private function, with
+	// metadata referenced only by the global variable.
+	md := &metadata.Def{ID: 0, Node: &metadata.Tuple{Fields: []metadata.Field{&metadata.String{Value: "unused"}}}}
+	m.MetadataDefs = append(m.MetadataDefs, md)
+	table := m.NewGlobalDef("table", constant.NewInt(types.I32, 0))
+	table.Linkage = enum.LinkagePrivate
+	table.Metadata = append(table.Metadata, &metadata.Attachment{Name: "unused", Node: md})
+	// Unused private function.
+	unused := m.NewFunc("unused", types.I32)
+	unused.Linkage = enum.LinkagePrivate
+	unusedEntry := unused.NewBlock("")
+	unusedEntry.NewRet(unusedEntry.NewLoad(table))
+	// Private function called by root.
+	helper := m.NewFunc("helper", types.Void)
+	helper.Linkage = enum.LinkageInternal
+	helper.NewBlock("").NewRet(nil)
+	// Private function called only indirectly, through a function pointer of
+	// an exported global variable.
+	callback := m.NewFunc("callback", types.Void)
+	callback.Linkage = enum.LinkagePrivate
+	callback.NewBlock("").NewRet(nil)
+	m.NewGlobalDef("callbacks", callback)
+	// Unused exported function.
+	exported := m.NewFunc("exported", types.Void)
+	exported.NewBlock("").NewRet(nil)
+	// Unused function declaration.
+	m.NewFunc("decl", types.Void)
+	// Root function.
+	main := m.NewFunc("main", types.Void)
+	main.Linkage = enum.LinkageInternal
+	entry := main.NewBlock("")
+	entry.NewCall(helper)
+	entry.NewRet(nil)
+	if err := RemoveUnusedFunctions(m, []*Function{main}); err != nil {
+		t.Fatalf("unable to remove unused functions; %+v", err)
+	}
+	var got []string
+	for _, g := range m.Globals {
+		got = append(got, g.Ident())
+	}
+	for _, f := range m.Funcs {
+		got = append(got, f.Ident())
+	}
+	want := []string{"@callbacks", "@helper", "@callback", "@exported", "@main"}
+	if strings.Join(want, " ") != strings.Join(got, " ") {
+		t.Errorf("retained globals mismatch; expected %v, got %v", want, got)
+	}
+	if len(m.MetadataDefs) != 0 {
+		t.Errorf("metadata definition count mismatch; expected 0, got %d", len(m.MetadataDefs))
+	}
+	// Root not present in module.
+	other := NewModule().NewFunc("other", types.Void)
+	if err := RemoveUnusedFunctions(m, []*Function{other}); err == nil {
+		t.Errorf("expected error for root function not present in module")
+	}
+}
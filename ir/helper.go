@@ -2,6 +2,7 @@ package ir
 
 import (
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 
@@ -97,6 +98,79 @@ func (d Dereferenceable) String() string {
 	return fmt.Sprintf("dereferenceable(%d)", d.N)
 }
 
+// Byval is a typed byval parameter attribute, specifying that the pointer
+// parameter is passed by value as a hidden copy of the pointee.
+type Byval struct {
+	// Pointee type.
+	Typ types.Type
+}
+
+// String returns the string representation of the typed byval parameter
+// attribute.
+func (b Byval) String() string {
+	// 'byval' '(' Typ=Type ')'
+	return fmt.Sprintf("byval(%s)", b.Typ)
+}
+
+// SRet is a typed sret parameter attribute, specifying that the pointer
+// parameter is the address of the structure return value.
+type SRet struct {
+	// Pointee type.
+	Typ types.Type
+}
+
+// String returns the string representation of the typed sret parameter
+// attribute.
+func (s SRet) String() string {
+	// 'sret' '(' Typ=Type ')'
+	return fmt.Sprintf("sret(%s)", s.Typ)
+}
+
+// ByRef is a typed byref parameter attribute, specifying that the pointer
+// parameter refers to a read-only object of the pointee type, passed by
+// reference.
+type ByRef struct {
+	// Pointee type.
+	Typ types.Type
+}
+
+// String returns the string representation of the typed byref parameter
+// attribute.
+func (b ByRef) String() string {
+	// 'byref' '(' Typ=Type ')'
+	return fmt.Sprintf("byref(%s)", b.Typ)
+}
+
+// Preallocated is a typed preallocated parameter attribute, specifying that the
+// pointer parameter refers to an object of the pointee type, preallocated by
+// the caller using llvm.call.preallocated.setup.
+type Preallocated struct {
+	// Pointee type.
+	Typ types.Type
+}
+
+// String returns the string representation of the typed preallocated
+// parameter attribute.
+func (p Preallocated) String() string {
+	// 'preallocated' '(' Typ=Type ')'
+	return fmt.Sprintf("preallocated(%s)", p.Typ)
+}
+
+// InAlloca is a typed inalloca parameter attribute, specifying that the
+// pointer parameter refers to an object of the pointee type, allocated by the
+// caller using an inalloca alloca instruction.
+type InAlloca struct {
+	// Pointee type.
+	Typ types.Type
+}
+
+// String returns the string representation of the typed inalloca parameter
+// attribute.
+func (i InAlloca) String() string {
+	// 'inalloca' '(' Typ=Type ')'
+	return fmt.Sprintf("inalloca(%s)", i.Typ)
+}
+
 // TODO: figure out definition of ExceptionScope.
 
 // ExceptionScope is an exception scope.
@@ -268,6 +342,11 @@ func (o *OperandBundle) String() string {
 //    ir.AttrPair
 //    ir.Align
 //    ir.Dereferenceable
+//    ir.Byval
+//    ir.SRet
+//    ir.ByRef
+//    ir.Preallocated
+//    ir.InAlloca
 //    enum.ParamAttr
 type ParamAttribute interface {
 	fmt.Stringer
@@ -360,8 +439,96 @@ func (p *Param) Def() string {
 	return buf.String()
 }
 
+// AddAttrs appends the given parameter attributes to the function parameter,
+// and returns the parameter.
+func (p *Param) AddAttrs(attrs ...ParamAttribute) *Param {
+	p.Attrs = append(p.Attrs, attrs...)
+	return p
+}
+
+// HasAttr reports whether the function parameter has the given parameter
+// attribute.
+func (p *Param) HasAttr(attr enum.ParamAttr) bool {
+	for _, a := range p.Attrs {
+		if a == attr {
+			return true
+		}
+	}
+	return false
+}
+
+// AlignAttr returns the alignment attribute of the function parameter, and a
+// boolean indicating whether the alignment attribute was present.
+func (p *Param) AlignAttr() (Align, bool) {
+	for _, a := range p.Attrs {
+		if align, ok := a.(Align); ok {
+			return align, true
+		}
+	}
+	return 0, false
+}
+
+// ByvalType returns the pointee type of the byval attribute of the function
+// parameter, and a boolean indicating whether the byval attribute was present.
+// The pointee type of untyped byval attributes is the element type of the
+// parameter type.
+func (p *Param) ByvalType() (types.Type, bool) {
+	for _, a := range p.Attrs {
+		switch a := a.(type) {
+		case Byval:
+			return a.Typ, true
+		case enum.ParamAttr:
+			if a == enum.ParamAttrByval {
+				if t, ok := p.Typ.(*types.PointerType); ok {
+					return t.ElemType, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// SRetType returns the pointee type of the sret attribute of the function
+// parameter, and a boolean indicating whether the sret attribute was present.
+// The pointee type of untyped sret attributes is the element type of the
+// parameter type.
+func (p *Param) SRetType() (types.Type, bool) {
+	for _, a := range p.Attrs {
+		switch a := a.(type) {
+		case SRet:
+			return a.Typ, true
+		case enum.ParamAttr:
+			if a == enum.ParamAttrSRet {
+				if t, ok := p.Typ.(*types.PointerType); ok {
+					return t.ElemType, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
 // ### [ Helper functions ] ####################################################
 
+// paramAttrType returns the type operand of the given typed parameter
+// attribute (e.g. byval(%T)), or nil if not a typed parameter attribute.
+func paramAttrType(attr ParamAttribute) types.Type {
+	switch attr := attr.(type) {
+	case Byval:
+		return attr.Typ
+	case SRet:
+		return attr.Typ
+	case ByRef:
+		return attr.Typ
+	case Preallocated:
+		return attr.Typ
+	case InAlloca:
+		return attr.Typ
+	default:
+		return nil
+	}
+}
+
 // isUnnamed reports whether the given identifier is unnamed.
 func isUnnamed(name string) bool {
 	return len(name) == 0
@@ -407,3 +574,78 @@ func tlsModelString(model enum.TLSModel) string {
 	}
 	return fmt.Sprintf("thread_local(%s)", model)
 }
+
+// funcAttrsComment returns the function attributes of the given function
+// attribute list as presented in the "; Function Attrs:" comment of Clang; i.e.
+// with attribute groups expanded and string attributes omitted.
+func funcAttrsComment(attrs []FuncAttribute) string {
+	var ss []string
+	for _, attr := range attrs {
+		switch attr := attr.(type) {
+		case AttrString, AttrPair:
+			// omit string attributes.
+		case *AttrGroupDef:
+			if s := funcAttrsComment(attr.FuncAttrs); len(s) > 0 {
+				ss = append(ss, s)
+			}
+		default:
+			ss = append(ss, attr.String())
+		}
+	}
+	return strings.Join(ss, " ")
+}
+
+// fmtWriter is a formatted writer which keeps track of the number of bytes
+// written and the first error encountered.
+type fmtWriter struct {
+	// Underlying writer.
+	w io.Writer
+	// Number of bytes written.
+	size int64
+	// First error encountered; or nil if no error.
+	err error
+}
+
+// Fprint formats using the default formats of its operands and writes to fw.
+func (fw *fmtWriter) Fprint(a ...interface{}) {
+	if fw.err != nil {
+		return
+	}
+	n, err := fmt.Fprint(fw.w, a...)
+	fw.size += int64(n)
+	fw.err = err
+}
+
+// Fprintf formats according to a format specifier and writes to fw.
+func (fw *fmtWriter) Fprintf(format string, a ...interface{}) {
+	if fw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(fw.w, format, a...)
+	fw.size += int64(n)
+	fw.err = err
+}
+
+// Fprintln formats using the default formats of its operands and writes to
+// fw, appending a new line.
+func (fw *fmtWriter) Fprintln(a ...interface{}) {
+	if fw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintln(fw.w, a...)
+	fw.size += int64(n)
+	fw.err = err
+}
+
+// countWriter is a writer which discards its input and counts the number of
+// bytes written.
+type countWriter struct {
+	// Number of bytes written.
+	n int
+}
+
+// Write discards p and adds its length to the number of bytes written.
+func (cw *countWriter) Write(p []byte) (int, error) {
+	cw.n += len(p)
+	return len(p), nil
+}
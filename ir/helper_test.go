@@ -0,0 +1,83 @@
+package ir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestParamAttrs(t *testing.T) {
+	m := NewModule()
+	foo := m.NewTypeDef("struct.foo", types.NewStruct(types.I32, types.I64))
+	fooPtr := types.NewPointer(foo)
+	ret := NewParam("ret", fooPtr).AddAttrs(SRet{Typ: foo}, enum.ParamAttrNoAlias)
+	x := NewParam("x", fooPtr).AddAttrs(Byval{Typ: foo}, Align(8))
+	p := NewParam("p", types.I8Ptr).AddAttrs(enum.ParamAttrNonNull, Dereferenceable{N: 16})
+	n := NewParam("n", types.I8).AddAttrs(enum.ParamAttrSignExt)
+	f := m.NewFunc("f", types.Void, ret, x, p, n)
+	f.NewBlock("").NewRet(nil)
+	want := "define void @f(%struct.foo* sret(%struct.foo) noalias %ret, %struct.foo* byval(%struct.foo) align 8 %x, i8* nonnull dereferenceable(16) %p, i8 signext %n) {"
+	if got := strings.SplitN(f.Def(), "\n", 2)[0]; want != got {
+		t.Errorf("function header mismatch; expected `%v`, got `%v`", want, got)
+	}
+	// Attribute accessors.
+	if typ, ok := x.ByvalType(); !ok || !typ.Equal(foo) {
+		t.Errorf("byval type mismatch of %s; expected %v, got %v", x.Ident(), foo, typ)
+	}
+	if typ, ok := ret.SRetType(); !ok || !typ.Equal(foo) {
+		t.Errorf("sret type mismatch of %s; expected %v, got %v", ret.Ident(), foo, typ)
+	}
+	if align, ok := x.AlignAttr(); !ok || align != 8 {
+		t.Errorf("alignment mismatch of %s; expected 8, got %v", x.Ident(), align)
+	}
+	if _, ok := p.AlignAttr(); ok {
+		t.Errorf("unexpected alignment of %s", p.Ident())
+	}
+	if !p.HasAttr(enum.ParamAttrNonNull) || n.HasAttr(enum.ParamAttrNonNull) {
+		t.Errorf("nonnull attribute mismatch")
+	}
+	// Untyped byval attribute.
+	y := NewParam("y", fooPtr).AddAttrs(enum.ParamAttrByval)
+	if typ, ok := y.ByvalType(); !ok || !typ.Equal(foo) {
+		t.Errorf("byval type mismatch of %s; expected %v, got %v", y.Ident(), foo, typ)
+	}
+	if _, ok := p.ByvalType(); ok {
+		t.Errorf("unexpected byval attribute of %s", p.Ident())
+	}
+}
+
+func TestTypedParamAttrs(t *testing.T) {
+	m := NewModule()
+	foo := m.NewTypeDef("struct.Foo", types.NewStruct(types.I32, types.I64))
+	bar := m.NewTypeDef("struct.Bar", types.NewStruct(types.Double))
+	// The type operands of the attributes are independent of the pointer types
+	// of the parameters.
+	golden := []struct {
+		attr ParamAttribute
+		want string
+	}{
+		{attr: Byval{Typ: foo}, want: "i8* byval(%struct.Foo) %p"},
+		{attr: SRet{Typ: foo}, want: "i8* sret(%struct.Foo) %p"},
+		{attr: ByRef{Typ: foo}, want: "i8* byref(%struct.Foo) %p"},
+		{attr: Preallocated{Typ: foo}, want: "i8* preallocated(%struct.Foo) %p"},
+		{attr: InAlloca{Typ: types.NewArray(4, types.I32)}, want: "i8* inalloca([4 x i32]) %p"},
+	}
+	for _, g := range golden {
+		p := NewParam("p", types.I8Ptr).AddAttrs(g.attr)
+		if got := p.Def(); g.want != got {
+			t.Errorf("parameter mismatch; expected `%v`, got `%v`", g.want, got)
+		}
+	}
+	// Types of typed parameter attributes are used by the function.
+	f := m.NewFunc("f", types.Void, NewParam("p", types.I8Ptr).AddAttrs(ByRef{Typ: bar}))
+	f.NewBlock("").NewRet(nil)
+	var used []string
+	for _, typ := range TypesUsedBy(f) {
+		used = append(used, typ.String())
+	}
+	if want, got := "void (i8*) void i8* i8 %struct.Bar double", strings.Join(used, " "); want != got {
+		t.Errorf("types used mismatch; expected %q, got %q", want, got)
+	}
+}
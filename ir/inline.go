@@ -0,0 +1,228 @@
+package ir
+
+import (
+	"strconv"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
+)
+
+// === [ Function inlining ] ===================================================
+
+// InlineCall replaces the given direct call instruction with a copy of the body
+// of the callee. The caller is the parent function of the parent basic block of
+// the call instruction.
+//
+// The basic block containing the call instruction is split at the call, and
+// the copied basic blocks of the callee are inserted between the two halves.
+// Parameters of the callee are replaced by the arguments of the call, and
+// return terminators by branches to the continuation basic block; a phi
+// instruction selects the return value if the callee has several return
+// terminators. Alloca instructions of the entry block of the callee with a
+// constant number of elements are moved to the entry block of the caller.
+// Copied basic blocks and local variables whose names are already used by the
+// caller are renamed by appending a numeric suffix (e.g. %tmp.1); the names of
+// the caller are left unchanged.
+//
+// An error is returned for call instructions without parent basic block or
+// function, indirect calls, calls to function declarations,
+// recursive calls, calls with a number of arguments different from the number
+// of parameters of the callee (e.g. variadic calls), and callees with
+// terminators other than ret, br, switch and unreachable (e.g. exception
+// handling terminators).
+func InlineCall(call *InstCall) error {
+	block := call.Parent
+	if block == nil || block.Parent == nil {
+		return errors.Errorf("unable to inline call to %s; parent basic block or function not set", call.Callee.Ident())
+	}
+	caller := block.Parent
+	callee, ok := call.Callee.(*Function)
+	if !ok {
+		return errors.Errorf("unable to inline indirect call to %s", call.Callee.Ident())
+	}
+	if callee == caller {
+		return errors.Errorf("unable to inline recursive call to %s", callee.Ident())
+	}
+	if err := callee.Materialize(); err != nil {
+		return errors.WithStack(err)
+	}
+	if len(callee.Blocks) == 0 {
+		return errors.Errorf("unable to inline call to function declaration %s", callee.Ident())
+	}
+	if callee.Sig.Variadic || len(call.Args) != len(callee.Params) {
+		return errors.Errorf("unable to inline call to %s; mismatch between number of arguments (%d) and parameters (%d)", callee.Ident(), len(call.Args), len(callee.Params))
+	}
+	for _, block := range callee.Blocks {
+		switch block.Term.(type) {
+		case *TermRet, *TermBr, *TermCondBr, *TermSwitch, *TermUnreachable:
+			// valid terminator.
+		default:
+			return errors.Errorf("unable to inline call to %s; support for terminator %T not yet implemented", callee.Ident(), block.Term)
+		}
+	}
+	// Split the basic block at the call instruction, and remove the call.
+	cont, err := block.SplitAt(call)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	cont.Insts = cont.Insts[1:]
+	// Map parameters to arguments, and basic blocks and instructions of the
+	// callee to their copies.
+	valueMap := make(map[value.Value]value.Value)
+	for i, param := range callee.Params {
+		arg := call.Args[i]
+		if a, ok := arg.(*Arg); ok {
+			arg = a.Value
+		}
+		valueMap[param] = arg
+	}
+	blocks := make([]*BasicBlock, len(callee.Blocks))
+	blockMap := make(map[*BasicBlock]*BasicBlock)
+	for i, old := range callee.Blocks {
+		new := &BasicBlock{Parent: caller}
+		if !old.IsUnnamed() {
+			new.SetName(old.LocalName)
+		}
+		blocks[i] = new
+		blockMap[old] = new
+		valueMap[old] = new
+	}
+	var rets []*Incoming
+	for i, old := range callee.Blocks {
+		new := blocks[i]
+		for _, inst := range old.Insts {
+			clone := CloneInstruction(inst)
+			if n, ok := inst.(local); ok {
+				if !n.IsUnnamed() {
+					clone.(local).SetName(n.Name())
+				}
+				valueMap[n] = clone.(local)
+			}
+			if c, ok := clone.(*InstCall); ok {
+				c.Parent = new
+			}
+			new.Insts = append(new.Insts, clone)
+		}
+		switch term := old.Term.(type) {
+		case *TermRet:
+			if term.X != nil {
+				rets = append(rets, NewIncoming(term.X, new))
+			}
+			new.Term = NewBr(cont)
+		case *TermBr:
+			new.Term = &TermBr{Target: blockMap[term.Target], Metadata: term.Metadata}
+		case *TermCondBr:
+			new.Term = &TermCondBr{Cond: term.Cond, TargetTrue: blockMap[term.TargetTrue], TargetFalse: blockMap[term.TargetFalse], Metadata: term.Metadata}
+		case *TermSwitch:
+			cases := make([]*Case, len(term.Cases))
+			for j, c := range term.Cases {
+				cases[j] = NewCase(c.X, blockMap[c.Target])
+			}
+			new.Term = &TermSwitch{X: term.X, TargetDefault: blockMap[term.TargetDefault], Cases: cases, Metadata: term.Metadata}
+		case *TermUnreachable:
+			new.Term = &TermUnreachable{Metadata: term.Metadata}
+		}
+	}
+	for _, new := range blocks {
+		for _, inst := range new.Insts {
+			RemapInstruction(inst, valueMap)
+		}
+		RemapInstruction(new.Term, valueMap)
+	}
+	for _, ret := range rets {
+		if v, ok := valueMap[ret.X]; ok {
+			ret.X = v
+		}
+	}
+	renameCopies(caller, blocks)
+	// Move static alloca instructions to the entry block of the caller.
+	allocas := moveStaticAllocas(blocks[0])
+	entry := caller.Blocks[0]
+	entry.Insts = append(allocas, entry.Insts...)
+	// Insert the copied basic blocks before the continuation basic block.
+	block.Term = NewBr(blocks[0])
+	pos := 0
+	for i, b := range caller.Blocks {
+		if b == cont {
+			pos = i
+			break
+		}
+	}
+	tail := append(blocks, caller.Blocks[pos:]...)
+	caller.Blocks = append(caller.Blocks[:pos], tail...)
+	// Replace uses of the call instruction with the return value.
+	if !types.Equal(call.Type(), types.Void) {
+		var result value.Value
+		switch len(rets) {
+		case 0:
+			result = constant.NewUndef(call.Type())
+		case 1:
+			result = rets[0].X
+		default:
+			phi := NewPhi(rets...)
+			cont.Insts = append([]Instruction{phi}, cont.Insts...)
+			result = phi
+		}
+		uses := map[value.Value]value.Value{call: result}
+		for _, b := range caller.Blocks {
+			for _, inst := range b.Insts {
+				RemapInstruction(inst, uses)
+			}
+			RemapInstruction(b.Term, uses)
+		}
+	}
+	caller.InvalidateCFG()
+	resetLocalIDs(caller)
+	return nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// renameCopies renames the given copied basic blocks and their local variables
+// whose names are already used by the caller, or by a preceding copy. The
+// copied basic blocks must not yet be part of the caller.
+func renameCopies(caller *Function, blocks []*BasicBlock) {
+	used := make(map[string]bool)
+	for _, n := range funcLocals(caller) {
+		used[n.Name()] = true
+	}
+	var copies []local
+	for _, block := range blocks {
+		copies = appendLocal(copies, block)
+		for _, inst := range block.Insts {
+			copies = appendLocal(copies, inst)
+		}
+	}
+	for _, n := range copies {
+		name := n.Name()
+		if !used[name] {
+			used[name] = true
+			continue
+		}
+		if s, err := strconv.Unquote(name); err == nil {
+			// Numeric name; e.g. "42".
+			name = s
+		}
+		n.SetName(uniqueName(name, used))
+	}
+}
+
+// moveStaticAllocas removes the alloca instructions with a constant number of
+// elements from the given basic block, and returns them.
+func moveStaticAllocas(block *BasicBlock) []Instruction {
+	var allocas []Instruction
+	insts := block.Insts[:0]
+	for _, inst := range block.Insts {
+		if alloca, ok := inst.(*InstAlloca); ok {
+			if _, ok := alloca.NElems.(constant.Constant); ok || alloca.NElems == nil {
+				allocas = append(allocas, alloca)
+				continue
+			}
+		}
+		insts = append(insts, inst)
+	}
+	block.Insts = insts
+	return allocas
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/llir/llvm/ir/types"
+	"github.com/pkg/errors"
 )
 
 // InlineAsm is an inline assembler expression.
@@ -60,3 +61,153 @@ func (asm *InlineAsm) Ident() string {
 	fmt.Fprintf(buf, " %s, %s", quote(asm.Asm), quote(asm.Constraint))
 	return buf.String()
 }
+
+// Constraints returns the constraints of the inline assembler expression,
+// parsed from the constraint string.
+func (asm *InlineAsm) Constraints() ([]*AsmConstraint, error) {
+	return ParseAsmConstraints(asm.Constraint)
+}
+
+// ~~~ [ Inline assembler constraint ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// AsmConstraint is a constraint of an inline assembler expression; e.g. "=r",
+// "{eax}" or "~{memory}".
+//
+// ref: https://llvm.org/docs/LangRef.html#inline-asm-constraint-string
+type AsmConstraint struct {
+	// Output constraint ('=' prefix).
+	Output bool
+	// Clobber constraint ('~' prefix).
+	Clobber bool
+	// Indirect constraint ('*' prefix); the operand is a pointer to memory.
+	Indirect bool
+	// Early clobber output ('&' modifier).
+	EarlyClobber bool
+	// Commutative with the following operand ('%' modifier).
+	Commutative bool
+	// Constraint codes; e.g. "r", "m", "{eax}" or "0" (tied to output operand
+	// 0). Alternatives of multiple-alternative constraints are separated by a
+	// "|" code.
+	Codes []string
+}
+
+// String returns the string representation of the inline assembler
+// constraint.
+func (c *AsmConstraint) String() string {
+	buf := &strings.Builder{}
+	switch {
+	case c.Output:
+		buf.WriteString("=")
+	case c.Clobber:
+		buf.WriteString("~")
+	}
+	if c.Indirect {
+		buf.WriteString("*")
+	}
+	if c.EarlyClobber {
+		buf.WriteString("&")
+	}
+	if c.Commutative {
+		buf.WriteString("%")
+	}
+	for _, code := range c.Codes {
+		buf.WriteString(code)
+	}
+	return buf.String()
+}
+
+// ParseAsmConstraints parses the given constraint string of an inline
+// assembler expression into its comma-separated constraints.
+func ParseAsmConstraints(s string) ([]*AsmConstraint, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	var cs []*AsmConstraint
+	start := 0
+	depth := 0
+	for i := 0; i <= len(s); i++ {
+		if i < len(s) {
+			switch s[i] {
+			case '{':
+				depth++
+				continue
+			case '}':
+				depth--
+				continue
+			case ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		c, err := parseAsmConstraint(s[start:i])
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		cs = append(cs, c)
+		start = i + 1
+	}
+	return cs, nil
+}
+
+// parseAsmConstraint parses the given inline assembler constraint.
+func parseAsmConstraint(s string) (*AsmConstraint, error) {
+	c := &AsmConstraint{}
+	i := 0
+	// Constraint kind.
+	if i < len(s) {
+		switch s[i] {
+		case '=':
+			c.Output = true
+			i++
+		case '~':
+			c.Clobber = true
+			i++
+		}
+	}
+	// Modifiers.
+loop:
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '*':
+			c.Indirect = true
+		case '&':
+			c.EarlyClobber = true
+		case '%':
+			c.Commutative = true
+		default:
+			break loop
+		}
+	}
+	// Constraint codes.
+	for i < len(s) {
+		start := i
+		switch ch := s[i]; {
+		case ch == '{':
+			end := strings.IndexByte(s[i:], '}')
+			if end == -1 {
+				return nil, errors.Errorf("invalid inline assembler constraint %q; missing '}'", s)
+			}
+			i += end + 1
+		case '0' <= ch && ch <= '9':
+			for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+				i++
+			}
+		case ch == '^':
+			// Two-letter target-specific constraint code; e.g. "^Rg".
+			i += 3
+			if i > len(s) {
+				return nil, errors.Errorf("invalid inline assembler constraint %q; incomplete constraint code %q", s, s[start:])
+			}
+		default:
+			i++
+		}
+		c.Codes = append(c.Codes, s[start:i])
+	}
+	if len(c.Codes) == 0 {
+		return nil, errors.Errorf("invalid inline assembler constraint %q; missing constraint code", s)
+	}
+	return c, nil
+}
@@ -0,0 +1,61 @@
+package ir
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestInlineAsm(t *testing.T) {
+	// x86 inline assembler with output, tied input, early clobber and clobber
+	// constraints.
+	constraint := "=r,=&{ax},0,*m,~{dirflag},~{fpsr},~{flags}"
+	sig := types.NewFunc(types.Void, types.I32, types.I32Ptr)
+	asm := NewInlineAsm(types.NewPointer(sig), "movl $2, $0", constraint)
+	asm.SideEffect = true
+	f := NewFunc("f", types.Void, NewParam("x", types.I32), NewParam("p", types.I32Ptr))
+	entry := f.NewBlock("")
+	entry.NewCall(asm, f.Params[0], f.Params[1])
+	entry.NewRet(nil)
+	want := `call void asm sideeffect "movl $2, $0", "=r,=&{ax},0,*m,~{dirflag},~{fpsr},~{flags}"(i32 %x, i32* %p)`
+	if got := entry.Insts[0].Def(); want != got {
+		t.Errorf("call instruction mismatch; expected `%v`, got `%v`", want, got)
+	}
+	cs, err := asm.Constraints()
+	if err != nil {
+		t.Fatalf("unable to parse constraints; %v", err)
+	}
+	wantConstraints := []*AsmConstraint{
+		{Output: true, Codes: []string{"r"}},
+		{Output: true, EarlyClobber: true, Codes: []string{"{ax}"}},
+		{Codes: []string{"0"}},
+		{Indirect: true, Codes: []string{"m"}},
+		{Clobber: true, Codes: []string{"{dirflag}"}},
+		{Clobber: true, Codes: []string{"{fpsr}"}},
+		{Clobber: true, Codes: []string{"{flags}"}},
+	}
+	if len(wantConstraints) != len(cs) {
+		t.Fatalf("number of constraints mismatch; expected %d, got %d", len(wantConstraints), len(cs))
+	}
+	for i := range wantConstraints {
+		if want, got := fmt.Sprintf("%+v", *wantConstraints[i]), fmt.Sprintf("%+v", *cs[i]); want != got {
+			t.Errorf("constraint mismatch; expected %v, got %v", want, got)
+		}
+	}
+	// Round-trip constraints.
+	var parts []string
+	for _, c := range cs {
+		parts = append(parts, c.String())
+	}
+	if got := strings.Join(parts, ","); constraint != got {
+		t.Errorf("constraint string mismatch; expected %q, got %q", constraint, got)
+	}
+	// Invalid constraints.
+	for _, s := range []string{"=r,", "{ax", "=&"} {
+		if _, err := ParseAsmConstraints(s); err == nil {
+			t.Errorf("expected error for invalid constraint string %q", s)
+		}
+	}
+}
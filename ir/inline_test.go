@@ -0,0 +1,137 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestInlineCall(t *testing.T) {
+	m := NewModule()
+	// Callee with an alloca and two return terminators.
+	x := NewParam("x", types.I32)
+	abs := m.NewFunc("abs", types.I32, x)
+	entry := abs.NewBlock("entry")
+	neg := abs.NewBlock("neg")
+	pos := abs.NewBlock("pos")
+	tmp := entry.NewAlloca(types.I32)
+	tmp.SetName("tmp")
+	entry.NewStore(x, tmp)
+	cond := entry.NewICmp(enum.IPredSLT, x, constant.NewInt(types.I32, 0))
+	cond.SetName("cond")
+	entry.NewCondBr(cond, neg, pos)
+	y := neg.NewSub(constant.NewInt(types.I32, 0), x)
+	y.SetName("y")
+	neg.NewRet(y)
+	pos.NewRet(x)
+	// Caller.
+	a := NewParam("a", types.I32)
+	f := m.NewFunc("f", types.I32, a)
+	fEntry := f.NewBlock("entry")
+	b := fEntry.NewAdd(a, constant.NewInt(types.I32, 1))
+	b.SetName("b")
+	call := fEntry.NewCall(abs, b)
+	call.SetName("c")
+	cond2 := fEntry.NewMul(call, constant.NewInt(types.I32, 2))
+	cond2.SetName("cond")
+	fEntry.NewRet(cond2)
+	if err := InlineCall(call); err != nil {
+		t.Fatalf("unable to inline call; %v", err)
+	}
+	want := `define i32 @f(i32 %a) {
+entry:
+	%tmp = alloca i32
+	%b = add i32 %a, 1
+	br label %entry.1
+
+entry.1:
+	store i32 %b, i32* %tmp
+	%cond.1 = icmp slt i32 %b, 0
+	br i1 %cond.1, label %neg, label %pos
+
+neg:
+	%y = sub i32 0, %b
+	br label %0
+
+pos:
+	br label %0
+
+; <label>:0
+	%1 = phi i32 [ %y, %neg ], [ %b, %pos ]
+	%cond = mul i32 %1, 2
+	ret i32 %cond
+}`
+	if got := f.Def(); got != want {
+		t.Errorf("function mismatch; expected `%s`, got `%s`", want, got)
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("unable to verify module after inlining; %v", err)
+	}
+	// The callee is left untouched.
+	if got := len(abs.Blocks[0].Insts); got != 3 {
+		t.Errorf("number of instructions of callee mismatch; expected 3, got %d", got)
+	}
+	// Calls without parent basic block, indirect calls and calls to
+	// declarations are rejected.
+	if err := InlineCall(NewCall(abs, a)); err == nil {
+		t.Errorf("expected error for call without parent basic block, got nil")
+	}
+	decl := m.NewFunc("g", types.Void)
+	callDecl := f.Blocks[0].NewCall(decl)
+	if err := InlineCall(callDecl); err == nil {
+		t.Errorf("expected error for call to function declaration, got nil")
+	}
+	callIndirect := f.Blocks[0].NewCall(NewParam("fp", types.NewPointer(types.NewFunc(types.Void))))
+	if err := InlineCall(callIndirect); err == nil {
+		t.Errorf("expected error for indirect call, got nil")
+	}
+}
+
+func TestInlineCallConsecutive(t *testing.T) {
+	m := NewModule()
+	x := NewParam("x", types.I32)
+	inc := m.NewFunc("inc", types.I32, x)
+	entry := inc.NewBlock("entry")
+	y := entry.NewAdd(x, constant.NewInt(types.I32, 1))
+	y.SetName("y")
+	entry.NewRet(y)
+	// The second call is moved to the continuation basic block when inlining
+	// the first call.
+	a := NewParam("a", types.I32)
+	f := m.NewFunc("f", types.I32, a)
+	fEntry := f.NewBlock("entry")
+	first := fEntry.NewCall(inc, a)
+	second := fEntry.NewCall(inc, first)
+	fEntry.NewRet(second)
+	for _, call := range []*InstCall{first, second} {
+		if err := InlineCall(call); err != nil {
+			t.Fatalf("unable to inline call; %v", err)
+		}
+	}
+	want := `define i32 @f(i32 %a) {
+entry:
+	br label %entry.1
+
+entry.1:
+	%y = add i32 %a, 1
+	br label %0
+
+; <label>:0
+	br label %entry.2
+
+entry.2:
+	%y.1 = add i32 %y, 1
+	br label %1
+
+; <label>:1
+	ret i32 %y.1
+}`
+	if got := f.Def(); got != want {
+		t.Errorf("function mismatch; expected `%s`, got `%s`", want, got)
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("unable to verify module after inlining; %v", err)
+	}
+}
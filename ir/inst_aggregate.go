@@ -31,10 +31,12 @@ type InstExtractValue struct {
 }
 
 // NewExtractValue returns a new extractvalue instruction based on the given
-// aggregate value and indicies.
+// aggregate value and indicies. The result type is the element type of the
+// aggregate at the given indices.
 func NewExtractValue(x value.Value, indices ...uint64) *InstExtractValue {
 	inst := &InstExtractValue{X: x, Indices: indices}
 	// Compute type.
+	inst.Type()
 	return inst
 }
 
@@ -91,10 +93,16 @@ type InstInsertValue struct {
 }
 
 // NewInsertValue returns a new insertvalue instruction based on the given
-// aggregate value, element and indicies.
+// aggregate value, element and indicies. The type of the element must match
+// the element type of the aggregate at the given indices.
 func NewInsertValue(x, elem value.Value, indices ...uint64) *InstInsertValue {
 	inst := &InstInsertValue{X: x, Elem: elem, Indices: indices}
 	// Compute type.
+	inst.Type()
+	// Validate type of inserted element.
+	if elemType := aggregateElemType(x.Type(), indices); !elemType.Equal(elem.Type()) {
+		panic(fmt.Errorf("element type mismatch of insertvalue; expected %s, got %s", elemType, elem.Type()))
+	}
 	return inst
 }
 
@@ -140,8 +148,14 @@ func aggregateElemType(t types.Type, indices []uint64) types.Type {
 	}
 	switch t := t.(type) {
 	case *types.ArrayType:
+		if indices[0] >= t.Len {
+			panic(fmt.Errorf("array index %d out of bounds of array type %s", indices[0], t))
+		}
 		return aggregateElemType(t.ElemType, indices[1:])
 	case *types.StructType:
+		if indices[0] >= uint64(len(t.Fields)) {
+			panic(fmt.Errorf("struct index %d out of bounds of struct type %s", indices[0], t))
+		}
 		return aggregateElemType(t.Fields[indices[0]], indices[1:])
 	default:
 		panic(fmt.Errorf("support for aggregate type %T not yet implemented", t))
@@ -0,0 +1,51 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestAggregateElemType(t *testing.T) {
+	inner := types.NewStruct(types.Float, types.I8)
+	st := types.NewStruct(types.I32, inner)
+	arr := types.NewArray(2, st)
+	x := NewParam("x", st)
+	y := NewParam("y", arr)
+	golden := []struct {
+		inst *InstExtractValue
+		want string
+	}{
+		{inst: NewExtractValue(x, 0), want: "i32"},
+		{inst: NewExtractValue(x, 1), want: "{ float, i8 }"},
+		{inst: NewExtractValue(x, 1, 0), want: "float"},
+		{inst: NewExtractValue(y, 1, 1, 1), want: "i8"},
+	}
+	for _, g := range golden {
+		if got := g.inst.Typ.String(); got != g.want {
+			t.Errorf("result type mismatch of extractvalue with indices %v; expected %q, got %q", g.inst.Indices, g.want, got)
+		}
+	}
+	// Insert value of matching type.
+	inst := NewInsertValue(y, constant.NewFloat(types.Float, 1), 0, 1, 0)
+	if got, want := inst.Typ.String(), arr.String(); got != want {
+		t.Errorf("result type mismatch of insertvalue; expected %q, got %q", want, got)
+	}
+	// Invalid indices and mismatching types of inserted values.
+	invalid := []func(){
+		func() { NewExtractValue(x, 2) },
+		func() { NewExtractValue(y, 2, 0) },
+		func() { NewInsertValue(x, constant.NewInt(types.I8, 1), 1, 0) },
+	}
+	for i, f := range invalid {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for invalid aggregate instruction %d", i)
+				}
+			}()
+			f()
+		}()
+	}
+}
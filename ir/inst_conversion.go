@@ -535,8 +535,15 @@ type InstBitCast struct {
 }
 
 // NewBitCast returns a new bitcast instruction based on the given source value
-// and target type.
+// and target type. A bitcast instruction may not change the address space of
+// pointers; use addrspacecast instead.
 func NewBitCast(from value.Value, to types.Type) *InstBitCast {
+	// Validate source and target address spaces.
+	fromPtr, fromIsPtr := pointerType(from.Type())
+	toPtr, toIsPtr := pointerType(to)
+	if fromIsPtr && toIsPtr && fromPtr.AddrSpace != toPtr.AddrSpace {
+		panic(fmt.Errorf("invalid bitcast instruction; unable to convert from `%s` to `%s` in different address spaces (use addrspacecast)", from.Type(), to))
+	}
 	return &InstBitCast{From: from, To: to}
 }
 
@@ -581,8 +588,15 @@ type InstAddrSpaceCast struct {
 }
 
 // NewAddrSpaceCast returns a new addrspacecast instruction based on the given
-// source value and target type.
+// source value and target type. The source and target types must be pointers
+// (or vectors of pointers) in different address spaces.
 func NewAddrSpaceCast(from value.Value, to types.Type) *InstAddrSpaceCast {
+	// Validate source and target address spaces.
+	fromPtr, fromIsPtr := pointerType(from.Type())
+	toPtr, toIsPtr := pointerType(to)
+	if !fromIsPtr || !toIsPtr || fromPtr.AddrSpace == toPtr.AddrSpace {
+		panic(fmt.Errorf("invalid addrspacecast instruction; unable to convert from `%s` to `%s` (expected pointers in different address spaces)", from.Type(), to))
+	}
 	return &InstAddrSpaceCast{From: from, To: to}
 }
 
@@ -609,3 +623,15 @@ func (inst *InstAddrSpaceCast) Def() string {
 	}
 	return buf.String()
 }
+
+// ### [ Helper functions ] ####################################################
+
+// pointerType returns the pointer type of the given pointer or vector of
+// pointers type, and a boolean indicating if the type was a pointer type.
+func pointerType(t types.Type) (*types.PointerType, bool) {
+	if vt, ok := t.(*types.VectorType); ok {
+		t = vt.ElemType
+	}
+	ptr, ok := t.(*types.PointerType)
+	return ptr, ok
+}
@@ -0,0 +1,51 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestAddrSpaceConversions(t *testing.T) {
+	i8Ptr := types.NewPointer(types.I8)
+	i8PtrAS1 := types.NewPointer(types.I8)
+	i8PtrAS1.AddrSpace = 1
+	x := NewParam("x", i8Ptr)
+	xs := NewParam("xs", types.NewVector(2, i8Ptr))
+	// Valid conversions.
+	golden := []struct {
+		inst Instruction
+		want string
+	}{
+		{inst: NewBitCast(x, types.NewPointer(types.I32)), want: "%0 = bitcast i8* %x to i32*"},
+		{inst: NewBitCast(x, types.I64), want: "%0 = bitcast i8* %x to i64"},
+		{inst: NewAddrSpaceCast(x, i8PtrAS1), want: "%0 = addrspacecast i8* %x to i8 addrspace(1)*"},
+		{inst: NewAddrSpaceCast(xs, types.NewVector(2, i8PtrAS1)), want: "%0 = addrspacecast <2 x i8*> %xs to <2 x i8 addrspace(1)*>"},
+	}
+	for _, g := range golden {
+		g.inst.(local).SetID(0)
+		if got := g.inst.Def(); got != g.want {
+			t.Errorf("instruction mismatch; expected %q, got %q", g.want, got)
+		}
+	}
+	// Invalid conversions.
+	invalid := []func(){
+		// bitcast between address spaces.
+		func() { NewBitCast(x, i8PtrAS1) },
+		func() { NewBitCast(xs, types.NewVector(2, i8PtrAS1)) },
+		// addrspacecast within the same address space.
+		func() { NewAddrSpaceCast(x, types.NewPointer(types.I32)) },
+		// addrspacecast of non-pointer.
+		func() { NewAddrSpaceCast(NewParam("y", types.I64), i8PtrAS1) },
+	}
+	for i, f := range invalid {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for invalid conversion instruction %d", i)
+				}
+			}()
+			f()
+		}()
+	}
+}
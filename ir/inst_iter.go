@@ -0,0 +1,104 @@
+package ir
+
+import "fmt"
+
+// === [ Instruction iterator ] ================================================
+
+// InstIter is an iterator over the instructions of a basic block, which
+// supports insertion and removal of instructions during iteration.
+//
+// Instructions inserted during iteration are not visited by the iterator, and
+// removal of the current instruction does not affect which instructions are
+// visited next.
+type InstIter struct {
+	// Basic block of the iterator.
+	block *BasicBlock
+	// Index of the current instruction; or of the instruction following the
+	// current instruction if removed.
+	i int
+	// Current instruction removed.
+	removed bool
+	// Number of instructions inserted after the current instruction.
+	after int
+}
+
+// Iterate returns an iterator over the instructions of the basic block. The
+// iterator is positioned before the first instruction; invoke Next to advance
+// it to the first instruction.
+func (block *BasicBlock) Iterate() *InstIter {
+	return &InstIter{block: block, i: -1}
+}
+
+// Next advances the iterator to the next instruction of the basic block, and
+// reports whether there was a next instruction.
+func (it *InstIter) Next() bool {
+	next := it.i + it.after
+	if !it.removed {
+		next++
+	}
+	it.i = next
+	it.removed = false
+	it.after = 0
+	return it.i < len(it.block.Insts)
+}
+
+// Inst returns the current instruction of the iterator; or nil if the current
+// instruction has been removed.
+func (it *InstIter) Inst() Instruction {
+	it.check()
+	if it.removed {
+		return nil
+	}
+	return it.block.Insts[it.i]
+}
+
+// InsertBefore inserts the given instruction before the current instruction.
+// Instructions inserted before the current instruction are kept in order of
+// insertion.
+func (it *InstIter) InsertBefore(inst Instruction) {
+	it.check()
+	it.insert(it.i, inst)
+	it.i++
+}
+
+// InsertAfter inserts the given instruction after the current instruction.
+// Instructions inserted after the current instruction are kept in order of
+// insertion, and are not visited by the iterator.
+func (it *InstIter) InsertAfter(inst Instruction) {
+	it.check()
+	pos := it.i + it.after
+	if !it.removed {
+		pos++
+	}
+	it.insert(pos, inst)
+	it.after++
+}
+
+// Remove removes the current instruction from the basic block.
+func (it *InstIter) Remove() {
+	it.check()
+	if it.removed {
+		panic(fmt.Errorf("current instruction of basic block %q already removed", it.block.Ident()))
+	}
+	insts := it.block.Insts
+	copy(insts[it.i:], insts[it.i+1:])
+	insts[len(insts)-1] = nil
+	it.block.Insts = insts[:len(insts)-1]
+	it.removed = true
+}
+
+// insert inserts the given instruction at the specified index of the basic
+// block.
+func (it *InstIter) insert(i int, inst Instruction) {
+	insts := append(it.block.Insts, nil)
+	copy(insts[i+1:], insts[i:])
+	insts[i] = inst
+	it.block.Insts = insts
+}
+
+// check panics if the iterator is not positioned at an instruction.
+func (it *InstIter) check() {
+	if it.i < 0 || it.i > len(it.block.Insts) || (!it.removed && it.i == len(it.block.Insts)) {
+		panic(fmt.Errorf("instruction iterator of basic block %q not positioned at an instruction", it.block.Ident()))
+	}
+}
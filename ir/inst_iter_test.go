@@ -0,0 +1,51 @@
+package ir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestInstIter(t *testing.T) {
+	one := constant.NewInt(types.I32, 1)
+	newInst := func(name string) *InstAdd {
+		inst := NewAdd(one, one)
+		inst.SetName(name)
+		return inst
+	}
+	block := NewBlock("")
+	block.Insts = []Instruction{newInst("a"), newInst("b"), newInst("c")}
+	var visited []string
+	for it := block.Iterate(); it.Next(); {
+		name := it.Inst().(*InstAdd).Name()
+		visited = append(visited, name)
+		switch name {
+		case "a":
+			it.InsertBefore(newInst("p"))
+		case "b":
+			// Remove then insert at the same position.
+			it.Remove()
+			it.InsertBefore(newInst("x"))
+			it.InsertAfter(newInst("y"))
+		case "c":
+			// Insert then remove.
+			it.InsertAfter(newInst("q"))
+			it.Remove()
+			if inst := it.Inst(); inst != nil {
+				t.Errorf("removed instruction mismatch; expected nil, got %v", inst)
+			}
+		}
+	}
+	if want, got := "a b c", strings.Join(visited, " "); want != got {
+		t.Errorf("visited instructions mismatch; expected %q, got %q", want, got)
+	}
+	var names []string
+	for _, inst := range block.Insts {
+		names = append(names, inst.(*InstAdd).Name())
+	}
+	if want, got := "p a x y q", strings.Join(names, " "); want != got {
+		t.Errorf("instructions mismatch; expected %q, got %q", want, got)
+	}
+}
@@ -47,6 +47,21 @@ func NewAlloca(elemType types.Type) *InstAlloca {
 	return inst
 }
 
+// NewAllocaInAddrSpace returns a new alloca instruction based on the given
+// element type, allocating memory in the given address space.
+func NewAllocaInAddrSpace(elemType types.Type, addrSpace types.AddrSpace) *InstAlloca {
+	inst := NewAlloca(elemType)
+	inst.Typ.AddrSpace = addrSpace
+	return inst
+}
+
+// SetInAlloca sets the in-alloca property of the alloca instruction, and
+// returns the instruction.
+func (inst *InstAlloca) SetInAlloca(inAlloca bool) *InstAlloca {
+	inst.InAlloca = inAlloca
+	return inst
+}
+
 // String returns the LLVM syntax representation of the instruction as a
 // type-value pair.
 func (inst *InstAlloca) String() string {
@@ -502,7 +517,7 @@ func (inst *InstGetElementPtr) Type() types.Type {
 	}
 	// Cache type if not present.
 	if inst.Typ == nil {
-		inst.Typ = gepType(inst.ElemType, inst.Indices)
+		inst.Typ = gepType(inst.ElemType, inst.Src.Type(), inst.Indices)
 	}
 	return inst.Typ
 }
@@ -531,8 +546,8 @@ func (inst *InstGetElementPtr) Def() string {
 
 // gepType returns the pointer type or vector of pointers type to the element at
 // the position in the type specified by the given indices, as calculated by the
-// getelementptr instruction.
-func gepType(elemType types.Type, indices []value.Value) types.Type {
+// getelementptr instruction with the given source address type.
+func gepType(elemType, srcType types.Type, indices []value.Value) types.Type {
 	e := elemType
 	for i, index := range indices {
 		if i == 0 {
@@ -583,14 +598,18 @@ func gepType(elemType types.Type, indices []value.Value) types.Type {
 			panic(fmt.Errorf("support for indexing element type %T not yet implemented", e))
 		}
 	}
-	// TODO: Validate how index vectors in gep are supposed to work.
+	// The result is a vector of pointers if the source address or any of the
+	// indices is a vector.
 	//
 	// Example from dir.ll:
 	//    %113 = getelementptr inbounds %struct.fileinfo, %struct.fileinfo* %96, <2 x i64> %110, !dbg !4736
 	//    %116 = bitcast i8** %115 to <2 x %struct.fileinfo*>*, !dbg !4738
 	//    store <2 x %struct.fileinfo*> %113, <2 x %struct.fileinfo*>* %116, align 8, !dbg !4738, !tbaa !1793
-	if len(indices) > 0 {
-		if t, ok := indices[0].Type().(*types.VectorType); ok {
+	if t, ok := srcType.(*types.VectorType); ok {
+		return types.NewVector(t.Len, types.NewPointer(e))
+	}
+	for _, index := range indices {
+		if t, ok := index.Type().(*types.VectorType); ok {
 			return types.NewVector(t.Len, types.NewPointer(e))
 		}
 	}
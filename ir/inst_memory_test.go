@@ -0,0 +1,58 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestGetElementPtrVector(t *testing.T) {
+	ptrs := NewParam("ptrs", types.NewVector(4, types.I32Ptr))
+	idx := NewParam("idx", types.NewVector(4, types.I64))
+	arr := NewParam("arr", types.NewPointer(types.NewArray(8, types.I32)))
+	golden := []struct {
+		in   *InstGetElementPtr
+		want string
+	}{
+		// Vector of pointers base and vector index.
+		{in: NewGetElementPtr(ptrs, idx), want: "<4 x i32*>"},
+		// Vector of pointers base and scalar index.
+		{in: NewGetElementPtr(ptrs, constant.NewInt(types.I64, 1)), want: "<4 x i32*>"},
+		// Scalar base and vector index after the first index.
+		{in: NewGetElementPtr(arr, constant.NewInt(types.I64, 0), idx), want: "<4 x i32*>"},
+		// Scalar base and scalar indices.
+		{in: NewGetElementPtr(arr, constant.NewInt(types.I64, 0), constant.NewInt(types.I64, 1)), want: "i32*"},
+	}
+	for _, g := range golden {
+		if got := g.in.Type().String(); g.want != got {
+			t.Errorf("type mismatch of `%s`; expected %q, got %q", g.in.Def(), g.want, got)
+		}
+	}
+}
+
+func TestAllocaAddrSpace(t *testing.T) {
+	f := NewFunc("f", types.Void)
+	entry := f.NewBlock("")
+	x := entry.NewAllocaInAddrSpace(types.I32, 5)
+	y := entry.NewAlloca(types.I32).SetInAlloca(true)
+	entry.NewStore(constant.NewInt(types.I32, 42), x)
+	entry.NewRet(nil)
+	if err := f.AssignIDs(); err != nil {
+		t.Fatalf("unable to assign IDs; %+v", err)
+	}
+	golden := []struct {
+		got, want string
+	}{
+		{got: x.Def(), want: "%1 = alloca i32, addrspace(5)"},
+		{got: x.Type().String(), want: "i32 addrspace(5)*"},
+		{got: y.Def(), want: "%2 = alloca inalloca i32"},
+		{got: y.Type().String(), want: "i32*"},
+		{got: entry.Insts[2].Def(), want: "store i32 42, i32 addrspace(5)* %1"},
+	}
+	for _, g := range golden {
+		if g.want != g.got {
+			t.Errorf("alloca mismatch; expected `%v`, got `%v`", g.want, g.got)
+		}
+	}
+}
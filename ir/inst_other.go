@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/llir/llvm/ir/constant"
 	"github.com/llir/llvm/ir/enum"
 	"github.com/llir/llvm/ir/metadata"
 	"github.com/llir/llvm/ir/types"
 	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
 )
 
 // --- [ Other instructions ] --------------------------------------------------
@@ -319,6 +321,9 @@ type InstCall struct {
 	OperandBundles []*OperandBundle
 	// (optional) Metadata.
 	Metadata []*metadata.Attachment
+
+	// Parent basic block; field set by ir.BasicBlock.NewCall.
+	Parent *BasicBlock
 }
 
 // NewCall returns a new call instruction based on the given callee and function
@@ -419,6 +424,43 @@ func (inst *InstCall) Def() string {
 	return buf.String()
 }
 
+// SetCallee sets the callee of the call instruction to the given value, after
+// validating that the function signature of the callee is compatible with the
+// arguments and result type of the call instruction.
+func (inst *InstCall) SetCallee(callee value.Value) error {
+	t, ok := callee.Type().(*types.PointerType)
+	if !ok {
+		return errors.Errorf("invalid callee type of %q; expected *types.PointerType, got %T", callee.Ident(), callee.Type())
+	}
+	sig, ok := t.ElemType.(*types.FuncType)
+	if !ok {
+		return errors.Errorf("invalid callee type of %q; expected pointer to *types.FuncType, got pointer to %T", callee.Ident(), t.ElemType)
+	}
+	if !sig.RetType.Equal(inst.Type()) {
+		return errors.Errorf("return type mismatch of callee %q; expected %s, got %s", callee.Ident(), inst.Type(), sig.RetType)
+	}
+	if len(inst.Args) < len(sig.Params) || (!sig.Variadic && len(inst.Args) != len(sig.Params)) {
+		return errors.Errorf("argument count mismatch of callee %q; expected %d, got %d", callee.Ident(), len(sig.Params), len(inst.Args))
+	}
+	for i, param := range sig.Params {
+		if argType := inst.Args[i].Type(); !param.Equal(argType) {
+			return errors.Errorf("argument type mismatch of callee %q at index %d; expected %s, got %s", callee.Ident(), i, param, argType)
+		}
+	}
+	inst.Callee = callee
+	// Recompute type, as the function signature is used for variadic callees.
+	inst.Typ = nil
+	inst.Type()
+	return nil
+}
+
+// SetTail sets the tail call marker of the call instruction (e.g. tail,
+// musttail or notail), and returns the instruction.
+func (inst *InstCall) SetTail(tail enum.Tail) *InstCall {
+	inst.Tail = tail
+	return inst
+}
+
 // ~~~ [ va_arg ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstVAArg is an LLVM IR va_arg instruction.
@@ -522,6 +564,27 @@ func (inst *InstLandingPad) Def() string {
 	return buf.String()
 }
 
+// SetCleanup sets whether the landingpad instruction is a cleanup landing pad,
+// and returns the instruction.
+func (inst *InstLandingPad) SetCleanup(cleanup bool) *InstLandingPad {
+	inst.Cleanup = cleanup
+	return inst
+}
+
+// AddCatchClause appends a catch clause of the given exception type to the
+// landingpad instruction, and returns the instruction.
+func (inst *InstLandingPad) AddCatchClause(c constant.Constant) *InstLandingPad {
+	inst.Clauses = append(inst.Clauses, NewClause(enum.ClauseTypeCatch, c))
+	return inst
+}
+
+// AddFilterClause appends a filter clause of the given array of exception types
+// to the landingpad instruction, and returns the instruction.
+func (inst *InstLandingPad) AddFilterClause(c constant.Constant) *InstLandingPad {
+	inst.Clauses = append(inst.Clauses, NewClause(enum.ClauseTypeFilter, c))
+	return inst
+}
+
 // ___ [ Landingpad clause ] ___________________________________________________
 
 // Clause is a landingpad catch or filter clause.
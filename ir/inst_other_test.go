@@ -0,0 +1,90 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestInstCallSetCallee(t *testing.T) {
+	f := NewFunc("f", types.I32, NewParam("x", types.I32))
+	compatible := NewFunc("g", types.I32, NewParam("y", types.I32))
+	badParam := NewFunc("h", types.I32, NewParam("y", types.I64))
+	badRet := NewFunc("k", types.Void, NewParam("y", types.I32))
+	main := NewFunc("main", types.I32)
+	entry := main.NewBlock("")
+	call := entry.NewCall(f, constant.NewInt(types.I32, 42))
+	entry.NewRet(call)
+	golden := []struct {
+		callee value.Value
+		err    string
+	}{
+		// Compatible callee.
+		{callee: compatible},
+		// Incompatible parameter type.
+		{callee: badParam, err: `argument type mismatch of callee "@h" at index 0; expected i64, got i32`},
+		// Incompatible return type.
+		{callee: badRet, err: `return type mismatch of callee "@k"; expected i32, got void`},
+		// Non-function callee.
+		{callee: constant.NewInt(types.I32, 0), err: `invalid callee type of "0"; expected *types.PointerType, got *types.IntType`},
+	}
+	for _, g := range golden {
+		var got string
+		if err := call.SetCallee(g.callee); err != nil {
+			got = err.Error()
+		}
+		if g.err != got {
+			t.Errorf("error mismatch; expected `%v`, got `%v`", g.err, got)
+		}
+	}
+	if call.Callee != compatible {
+		t.Errorf("callee mismatch; expected %v, got %v", compatible.Ident(), call.Callee.Ident())
+	}
+}
+
+func TestLandingPadClauses(t *testing.T) {
+	m := NewModule()
+	i8Ptr := types.NewPointer(types.I8)
+	typeInfo := m.NewGlobalDecl("_ZTIi", i8Ptr)
+	typeInfo.Immutable = true
+	typeInfoPtr := constant.NewBitCast(typeInfo, i8Ptr)
+	lp := NewLandingPad(types.NewStruct(i8Ptr, types.I32))
+	lp.AddCatchClause(typeInfoPtr).AddFilterClause(constant.NewArray(typeInfoPtr)).SetCleanup(true)
+	lp.SetName("lp")
+	const want = "%lp = landingpad { i8*, i32 }\n\t\tcleanup\n\t\tcatch i8* bitcast (i8** @_ZTIi to i8*)\n\t\tfilter [1 x i8*] [i8* bitcast (i8** @_ZTIi to i8*)]"
+	if got := lp.Def(); got != want {
+		t.Errorf("landingpad mismatch; expected %q, got %q", want, got)
+	}
+	if len(lp.Clauses) != 2 || lp.Clauses[0].Type != enum.ClauseTypeCatch || lp.Clauses[1].Type != enum.ClauseTypeFilter {
+		t.Errorf("clause order mismatch; expected catch followed by filter, got %v", lp.Clauses)
+	}
+}
+
+func TestCallTail(t *testing.T) {
+	golden := []struct {
+		tail enum.Tail
+		want string
+	}{
+		{tail: enum.TailNone, want: "%1 = call i32 @g(i32 %x)"},
+		{tail: enum.TailTail, want: "%1 = tail call i32 @g(i32 %x)"},
+		{tail: enum.TailMustTail, want: "%1 = musttail call i32 @g(i32 %x)"},
+		{tail: enum.TailNoTail, want: "%1 = notail call i32 @g(i32 %x)"},
+	}
+	for _, gold := range golden {
+		x := NewParam("x", types.I32)
+		g := NewFunc("g", types.I32, NewParam("", types.I32))
+		f := NewFunc("f", types.I32, x)
+		entry := f.NewBlock("")
+		call := entry.NewCall(g, x).SetTail(gold.tail)
+		entry.NewRet(call)
+		if err := f.AssignIDs(); err != nil {
+			t.Fatalf("unable to assign IDs; %+v", err)
+		}
+		if got := call.Def(); got != gold.want {
+			t.Errorf("call instruction mismatch; expected %q, got %q", gold.want, got)
+		}
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/llir/llvm/ir/constant"
 	"github.com/llir/llvm/ir/metadata"
 	"github.com/llir/llvm/ir/types"
 	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
 )
 
 // --- [ Vector instructions ] -------------------------------------------------
@@ -153,14 +155,54 @@ type InstShuffleVector struct {
 }
 
 // NewShuffleVector returns a new shufflevector instruction based on the given
-// vectors and shuffle mask.
+// vectors and shuffle mask. The result vector has the length of the shuffle
+// mask and the element type of the vectors.
+//
+// The shuffle mask must be a constant vector of i32 indices into the
+// concatenation of x and y, of which each element is either in range, or undef
+// or poison (see NewShuffleMask); or a zeroinitializer, undef or poison vector.
+// The shuffle mask of scalable vectors must be a zeroinitializer (i.e. splat),
+// undef or poison vector.
 func NewShuffleVector(x, y, mask value.Value) *InstShuffleVector {
 	inst := &InstShuffleVector{X: x, Y: y, Mask: mask}
 	// Compute type.
 	inst.Type()
+	// Validate vectors and shuffle mask.
+	if !x.Type().Equal(y.Type()) {
+		panic(fmt.Errorf("vector type mismatch of shufflevector; %s and %s", x.Type(), y.Type()))
+	}
+	if _, err := shuffleMaskIndices(x.Type().(*types.VectorType), mask); err != nil {
+		panic(fmt.Errorf("unable to create shufflevector instruction; %v", err))
+	}
 	return inst
 }
 
+// NewShuffleMask returns a new shuffle mask of shufflevector instructions based
+// on the given indices into the concatenation of the shuffled vectors. The
+// index -1 denotes an undefined element of the resulting vector.
+func NewShuffleMask(indices ...int64) *constant.Vector {
+	elems := make([]constant.Constant, len(indices))
+	for i, index := range indices {
+		if index == -1 {
+			elems[i] = constant.NewUndef(types.I32)
+			continue
+		}
+		elems[i] = constant.NewInt(types.I32, index)
+	}
+	return constant.NewVector(elems...)
+}
+
+// MaskIndices returns the indices of the shuffle mask of the shufflevector
+// instruction into the concatenation of the shuffled vectors. The index -1
+// denotes an undefined (undef or poison) element of the resulting vector.
+func (inst *InstShuffleVector) MaskIndices() ([]int64, error) {
+	t, ok := inst.X.Type().(*types.VectorType)
+	if !ok {
+		return nil, errors.Errorf("invalid vector type; expected *types.VectorType, got %T", inst.X.Type())
+	}
+	return shuffleMaskIndices(t, inst.Mask)
+}
+
 // String returns the LLVM syntax representation of the instruction as a
 // type-value pair.
 func (inst *InstShuffleVector) String() string {
@@ -180,6 +222,7 @@ func (inst *InstShuffleVector) Type() types.Type {
 			panic(fmt.Errorf("invalid vector type; expected *types.VectorType, got %T", inst.Mask.Type()))
 		}
 		inst.Typ = types.NewVector(maskType.Len, xType.ElemType)
+		inst.Typ.Scalable = xType.Scalable
 	}
 	return inst.Typ
 }
@@ -196,3 +239,50 @@ func (inst *InstShuffleVector) Def() string {
 	}
 	return buf.String()
 }
+
+// ### [ Helper functions ] ####################################################
+
+// shuffleMaskIndices returns the indices of the given shuffle mask into the
+// concatenation of two vectors of type t, after validating the shuffle mask.
+// The index -1 denotes an undefined element.
+func shuffleMaskIndices(t *types.VectorType, mask value.Value) ([]int64, error) {
+	maskType, ok := mask.Type().(*types.VectorType)
+	if !ok || !maskType.ElemType.Equal(types.I32) {
+		return nil, errors.Errorf("invalid shuffle mask type; expected vector of i32, got %s", mask.Type())
+	}
+	if maskType.Scalable != t.Scalable {
+		return nil, errors.Errorf("invalid shuffle mask type %s of vector type %s; mismatch of scalable vectors", maskType, t)
+	}
+	indices := make([]int64, maskType.Len)
+	switch mask := mask.(type) {
+	case *constant.ZeroInitializer:
+		// Splat mask; all indices zero.
+		return indices, nil
+	case *constant.Undef, *constant.Poison:
+		for i := range indices {
+			indices[i] = -1
+		}
+		return indices, nil
+	case *constant.Vector:
+		if t.Scalable {
+			return nil, errors.Errorf("invalid shuffle mask %s of scalable vector type %s; expected zeroinitializer", mask.Ident(), t)
+		}
+		for i, elem := range mask.Elems {
+			switch elem := elem.(type) {
+			case *constant.Undef, *constant.Poison:
+				indices[i] = -1
+			case *constant.Int:
+				index := elem.X.Int64()
+				if !elem.X.IsInt64() || index < 0 || index >= int64(2*t.Len) {
+					return nil, errors.Errorf("shuffle mask index %s out of range of vector type %s", elem.X, t)
+				}
+				indices[i] = index
+			default:
+				return nil, errors.Errorf("invalid shuffle mask element %s; expected integer constant, undef or poison", elem.Ident())
+			}
+		}
+		return indices, nil
+	default:
+		return nil, errors.Errorf("invalid shuffle mask %s; expected constant vector, zeroinitializer, undef or poison", mask.Ident())
+	}
+}
@@ -0,0 +1,59 @@
+package ir
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestShuffleVector(t *testing.T) {
+	v4f32 := types.NewVector(4, types.Float)
+	x := NewParam("x", v4f32)
+	y := NewParam("y", v4f32)
+	// Widening shuffle.
+	inst := NewShuffleVector(x, y, NewShuffleMask(0, 4, 1, 5, 2, 6, 3, -1))
+	if got, want := inst.Type().String(), "<8 x float>"; got != want {
+		t.Errorf("result type mismatch; expected %q, got %q", want, got)
+	}
+	if got, want := inst.Mask.String(), "<8 x i32> <i32 0, i32 4, i32 1, i32 5, i32 2, i32 6, i32 3, i32 undef>"; got != want {
+		t.Errorf("shuffle mask mismatch; expected %q, got %q", want, got)
+	}
+	indices, err := inst.MaskIndices()
+	if err != nil {
+		t.Fatalf("unable to get shuffle mask indices; %+v", err)
+	}
+	if got, want := fmt.Sprint(indices), "[0 4 1 5 2 6 3 -1]"; got != want {
+		t.Errorf("shuffle mask indices mismatch; expected %q, got %q", want, got)
+	}
+	// Splat of scalable vector.
+	nxv4f32 := &types.VectorType{Len: 4, ElemType: types.Float, Scalable: true}
+	nxv4i32 := &types.VectorType{Len: 4, ElemType: types.I32, Scalable: true}
+	z := NewParam("z", nxv4f32)
+	splat := NewShuffleVector(z, constant.NewUndef(nxv4f32), constant.NewZeroInitializer(nxv4i32))
+	if got, want := splat.Type().String(), "<vscale x 4 x float>"; got != want {
+		t.Errorf("result type mismatch; expected %q, got %q", want, got)
+	}
+	// Invalid shuffle masks.
+	invalid := []func(){
+		// Index out of range.
+		func() { NewShuffleVector(x, y, NewShuffleMask(0, 8)) },
+		// Non-constant mask.
+		func() { NewShuffleVector(x, y, NewParam("mask", types.NewVector(4, types.I32))) },
+		// Mask of scalable vector other than splat.
+		func() { NewShuffleVector(z, z, constant.NewVector(constant.NewInt(types.I32, 1))) },
+		// Mismatching vector types.
+		func() { NewShuffleVector(x, z, NewShuffleMask(0)) },
+	}
+	for i, f := range invalid {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for invalid shufflevector instruction %d", i)
+				}
+			}()
+			f()
+		}()
+	}
+}
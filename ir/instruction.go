@@ -1,5 +1,7 @@
 package ir
 
+import "github.com/llir/llvm/ir/enum"
+
 // === [ Instructions ] ========================================================
 
 // Instruction is an LLVM IR instruction. All instructions (except store and
@@ -97,6 +99,8 @@ package ir
 type Instruction interface {
 	// Def returns the LLVM syntax representation of the instruction.
 	Def() string
+	// Opcode returns the opcode of the instruction.
+	Opcode() enum.Opcode
 	// isInstruction ensures that only instructions can be assigned to the
 	// instruction.Instruction interface.
 	isInstruction()
@@ -0,0 +1,40 @@
+package ir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestInstMetadataAttachment(t *testing.T) {
+	m := NewModule()
+	weights := &metadata.Def{
+		ID: 0,
+		Node: &metadata.Tuple{
+			Fields: []metadata.Field{
+				&metadata.String{Value: "branch_weights"},
+				constant.NewInt(types.I32, 1),
+				constant.NewInt(types.I32, 2000),
+			},
+		},
+	}
+	m.MetadataDefs = append(m.MetadataDefs, weights)
+	cond := NewParam("cond", types.I1)
+	f := m.NewFunc("f", types.Void, cond)
+	entry := f.NewBlock("entry")
+	then := f.NewBlock("then")
+	exit := f.NewBlock("exit")
+	br := entry.NewCondBr(cond, then, exit)
+	br.Metadata = append(br.Metadata, &metadata.Attachment{Name: "prof", Node: weights})
+	then.NewBr(exit)
+	exit.NewRet(nil)
+	if got, want := br.Def(), "br i1 %cond, label %then, label %exit, !prof !0"; got != want {
+		t.Errorf("terminator mismatch; expected %q, got %q", want, got)
+	}
+	if want := `!0 = !{!"branch_weights", i32 1, i32 2000}`; !strings.Contains(m.String(), want) {
+		t.Errorf("module mismatch; expected metadata definition %q in %q", want, m.String())
+	}
+}
@@ -0,0 +1,749 @@
+package ir
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
+)
+
+// === [ Interpreter ] =========================================================
+
+// maxInterpSteps is the maximum number of instructions executed by Interpret
+// before giving up; e.g. on infinite loops.
+const maxInterpSteps = 1 << 24
+
+// Interpret executes the given function definition with the given arguments,
+// and returns its return value; or nil if the function returns void. Interpret
+// is intended for testing transformations, by comparing the results of
+// functions before and after transformation.
+//
+// Interpret supports integer and floating-point (float and double) scalar
+// values, and the following operations: binary and bitwise instructions,
+// comparisons, conversions between integers and floating-point values, select,
+// phi, calls to function definitions, and alloca, load, store and
+// getelementptr on a simulated memory, which contains the stack allocations of
+// the interpreted functions and the global variables referenced by them. The
+// ret, br, conditional br and switch terminators are supported.
+//
+// An error is returned for unsupported operations (e.g. calls to function
+// declarations, volatile memory accesses and vector values), for undefined
+// behaviour detected during execution (e.g. division by zero), and if a pointer
+// into the simulated memory is returned.
+func Interpret(f *Function, args []constant.Constant) (constant.Constant, error) {
+	in := &interpreter{globals: make(map[*Global]*memObject)}
+	vals := make([]value.Value, len(args))
+	for i, arg := range args {
+		vals[i] = arg
+	}
+	ret, err := in.call(f, vals)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if ret == nil {
+		return nil, nil
+	}
+	c, ok := ret.(constant.Constant)
+	if !ok {
+		return nil, errors.Errorf("unable to return pointer into simulated memory from function %s", f.Ident())
+	}
+	return c, nil
+}
+
+// interpreter is an interpreter of LLVM IR functions.
+type interpreter struct {
+	// Memory objects of global variables.
+	globals map[*Global]*memObject
+	// Number of executed instructions.
+	steps int
+}
+
+// frame is a stack frame of an interpreted function.
+type frame struct {
+	// Interpreted function.
+	f *Function
+	// Map from parameter or instruction to value.
+	vals map[value.Value]value.Value
+}
+
+// call executes the given function definition with the given arguments, and
+// returns its return value; or nil if void.
+func (in *interpreter) call(f *Function, args []value.Value) (value.Value, error) {
+	if err := f.Materialize(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(f.Blocks) == 0 {
+		return nil, errors.Errorf("unable to interpret call to function declaration %s", f.Ident())
+	}
+	if len(args) != len(f.Params) {
+		return nil, errors.Errorf("unable to interpret call to %s; mismatch between number of arguments (%d) and parameters (%d)", f.Ident(), len(args), len(f.Params))
+	}
+	fr := &frame{f: f, vals: make(map[value.Value]value.Value)}
+	for i, param := range f.Params {
+		fr.vals[param] = args[i]
+	}
+	var pred *BasicBlock
+	block := f.Blocks[0]
+	for {
+		// Evaluate the phi instructions of the basic block simultaneously.
+		insts := block.Insts
+		phis := make(map[value.Value]value.Value)
+		for len(insts) > 0 {
+			phi, ok := insts[0].(*InstPhi)
+			if !ok {
+				break
+			}
+			v, err := in.phi(fr, phi, pred)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			phis[phi] = v
+			insts = insts[1:]
+		}
+		for phi, v := range phis {
+			fr.vals[phi] = v
+		}
+		for _, inst := range insts {
+			in.steps++
+			if in.steps > maxInterpSteps {
+				return nil, errors.Errorf("unable to interpret function %s; maximum number of steps (%d) exceeded", f.Ident(), maxInterpSteps)
+			}
+			if err := in.exec(fr, inst); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+		switch term := block.Term.(type) {
+		case *TermRet:
+			if term.X == nil {
+				return nil, nil
+			}
+			return in.get(fr, term.X)
+		case *TermBr:
+			pred, block = block, term.Target
+		case *TermCondBr:
+			cond, err := in.getInt(fr, term.Cond)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			pred = block
+			if cond.X.Sign() != 0 {
+				block = term.TargetTrue
+			} else {
+				block = term.TargetFalse
+			}
+		case *TermSwitch:
+			x, err := in.getInt(fr, term.X)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			pred, block = block, term.TargetDefault
+			for _, c := range term.Cases {
+				y, ok := c.X.(*constant.Int)
+				if !ok {
+					return nil, errors.Errorf("support for switch case comparand %T not yet implemented", c.X)
+				}
+				if x.X.Cmp(y.X) == 0 {
+					block = c.Target
+					break
+				}
+			}
+		case *TermUnreachable:
+			return nil, errors.Errorf("unreachable terminator reached in function %s", f.Ident())
+		default:
+			return nil, errors.Errorf("support for terminator %T not yet implemented", term)
+		}
+	}
+}
+
+// phi returns the incoming value of the given phi instruction from the given
+// predecessor basic block.
+func (in *interpreter) phi(fr *frame, phi *InstPhi, pred *BasicBlock) (value.Value, error) {
+	for _, inc := range phi.Incs {
+		if inc.Pred == pred {
+			return in.get(fr, inc.X)
+		}
+	}
+	return nil, errors.Errorf("unable to locate incoming value of phi instruction %s from predecessor basic block", phi.Ident())
+}
+
+// exec executes the given instruction.
+func (in *interpreter) exec(fr *frame, inst Instruction) error {
+	var ops []value.Value
+	for _, op := range operands(inst) {
+		// Function callees are resolved separately.
+		if _, ok := (*op).(*Function); ok {
+			ops = append(ops, *op)
+			continue
+		}
+		v, err := in.get(fr, *op)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		ops = append(ops, v)
+	}
+	var result value.Value
+	switch inst := inst.(type) {
+	// Binary and bitwise instructions.
+	case *InstAdd, *InstSub, *InstMul, *InstUDiv, *InstSDiv, *InstURem, *InstSRem, *InstShl, *InstLShr, *InstAShr, *InstAnd, *InstOr, *InstXor:
+		x, y, err := intOperands(ops[0], ops[1])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		result, err = intBinaryOp(inst.Opcode(), x, y)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	case *InstFAdd, *InstFSub, *InstFMul, *InstFDiv, *InstFRem:
+		x, y, err := floatOperands(ops[0], ops[1])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		result, err = floatBinaryOp(inst.Opcode(), x, y)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	// Memory instructions.
+	case *InstAlloca:
+		if len(ops) > 0 {
+			if n, ok := ops[0].(*constant.Int); !ok || n.X.Cmp(big.NewInt(1)) != 0 {
+				return errors.Errorf("support for alloca with number of elements %s not yet implemented", inst.NElems)
+			}
+		}
+		obj := &memObject{cells: make(map[string]value.Value)}
+		result = &memPtr{obj: obj, typ: inst.Type(), path: []int64{0}}
+	case *InstLoad:
+		if inst.Volatile || inst.Atomic {
+			return errors.Errorf("support for volatile or atomic load not yet implemented")
+		}
+		ptr, ok := ops[0].(*memPtr)
+		if !ok {
+			return errors.Errorf("unable to load from %s; not a pointer into simulated memory", inst.Src.Ident())
+		}
+		v, err := ptr.obj.load(ptr.path, inst.Type())
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		result = v
+	case *InstStore:
+		if inst.Volatile || inst.Atomic {
+			return errors.Errorf("support for volatile or atomic store not yet implemented")
+		}
+		ptr, ok := ops[1].(*memPtr)
+		if !ok {
+			return errors.Errorf("unable to store to %s; not a pointer into simulated memory", inst.Dst.Ident())
+		}
+		ptr.obj.cells[pathKey(ptr.path)] = ops[0]
+		return nil
+	case *InstGetElementPtr:
+		ptr, ok := ops[0].(*memPtr)
+		if !ok {
+			return errors.Errorf("unable to compute address of %s; not a pointer into simulated memory", inst.Src.Ident())
+		}
+		path := append([]int64(nil), ptr.path...)
+		for i, op := range ops[1:] {
+			index, ok := op.(*constant.Int)
+			if !ok || !index.X.IsInt64() {
+				return errors.Errorf("support for getelementptr index %s not yet implemented", inst.Indices[i].Ident())
+			}
+			if i == 0 {
+				path[len(path)-1] += index.X.Int64()
+			} else {
+				path = append(path, index.X.Int64())
+			}
+		}
+		result = &memPtr{obj: ptr.obj, typ: inst.Type(), path: path}
+	// Conversion instructions.
+	case *InstTrunc, *InstZExt, *InstSExt, *InstFPTrunc, *InstFPExt, *InstFPToUI, *InstFPToSI, *InstUIToFP, *InstSIToFP, *InstBitCast:
+		v, err := convert(inst.Opcode(), ops[0], inst.(value.Value).Type())
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		result = v
+	// Other instructions.
+	case *InstICmp:
+		x, y, err := intOperands(ops[0], ops[1])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		result = constant.NewBool(intCompare(inst.Pred, x, y))
+	case *InstFCmp:
+		x, y, err := floatOperands(ops[0], ops[1])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		result = constant.NewBool(floatCompare(inst.Pred, x, y))
+	case *InstSelect:
+		cond, ok := ops[0].(*constant.Int)
+		if !ok {
+			return errors.Errorf("support for select condition %s not yet implemented", inst.Cond.Ident())
+		}
+		if cond.X.Sign() != 0 {
+			result = ops[1]
+		} else {
+			result = ops[2]
+		}
+	case *InstCall:
+		callee, ok := inst.Callee.(*Function)
+		if !ok {
+			return errors.Errorf("support for indirect call to %s not yet implemented", inst.Callee.Ident())
+		}
+		if len(inst.OperandBundles) > 0 {
+			return errors.Errorf("support for call with operand bundles not yet implemented")
+		}
+		v, err := in.call(callee, ops[1:])
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if v == nil {
+			return nil
+		}
+		result = v
+	default:
+		return errors.Errorf("support for instruction %T not yet implemented", inst)
+	}
+	fr.vals[inst.(value.Value)] = result
+	return nil
+}
+
+// get returns the value of the given operand.
+func (in *interpreter) get(fr *frame, v value.Value) (value.Value, error) {
+	switch v := v.(type) {
+	case *Arg:
+		return in.get(fr, v.Value)
+	case *constant.Int, *constant.Float, *constant.Undef:
+		return v, nil
+	case *constant.ZeroInitializer:
+		return v.Expand(), nil
+	case *Global:
+		obj, ok := in.globals[v]
+		if !ok {
+			obj = &memObject{cells: make(map[string]value.Value), init: v.Init}
+			in.globals[v] = obj
+		}
+		return &memPtr{obj: obj, typ: v.Type(), path: []int64{0}}, nil
+	case constant.Constant:
+		return nil, errors.Errorf("support for constant %s not yet implemented", v)
+	}
+	x, ok := fr.vals[v]
+	if !ok {
+		return nil, errors.Errorf("unable to locate value of %s in function %s", v.Ident(), fr.f.Ident())
+	}
+	return x, nil
+}
+
+// getInt returns the value of the given integer operand.
+func (in *interpreter) getInt(fr *frame, v value.Value) (*constant.Int, error) {
+	x, err := in.get(fr, v)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	c, ok := x.(*constant.Int)
+	if !ok {
+		return nil, errors.Errorf("support for value %s not yet implemented; expected integer constant", x)
+	}
+	return c, nil
+}
+
+// ~~~ [ Simulated memory ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// memObject is a memory object of the simulated memory; i.e. a stack
+// allocation or global variable.
+type memObject struct {
+	// Memory cells, keyed by index path.
+	cells map[string]value.Value
+	// (optional) Initializer of global variable; used for memory cells not yet
+	// stored to.
+	init constant.Constant
+}
+
+// load returns the value of the memory cell at the given index path.
+func (obj *memObject) load(path []int64, typ types.Type) (value.Value, error) {
+	if v, ok := obj.cells[pathKey(path)]; ok {
+		return v, nil
+	}
+	if obj.init == nil {
+		// Uninitialized memory.
+		return constant.NewUndef(typ), nil
+	}
+	if path[0] != 0 {
+		return nil, errors.Errorf("out of bounds access of global variable initializer at index %d", path[0])
+	}
+	c := obj.init
+	for _, index := range path[1:] {
+		if zero, ok := c.(*constant.ZeroInitializer); ok {
+			c = zero.Expand()
+		}
+		switch x := c.(type) {
+		case *constant.Array:
+			if index < 0 || index >= int64(len(x.Elems)) {
+				return nil, errors.Errorf("out of bounds access of array at index %d", index)
+			}
+			c = x.Elems[index]
+		case *constant.CharArray:
+			if index < 0 || index >= int64(len(x.X)) {
+				return nil, errors.Errorf("out of bounds access of array at index %d", index)
+			}
+			c = constant.NewInt(types.I8, int64(x.X[index]))
+		case *constant.Struct:
+			if index < 0 || index >= int64(len(x.Fields)) {
+				return nil, errors.Errorf("out of bounds access of struct at index %d", index)
+			}
+			c = x.Fields[index]
+		default:
+			return nil, errors.Errorf("support for global variable initializer %s not yet implemented", c)
+		}
+	}
+	if zero, ok := c.(*constant.ZeroInitializer); ok {
+		c = zero.Expand()
+	}
+	return c, nil
+}
+
+// memPtr is a pointer into a memory object of the simulated memory.
+type memPtr struct {
+	// Memory object.
+	obj *memObject
+	// Pointer type.
+	typ types.Type
+	// Index path of the memory cell within the memory object.
+	path []int64
+}
+
+// String returns the string representation of the pointer as a type-value pair.
+func (p *memPtr) String() string {
+	return fmt.Sprintf("%s %s", p.typ, p.Ident())
+}
+
+// Type returns the type of the pointer.
+func (p *memPtr) Type() types.Type {
+	return p.typ
+}
+
+// Ident returns the identifier associated with the pointer.
+func (p *memPtr) Ident() string {
+	return fmt.Sprintf("<pointer %s>", pathKey(p.path))
+}
+
+// pathKey returns the key of the memory cell at the given index path.
+func pathKey(path []int64) string {
+	var keys []string
+	for _, index := range path {
+		keys = append(keys, fmt.Sprint(index))
+	}
+	return strings.Join(keys, ".")
+}
+
+// ~~~ [ Integer operations ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// intOperands returns the given operands as integer constants of the same
+// type.
+func intOperands(x, y value.Value) (*constant.Int, *constant.Int, error) {
+	a, ok := x.(*constant.Int)
+	if !ok {
+		return nil, nil, errors.Errorf("support for operand %s not yet implemented; expected integer constant", x)
+	}
+	b, ok := y.(*constant.Int)
+	if !ok {
+		return nil, nil, errors.Errorf("support for operand %s not yet implemented; expected integer constant", y)
+	}
+	return a, b, nil
+}
+
+// intBinaryOp returns the result of the given binary or bitwise operation on
+// the integer operands.
+func intBinaryOp(op enum.Opcode, x, y *constant.Int) (*constant.Int, error) {
+	bits := x.Typ.BitSize
+	ux, uy := toUnsigned(x.X, bits), toUnsigned(y.X, bits)
+	sx, sy := toSigned(x.X, bits), toSigned(y.X, bits)
+	z := new(big.Int)
+	switch op {
+	case enum.OpcodeAdd:
+		z.Add(ux, uy)
+	case enum.OpcodeSub:
+		z.Sub(ux, uy)
+	case enum.OpcodeMul:
+		z.Mul(ux, uy)
+	case enum.OpcodeUDiv, enum.OpcodeSDiv, enum.OpcodeURem, enum.OpcodeSRem:
+		if uy.Sign() == 0 {
+			return nil, errors.Errorf("integer division by zero")
+		}
+		switch op {
+		case enum.OpcodeUDiv:
+			z.Quo(ux, uy)
+		case enum.OpcodeSDiv:
+			z.Quo(sx, sy)
+		case enum.OpcodeURem:
+			z.Rem(ux, uy)
+		case enum.OpcodeSRem:
+			z.Rem(sx, sy)
+		}
+	case enum.OpcodeShl, enum.OpcodeLShr, enum.OpcodeAShr:
+		if uy.Cmp(big.NewInt(int64(bits))) >= 0 {
+			return nil, errors.Errorf("shift amount %s exceeds bit width %d", uy, bits)
+		}
+		n := uint(uy.Uint64())
+		switch op {
+		case enum.OpcodeShl:
+			z.Lsh(ux, n)
+		case enum.OpcodeLShr:
+			z.Rsh(ux, n)
+		case enum.OpcodeAShr:
+			z.Rsh(sx, n)
+		}
+	case enum.OpcodeAnd:
+		z.And(ux, uy)
+	case enum.OpcodeOr:
+		z.Or(ux, uy)
+	case enum.OpcodeXor:
+		z.Xor(ux, uy)
+	default:
+		panic(fmt.Errorf("support for integer operation %v not yet implemented", op))
+	}
+	return newIntValue(x.Typ, z), nil
+}
+
+// intCompare returns the result of the given integer comparison.
+func intCompare(pred enum.IPred, x, y *constant.Int) bool {
+	bits := x.Typ.BitSize
+	u := toUnsigned(x.X, bits).Cmp(toUnsigned(y.X, bits))
+	s := toSigned(x.X, bits).Cmp(toSigned(y.X, bits))
+	switch pred {
+	case enum.IPredEQ:
+		return u == 0
+	case enum.IPredNE:
+		return u != 0
+	case enum.IPredSGE:
+		return s >= 0
+	case enum.IPredSGT:
+		return s > 0
+	case enum.IPredSLE:
+		return s <= 0
+	case enum.IPredSLT:
+		return s < 0
+	case enum.IPredUGE:
+		return u >= 0
+	case enum.IPredUGT:
+		return u > 0
+	case enum.IPredULE:
+		return u <= 0
+	case enum.IPredULT:
+		return u < 0
+	default:
+		panic(fmt.Errorf("support for integer predicate %v not yet implemented", pred))
+	}
+}
+
+// newIntValue returns a new integer constant of the given type, with the value
+// of x truncated to the bit width of the type. Booleans are represented as 0
+// and 1, and other integers as signed values.
+func newIntValue(typ *types.IntType, x *big.Int) *constant.Int {
+	if typ.BitSize == 1 {
+		return &constant.Int{Typ: typ, X: toUnsigned(x, 1)}
+	}
+	return &constant.Int{Typ: typ, X: toSigned(x, typ.BitSize)}
+}
+
+// toUnsigned returns x truncated to the given bit width, as an unsigned value.
+func toUnsigned(x *big.Int, bits uint64) *big.Int {
+	mod := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	return new(big.Int).Mod(x, mod)
+}
+
+// toSigned returns x truncated to the given bit width, as a signed value.
+func toSigned(x *big.Int, bits uint64) *big.Int {
+	u := toUnsigned(x, bits)
+	if u.Bit(int(bits-1)) == 1 {
+		mod := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+		u.Sub(u, mod)
+	}
+	return u
+}
+
+// ~~~ [ Floating-point operations ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// floatOperands returns the given operands as floating-point constants.
+func floatOperands(x, y value.Value) (*constant.Float, *constant.Float, error) {
+	a, ok := x.(*constant.Float)
+	if !ok {
+		return nil, nil, errors.Errorf("support for operand %s not yet implemented; expected floating-point constant", x)
+	}
+	b, ok := y.(*constant.Float)
+	if !ok {
+		return nil, nil, errors.Errorf("support for operand %s not yet implemented; expected floating-point constant", y)
+	}
+	return a, b, nil
+}
+
+// floatBinaryOp returns the result of the given binary operation on the
+// floating-point operands.
+func floatBinaryOp(op enum.Opcode, x, y *constant.Float) (*constant.Float, error) {
+	a, err := floatValue(x)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	b, err := floatValue(y)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var z float64
+	switch op {
+	case enum.OpcodeFAdd:
+		z = a + b
+	case enum.OpcodeFSub:
+		z = a - b
+	case enum.OpcodeFMul:
+		z = a * b
+	case enum.OpcodeFDiv:
+		z = a / b
+	case enum.OpcodeFRem:
+		z = math.Mod(a, b)
+	default:
+		panic(fmt.Errorf("support for floating-point operation %v not yet implemented", op))
+	}
+	return newFloatValue(x.Typ, z)
+}
+
+// floatCompare returns the result of the given floating-point comparison.
+func floatCompare(pred enum.FPred, x, y *constant.Float) bool {
+	a, _ := floatValue(x)
+	b, _ := floatValue(y)
+	unordered := math.IsNaN(a) || math.IsNaN(b)
+	switch pred {
+	case enum.FPredFalse:
+		return false
+	case enum.FPredOEQ:
+		return !unordered && a == b
+	case enum.FPredOGE:
+		return !unordered && a >= b
+	case enum.FPredOGT:
+		return !unordered && a > b
+	case enum.FPredOLE:
+		return !unordered && a <= b
+	case enum.FPredOLT:
+		return !unordered && a < b
+	case enum.FPredONE:
+		return !unordered && a != b
+	case enum.FPredORD:
+		return !unordered
+	case enum.FPredTrue:
+		return true
+	case enum.FPredUEQ:
+		return unordered || a == b
+	case enum.FPredUGE:
+		return unordered || a >= b
+	case enum.FPredUGT:
+		return unordered || a > b
+	case enum.FPredULE:
+		return unordered || a <= b
+	case enum.FPredULT:
+		return unordered || a < b
+	case enum.FPredUNE:
+		return unordered || a != b
+	case enum.FPredUNO:
+		return unordered
+	default:
+		panic(fmt.Errorf("support for floating-point predicate %v not yet implemented", pred))
+	}
+}
+
+// floatValue returns the value of the given float or double constant.
+func floatValue(x *constant.Float) (float64, error) {
+	switch x.Typ.Kind {
+	case types.FloatKindFloat, types.FloatKindDouble:
+		// valid floating-point kind.
+	default:
+		return 0, errors.Errorf("support for floating-point type %s not yet implemented", x.Typ)
+	}
+	if x.NaN {
+		return math.NaN(), nil
+	}
+	f, _ := x.X.Float64()
+	return f, nil
+}
+
+// newFloatValue returns a new floating-point constant of the given float or
+// double type, with the value of x rounded to the precision of the type.
+func newFloatValue(typ *types.FloatType, x float64) (*constant.Float, error) {
+	switch typ.Kind {
+	case types.FloatKindFloat:
+		x = float64(float32(x))
+	case types.FloatKindDouble:
+		// nothing to do.
+	default:
+		return nil, errors.Errorf("support for floating-point type %s not yet implemented", typ)
+	}
+	return constant.NewFloat(typ, x), nil
+}
+
+// ~~~ [ Conversion operations ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// convert returns the result of the given conversion of x to the given type.
+func convert(op enum.Opcode, x value.Value, to types.Type) (value.Value, error) {
+	if ptr, ok := x.(*memPtr); ok && op == enum.OpcodeBitCast {
+		return &memPtr{obj: ptr.obj, typ: to, path: ptr.path}, nil
+	}
+	switch x := x.(type) {
+	case *constant.Int:
+		bits := x.Typ.BitSize
+		switch to := to.(type) {
+		case *types.IntType:
+			switch op {
+			case enum.OpcodeTrunc, enum.OpcodeZExt:
+				return newIntValue(to, toUnsigned(x.X, bits)), nil
+			case enum.OpcodeSExt:
+				return newIntValue(to, toSigned(x.X, bits)), nil
+			case enum.OpcodeBitCast:
+				return newIntValue(to, x.X), nil
+			}
+		case *types.FloatType:
+			switch op {
+			case enum.OpcodeUIToFP, enum.OpcodeSIToFP:
+				v := toUnsigned(x.X, bits)
+				if op == enum.OpcodeSIToFP {
+					v = toSigned(x.X, bits)
+				}
+				f, _ := new(big.Float).SetInt(v).Float64()
+				return newFloatValue(to, f)
+			case enum.OpcodeBitCast:
+				u := toUnsigned(x.X, bits).Uint64()
+				if to.Kind == types.FloatKindFloat {
+					return newFloatValue(to, float64(math.Float32frombits(uint32(u))))
+				}
+				return newFloatValue(to, math.Float64frombits(u))
+			}
+		}
+	case *constant.Float:
+		f, err := floatValue(x)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		switch to := to.(type) {
+		case *types.FloatType:
+			switch op {
+			case enum.OpcodeFPTrunc, enum.OpcodeFPExt, enum.OpcodeBitCast:
+				return newFloatValue(to, f)
+			}
+		case *types.IntType:
+			switch op {
+			case enum.OpcodeFPToUI, enum.OpcodeFPToSI:
+				if math.IsNaN(f) || math.IsInf(f, 0) {
+					return nil, errors.Errorf("unable to convert %v to integer", f)
+				}
+				v, _ := new(big.Float).SetFloat64(f).Int(nil)
+				return newIntValue(to, v), nil
+			case enum.OpcodeBitCast:
+				if x.Typ.Kind == types.FloatKindFloat {
+					return newIntValue(to, new(big.Int).SetUint64(uint64(math.Float32bits(float32(f))))), nil
+				}
+				return newIntValue(to, new(big.Int).SetUint64(math.Float64bits(f))), nil
+			}
+		}
+	}
+	return nil, errors.Errorf("support for %v of %s to %s not yet implemented", op, x, to)
+}
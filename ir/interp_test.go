@@ -0,0 +1,113 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestInterpret(t *testing.T) {
+	m := NewModule()
+	// Factorial loop, with the accumulator stored in a stack allocation.
+	n := NewParam("n", types.I64)
+	fact := m.NewFunc("fact", types.I64, n)
+	entry := fact.NewBlock("entry")
+	loop := fact.NewBlock("loop")
+	exit := fact.NewBlock("exit")
+	acc := entry.NewAlloca(types.I64)
+	entry.NewStore(constant.NewInt(types.I64, 1), acc)
+	entry.NewBr(loop)
+	i := loop.NewPhi(NewIncoming(constant.NewInt(types.I64, 1), entry))
+	prod := loop.NewMul(loop.NewLoad(acc), i)
+	loop.NewStore(prod, acc)
+	next := loop.NewAdd(i, constant.NewInt(types.I64, 1))
+	i.Incs = append(i.Incs, NewIncoming(next, loop))
+	loop.NewCondBr(loop.NewICmp(enum.IPredSLE, next, n), loop, exit)
+	exit.NewRet(exit.NewLoad(acc))
+	// Caller, truncating the result of a call and summing a global array.
+	arr := m.NewGlobalDef("arr", constant.NewArray(constant.NewInt(types.I32, 10), constant.NewInt(types.I32, 20)))
+	x := NewParam("x", types.I64)
+	f := m.NewFunc("f", types.I32, x)
+	fEntry := f.NewBlock("entry")
+	r := fEntry.NewTrunc(fEntry.NewCall(fact, x), types.I32)
+	elem := fEntry.NewLoad(fEntry.NewGetElementPtr(arr, constant.NewInt(types.I64, 0), constant.NewInt(types.I64, 1)))
+	fEntry.NewRet(fEntry.NewAdd(r, elem))
+	golden := []struct {
+		f    *Function
+		args []constant.Constant
+		want string
+	}{
+		{f: fact, args: []constant.Constant{constant.NewInt(types.I64, 1)}, want: "i64 1"},
+		{f: fact, args: []constant.Constant{constant.NewInt(types.I64, 5)}, want: "i64 120"},
+		{f: fact, args: []constant.Constant{constant.NewInt(types.I64, 20)}, want: "i64 2432902008176640000"},
+		// 21! overflows 64 bits.
+		{f: fact, args: []constant.Constant{constant.NewInt(types.I64, 21)}, want: "i64 -4249290049419214848"},
+		{f: f, args: []constant.Constant{constant.NewInt(types.I64, 4)}, want: "i32 44"},
+	}
+	for _, g := range golden {
+		got, err := Interpret(g.f, g.args)
+		if err != nil {
+			t.Errorf("unable to interpret %s; %v", g.f.Ident(), err)
+			continue
+		}
+		if got.String() != g.want {
+			t.Errorf("result mismatch of %s; expected %q, got %q", g.f.Ident(), g.want, got)
+		}
+	}
+	// Calls to function declarations are not supported.
+	decl := m.NewFunc("decl", types.I32)
+	g := m.NewFunc("g", types.I32)
+	g.NewBlock("").NewRet(g.Blocks[0].NewCall(decl))
+	if _, err := Interpret(g, nil); err == nil {
+		t.Errorf("expected error for call to function declaration, got nil")
+	}
+}
+
+func TestInterpretArithmetic(t *testing.T) {
+	golden := []struct {
+		op   func(block *BasicBlock, x, y value.Value) value.Value
+		x, y constant.Constant
+		want string
+	}{
+		{op: func(b *BasicBlock, x, y value.Value) value.Value { return b.NewSDiv(x, y) }, x: constant.NewInt(types.I8, -7), y: constant.NewInt(types.I8, 2), want: "i8 -3"},
+		{op: func(b *BasicBlock, x, y value.Value) value.Value { return b.NewUDiv(x, y) }, x: constant.NewInt(types.I8, -2), y: constant.NewInt(types.I8, 2), want: "i8 127"},
+		{op: func(b *BasicBlock, x, y value.Value) value.Value { return b.NewSRem(x, y) }, x: constant.NewInt(types.I8, -7), y: constant.NewInt(types.I8, 2), want: "i8 -1"},
+		{op: func(b *BasicBlock, x, y value.Value) value.Value { return b.NewAShr(x, y) }, x: constant.NewInt(types.I8, -8), y: constant.NewInt(types.I8, 2), want: "i8 -2"},
+		{op: func(b *BasicBlock, x, y value.Value) value.Value { return b.NewLShr(x, y) }, x: constant.NewInt(types.I8, -8), y: constant.NewInt(types.I8, 2), want: "i8 62"},
+		{op: func(b *BasicBlock, x, y value.Value) value.Value { return b.NewAdd(x, y) }, x: constant.NewInt(types.I8, 127), y: constant.NewInt(types.I8, 1), want: "i8 -128"},
+		{op: func(b *BasicBlock, x, y value.Value) value.Value { return b.NewICmp(enum.IPredULT, x, y) }, x: constant.NewInt(types.I8, 1), y: constant.NewInt(types.I8, -1), want: "i1 true"},
+		{op: func(b *BasicBlock, x, y value.Value) value.Value { return b.NewICmp(enum.IPredSLT, x, y) }, x: constant.NewInt(types.I8, 1), y: constant.NewInt(types.I8, -1), want: "i1 false"},
+		{op: func(b *BasicBlock, x, y value.Value) value.Value { return b.NewFDiv(x, y) }, x: constant.NewFloat(types.Double, 1), y: constant.NewFloat(types.Double, 4), want: "double 0.25"},
+		{op: func(b *BasicBlock, x, y value.Value) value.Value { return b.NewFCmp(enum.FPredOLT, x, y) }, x: constant.NewFloat(types.Double, 1), y: constant.NewFloat(types.Double, 4), want: "i1 true"},
+		{op: func(b *BasicBlock, x, y value.Value) value.Value { return b.NewSIToFP(b.NewAdd(x, y), types.Double) }, x: constant.NewInt(types.I32, -3), y: constant.NewInt(types.I32, 1), want: "double -2.0"},
+	}
+	for _, g := range golden {
+		x := NewParam("x", g.x.Type())
+		y := NewParam("y", g.y.Type())
+		block := NewBlock("")
+		result := g.op(block, x, y)
+		f := NewFunc("f", result.Type(), x, y)
+		block.Parent = f
+		f.Blocks = append(f.Blocks, block)
+		block.NewRet(result)
+		got, err := Interpret(f, []constant.Constant{g.x, g.y})
+		if err != nil {
+			t.Errorf("unable to interpret %q; %v", block.Insts[0].Def(), err)
+			continue
+		}
+		if got.String() != g.want {
+			t.Errorf("result mismatch of %q; expected %q, got %q", block.Insts[0].Def(), g.want, got)
+		}
+	}
+	// Division by zero.
+	x := NewParam("x", types.I32)
+	f := NewFunc("f", types.I32, x)
+	block := f.NewBlock("")
+	block.NewRet(block.NewSDiv(x, constant.NewInt(types.I32, 0)))
+	if _, err := Interpret(f, []constant.Constant{constant.NewInt(types.I32, 1)}); err == nil {
+		t.Errorf("expected error for division by zero, got nil")
+	}
+}
@@ -0,0 +1,338 @@
+package ir
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Memory intrinsics ] ===================================================
+
+// NewMemcpy appends a new call to the llvm.memcpy intrinsic to the basic block,
+// which copies length bytes from src to dst; the source and destination memory
+// regions may not overlap. The intrinsic is declared in the module if not
+// already present, and its name is mangled based on the types of dst, src and
+// length (e.g. llvm.memcpy.p0i8.p0i8.i64).
+func NewMemcpy(m *Module, block *BasicBlock, dst, src, length value.Value, volatile bool) *InstCall {
+	callee := intrinsic(m, "llvm.memcpy", []types.Type{dst.Type(), src.Type(), length.Type()}, types.Void, dst.Type(), src.Type(), length.Type(), types.I1)
+	return block.NewCall(callee, dst, src, length, constant.NewBool(volatile))
+}
+
+// NewMemmove appends a new call to the llvm.memmove intrinsic to the basic
+// block, which copies length bytes from src to dst; the source and destination
+// memory regions may overlap. The intrinsic is declared in the module if not
+// already present, and its name is mangled based on the types of dst, src and
+// length (e.g. llvm.memmove.p0i8.p0i8.i64).
+func NewMemmove(m *Module, block *BasicBlock, dst, src, length value.Value, volatile bool) *InstCall {
+	callee := intrinsic(m, "llvm.memmove", []types.Type{dst.Type(), src.Type(), length.Type()}, types.Void, dst.Type(), src.Type(), length.Type(), types.I1)
+	return block.NewCall(callee, dst, src, length, constant.NewBool(volatile))
+}
+
+// NewMemset appends a new call to the llvm.memset intrinsic to the basic block,
+// which sets length bytes of dst to the byte value val. The intrinsic is
+// declared in the module if not already present, and its name is mangled based
+// on the types of dst and length (e.g. llvm.memset.p0i8.i64).
+func NewMemset(m *Module, block *BasicBlock, dst, val, length value.Value, volatile bool) *InstCall {
+	callee := intrinsic(m, "llvm.memset", []types.Type{dst.Type(), length.Type()}, types.Void, dst.Type(), val.Type(), length.Type(), types.I1)
+	return block.NewCall(callee, dst, val, length, constant.NewBool(volatile))
+}
+
+// === [ Intrinsic name mangling ] =============================================
+
+// MangleIntrinsic returns the name of the overloaded intrinsic with the given
+// base name and overload types, following the name mangling scheme of LLVM;
+// e.g. llvm.memcpy.p0i8.p0i8.i64 or llvm.masked.load.v4f32.p0v4f32.
+func MangleIntrinsic(base string, overloadTypes ...types.Type) string {
+	buf := &strings.Builder{}
+	buf.WriteString(base)
+	for _, t := range overloadTypes {
+		buf.WriteString(".")
+		buf.WriteString(mangleType(t))
+	}
+	return buf.String()
+}
+
+// ParseIntrinsicName splits the given intrinsic name into its base name and
+// overload types; the inverse of MangleIntrinsic. The overload types are the
+// longest sequence of trailing dot-separated name components which are valid
+// mangled types. The base name is returned unchanged with no overload types if
+// no such components are present.
+//
+// Named struct types are returned as opaque struct types with the given type
+// name, and are to be resolved by the caller. Names of named struct types in
+// overload types are assumed not to contain dots.
+func ParseIntrinsicName(name string) (base string, overloadTypes []types.Type) {
+	for i := 0; i < len(name); i++ {
+		if name[i] != '.' {
+			continue
+		}
+		if ts, ok := parseMangledTypes(name[i:]); ok {
+			return name[:i], ts
+		}
+	}
+	return name, nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// intrinsic returns the declaration of the overloaded intrinsic with the given
+// base name and signature, declaring it in the module if not already present.
+// The name of the intrinsic is mangled based on the given overloaded types.
+func intrinsic(m *Module, name string, overloads []types.Type, retType types.Type, paramTypes ...types.Type) *Function {
+	mangled := MangleIntrinsic(name, overloads...)
+	if f, ok := m.Func(mangled); ok {
+		return f
+	}
+	params := make([]*Param, len(paramTypes))
+	for i, paramType := range paramTypes {
+		params[i] = NewParam("", paramType)
+	}
+	return m.NewFunc(mangled, retType, params...)
+}
+
+// mangleType returns the mangled name of the given type, as used in the names
+// of overloaded intrinsics.
+func mangleType(t types.Type) string {
+	switch t := t.(type) {
+	case *types.VoidType:
+		return "isVoid"
+	case *types.FuncType:
+		buf := &strings.Builder{}
+		fmt.Fprintf(buf, "f_%s", mangleType(t.RetType))
+		for _, param := range t.Params {
+			buf.WriteString(mangleType(param))
+		}
+		if t.Variadic {
+			buf.WriteString("vararg")
+		}
+		buf.WriteString("f")
+		return buf.String()
+	case *types.IntType:
+		return fmt.Sprintf("i%d", t.BitSize)
+	case *types.FloatType:
+		switch t.Kind {
+		case types.FloatKindHalf:
+			return "f16"
+		case types.FloatKindFloat:
+			return "f32"
+		case types.FloatKindDouble:
+			return "f64"
+		case types.FloatKindFP128:
+			return "f128"
+		case types.FloatKindX86_FP80:
+			return "f80"
+		case types.FloatKindPPC_FP128:
+			return "ppcf128"
+		default:
+			panic(fmt.Errorf("support for floating-point kind %v not yet implemented", t.Kind))
+		}
+	case *types.MMXType:
+		return "x86mmx"
+	case *types.PointerType:
+		return fmt.Sprintf("p%d%s", uint64(t.AddrSpace), mangleType(t.ElemType))
+	case *types.VectorType:
+		if t.Scalable {
+			return fmt.Sprintf("nxv%d%s", t.Len, mangleType(t.ElemType))
+		}
+		return fmt.Sprintf("v%d%s", t.Len, mangleType(t.ElemType))
+	case *types.LabelType:
+		return "label"
+	case *types.TokenType:
+		return "token"
+	case *types.MetadataType:
+		return "Metadata"
+	case *types.ArrayType:
+		return fmt.Sprintf("a%d%s", t.Len, mangleType(t.ElemType))
+	case *types.StructType:
+		if len(t.TypeName) > 0 {
+			return fmt.Sprintf("s_%s", t.TypeName)
+		}
+		buf := &strings.Builder{}
+		buf.WriteString("sl_")
+		for _, field := range t.Fields {
+			buf.WriteString(mangleType(field))
+		}
+		buf.WriteString("s")
+		return buf.String()
+	default:
+		panic(fmt.Errorf("support for type %T not yet implemented", t))
+	}
+}
+
+// parseMangledTypes parses the given sequence of dot-prefixed mangled types,
+// and reports whether the entire string was parsed.
+func parseMangledTypes(s string) ([]types.Type, bool) {
+	var ts []types.Type
+	for len(s) > 0 {
+		if s[0] != '.' {
+			return nil, false
+		}
+		t, rest, ok := parseMangledType(s[1:])
+		if !ok {
+			return nil, false
+		}
+		ts = append(ts, t)
+		s = rest
+	}
+	return ts, len(ts) > 0
+}
+
+// parseMangledType parses the mangled type at the start of the given string,
+// and returns the type, the remaining string, and a boolean indicating success.
+func parseMangledType(s string) (types.Type, string, bool) {
+	// Keyword types.
+	keywords := []struct {
+		name string
+		typ  types.Type
+	}{
+		{name: "isVoid", typ: types.Void},
+		{name: "x86mmx", typ: types.MMX},
+		{name: "label", typ: types.Label},
+		{name: "token", typ: types.Token},
+		{name: "Metadata", typ: types.Metadata},
+		{name: "ppcf128", typ: types.PPC_FP128},
+		{name: "f128", typ: types.FP128},
+		{name: "f16", typ: types.Half},
+		{name: "f32", typ: types.Float},
+		{name: "f64", typ: types.Double},
+		{name: "f80", typ: types.X86_FP80},
+	}
+	for _, keyword := range keywords {
+		if strings.HasPrefix(s, keyword.name) {
+			return keyword.typ, s[len(keyword.name):], true
+		}
+	}
+	switch {
+	// Function types.
+	case strings.HasPrefix(s, "f_"):
+		retType, rest, ok := parseMangledType(s[len("f_"):])
+		if !ok {
+			return nil, "", false
+		}
+		t := &types.FuncType{RetType: retType}
+		for {
+			if strings.HasPrefix(rest, "varargf") {
+				t.Variadic = true
+				return t, rest[len("varargf"):], true
+			}
+			if isMangledEnd(rest, 'f') {
+				return t, rest[len("f"):], true
+			}
+			param, r, ok := parseMangledType(rest)
+			if !ok {
+				return nil, "", false
+			}
+			t.Params = append(t.Params, param)
+			rest = r
+		}
+	// Integer types.
+	case strings.HasPrefix(s, "i"):
+		n, rest, ok := parseMangledInt(s[len("i"):])
+		if !ok || n == 0 {
+			return nil, "", false
+		}
+		return types.NewInt(n), rest, true
+	// Pointer types.
+	case strings.HasPrefix(s, "p"):
+		addrSpace, rest, ok := parseMangledInt(s[len("p"):])
+		if !ok {
+			return nil, "", false
+		}
+		elemType, rest, ok := parseMangledType(rest)
+		if !ok {
+			return nil, "", false
+		}
+		t := types.NewPointer(elemType)
+		t.AddrSpace = types.AddrSpace(addrSpace)
+		return t, rest, true
+	// Vector types.
+	case strings.HasPrefix(s, "v"), strings.HasPrefix(s, "nxv"):
+		scalable := strings.HasPrefix(s, "nxv")
+		s = strings.TrimPrefix(strings.TrimPrefix(s, "nx"), "v")
+		n, rest, ok := parseMangledInt(s)
+		if !ok {
+			return nil, "", false
+		}
+		elemType, rest, ok := parseMangledType(rest)
+		if !ok {
+			return nil, "", false
+		}
+		t := types.NewVector(n, elemType)
+		t.Scalable = scalable
+		return t, rest, true
+	// Array types.
+	case strings.HasPrefix(s, "a"):
+		n, rest, ok := parseMangledInt(s[len("a"):])
+		if !ok {
+			return nil, "", false
+		}
+		elemType, rest, ok := parseMangledType(rest)
+		if !ok {
+			return nil, "", false
+		}
+		return types.NewArray(n, elemType), rest, true
+	// Literal struct types.
+	case strings.HasPrefix(s, "sl_"):
+		t := &types.StructType{}
+		rest := s[len("sl_"):]
+		for !isMangledEnd(rest, 's') {
+			field, r, ok := parseMangledType(rest)
+			if !ok {
+				return nil, "", false
+			}
+			t.Fields = append(t.Fields, field)
+			rest = r
+		}
+		return t, rest[len("s"):], true
+	// Named struct types.
+	case strings.HasPrefix(s, "s_"):
+		name := s[len("s_"):]
+		end := strings.IndexByte(name, '.')
+		if end == -1 {
+			end = len(name)
+		}
+		if end == 0 {
+			return nil, "", false
+		}
+		t := &types.StructType{TypeName: name[:end], Opaque: true}
+		return t, name[end:], true
+	}
+	return nil, "", false
+}
+
+// isMangledEnd reports whether the given string starts with the end marker of a
+// mangled function or literal struct type, rather than with a mangled type.
+func isMangledEnd(s string, marker byte) bool {
+	if len(s) == 0 || s[0] != marker {
+		return false
+	}
+	// Distinguish the end marker from mangled types starting with the marker;
+	// e.g. f32 and f_ for function end markers, and s_ and sl_ for struct end
+	// markers.
+	if len(s) > 1 && (s[1] == '_' || ('0' <= s[1] && s[1] <= '9') || (marker == 's' && s[1] == 'l' && strings.HasPrefix(s, "sl_"))) {
+		return false
+	}
+	return true
+}
+
+// parseMangledInt parses the decimal integer at the start of the given string,
+// and returns the integer, the remaining string, and a boolean indicating
+// success.
+func parseMangledInt(s string) (uint64, string, bool) {
+	i := 0
+	for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+		i++
+	}
+	if i == 0 {
+		return 0, "", false
+	}
+	n, err := strconv.ParseUint(s[:i], 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return n, s[i:], true
+}
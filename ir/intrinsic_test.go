@@ -0,0 +1,135 @@
+package ir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestMemIntrinsics(t *testing.T) {
+	m := NewModule()
+	i8Ptr := types.NewPointer(types.I8)
+	dst := NewParam("dst", i8Ptr)
+	src := NewParam("src", i8Ptr)
+	gpuDst := NewParam("gpu", &types.PointerType{ElemType: types.I8, AddrSpace: 1})
+	f := m.NewFunc("f", types.Void, dst, src, gpuDst)
+	entry := f.NewBlock("entry")
+	n := constant.NewInt(types.I64, 16)
+	golden := []struct {
+		in   *InstCall
+		want string
+	}{
+		{
+			in:   NewMemcpy(m, entry, dst, src, n, false),
+			want: "call void @llvm.memcpy.p0i8.p0i8.i64(i8* %dst, i8* %src, i64 16, i1 false)",
+		},
+		{
+			in:   NewMemmove(m, entry, dst, src, constant.NewInt(types.I32, 8), true),
+			want: "call void @llvm.memmove.p0i8.p0i8.i32(i8* %dst, i8* %src, i32 8, i1 true)",
+		},
+		{
+			in:   NewMemset(m, entry, gpuDst, constant.NewInt(types.I8, 0), n, false),
+			want: "call void @llvm.memset.p1i8.i64(i8 addrspace(1)* %gpu, i8 0, i64 16, i1 false)",
+		},
+		// Reuse of intrinsic declaration.
+		{
+			in:   NewMemcpy(m, entry, src, dst, n, false),
+			want: "call void @llvm.memcpy.p0i8.p0i8.i64(i8* %src, i8* %dst, i64 16, i1 false)",
+		},
+	}
+	for _, g := range golden {
+		if got := g.in.Def(); got != g.want {
+			t.Errorf("call mismatch; expected %q, got %q", g.want, got)
+		}
+	}
+	if len(entry.Insts) != len(golden) {
+		t.Errorf("number of instructions mismatch; expected %d, got %d", len(golden), len(entry.Insts))
+	}
+	var decls []string
+	for _, f := range m.Funcs[1:] {
+		decls = append(decls, f.Def())
+	}
+	want := "declare void @llvm.memcpy.p0i8.p0i8.i64(i8*, i8*, i64, i1)\ndeclare void @llvm.memmove.p0i8.p0i8.i32(i8*, i8*, i32, i1)\ndeclare void @llvm.memset.p1i8.i64(i8 addrspace(1)*, i8, i64, i1)"
+	if got := strings.Join(decls, "\n"); got != want {
+		t.Errorf("intrinsic declarations mismatch; expected %q, got %q", want, got)
+	}
+}
+
+func TestMangleIntrinsic(t *testing.T) {
+	i8Ptr := types.NewPointer(types.I8)
+	v4f32 := types.NewVector(4, types.Float)
+	pair := types.NewStruct(types.I32, v4f32)
+	pair.SetName("pair")
+	golden := []struct {
+		base     string
+		overload []types.Type
+		want     string
+	}{
+		// Non-overloaded intrinsic.
+		{base: "llvm.trap", want: "llvm.trap"},
+		// Integer and pointer types.
+		{base: "llvm.memcpy", overload: []types.Type{i8Ptr, i8Ptr, types.I64}, want: "llvm.memcpy.p0i8.p0i8.i64"},
+		// Pointer address spaces.
+		{base: "llvm.memset", overload: []types.Type{&types.PointerType{ElemType: types.I8, AddrSpace: 3}, types.I32}, want: "llvm.memset.p3i8.i32"},
+		// Vector types.
+		{base: "llvm.masked.load", overload: []types.Type{v4f32, types.NewPointer(v4f32)}, want: "llvm.masked.load.v4f32.p0v4f32"},
+		{base: "llvm.sqrt", overload: []types.Type{types.NewScalableVector(2, types.Double)}, want: "llvm.sqrt.nxv2f64"},
+		// Floating-point types.
+		{base: "llvm.fma", overload: []types.Type{types.X86_FP80}, want: "llvm.fma.f80"},
+		{base: "llvm.fma", overload: []types.Type{types.PPC_FP128}, want: "llvm.fma.ppcf128"},
+		// Nested aggregate types.
+		{base: "llvm.ssa.copy", overload: []types.Type{types.NewPointer(types.NewArray(2, types.NewStruct(types.I8, types.NewVector(2, types.I64))))}, want: "llvm.ssa.copy.p0a2sl_i8v2i64s"},
+		{base: "llvm.ssa.copy", overload: []types.Type{types.NewPointer(pair)}, want: "llvm.ssa.copy.p0s_pair"},
+		// Function types.
+		{base: "llvm.ssa.copy", overload: []types.Type{types.NewPointer(types.NewFunc(types.Float, types.Float, i8Ptr))}, want: "llvm.ssa.copy.p0f_f32f32p0i8f"},
+		{base: "llvm.ssa.copy", overload: []types.Type{types.NewPointer(&types.FuncType{RetType: types.Void, Params: []types.Type{types.I32}, Variadic: true})}, want: "llvm.ssa.copy.p0f_isVoidi32varargf"},
+	}
+	for _, g := range golden {
+		got := MangleIntrinsic(g.base, g.overload...)
+		if g.want != got {
+			t.Errorf("mangled name mismatch; expected %q, got %q", g.want, got)
+			continue
+		}
+		// Parse mangled name.
+		base, overload := ParseIntrinsicName(got)
+		if base != g.base {
+			t.Errorf("base name mismatch of %q; expected %q, got %q", got, g.base, base)
+		}
+		if len(overload) != len(g.overload) {
+			t.Errorf("number of overload types mismatch of %q; expected %d, got %d", got, len(g.overload), len(overload))
+			continue
+		}
+		for i := range overload {
+			if !overload[i].Equal(g.overload[i]) {
+				t.Errorf("overload type mismatch of %q; expected %s, got %s", got, g.overload[i], overload[i])
+			}
+		}
+	}
+}
+
+func TestParseIntrinsicName(t *testing.T) {
+	golden := []struct {
+		in       string
+		base     string
+		overload string
+	}{
+		{in: "llvm.memcpy.p0i8.p0i8.i64", base: "llvm.memcpy", overload: "i8*, i8*, i64"},
+		{in: "llvm.lifetime.start.p0i8", base: "llvm.lifetime.start", overload: "i8*"},
+		{in: "llvm.experimental.vector.reduce.add.v4i32", base: "llvm.experimental.vector.reduce.add", overload: "<4 x i32>"},
+		{in: "llvm.x86.sse2.pause", base: "llvm.x86.sse2.pause"},
+		{in: "llvm.powi.f32", base: "llvm.powi", overload: "float"},
+		{in: "llvm.trap", base: "llvm.trap"},
+	}
+	for _, g := range golden {
+		base, overload := ParseIntrinsicName(g.in)
+		var ts []string
+		for _, typ := range overload {
+			ts = append(ts, typ.String())
+		}
+		if base != g.base || strings.Join(ts, ", ") != g.overload {
+			t.Errorf("intrinsic name mismatch of %q; expected %q and %q, got %q and %q", g.in, g.base, g.overload, base, strings.Join(ts, ", "))
+		}
+	}
+}
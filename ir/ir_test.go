@@ -1,10 +1,12 @@
 package ir
 
 import (
+	"io/ioutil"
 	"strings"
 	"testing"
 
 	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
 	"github.com/llir/llvm/ir/metadata"
 	"github.com/llir/llvm/ir/types"
 	"github.com/llir/llvm/ir/value"
@@ -30,6 +32,51 @@ func TestModuleString(t *testing.T) {
 			},
 			want: "%foo = type { i32 }",
 		},
+		// Global variable definition with alignment.
+		{
+			in: &Module{
+				Globals: []*Global{{
+					GlobalIdent: GlobalIdent{GlobalName: "X"},
+					ContentType: types.I32,
+					Init:        constant.NewInt(types.I32, 4),
+					Align:       16,
+				}},
+			},
+			want: "@X = global i32 4, align 16",
+		},
+		// Function declaration with alignment (alignment.ll line 7).
+		{
+			in: &Module{
+				Funcs: []*Function{{
+					GlobalIdent: GlobalIdent{GlobalName: "test"},
+					Sig:         types.NewFunc(types.I32Ptr),
+					FuncAttrs:   []FuncAttribute{Align(32)},
+				}},
+			},
+			want: "declare i32* @test() align 32",
+		},
+		// Global variable definition with dso_local_equivalent initializer.
+		{
+			in: &Module{
+				Globals: []*Global{{
+					GlobalIdent: GlobalIdent{GlobalName: "p"},
+					ContentType: types.NewPointer(types.NewFunc(types.Void)),
+					Init:        constant.NewDSOLocalEquivalent(NewFunc("f", types.Void)),
+				}},
+			},
+			want: "@p = global void ()* dso_local_equivalent @f",
+		},
+		// Global variable definition with no_cfi initializer.
+		{
+			in: &Module{
+				Globals: []*Global{{
+					GlobalIdent: GlobalIdent{GlobalName: "p"},
+					ContentType: types.NewPointer(types.NewFunc(types.Void)),
+					Init:        constant.NewNoCFI(NewFunc("f", types.Void)),
+				}},
+			},
+			want: "@p = global void ()* no_cfi @f",
+		},
 	}
 	for _, g := range golden {
 		got := strings.TrimSpace(g.in.String())
@@ -39,6 +86,194 @@ func TestModuleString(t *testing.T) {
 	}
 }
 
+func TestModuleEncodedLen(t *testing.T) {
+	m := NewModule()
+	m.SourceFilename = "foo.c"
+	m.TargetTriple = "x86_64-unknown-linux-gnu"
+	m.NewTypeDef("foo", &types.StructType{Fields: []types.Type{types.I32}})
+	m.NewGlobalDef("x", constant.NewInt(types.I32, 42))
+	f := m.NewFunc("f", types.I32, NewParam("a", types.I32))
+	entry := f.NewBlock("")
+	sum := entry.NewAdd(f.Params[0], constant.NewInt(types.I32, 1))
+	entry.NewRet(sum)
+	m.NewFunc("g", types.Void)
+	m.NamedMetadataDefs = append(m.NamedMetadataDefs, &metadata.NamedDef{Name: "foo"})
+	golden := []*Module{
+		// Empty module.
+		{},
+		m,
+	}
+	for _, g := range golden {
+		want := len(g.String())
+		got := g.EncodedLen()
+		if want != got {
+			t.Errorf("encoded length mismatch; expected %d, got %d", want, got)
+		}
+	}
+}
+
+func TestModuleSourceFilename(t *testing.T) {
+	m := NewModule()
+	m.SourceFilename = `/home/user/src/my "file".c`
+	m.DataLayout = "e-m:e-i64:64-S128"
+	m.NewFunc("f", types.Void)
+	const want = "source_filename = \"/home/user/src/my \\22file\\22.c\"\ntarget datalayout = \"e-m:e-i64:64-S128\"\n\ndeclare void @f()\n"
+	if got := m.String(); want != got {
+		t.Errorf("module mismatch; expected %q, got %q", want, got)
+	}
+}
+
+func TestModuleAsms(t *testing.T) {
+	m := NewModule()
+	m.TargetTriple = "x86_64-unknown-linux-gnu"
+	m.ModuleAsms = append(m.ModuleAsms, ".globl foo", `foo: .ascii "bar"`)
+	m.NewFunc("f", types.Void)
+	const want = "target triple = \"x86_64-unknown-linux-gnu\"\n\nmodule asm \".globl foo\"\nmodule asm \"foo: .ascii \\22bar\\22\"\n\ndeclare void @f()\n"
+	if got := m.String(); want != got {
+		t.Errorf("module mismatch; expected %q, got %q", want, got)
+	}
+}
+
+func TestPoison(t *testing.T) {
+	p := NewParam("p", types.I32Ptr)
+	f := NewFunc("f", types.I32, p)
+	entry := f.NewBlock("entry")
+	exit := f.NewBlock("exit")
+	gep := entry.NewGetElementPtr(p, constant.NewPoison(types.I64))
+	gep.SetName("q")
+	load := entry.NewLoad(gep)
+	load.SetName("x")
+	entry.NewCondBr(constant.NewPoison(types.I1), exit, exit)
+	phi := exit.NewPhi(NewIncoming(constant.NewPoison(types.I32), entry))
+	phi.SetName("y")
+	exit.NewRet(constant.NewPoison(types.I32))
+	want := `define i32 @f(i32* %p) {
+entry:
+	%q = getelementptr i32, i32* %p, i64 poison
+	%x = load i32, i32* %q
+	br i1 poison, label %exit, label %exit
+
+exit:
+	%y = phi i32 [ poison, %entry ]
+	ret i32 poison
+}`
+	if got := f.Def(); want != got {
+		t.Errorf("function mismatch; expected `%v`, got `%v`", want, got)
+	}
+}
+
+func TestMetadataValue(t *testing.T) {
+	m := NewModule()
+	x := NewParam("x", types.I32)
+	f := m.NewFunc("f", types.Void, x)
+	dbgValue := m.NewFunc("llvm.dbg.value", types.Void, NewParam("", types.Metadata), NewParam("", types.Metadata), NewParam("", types.Metadata))
+	variable := &metadata.Def{ID: 0, Node: &metadata.Tuple{Fields: []metadata.Field{&metadata.String{Value: "x"}}}}
+	m.MetadataDefs = append(m.MetadataDefs, variable)
+	entry := f.NewBlock("entry")
+	entry.NewCall(dbgValue, &metadata.Value{Value: x}, &metadata.Value{Value: variable}, &metadata.Value{Value: &metadata.DIExpression{}})
+	entry.NewCall(dbgValue, &metadata.Value{Value: constant.NewInt(types.I32, 42)}, &metadata.Value{Value: variable}, &metadata.Value{Value: &metadata.DIExpression{Fields: []metadata.DIExpressionField{enum.DwarfOpPlusUconst, metadata.UintLit(1)}}})
+	entry.NewRet(nil)
+	want := `define void @f(i32 %x) {
+entry:
+	call void @llvm.dbg.value(metadata i32 %x, metadata !0, metadata !DIExpression())
+	call void @llvm.dbg.value(metadata i32 42, metadata !0, metadata !DIExpression(DW_OP_plus_uconst, 1))
+	ret void
+}
+
+declare void @llvm.dbg.value(metadata, metadata, metadata)
+
+!0 = !{!"x"}
+`
+	if got := m.String(); want != got {
+		t.Errorf("module mismatch; expected `%v`, got `%v`", want, got)
+	}
+}
+
+func TestLinkage(t *testing.T) {
+	// Generate a module with a global variable and an alias of each linkage
+	// kind, and compare against the testdata file used by the parser tests.
+	m := NewModule()
+	var globals []*Global
+	for linkage := enum.LinkageAppending; linkage <= enum.LinkageExternWeak; linkage++ {
+		name := linkage.String()
+		var g *Global
+		switch linkage {
+		case enum.LinkageAppending:
+			g = m.NewGlobalDef(name, constant.NewArray(constant.NewInt(types.I32, 0)))
+		case enum.LinkageExternal, enum.LinkageExternWeak:
+			g = m.NewGlobalDecl(name, types.I32)
+		default:
+			g = m.NewGlobalDef(name, constant.NewInt(types.I32, 0))
+		}
+		g.Linkage = linkage
+		globals = append(globals, g)
+	}
+	for _, g := range globals {
+		alias := m.NewAlias(g.Name()+"_alias", g)
+		alias.Linkage = g.Linkage
+	}
+	const path = "../asm/testdata/linkage.ll"
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read %q; %+v", path, err)
+	}
+	if got, want := m.String(), string(buf); got != want {
+		t.Errorf("module mismatch %q; expected `%s`, got `%s`", path, want, got)
+	}
+}
+
+func TestEmitClangStyleComments(t *testing.T) {
+	m := NewModule()
+	group := &AttrGroupDef{ID: 0, FuncAttrs: []FuncAttribute{enum.FuncAttrNoInline, enum.FuncAttrNoUnwind, AttrPair{Key: "frame-pointer", Value: "all"}}}
+	m.AttrGroupDefs = append(m.AttrGroupDefs, group)
+	f := m.NewFunc("f", types.Void)
+	f.FuncAttrs = append(f.FuncAttrs, group, enum.FuncAttrReadNone)
+	f.NewBlock("").NewRet(nil)
+	g := m.NewFunc("g", types.Void)
+	g.FuncAttrs = append(g.FuncAttrs, AttrString("no-attrs"))
+	m.NewFunc("h", types.Void)
+	golden := []struct {
+		emit bool
+		want string
+	}{
+		{
+			emit: false,
+			want: `define void @f() #0 readnone {
+; <label>:0
+	ret void
+}
+
+declare void @g() "no-attrs"
+
+declare void @h()
+
+attributes #0 = { noinline nounwind "frame-pointer"="all" }
+`,
+		},
+		{
+			emit: true,
+			want: `; Function Attrs: noinline nounwind readnone
+define void @f() #0 readnone {
+; <label>:0
+	ret void
+}
+
+declare void @g() "no-attrs"
+
+declare void @h()
+
+attributes #0 = { noinline nounwind "frame-pointer"="all" }
+`,
+		},
+	}
+	for _, g := range golden {
+		m.EmitClangStyleComments = g.emit
+		if got := m.String(); g.want != got {
+			t.Errorf("module mismatch (EmitClangStyleComments=%v); expected `%v`, got `%v`", g.emit, g.want, got)
+		}
+	}
+}
+
 // Assert that each constant implements the constant.Constant interface.
 var (
 	// Constants.
@@ -120,6 +355,7 @@ var (
 	_ Terminator = (*TermSwitch)(nil)
 	_ Terminator = (*TermIndirectBr)(nil)
 	_ Terminator = (*TermInvoke)(nil)
+	_ Terminator = (*TermCallBr)(nil)
 	_ Terminator = (*TermResume)(nil)
 	_ Terminator = (*TermCatchSwitch)(nil)
 	_ Terminator = (*TermCatchRet)(nil)
@@ -210,5 +446,6 @@ var (
 
 	// Terminators.
 	_ value.Named = (*TermInvoke)(nil)
+	_ value.Named = (*TermCallBr)(nil)
 	_ value.Named = (*TermCatchSwitch)(nil) // token result used by catchpad
 )
@@ -0,0 +1,232 @@
+package ir
+
+import (
+	"encoding/json"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
+)
+
+// === [ JSON representation ] =================================================
+
+// ToJSON returns the JSON representation of the given module, for inspection of
+// the module by tools without parsing LLVM IR assembly.
+//
+// Each instruction and terminator is represented by its opcode (e.g. "add"),
+// its result type and name, and its operands. Operands refer to local
+// variables, basic blocks and global identifiers by their identifiers (e.g.
+// "%1" or "@f"); constants are represented by their LLVM IR assembly. Unnamed
+// local variables are assigned IDs before encoding.
+func ToJSON(m *Module) ([]byte, error) {
+	jm := &jsonModule{
+		SourceFilename: m.SourceFilename,
+		DataLayout:     m.DataLayout,
+		TargetTriple:   m.TargetTriple,
+	}
+	for _, t := range m.TypeDefs {
+		jm.TypeDefs = append(jm.TypeDefs, jsonTypeDef{Name: t.String(), Type: t.Def()})
+	}
+	for _, g := range m.Globals {
+		jg := jsonGlobal{
+			Name:        g.Ident(),
+			ContentType: g.ContentType.String(),
+			Immutable:   g.Immutable,
+			Linkage:     linkageString(g.Linkage),
+		}
+		if g.Init != nil {
+			init := jsonOperand(g.Init)
+			jg.Init = &init
+		}
+		jm.Globals = append(jm.Globals, jg)
+	}
+	for _, f := range m.Funcs {
+		jf, err := jsonFunction(f)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		jm.Funcs = append(jm.Funcs, jf)
+	}
+	buf, err := json.Marshal(jm)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return buf, nil
+}
+
+// jsonModule is the JSON representation of a module.
+type jsonModule struct {
+	// Source filename; or empty if not present.
+	SourceFilename string `json:"source_filename,omitempty"`
+	// Data layout; or empty if not present.
+	DataLayout string `json:"datalayout,omitempty"`
+	// Target triple; or empty if not present.
+	TargetTriple string `json:"triple,omitempty"`
+	// Type definitions.
+	TypeDefs []jsonTypeDef `json:"types,omitempty"`
+	// Global variable declarations and definitions.
+	Globals []jsonGlobal `json:"globals,omitempty"`
+	// Function declarations and definitions.
+	Funcs []jsonFunc `json:"functions,omitempty"`
+}
+
+// jsonTypeDef is the JSON representation of a type definition.
+type jsonTypeDef struct {
+	// Type name (with '%' prefix).
+	Name string `json:"name"`
+	// Type definition.
+	Type string `json:"type"`
+}
+
+// jsonGlobal is the JSON representation of a global variable.
+type jsonGlobal struct {
+	// Global variable name (with '@' prefix).
+	Name string `json:"name"`
+	// Content type.
+	ContentType string `json:"content_type"`
+	// Immutability of global variable (constant or global).
+	Immutable bool `json:"immutable,omitempty"`
+	// Linkage; or empty if not present.
+	Linkage string `json:"linkage,omitempty"`
+	// Initial value; or nil if declaration.
+	Init *jsonValue `json:"init,omitempty"`
+}
+
+// jsonFunc is the JSON representation of a function.
+type jsonFunc struct {
+	// Function name (with '@' prefix).
+	Name string `json:"name"`
+	// Function signature.
+	Sig string `json:"type"`
+	// Linkage; or empty if not present.
+	Linkage string `json:"linkage,omitempty"`
+	// Function parameters.
+	Params []jsonValue `json:"params,omitempty"`
+	// Basic blocks; or nil if declaration.
+	Blocks []jsonBlock `json:"blocks,omitempty"`
+}
+
+// jsonBlock is the JSON representation of a basic block.
+type jsonBlock struct {
+	// Basic block label (with '%' prefix).
+	Name string `json:"name"`
+	// Instructions.
+	Insts []jsonInst `json:"insts,omitempty"`
+	// Terminator.
+	Term *jsonInst `json:"term,omitempty"`
+}
+
+// jsonInst is the JSON representation of an instruction or terminator.
+type jsonInst struct {
+	// Opcode.
+	Opcode string `json:"opcode"`
+	// Name of local variable associated with the result (with '%' prefix); or
+	// empty if the instruction does not produce a value.
+	Name string `json:"name,omitempty"`
+	// Result type; or empty if the instruction does not produce a value.
+	Type string `json:"type,omitempty"`
+	// Value operands.
+	Operands []jsonValue `json:"operands,omitempty"`
+	// Predecessor basic blocks of the incoming values of phi instructions.
+	Preds []string `json:"preds,omitempty"`
+	// Successor basic blocks of terminators.
+	Succs []string `json:"succs,omitempty"`
+}
+
+// jsonValue is the JSON representation of a value operand.
+type jsonValue struct {
+	// Value kind; local, global, constant, metadata or inline_asm.
+	Kind string `json:"kind"`
+	// Reference to the value; identifier of local variables and global
+	// identifiers, and LLVM IR assembly of other values.
+	Ref string `json:"ref"`
+	// Value type.
+	Type string `json:"type"`
+}
+
+// ### [ Helper functions ] ####################################################
+
+// jsonFunction returns the JSON representation of the given function.
+func jsonFunction(f *Function) (jsonFunc, error) {
+	if err := f.AssignIDs(); err != nil {
+		return jsonFunc{}, errors.WithStack(err)
+	}
+	jf := jsonFunc{
+		Name:    f.Ident(),
+		Sig:     f.Sig.String(),
+		Linkage: linkageString(f.Linkage),
+	}
+	for _, param := range f.Params {
+		jf.Params = append(jf.Params, jsonOperand(param))
+	}
+	for _, block := range f.Blocks {
+		jb := jsonBlock{Name: block.Ident()}
+		for _, inst := range block.Insts {
+			jb.Insts = append(jb.Insts, jsonInstruction(inst))
+		}
+		if block.Term != nil {
+			term := jsonInstruction(block.Term)
+			for _, succ := range block.Term.Succs() {
+				term.Succs = append(term.Succs, succ.Ident())
+			}
+			jb.Term = &term
+		}
+		jf.Blocks = append(jf.Blocks, jb)
+	}
+	return jf, nil
+}
+
+// jsonInstruction returns the JSON representation of the given instruction or
+// terminator.
+func jsonInstruction(inst interface {
+	Opcode() enum.Opcode
+}) jsonInst {
+	ji := jsonInst{Opcode: inst.Opcode().String()}
+	if n, ok := inst.(value.Named); ok && !isVoidValue(n) {
+		ji.Name = n.Ident()
+		ji.Type = n.Type().String()
+	}
+	for _, op := range operands(inst) {
+		if *op != nil {
+			ji.Operands = append(ji.Operands, jsonOperand(*op))
+		}
+	}
+	if phi, ok := inst.(*InstPhi); ok {
+		for _, inc := range phi.Incs {
+			ji.Preds = append(ji.Preds, inc.Pred.Ident())
+		}
+	}
+	return ji
+}
+
+// jsonOperand returns the JSON representation of the given value operand.
+func jsonOperand(v value.Value) jsonValue {
+	if arg, ok := v.(*Arg); ok {
+		v = arg.Value
+	}
+	jv := jsonValue{Ref: v.Ident(), Type: v.Type().String()}
+	switch v.(type) {
+	case *Global, *Function, *Alias, *IFunc:
+		jv.Kind = "global"
+	case constant.Constant:
+		jv.Kind = "constant"
+	case *metadata.Value:
+		jv.Kind = "metadata"
+	case *InlineAsm:
+		jv.Kind = "inline_asm"
+	default:
+		jv.Kind = "local"
+	}
+	return jv
+}
+
+// linkageString returns the string representation of the given linkage; or an
+// empty string if not present.
+func linkageString(linkage enum.Linkage) string {
+	if linkage == enum.LinkageNone {
+		return ""
+	}
+	return linkage.String()
+}
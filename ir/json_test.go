@@ -0,0 +1,124 @@
+package ir
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestToJSON(t *testing.T) {
+	m := NewModule()
+	m.TargetTriple = "x86_64-unknown-linux-gnu"
+	counter := m.NewGlobalDef("counter", constant.NewInt(types.I32, 0))
+	x := NewParam("x", types.I32)
+	f := m.NewFunc("f", types.I32, x)
+	entry := f.NewBlock("")
+	loop := f.NewBlock("loop")
+	exit := f.NewBlock("exit")
+	sum := entry.NewAdd(x, constant.NewInt(types.I32, 1))
+	entry.NewStore(sum, counter)
+	entry.NewBr(loop)
+	phi := loop.NewPhi(NewIncoming(sum, entry), NewIncoming(x, loop))
+	cond := loop.NewICmp(enum.IPredSLT, phi, constant.NewInt(types.I32, 10))
+	loop.NewCondBr(cond, loop, exit)
+	exit.NewRet(phi)
+	buf, err := ToJSON(m)
+	if err != nil {
+		t.Fatalf("unable to encode module as JSON; %+v", err)
+	}
+	// Decode JSON representation without Go type information, as would tools
+	// of other languages.
+	var got struct {
+		Triple  string `json:"triple"`
+		Globals []struct {
+			Name        string `json:"name"`
+			ContentType string `json:"content_type"`
+			Init        struct {
+				Kind string `json:"kind"`
+				Ref  string `json:"ref"`
+			} `json:"init"`
+		} `json:"globals"`
+		Functions []struct {
+			Name   string `json:"name"`
+			Type   string `json:"type"`
+			Params []struct {
+				Ref string `json:"ref"`
+			} `json:"params"`
+			Blocks []struct {
+				Name  string          `json:"name"`
+				Insts []jsonInstShape `json:"insts"`
+				Term  jsonInstShape   `json:"term"`
+			} `json:"blocks"`
+		} `json:"functions"`
+	}
+	if err := json.Unmarshal(buf, &got); err != nil {
+		t.Fatalf("unable to decode JSON %s; %v", buf, err)
+	}
+	if got.Triple != m.TargetTriple {
+		t.Errorf("target triple mismatch; expected %q, got %q", m.TargetTriple, got.Triple)
+	}
+	if len(got.Globals) != 1 || got.Globals[0].Name != "@counter" || got.Globals[0].ContentType != "i32" || got.Globals[0].Init.Kind != "constant" || got.Globals[0].Init.Ref != "0" {
+		t.Errorf("global variables mismatch; got %s", buf)
+	}
+	if len(got.Functions) != 1 {
+		t.Fatalf("function count mismatch; expected 1, got %d", len(got.Functions))
+	}
+	fn := got.Functions[0]
+	if fn.Name != "@f" || fn.Type != "i32 (i32)" || len(fn.Params) != 1 || fn.Params[0].Ref != "%x" {
+		t.Errorf("function header mismatch; got %s", buf)
+	}
+	// Compare structure of function body.
+	var blocks []string
+	for _, block := range fn.Blocks {
+		var insts []string
+		for _, inst := range append(block.Insts, block.Term) {
+			insts = append(insts, inst.String())
+		}
+		blocks = append(blocks, fmt.Sprintf("%s: %s", block.Name, strings.Join(insts, "; ")))
+	}
+	want := []string{
+		"%0: %1 = add(local %x, constant 1); store(local %1, global @counter); br() -> [%loop]",
+		"%loop: %2 = phi(local %1, local %x) <- [%0 %loop]; %3 = icmp(local %2, constant 10); br(local %3) -> [%loop %exit]",
+		"%exit: ret(local %2)",
+	}
+	if strings.Join(blocks, "\n") != strings.Join(want, "\n") {
+		t.Errorf("function body mismatch; expected\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(blocks, "\n"))
+	}
+}
+
+// jsonInstShape is the shape of the JSON representation of an instruction.
+type jsonInstShape struct {
+	Opcode   string `json:"opcode"`
+	Name     string `json:"name"`
+	Operands []struct {
+		Kind string `json:"kind"`
+		Ref  string `json:"ref"`
+	} `json:"operands"`
+	Preds []string `json:"preds"`
+	Succs []string `json:"succs"`
+}
+
+// String returns a compact string representation of the instruction shape.
+func (inst jsonInstShape) String() string {
+	buf := &strings.Builder{}
+	if len(inst.Name) > 0 {
+		fmt.Fprintf(buf, "%s = ", inst.Name)
+	}
+	var ops []string
+	for _, op := range inst.Operands {
+		ops = append(ops, fmt.Sprintf("%s %s", op.Kind, op.Ref))
+	}
+	fmt.Fprintf(buf, "%s(%s)", inst.Opcode, strings.Join(ops, ", "))
+	if len(inst.Preds) > 0 {
+		fmt.Fprintf(buf, " <- %v", inst.Preds)
+	}
+	if len(inst.Succs) > 0 {
+		fmt.Fprintf(buf, " -> %v", inst.Succs)
+	}
+	return buf.String()
+}
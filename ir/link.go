@@ -0,0 +1,456 @@
+package ir
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+	"github.com/pkg/errors"
+)
+
+// === [ Module linking ] ======================================================
+
+// Link links the source module into the destination module (mirroring
+// llvm-link). The type definitions, global variables, functions, aliases,
+// IFuncs, comdats, attribute groups and metadata of src are moved into dst; src
+// should not be used after linking.
+//
+// Global identifiers defined by both modules are resolved based on linkage.
+// Declarations are resolved to definitions, definitions with
+// available_externally linkage are overridden by other definitions, and
+// definitions with weak, linkonce or common linkage are overridden by strong
+// definitions (or otherwise by the definition of dst). Global variables with
+// appending linkage are concatenated. An error is returned if both modules
+// contain a strong definition of the same global identifier. Uses of resolved
+// declarations and overridden definitions are redirected, through a bitcast if
+// the types differ.
+//
+// Global identifiers with private or internal linkage, and type definitions
+// with conflicting definitions, are renamed on name collision (e.g. @x.1).
+// Unnamed global identifiers, metadata definitions and attribute groups of src
+// are assigned new IDs. The module flags of both modules are merged using
+// MergeModuleFlags, and the nodes of other named metadata definitions present
+// in both modules are concatenated. Use-list order directives of src are
+// discarded.
+func Link(dst, src *Module) error {
+	if dst == src {
+		return errors.New("unable to link module into itself")
+	}
+	// Materialize lazily parsed function bodies, as uses of global identifiers
+	// are redirected during linking.
+	for _, m := range []*Module{dst, src} {
+		for _, f := range m.Funcs {
+			if err := f.Materialize(); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+	if len(dst.DataLayout) == 0 {
+		dst.DataLayout = src.DataLayout
+	}
+	if len(dst.TargetTriple) == 0 {
+		dst.TargetTriple = src.TargetTriple
+	}
+	dst.ModuleAsms = append(dst.ModuleAsms, src.ModuleAsms...)
+	l := newLinker(dst, src)
+	l.linkTypeDefs()
+	if err := l.linkComdats(); err != nil {
+		return errors.WithStack(err)
+	}
+	l.linkAttrGroups()
+	if err := l.linkMetadata(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := l.linkSymbols(); err != nil {
+		return errors.WithStack(err)
+	}
+	// Redirect uses of resolved declarations and overridden definitions.
+	if len(l.repl) > 0 {
+		rewriteConstantUses(dst, func(c constant.Constant) (constant.Constant, bool) {
+			new, ok := l.repl[c]
+			return new, ok
+		})
+	}
+	// Invalidate indices of functions and global variables.
+	dst.funcIndex = nil
+	dst.globalIndex = nil
+	return nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// linker links a source module into a destination module.
+type linker struct {
+	// Destination module.
+	dst *Module
+	// Source module.
+	src *Module
+	// Map from resolved declaration or overridden definition to replacement.
+	repl map[constant.Constant]constant.Constant
+}
+
+// newLinker returns a new linker of the given source module into the
+// destination module.
+func newLinker(dst, src *Module) *linker {
+	return &linker{
+		dst:  dst,
+		src:  src,
+		repl: make(map[constant.Constant]constant.Constant),
+	}
+}
+
+// linkTypeDefs links the type definitions of the source module. Type
+// definitions identical to those of the destination module are shared by name,
+// and conflicting type definitions are renamed.
+func (l *linker) linkTypeDefs() {
+	used := make(map[string]bool)
+	index := make(map[string]types.Type)
+	for _, t := range l.dst.TypeDefs {
+		used[t.Name()] = true
+		index[t.Name()] = t
+	}
+	for _, t := range l.src.TypeDefs {
+		used[t.Name()] = true
+	}
+	for _, t := range l.src.TypeDefs {
+		if prev, ok := index[t.Name()]; ok {
+			if prev.Def() == t.Def() {
+				continue
+			}
+			t.SetName(uniqueName(t.Name(), used))
+		}
+		index[t.Name()] = t
+		l.dst.TypeDefs = append(l.dst.TypeDefs, t)
+	}
+}
+
+// linkComdats links the comdat definitions of the source module. Comdats
+// present in both modules are merged by name.
+func (l *linker) linkComdats() error {
+	index := make(map[string]*ComdatDef)
+	for _, def := range l.dst.ComdatDefs {
+		index[def.Name] = def
+	}
+	repl := make(map[*ComdatDef]*ComdatDef)
+	for _, def := range l.src.ComdatDefs {
+		prev, ok := index[def.Name]
+		if !ok {
+			l.dst.ComdatDefs = append(l.dst.ComdatDefs, def)
+			continue
+		}
+		if prev.Kind != def.Kind {
+			return errors.Errorf("conflicting selection kinds of comdat %q; %v and %v", def.Name, prev.Kind, def.Kind)
+		}
+		repl[def] = prev
+	}
+	for _, g := range l.src.Globals {
+		if c, ok := repl[g.Comdat]; ok {
+			g.Comdat = c
+		}
+	}
+	for _, f := range l.src.Funcs {
+		if c, ok := repl[f.Comdat]; ok {
+			f.Comdat = c
+		}
+	}
+	return nil
+}
+
+// linkAttrGroups links the attribute group definitions of the source module,
+// assigning new IDs.
+func (l *linker) linkAttrGroups() {
+	var nextID int64
+	for _, def := range l.dst.AttrGroupDefs {
+		if def.ID >= nextID {
+			nextID = def.ID + 1
+		}
+	}
+	for _, def := range l.src.AttrGroupDefs {
+		def.ID = nextID
+		nextID++
+		l.dst.AttrGroupDefs = append(l.dst.AttrGroupDefs, def)
+	}
+}
+
+// linkMetadata links the metadata definitions and named metadata definitions
+// of the source module, assigning new metadata IDs.
+func (l *linker) linkMetadata() error {
+	if err := MergeModuleFlags(l.dst, l.src); err != nil {
+		return errors.WithStack(err)
+	}
+	// Module flags of the source module have been merged.
+	flags := make(map[*metadata.Def]bool)
+	if named := findNamedMetadataDef(l.src, moduleFlagsName); named != nil {
+		for _, node := range named.Nodes {
+			if def, ok := node.(*metadata.Def); ok {
+				flags[def] = true
+			}
+		}
+	}
+	var nextID int64
+	for _, md := range l.dst.MetadataDefs {
+		if md.ID >= nextID {
+			nextID = md.ID + 1
+		}
+	}
+	for _, md := range l.src.MetadataDefs {
+		if flags[md] {
+			continue
+		}
+		md.ID = nextID
+		nextID++
+		l.dst.MetadataDefs = append(l.dst.MetadataDefs, md)
+	}
+	for _, named := range l.src.NamedMetadataDefs {
+		if named.Name == moduleFlagsName {
+			continue
+		}
+		if prev := findNamedMetadataDef(l.dst, named.Name); prev != nil {
+			prev.Nodes = append(prev.Nodes, named.Nodes...)
+			continue
+		}
+		l.dst.NamedMetadataDefs = append(l.dst.NamedMetadataDefs, named)
+	}
+	return nil
+}
+
+// symbol is a global variable, function, alias or IFunc.
+type symbol struct {
+	// Global variable, function, alias or IFunc.
+	v constant.Constant
+	// Global identifier of the symbol.
+	ident *GlobalIdent
+	// Linkage of the symbol.
+	linkage enum.Linkage
+	// Symbol is a declaration.
+	decl bool
+}
+
+// symbols returns the global variables, functions, aliases and IFuncs of the
+// given module.
+func symbols(m *Module) []*symbol {
+	var syms []*symbol
+	for _, g := range m.Globals {
+		syms = append(syms, &symbol{v: g, ident: &g.GlobalIdent, linkage: g.Linkage, decl: g.Init == nil})
+	}
+	for _, f := range m.Funcs {
+		syms = append(syms, &symbol{v: f, ident: &f.GlobalIdent, linkage: f.Linkage, decl: len(f.Blocks) == 0})
+	}
+	for _, alias := range m.Aliases {
+		syms = append(syms, &symbol{v: alias, ident: &alias.GlobalIdent, linkage: alias.Linkage})
+	}
+	for _, ifunc := range m.IFuncs {
+		syms = append(syms, &symbol{v: ifunc, ident: &ifunc.GlobalIdent, linkage: ifunc.Linkage})
+	}
+	return syms
+}
+
+// linkSymbols links the global variables, functions, aliases and IFuncs of the
+// source module, resolving global identifiers defined by both modules.
+func (l *linker) linkSymbols() error {
+	used := make(map[string]bool)
+	index := make(map[string]*symbol)
+	var nextID int64
+	for _, sym := range symbols(l.dst) {
+		if sym.ident.IsUnnamed() {
+			if sym.ident.GlobalID >= nextID {
+				nextID = sym.ident.GlobalID + 1
+			}
+			continue
+		}
+		used[sym.ident.GlobalName] = true
+		index[sym.ident.GlobalName] = sym
+	}
+	srcSyms := symbols(l.src)
+	for _, sym := range srcSyms {
+		used[sym.ident.GlobalName] = true
+	}
+	removed := make(map[constant.Constant]bool)
+	var added []*symbol
+	for _, sym := range srcSyms {
+		if sym.ident.IsUnnamed() {
+			sym.ident.SetID(nextID)
+			nextID++
+			added = append(added, sym)
+			continue
+		}
+		name := sym.ident.GlobalName
+		prev, ok := index[name]
+		switch {
+		case !ok:
+			// Global identifier not present in destination module.
+		case isLocalLinkage(sym.linkage):
+			sym.ident.SetName(uniqueName(name, used))
+		case isLocalLinkage(prev.linkage):
+			prev.ident.SetName(uniqueName(name, used))
+			index[prev.ident.GlobalName] = prev
+		case prev.linkage == enum.LinkageAppending && sym.linkage == enum.LinkageAppending:
+			if err := appendGlobals(prev, sym); err != nil {
+				return errors.WithStack(err)
+			}
+			l.repl[sym.v] = prev.v
+			continue
+		default:
+			keepDst, err := resolveSymbol(prev, sym)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if keepDst {
+				l.repl[sym.v] = bitCastIfNeeded(prev.v, sym.v.Type())
+				continue
+			}
+			l.repl[prev.v] = bitCastIfNeeded(sym.v, prev.v.Type())
+			removed[prev.v] = true
+		}
+		index[sym.ident.GlobalName] = sym
+		added = append(added, sym)
+	}
+	// Remove overridden symbols of the destination module.
+	if len(removed) > 0 {
+		globals := l.dst.Globals[:0]
+		for _, g := range l.dst.Globals {
+			if !removed[g] {
+				globals = append(globals, g)
+			}
+		}
+		l.dst.Globals = globals
+		funcs := l.dst.Funcs[:0]
+		for _, f := range l.dst.Funcs {
+			if !removed[f] {
+				funcs = append(funcs, f)
+			}
+		}
+		l.dst.Funcs = funcs
+		aliases := l.dst.Aliases[:0]
+		for _, alias := range l.dst.Aliases {
+			if !removed[alias] {
+				aliases = append(aliases, alias)
+			}
+		}
+		l.dst.Aliases = aliases
+		ifuncs := l.dst.IFuncs[:0]
+		for _, ifunc := range l.dst.IFuncs {
+			if !removed[ifunc] {
+				ifuncs = append(ifuncs, ifunc)
+			}
+		}
+		l.dst.IFuncs = ifuncs
+	}
+	// Add symbols of the source module.
+	for _, sym := range added {
+		switch v := sym.v.(type) {
+		case *Global:
+			l.dst.Globals = append(l.dst.Globals, v)
+		case *Function:
+			l.dst.Funcs = append(l.dst.Funcs, v)
+		case *Alias:
+			l.dst.Aliases = append(l.dst.Aliases, v)
+		case *IFunc:
+			l.dst.IFuncs = append(l.dst.IFuncs, v)
+		}
+	}
+	return nil
+}
+
+// resolveSymbol resolves the given symbols of the destination and source
+// module with the same global identifier, and reports whether the symbol of the
+// destination module is kept.
+func resolveSymbol(dst, src *symbol) (keepDst bool, err error) {
+	switch {
+	case src.decl:
+		return true, nil
+	case dst.decl:
+		return false, nil
+	case src.linkage == enum.LinkageAvailableExternally:
+		return true, nil
+	case dst.linkage == enum.LinkageAvailableExternally:
+		return false, nil
+	case isOverridableLinkage(src.linkage):
+		return true, nil
+	case isOverridableLinkage(dst.linkage):
+		return false, nil
+	default:
+		return false, errors.Errorf("global identifier %q defined in both modules", dst.ident.Ident())
+	}
+}
+
+// appendGlobals appends the elements of the array initializer of the source
+// global variable to the array initializer of the destination global variable,
+// both of which have appending linkage.
+func appendGlobals(dst, src *symbol) error {
+	x, ok := dst.v.(*Global)
+	if !ok {
+		return errors.Errorf("invalid global identifier %q with appending linkage; expected global variable, got %T", dst.ident.Ident(), dst.v)
+	}
+	y, ok := src.v.(*Global)
+	if !ok {
+		return errors.Errorf("invalid global identifier %q with appending linkage; expected global variable, got %T", src.ident.Ident(), src.v)
+	}
+	xt, ok := x.ContentType.(*types.ArrayType)
+	if !ok {
+		return errors.Errorf("invalid content type of global variable %q with appending linkage; expected array type, got %T", x.Ident(), x.ContentType)
+	}
+	yt, ok := y.ContentType.(*types.ArrayType)
+	if !ok || !xt.ElemType.Equal(yt.ElemType) {
+		return errors.Errorf("mismatching content types of global variable %q with appending linkage; %v and %v", x.Ident(), x.ContentType, y.ContentType)
+	}
+	var elems []constant.Constant
+	for _, g := range []*Global{x, y} {
+		switch init := g.Init.(type) {
+		case *constant.Array:
+			elems = append(elems, init.Elems...)
+		case *constant.ZeroInitializer:
+			if g.ContentType.(*types.ArrayType).Len != 0 {
+				return errors.Errorf("support for zero initialized global variable %q with appending linkage not yet implemented", g.Ident())
+			}
+		default:
+			return errors.Errorf("invalid initializer of global variable %q with appending linkage; expected array constant, got %T", g.Ident(), g.Init)
+		}
+	}
+	contentType := types.NewArray(uint64(len(elems)), xt.ElemType)
+	x.Init = &constant.Array{Typ: contentType, Elems: elems}
+	x.ContentType = contentType
+	typ := types.NewPointer(contentType)
+	typ.AddrSpace = x.Type().(*types.PointerType).AddrSpace
+	x.Typ = typ
+	return nil
+}
+
+// bitCastIfNeeded returns c if of the given type, and a bitcast of c to the
+// given type otherwise.
+func bitCastIfNeeded(c constant.Constant, t types.Type) constant.Constant {
+	if c.Type().Equal(t) {
+		return c
+	}
+	return constant.NewBitCast(c, t)
+}
+
+// isLocalLinkage reports whether the given linkage is private or internal.
+func isLocalLinkage(linkage enum.Linkage) bool {
+	return linkage == enum.LinkagePrivate || linkage == enum.LinkageInternal
+}
+
+// isOverridableLinkage reports whether definitions with the given linkage may
+// be overridden by other definitions when linking.
+func isOverridableLinkage(linkage enum.Linkage) bool {
+	switch linkage {
+	case enum.LinkageWeak, enum.LinkageWeakODR, enum.LinkageLinkOnce, enum.LinkageLinkOnceODR, enum.LinkageCommon, enum.LinkageExternWeak:
+		return true
+	default:
+		return false
+	}
+}
+
+// uniqueName returns a name based on the given name which is not present in
+// used (e.g. "x.1"), and marks the name as used.
+func uniqueName(name string, used map[string]bool) string {
+	for i := 1; ; i++ {
+		s := fmt.Sprintf("%s.%d", name, i)
+		if !used[s] {
+			used[s] = true
+			return s
+		}
+	}
+}
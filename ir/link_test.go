@@ -0,0 +1,92 @@
+package ir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestLink(t *testing.T) {
+	// Destination module declaring @f.
+	dst := NewModule()
+	dst.TargetTriple = "x86_64-unknown-linux-gnu"
+	dstCounter := dst.NewGlobalDef("counter", constant.NewInt(types.I32, 0))
+	dstCounter.Linkage = enum.LinkagePrivate
+	declF := dst.NewFunc("f", types.I32, NewParam("", types.I32))
+	main := dst.NewFunc("main", types.I32)
+	entry := main.NewBlock("")
+	entry.NewStore(constant.NewInt(types.I32, 1), dstCounter)
+	entry.NewRet(entry.NewCall(declF, constant.NewInt(types.I32, 42)))
+	// Source module defining @f, with a private global variable of the same
+	// name as in the destination module.
+	src := NewModule()
+	srcCounter := src.NewGlobalDef("counter", constant.NewInt(types.I32, 0))
+	srcCounter.Linkage = enum.LinkagePrivate
+	x := NewParam("x", types.I32)
+	f := src.NewFunc("f", types.I32, x)
+	body := f.NewBlock("")
+	body.NewStore(x, srcCounter)
+	body.NewRet(x)
+	// Weak definition overridden by strong definition of destination module.
+	weak := src.NewFunc("main", types.I32)
+	weak.Linkage = enum.LinkageWeak
+	weak.NewBlock("").NewRet(constant.NewInt(types.I32, 0))
+	if err := Link(dst, src); err != nil {
+		t.Fatalf("unable to link modules; %+v", err)
+	}
+	const want = `target triple = "x86_64-unknown-linux-gnu"
+
+@counter = private global i32 0
+@counter.1 = private global i32 0
+
+define i32 @main() {
+; <label>:0
+	store i32 1, i32* @counter
+	%1 = call i32 @f(i32 42)
+	ret i32 %1
+}
+
+define i32 @f(i32 %x) {
+; <label>:0
+	store i32 %x, i32* @counter.1
+	ret i32 %x
+}
+`
+	if got := dst.String(); got != want {
+		t.Errorf("linked module mismatch; expected\n%s\ngot\n%s", want, got)
+	}
+	if got, ok := dst.Func("f"); !ok || got != f {
+		t.Errorf("unable to locate definition of @f in linked module")
+	}
+	// Module flags present in both modules are deduplicated, and metadata
+	// definitions are assigned new IDs.
+	fm := newModuleWithFlags(newModuleFlag(1, "wchar_size", constant.NewInt(types.I32, 4)))
+	other := newModuleWithFlags(newModuleFlag(1, "wchar_size", constant.NewInt(types.I32, 4)), newModuleFlag(7, "PIC Level", constant.NewInt(types.I32, 2)))
+	ident := &metadata.Def{ID: 0, Node: &metadata.Tuple{Fields: []metadata.Field{&metadata.String{Value: "clang"}}}}
+	other.MetadataDefs = append([]*metadata.Def{ident}, other.MetadataDefs...)
+	other.NamedMetadataDefs = append(other.NamedMetadataDefs, &metadata.NamedDef{Name: "llvm.ident", Nodes: []metadata.Node{ident}})
+	if err := Link(fm, other); err != nil {
+		t.Fatalf("unable to link modules; %+v", err)
+	}
+	const wantFlags = `!llvm.module.flags = !{!0, !1}
+!llvm.ident = !{!2}
+
+!0 = !{i32 1, !"wchar_size", i32 4}
+!1 = !{i32 7, !"PIC Level", i32 2}
+!2 = !{!"clang"}`
+	if got := strings.TrimSpace(fm.String()); got != wantFlags {
+		t.Errorf("linked module mismatch; expected\n%s\ngot\n%s", wantFlags, got)
+	}
+	// Strong definitions of the same global identifier in both modules.
+	a := NewModule()
+	a.NewFunc("g", types.Void).NewBlock("").NewRet(nil)
+	b := NewModule()
+	b.NewFunc("g", types.Void).NewBlock("").NewRet(nil)
+	if err := Link(a, b); err == nil {
+		t.Errorf("expected error for global identifier defined in both modules")
+	}
+}
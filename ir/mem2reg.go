@@ -0,0 +1,279 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Promotion of memory to registers ] ====================================
+
+// PromoteMemoryToRegister promotes the local variables of the given function
+// from memory to registers; i.e. it rewrites alloca, load and store
+// instructions of local variables into SSA form.
+//
+// An alloca instruction is promotable if it allocates a single element, and is
+// only used as the source operand of non-volatile, non-atomic load instructions
+// and the destination operand of non-volatile, non-atomic store instructions of
+// the element type. Phi instructions are
+// inserted at the iterated dominance frontiers of the stores of promoted local
+// variables where the variables are live, and loads are replaced by the
+// reaching stored values. Loads of uninitialized variables are replaced by
+// undef.
+func PromoteMemoryToRegister(f *Function) {
+	if len(f.Blocks) == 0 {
+		return
+	}
+	allocas := promotableAllocas(f)
+	if len(allocas) == 0 {
+		return
+	}
+	promoted := make(map[*InstAlloca]bool)
+	for _, alloca := range allocas {
+		promoted[alloca] = true
+	}
+	dt := ComputeDominatorTree(f)
+	// Insert phi instructions.
+	phis := make(map[*BasicBlock][]*InstPhi)
+	phiVars := make(map[*InstPhi]*InstAlloca)
+	for _, alloca := range allocas {
+		defBlocks, liveIn := allocaBlocks(f, alloca)
+		for _, block := range iteratedFrontier(dt, defBlocks) {
+			if !liveIn[block] {
+				continue
+			}
+			phi := &InstPhi{Typ: alloca.ElemType}
+			phis[block] = append(phis[block], phi)
+			phiVars[phi] = alloca
+		}
+	}
+	// Rename loads and stores in dominator tree order.
+	repl := make(map[*InstLoad]value.Value)
+	children := make(map[*BasicBlock][]*BasicBlock)
+	for _, block := range f.Blocks {
+		if idom := dt.IDom(block); idom != nil {
+			children[idom] = append(children[idom], block)
+		}
+	}
+	var rename func(block *BasicBlock, vals map[*InstAlloca]value.Value)
+	rename = func(block *BasicBlock, incoming map[*InstAlloca]value.Value) {
+		vals := make(map[*InstAlloca]value.Value)
+		for alloca, v := range incoming {
+			vals[alloca] = v
+		}
+		for _, phi := range phis[block] {
+			vals[phiVars[phi]] = phi
+		}
+		block.Insts = promoteInsts(block.Insts, promoted, vals, repl)
+		for _, succ := range block.Succs() {
+			for _, phi := range phis[succ] {
+				phi.Incs = append(phi.Incs, NewIncoming(vals[phiVars[phi]], block))
+			}
+		}
+		for _, child := range children[block] {
+			rename(child, vals)
+		}
+	}
+	undefs := make(map[*InstAlloca]value.Value)
+	for _, alloca := range allocas {
+		undefs[alloca] = constant.NewUndef(alloca.ElemType)
+	}
+	entry := f.Blocks[0]
+	rename(entry, undefs)
+	// Rename loads and stores of basic blocks unreachable from the entry block.
+	for _, block := range f.Blocks {
+		if block != entry && dt.IDom(block) == nil {
+			rename(block, undefs)
+		}
+	}
+	// Prepend phi instructions to basic blocks.
+	for _, block := range f.Blocks {
+		if len(phis[block]) == 0 {
+			continue
+		}
+		insts := make([]Instruction, 0, len(phis[block])+len(block.Insts))
+		for _, phi := range phis[block] {
+			insts = append(insts, phi)
+		}
+		block.Insts = append(insts, block.Insts...)
+	}
+	// Replace uses of removed loads.
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			for _, op := range operands(inst) {
+				*op = resolveLoad(*op, repl)
+			}
+		}
+		if block.Term != nil {
+			for _, op := range operands(block.Term) {
+				*op = resolveLoad(*op, repl)
+			}
+		}
+	}
+}
+
+// ### [ Helper functions ] ####################################################
+
+// promotableAllocas returns the promotable alloca instructions of the given
+// function, in order of occurrence.
+func promotableAllocas(f *Function) []*InstAlloca {
+	var allocas []*InstAlloca
+	promotable := make(map[*InstAlloca]bool)
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			alloca, ok := inst.(*InstAlloca)
+			if !ok || alloca.NElems != nil || alloca.InAlloca || alloca.SwiftError {
+				continue
+			}
+			allocas = append(allocas, alloca)
+			promotable[alloca] = true
+		}
+	}
+	// Rule out allocas with uses other than plain loads and stores.
+	escape := func(v value.Value) {
+		if alloca, ok := v.(*InstAlloca); ok {
+			promotable[alloca] = false
+		}
+	}
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			switch inst := inst.(type) {
+			case *InstLoad:
+				if alloca, ok := inst.Src.(*InstAlloca); ok {
+					if inst.Atomic || inst.Volatile || !types.Equal(inst.Type(), alloca.ElemType) {
+						promotable[alloca] = false
+					}
+				}
+			case *InstStore:
+				escape(inst.Src)
+				if alloca, ok := inst.Dst.(*InstAlloca); ok {
+					if inst.Atomic || inst.Volatile || !types.Equal(inst.Src.Type(), alloca.ElemType) {
+						promotable[alloca] = false
+					}
+				}
+			default:
+				for _, op := range operands(inst) {
+					escape(*op)
+				}
+			}
+		}
+		if block.Term != nil {
+			for _, op := range operands(block.Term) {
+				escape(*op)
+			}
+		}
+	}
+	var result []*InstAlloca
+	for _, alloca := range allocas {
+		if promotable[alloca] {
+			result = append(result, alloca)
+		}
+	}
+	return result
+}
+
+// allocaBlocks returns the basic blocks containing stores to the given alloca
+// instruction, and the set of basic blocks in which the allocated variable is
+// live on entry.
+func allocaBlocks(f *Function, alloca *InstAlloca) (defBlocks []*BasicBlock, liveIn map[*BasicBlock]bool) {
+	liveIn = make(map[*BasicBlock]bool)
+	defined := make(map[*BasicBlock]bool)
+	var work []*BasicBlock
+	for _, block := range f.Blocks {
+		stored := false
+		for _, inst := range block.Insts {
+			switch inst := inst.(type) {
+			case *InstLoad:
+				if inst.Src == alloca && !stored && !liveIn[block] {
+					liveIn[block] = true
+					work = append(work, block)
+				}
+			case *InstStore:
+				if inst.Dst == alloca {
+					stored = true
+				}
+			}
+		}
+		if stored {
+			defined[block] = true
+			defBlocks = append(defBlocks, block)
+		}
+	}
+	// Propagate liveness to predecessors not defining the variable.
+	for len(work) > 0 {
+		block := work[len(work)-1]
+		work = work[:len(work)-1]
+		for _, pred := range f.predsOf(block) {
+			if defined[pred] || liveIn[pred] {
+				continue
+			}
+			liveIn[pred] = true
+			work = append(work, pred)
+		}
+	}
+	return defBlocks, liveIn
+}
+
+// iteratedFrontier returns the iterated dominance frontier of the given basic
+// blocks, in order of insertion.
+func iteratedFrontier(dt *DomTree, blocks []*BasicBlock) []*BasicBlock {
+	var frontier []*BasicBlock
+	added := make(map[*BasicBlock]bool)
+	work := append([]*BasicBlock(nil), blocks...)
+	for len(work) > 0 {
+		block := work[0]
+		work = work[1:]
+		for _, df := range dt.DominanceFrontier(block) {
+			if added[df] {
+				continue
+			}
+			added[df] = true
+			frontier = append(frontier, df)
+			work = append(work, df)
+		}
+	}
+	return frontier
+}
+
+// promoteInsts removes the promoted alloca instructions, and the loads and
+// stores of promoted variables from the given instructions. The current values
+// of promoted variables are tracked in vals, and removed loads are mapped to
+// their values in repl.
+func promoteInsts(insts []Instruction, promoted map[*InstAlloca]bool, vals map[*InstAlloca]value.Value, repl map[*InstLoad]value.Value) []Instruction {
+	var result []Instruction
+	for _, inst := range insts {
+		switch inst := inst.(type) {
+		case *InstAlloca:
+			if promoted[inst] {
+				continue
+			}
+		case *InstLoad:
+			if alloca, ok := inst.Src.(*InstAlloca); ok && promoted[alloca] {
+				repl[inst] = vals[alloca]
+				continue
+			}
+		case *InstStore:
+			if alloca, ok := inst.Dst.(*InstAlloca); ok && promoted[alloca] {
+				vals[alloca] = resolveLoad(inst.Src, repl)
+				continue
+			}
+		}
+		result = append(result, inst)
+	}
+	return result
+}
+
+// resolveLoad returns the value of the given value, following removed loads.
+func resolveLoad(v value.Value, repl map[*InstLoad]value.Value) value.Value {
+	for {
+		load, ok := v.(*InstLoad)
+		if !ok {
+			return v
+		}
+		x, ok := repl[load]
+		if !ok {
+			return v
+		}
+		v = x
+	}
+}
@@ -0,0 +1,171 @@
+package ir
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestPromoteMemoryToRegister(t *testing.T) {
+	// Counter loop; sum of 0 through n-1.
+	f := NewFunc("count", types.I32, NewParam("n", types.I32))
+	entry := f.NewBlock("entry")
+	head := f.NewBlock("head")
+	body := f.NewBlock("body")
+	exit := f.NewBlock("exit")
+	i := entry.NewAlloca(types.I32)
+	i.SetName("i")
+	sum := entry.NewAlloca(types.I32)
+	sum.SetName("sum")
+	zero := constant.NewInt(types.I32, 0)
+	entry.NewStore(zero, i)
+	entry.NewStore(zero, sum)
+	entry.NewBr(head)
+	cond := head.NewICmp(enum.IPredSLT, head.NewLoad(i), f.Params[0])
+	head.NewCondBr(cond, body, exit)
+	s := body.NewLoad(sum)
+	x := body.NewLoad(i)
+	body.NewStore(body.NewAdd(s, x), sum)
+	body.NewStore(body.NewAdd(x, constant.NewInt(types.I32, 1)), i)
+	body.NewBr(head)
+	exit.NewRet(exit.NewLoad(sum))
+	var before []int64
+	for n := int64(0); n < 5; n++ {
+		before = append(before, interpret(f, n))
+	}
+	PromoteMemoryToRegister(f)
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			if _, ok := inst.(*InstAlloca); ok {
+				t.Errorf("alloca not promoted; `%s`", inst.Def())
+			}
+		}
+	}
+	for n := int64(0); n < 5; n++ {
+		if want, got := before[n], interpret(f, n); want != got {
+			t.Errorf("result mismatch of @count(%d) after promotion; expected %d, got %d", n, want, got)
+		}
+	}
+	want := `define i32 @count(i32 %n) {
+entry:
+	br label %head
+
+head:
+	%0 = phi i32 [ 0, %entry ], [ %4, %body ]
+	%1 = phi i32 [ 0, %entry ], [ %3, %body ]
+	%2 = icmp slt i32 %0, %n
+	br i1 %2, label %body, label %exit
+
+body:
+	%3 = add i32 %1, %0
+	%4 = add i32 %0, 1
+	br label %head
+
+exit:
+	ret i32 %1
+}`
+	if got := f.Def(); want != got {
+		t.Errorf("function mismatch after promotion; expected `%v`, got `%v`", want, got)
+	}
+}
+
+// interpret evaluates the given function with integer arguments, supporting
+// the subset of LLVM IR used by TestPromoteMemoryToRegister.
+func interpret(f *Function, args ...int64) int64 {
+	vals := make(map[value.Value]int64)
+	for i, param := range f.Params {
+		vals[param] = args[i]
+	}
+	mem := make(map[value.Value]int64)
+	eval := func(v value.Value) int64 {
+		if c, ok := v.(*constant.Int); ok {
+			return c.X.Int64()
+		}
+		return vals[v]
+	}
+	var pred *BasicBlock
+	for block := f.Blocks[0]; ; {
+		// Evaluate phi instructions simultaneously.
+		phiVals := make(map[value.Value]int64)
+		for _, inst := range block.Insts {
+			if phi, ok := inst.(*InstPhi); ok {
+				for _, inc := range phi.Incs {
+					if inc.Pred == pred {
+						phiVals[phi] = eval(inc.X)
+					}
+				}
+			}
+		}
+		for phi, v := range phiVals {
+			vals[phi] = v
+		}
+		for _, inst := range block.Insts {
+			switch inst := inst.(type) {
+			case *InstPhi, *InstAlloca:
+			case *InstLoad:
+				vals[inst] = mem[inst.Src]
+			case *InstStore:
+				mem[inst.Dst] = eval(inst.Src)
+			case *InstAdd:
+				vals[inst] = eval(inst.X) + eval(inst.Y)
+			case *InstICmp:
+				if eval(inst.X) < eval(inst.Y) {
+					vals[inst] = 1
+				} else {
+					vals[inst] = 0
+				}
+			default:
+				panic(fmt.Errorf("support for instruction %T not yet implemented", inst))
+			}
+		}
+		pred = block
+		switch term := block.Term.(type) {
+		case *TermBr:
+			block = term.Target
+		case *TermCondBr:
+			if eval(term.Cond) != 0 {
+				block = term.TargetTrue
+			} else {
+				block = term.TargetFalse
+			}
+		case *TermRet:
+			return eval(term.X)
+		default:
+			panic(fmt.Errorf("support for terminator %T not yet implemented", term))
+		}
+	}
+}
+
+func TestPromoteMemoryToRegisterArg(t *testing.T) {
+	useptr := NewFunc("useptr", types.Void, NewParam("", types.I32Ptr))
+	usei := NewFunc("usei", types.Void, NewParam("", types.I32))
+	f := NewFunc("f", types.Void, NewParam("x", types.I32))
+	entry := f.NewBlock("entry")
+	// Alloca escaping through argument with parameter attributes.
+	a := entry.NewAlloca(types.I32)
+	a.SetName("a")
+	// Promotable alloca loaded into argument with parameter attributes.
+	b := entry.NewAlloca(types.I32)
+	b.SetName("b")
+	entry.NewStore(f.Params[0], b)
+	v := entry.NewLoad(b)
+	v.SetName("v")
+	entry.NewCall(useptr, NewArg(a, enum.ParamAttrNoCapture))
+	entry.NewCall(usei, NewArg(v, enum.ParamAttrSignExt))
+	entry.NewRet(nil)
+	PromoteMemoryToRegister(f)
+	want := `define void @f(i32 %x) {
+entry:
+	%a = alloca i32
+	call void @useptr(i32* nocapture %a)
+	call void @usei(i32 signext %x)
+	ret void
+}`
+	if got := f.Def(); want != got {
+		t.Errorf("function mismatch after promotion; expected `%v`, got `%v`", want, got)
+	}
+}
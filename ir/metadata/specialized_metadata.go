@@ -1026,8 +1026,9 @@ func (md *DISubroutineType) String() string {
 
 // DITemplateTypeParameter is a specialized metadata node.
 type DITemplateTypeParameter struct {
-	Name string // optional; empty if not present.
-	Type Field  // required.
+	Name      string // optional; empty if not present.
+	Type      Field  // required.
+	Defaulted bool   // optional; zero value if not present.
 }
 
 // String returns a string representation of the specialized metadata node.
@@ -1040,6 +1041,10 @@ func (md *DITemplateTypeParameter) String() string {
 	}
 	field := fmt.Sprintf("type: %s", md.Type)
 	fields = append(fields, field)
+	if md.Defaulted {
+		field = fmt.Sprintf("defaulted: %t", md.Defaulted)
+		fields = append(fields, field)
+	}
 	return fmt.Sprintf("!DITemplateTypeParameter(%s)", strings.Join(fields, ", "))
 }
 
@@ -1047,10 +1052,11 @@ func (md *DITemplateTypeParameter) String() string {
 
 // DITemplateValueParameter is a specialized metadata node.
 type DITemplateValueParameter struct {
-	Tag   enum.DwarfTag // optional; zero value if not present.
-	Name  string        // optional; empty if not present.
-	Type  Field         // optional; nil if not present.
-	Value Field         // required.
+	Tag       enum.DwarfTag // optional; zero value if not present.
+	Name      string        // optional; empty if not present.
+	Type      Field         // optional; nil if not present.
+	Defaulted bool          // optional; zero value if not present.
+	Value     Field         // required.
 }
 
 // String returns a string representation of the specialized metadata node.
@@ -1069,6 +1075,10 @@ func (md *DITemplateValueParameter) String() string {
 		field := fmt.Sprintf("type: %s", md.Type)
 		fields = append(fields, field)
 	}
+	if md.Defaulted {
+		field := fmt.Sprintf("defaulted: %t", md.Defaulted)
+		fields = append(fields, field)
+	}
 	field := fmt.Sprintf("value: %s", md.Value)
 	fields = append(fields, field)
 	return fmt.Sprintf("!DITemplateValueParameter(%s)", strings.Join(fields, ", "))
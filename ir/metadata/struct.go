@@ -0,0 +1,50 @@
+package metadata
+
+import (
+	"github.com/llir/llvm/ir/enum"
+)
+
+// === [ Struct debug information ] ============================================
+
+// StructFieldNames returns the names of the data members of the given composite
+// debug type (e.g. a struct, class or union), in order of declaration; or nil if
+// no debug information is present. Static data members are not included, and
+// base classes are included with an empty name, as both correspond to fields of
+// the struct type of the composite type.
+func StructFieldNames(composite *DICompositeType) []string {
+	if composite == nil {
+		return nil
+	}
+	elems, ok := node(composite.Elements).(*Tuple)
+	if !ok {
+		return nil
+	}
+	var names []string
+	for _, elem := range elems.Fields {
+		member, ok := node(elem).(*DIDerivedType)
+		if !ok {
+			continue
+		}
+		switch {
+		case member.Tag == enum.DwarfTagInheritance:
+			names = append(names, "")
+		case member.Tag == enum.DwarfTagMember && member.Flags&enum.DIFlagStaticMember == 0:
+			names = append(names, member.Name)
+		}
+	}
+	return names
+}
+
+// ### [ Helper functions ] ####################################################
+
+// node returns the metadata node of the given metadata field, following
+// metadata definitions.
+func node(field Field) Field {
+	for {
+		def, ok := field.(*Def)
+		if !ok {
+			return field
+		}
+		field = def.Node
+	}
+}
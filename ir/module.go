@@ -3,6 +3,7 @@ package ir
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/llir/llvm/internal/enc"
@@ -50,6 +51,16 @@ type Module struct {
 	UseListOrders []*UseListOrder
 	// (optional) Basic block specific use-list order directives.
 	UseListOrderBBs []*UseListOrderBB
+	// (optional) Emit comments in the style of Clang when printing the module;
+	// e.g. "; Function Attrs: noinline nounwind" above functions with
+	// attributes. Comments do not change the semantics of the module.
+	EmitClangStyleComments bool
+
+	// Index from function name to position in Funcs; lazily built by Func.
+	funcIndex map[string]int
+	// Index from global variable name to position in Globals; lazily built by
+	// Global.
+	globalIndex map[string]int
 }
 
 // NewModule returns a new LLVM IR module.
@@ -61,113 +72,139 @@ func NewModule() *Module {
 // syntax.
 func (m *Module) String() string {
 	buf := &strings.Builder{}
+	if _, err := m.WriteTo(buf); err != nil {
+		panic(fmt.Errorf("unable to write module to string builder; %v", err))
+	}
+	return buf.String()
+}
+
+// EncodedLen returns the length in bytes of the string representation of the
+// module in LLVM IR assembly syntax, as computed by summing the lengths of its
+// top-level entities without allocating the full string.
+func (m *Module) EncodedLen() int {
+	cw := &countWriter{}
+	if _, err := m.WriteTo(cw); err != nil {
+		panic(fmt.Errorf("unable to compute encoded length of module; %v", err))
+	}
+	return cw.n
+}
+
+// WriteTo writes the string representation of the module in LLVM IR assembly
+// syntax to w, and returns the number of bytes written.
+func (m *Module) WriteTo(w io.Writer) (n int64, err error) {
+	fw := &fmtWriter{w: w}
 	// Source filename.
 	if len(m.SourceFilename) > 0 {
 		// 'source_filename' '=' Name=StringLit
-		fmt.Fprintf(buf, "source_filename = %s\n", quote(m.SourceFilename))
+		fw.Fprintf("source_filename = %s\n", quote(m.SourceFilename))
 	}
 	// Data layout.
 	if len(m.DataLayout) > 0 {
 		// 'target' 'datalayout' '=' DataLayout=StringLit
-		fmt.Fprintf(buf, "target datalayout = %s\n", quote(m.DataLayout))
+		fw.Fprintf("target datalayout = %s\n", quote(m.DataLayout))
 	}
 	// Target triple.
 	if len(m.TargetTriple) > 0 {
 		// 'target' 'triple' '=' TargetTriple=StringLit
-		fmt.Fprintf(buf, "target triple = %s\n", quote(m.TargetTriple))
+		fw.Fprintf("target triple = %s\n", quote(m.TargetTriple))
 	}
 	// Module-level inline assembly.
-	if len(m.ModuleAsms) > 0 && buf.Len() > 0 {
-		buf.WriteString("\n")
+	if len(m.ModuleAsms) > 0 && fw.size > 0 {
+		fw.Fprint("\n")
 	}
 	for _, asm := range m.ModuleAsms {
 		// 'module' 'asm' Asm=StringLit
-		fmt.Fprintf(buf, "module asm %s\n", quote(asm))
+		fw.Fprintf("module asm %s\n", quote(asm))
 	}
 	// Type definitions.
-	if len(m.TypeDefs) > 0 && buf.Len() > 0 {
-		buf.WriteString("\n")
+	if len(m.TypeDefs) > 0 && fw.size > 0 {
+		fw.Fprint("\n")
 	}
 	for _, t := range m.TypeDefs {
 		// Alias=LocalIdent '=' 'type' Typ=OpaqueType
 		//
 		// Alias=LocalIdent '=' 'type' Typ=Type
-		fmt.Fprintf(buf, "%s = type %s\n", t, t.Def())
+		fw.Fprintf("%s = type %s\n", t, t.Def())
 	}
 	// Comdat definitions.
-	if len(m.ComdatDefs) > 0 && buf.Len() > 0 {
-		buf.WriteString("\n")
+	if len(m.ComdatDefs) > 0 && fw.size > 0 {
+		fw.Fprint("\n")
 	}
 	for _, def := range m.ComdatDefs {
-		fmt.Fprintln(buf, def.Def())
+		fw.Fprintln(def.Def())
 	}
 	// Global declarations and definitions.
-	if len(m.Globals) > 0 && buf.Len() > 0 {
-		buf.WriteString("\n")
+	if len(m.Globals) > 0 && fw.size > 0 {
+		fw.Fprint("\n")
 	}
 	for _, g := range m.Globals {
-		fmt.Fprintln(buf, g.Def())
+		fw.Fprintln(g.Def())
 	}
 	// Aliases.
-	if len(m.Aliases) > 0 && buf.Len() > 0 {
-		buf.WriteString("\n")
+	if len(m.Aliases) > 0 && fw.size > 0 {
+		fw.Fprint("\n")
 	}
 	for _, alias := range m.Aliases {
-		fmt.Fprintln(buf, alias.Def())
+		fw.Fprintln(alias.Def())
 	}
 	// IFuncs.
-	if len(m.IFuncs) > 0 && buf.Len() > 0 {
-		buf.WriteString("\n")
+	if len(m.IFuncs) > 0 && fw.size > 0 {
+		fw.Fprint("\n")
 	}
 	for _, ifunc := range m.IFuncs {
-		fmt.Fprintln(buf, ifunc.Def())
+		fw.Fprintln(ifunc.Def())
 	}
 	// Function declarations and definitions.
-	if len(m.Funcs) > 0 && buf.Len() > 0 {
-		buf.WriteString("\n")
+	if len(m.Funcs) > 0 && fw.size > 0 {
+		fw.Fprint("\n")
 	}
 	for i, f := range m.Funcs {
 		if i != 0 {
-			buf.WriteString("\n")
+			fw.Fprint("\n")
+		}
+		if m.EmitClangStyleComments {
+			if attrs := funcAttrsComment(f.FuncAttrs); len(attrs) > 0 {
+				fw.Fprintf("; Function Attrs: %s\n", attrs)
+			}
 		}
-		fmt.Fprintln(buf, f.Def())
+		fw.Fprintln(f.Def())
 	}
 	// Attribute group definitions.
-	if len(m.AttrGroupDefs) > 0 && buf.Len() > 0 {
-		buf.WriteString("\n")
+	if len(m.AttrGroupDefs) > 0 && fw.size > 0 {
+		fw.Fprint("\n")
 	}
 	for _, a := range m.AttrGroupDefs {
-		fmt.Fprintln(buf, a.Def())
+		fw.Fprintln(a.Def())
 	}
 	// Named metadata definitions.
-	if len(m.NamedMetadataDefs) > 0 && buf.Len() > 0 {
-		buf.WriteString("\n")
+	if len(m.NamedMetadataDefs) > 0 && fw.size > 0 {
+		fw.Fprint("\n")
 	}
 	for _, md := range m.NamedMetadataDefs {
-		fmt.Fprintln(buf, md.Def())
+		fw.Fprintln(md.Def())
 	}
 	// Metadata definitions.
-	if len(m.MetadataDefs) > 0 && buf.Len() > 0 {
-		buf.WriteString("\n")
+	if len(m.MetadataDefs) > 0 && fw.size > 0 {
+		fw.Fprint("\n")
 	}
 	for _, md := range m.MetadataDefs {
-		fmt.Fprintln(buf, md.Def())
+		fw.Fprintln(md.Def())
 	}
 	// Use-list orders.
-	if len(m.UseListOrders) > 0 && buf.Len() > 0 {
-		buf.WriteString("\n")
+	if len(m.UseListOrders) > 0 && fw.size > 0 {
+		fw.Fprint("\n")
 	}
 	for _, u := range m.UseListOrders {
-		fmt.Fprintln(buf, u)
+		fw.Fprintln(u)
 	}
 	// Basic block specific use-list orders.
-	if len(m.UseListOrderBBs) > 0 && buf.Len() > 0 {
-		buf.WriteString("\n")
+	if len(m.UseListOrderBBs) > 0 && fw.size > 0 {
+		fw.Fprint("\n")
 	}
 	for _, u := range m.UseListOrderBBs {
-		fmt.Fprintln(buf, u)
+		fw.Fprintln(u)
 	}
-	return buf.String()
+	return fw.size, fw.err
 }
 
 // ~~~ [ Comdat Definition ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -180,6 +217,12 @@ type ComdatDef struct {
 	Kind enum.SelectionKind
 }
 
+// NewComdat returns a new comdat definition based on the given comdat name and
+// selection kind.
+func NewComdat(name string, kind enum.SelectionKind) *ComdatDef {
+	return &ComdatDef{Name: name, Kind: kind}
+}
+
 // String returns the string representation of the Comdat definition.
 func (c *ComdatDef) String() string {
 	return fmt.Sprintf("comdat(%s)", enc.Comdat(c.Name))
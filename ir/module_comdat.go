@@ -0,0 +1,11 @@
+package ir
+
+import "github.com/llir/llvm/ir/enum"
+
+// NewComdat appends a new comdat definition to the module based on the given
+// comdat name and selection kind.
+func (m *Module) NewComdat(name string, kind enum.SelectionKind) *ComdatDef {
+	c := NewComdat(name, kind)
+	m.ComdatDefs = append(m.ComdatDefs, c)
+	return c
+}
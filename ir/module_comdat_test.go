@@ -0,0 +1,43 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestNewComdat(t *testing.T) {
+	m := NewModule()
+	anyComdat := m.NewComdat("any", enum.SelectionKindAny)
+	largest := m.NewComdat("largest", enum.SelectionKindLargest)
+	noDups := m.NewComdat("nodups", enum.SelectionKindNoDuplicates)
+	sameSize := m.NewComdat("samesize", enum.SelectionKindSameSize)
+	m.NewGlobalDef("any", constant.NewInt(types.I32, 0)).SetLinkage(enum.LinkageLinkOnceODR).SetComdat(anyComdat)
+	m.NewGlobalDef("foo", constant.NewInt(types.I32, 1)).SetComdat(largest)
+	m.NewGlobalDef("nodups", constant.NewInt(types.I32, 2)).SetComdat(noDups)
+	f := m.NewFunc("f", types.Void).SetComdat(sameSize)
+	f.Linkage = enum.LinkageLinkOnceODR
+	f.NewBlock("").NewRet(nil)
+	const want = `$any = comdat any
+$largest = comdat largest
+$nodups = comdat noduplicates
+$samesize = comdat samesize
+
+@any = linkonce_odr global i32 0, comdat
+@foo = global i32 1, comdat($largest)
+@nodups = global i32 2, comdat
+
+define linkonce_odr void @f() comdat($samesize) {
+; <label>:0
+	ret void
+}
+`
+	if got := m.String(); got != want {
+		t.Errorf("module mismatch; expected %q, got %q", want, got)
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected verification error; %v", err)
+	}
+}
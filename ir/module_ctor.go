@@ -0,0 +1,85 @@
+package ir
+
+import (
+	"github.com/llir/llvm/internal/enc"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/pkg/errors"
+)
+
+// --- [ Global constructors and destructors ] ---------------------------------
+
+// AddGlobalCtor registers the given function as a global constructor of the
+// module with the given priority, by appending it to the llvm.global_ctors
+// array; which is created with appending linkage if not present. The associated
+// data is a global value of which the constructor is only run if the data is
+// retained; or nil if not present.
+//
+// ref: https://llvm.org/docs/LangRef.html#the-llvm-global-ctors-global-variable
+func (m *Module) AddGlobalCtor(priority int, f *Function, data constant.Constant) error {
+	return m.appendStructor("llvm.global_ctors", priority, f, data)
+}
+
+// AddGlobalDtor registers the given function as a global destructor of the
+// module with the given priority, by appending it to the llvm.global_dtors
+// array; which is created with appending linkage if not present. The associated
+// data is a global value of which the destructor is only run if the data is
+// retained; or nil if not present.
+//
+// ref: https://llvm.org/docs/LangRef.html#the-llvm-global-dtors-global-variable
+func (m *Module) AddGlobalDtor(priority int, f *Function, data constant.Constant) error {
+	return m.appendStructor("llvm.global_dtors", priority, f, data)
+}
+
+// appendStructor appends the given constructor or destructor function to the
+// specified global array of the module; i.e. llvm.global_ctors or
+// llvm.global_dtors. The elements of the global array are of type
+// { i32, void ()*, i8* }.
+func (m *Module) appendStructor(name string, priority int, f *Function, data constant.Constant) error {
+	// Validate function signature and associated data.
+	if !types.Equal(f.Sig.RetType, types.Void) || len(f.Sig.Params) != 0 || f.Sig.Variadic {
+		return errors.Errorf("invalid signature of function %s in %s; expected void (), got %v", f.Ident(), enc.Global(name), f.Sig)
+	}
+	if data == nil {
+		data = constant.NewNull(types.I8Ptr)
+	}
+	if !types.IsPointer(data.Type()) {
+		return errors.Errorf("invalid associated data of function %s in %s; expected pointer type, got %v", f.Ident(), enc.Global(name), data.Type())
+	}
+	if !data.Type().Equal(types.I8Ptr) {
+		data = constant.NewBitCast(data, types.I8Ptr)
+	}
+	elem := constant.NewStruct(constant.NewInt(types.I32, int64(priority)), f, data)
+	// Create global array if not present.
+	g, ok := m.Global(name)
+	if !ok {
+		g = m.NewGlobalDef(name, constant.NewArray(elem))
+		g.Linkage = enum.LinkageAppending
+		return nil
+	}
+	// Append to global array.
+	t, ok := g.ContentType.(*types.ArrayType)
+	if !ok || !t.ElemType.Equal(elem.Type()) {
+		return errors.Errorf("invalid content type of global variable %s; expected array of %v, got %v", g.Ident(), elem.Type(), g.ContentType)
+	}
+	var elems []constant.Constant
+	switch init := g.Init.(type) {
+	case *constant.Array:
+		elems = append(elems, init.Elems...)
+	case *constant.ZeroInitializer:
+		if t.Len != 0 {
+			return errors.Errorf("support for zero initialized global variable %s not yet implemented", g.Ident())
+		}
+	default:
+		return errors.Errorf("invalid initializer of global variable %s; expected array constant, got %T", g.Ident(), g.Init)
+	}
+	elems = append(elems, elem)
+	contentType := types.NewArray(uint64(len(elems)), t.ElemType)
+	g.Init = &constant.Array{Typ: contentType, Elems: elems}
+	g.ContentType = contentType
+	typ := types.NewPointer(contentType)
+	typ.AddrSpace = g.Type().(*types.PointerType).AddrSpace
+	g.Typ = typ
+	return nil
+}
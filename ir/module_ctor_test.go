@@ -0,0 +1,50 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestAddGlobalCtor(t *testing.T) {
+	m := NewModule()
+	g := m.NewGlobalDef("x", constant.NewInt(types.I32, 0))
+	init1 := m.NewFunc("init1", types.Void)
+	init1.NewBlock("").NewRet(nil)
+	init2 := m.NewFunc("init2", types.Void)
+	init2.NewBlock("").NewRet(nil)
+	fini := m.NewFunc("fini", types.Void)
+	fini.NewBlock("").NewRet(nil)
+	if err := m.AddGlobalCtor(65535, init1, nil); err != nil {
+		t.Fatalf("unable to add global constructor; %+v", err)
+	}
+	if err := m.AddGlobalCtor(101, init2, g); err != nil {
+		t.Fatalf("unable to add global constructor; %+v", err)
+	}
+	if err := m.AddGlobalDtor(65535, fini, nil); err != nil {
+		t.Fatalf("unable to add global destructor; %+v", err)
+	}
+	// Invalid signature.
+	f := m.NewFunc("f", types.I32)
+	if err := m.AddGlobalCtor(65535, f, nil); err == nil {
+		t.Errorf("expected error for global constructor with invalid signature")
+	}
+	golden := []struct {
+		name string
+		want string
+	}{
+		{name: "llvm.global_ctors", want: "@llvm.global_ctors = appending global [2 x { i32, void ()*, i8* }] [{ i32, void ()*, i8* } { i32 65535, void ()* @init1, i8* null }, { i32, void ()*, i8* } { i32 101, void ()* @init2, i8* bitcast (i32* @x to i8*) }]"},
+		{name: "llvm.global_dtors", want: "@llvm.global_dtors = appending global [1 x { i32, void ()*, i8* }] [{ i32, void ()*, i8* } { i32 65535, void ()* @fini, i8* null }]"},
+	}
+	for _, g := range golden {
+		global, ok := m.Global(g.name)
+		if !ok {
+			t.Errorf("unable to locate global variable %q", g.name)
+			continue
+		}
+		if got := global.Def(); g.want != got {
+			t.Errorf("global variable mismatch; expected `%v`, got `%v`", g.want, got)
+		}
+	}
+}
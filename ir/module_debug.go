@@ -0,0 +1,78 @@
+package ir
+
+import (
+	"strings"
+
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+// === [ Struct debug information ] ============================================
+
+// StructDebugType returns the composite debug type of the given named struct
+// type, and a boolean indicating whether debug information was present.
+//
+// The struct type is correlated with the struct, class or union debug type of
+// the same name, following the naming convention of Clang; i.e. the struct type
+// %struct.foo, %class.ns::foo or %union.foo corresponds to the composite debug
+// type with the name foo. Definitions are preferred over forward declarations.
+// Use metadata.StructFieldNames to retrieve the names of the struct fields.
+func (m *Module) StructDebugType(t *types.StructType) (*metadata.DICompositeType, bool) {
+	name := structDebugName(t.Name())
+	if len(name) == 0 {
+		return nil, false
+	}
+	var decl *metadata.DICompositeType
+	for _, md := range m.MetadataDefs {
+		composite, ok := md.Node.(*metadata.DICompositeType)
+		if !ok || composite.Name != name {
+			continue
+		}
+		switch composite.Tag {
+		case enum.DwarfTagStructureType, enum.DwarfTagClassType, enum.DwarfTagUnionType:
+			if composite.Flags&enum.DIFlagFwdDecl == 0 {
+				return composite, true
+			}
+			if decl == nil {
+				decl = composite
+			}
+		}
+	}
+	return decl, decl != nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// structDebugName returns the name of the composite debug type corresponding to
+// the given struct type name; e.g. foo for struct.foo, class.ns::foo and
+// struct.foo.12. An empty string is returned for unnamed struct types.
+func structDebugName(typeName string) string {
+	pos := strings.Index(typeName, ".")
+	if pos == -1 {
+		return ""
+	}
+	name := typeName[pos+1:]
+	// Drop namespace qualifiers.
+	if pos := strings.LastIndex(name, "::"); pos != -1 {
+		name = name[pos+len("::"):]
+	}
+	// Drop numeric suffix used to make type names unique.
+	if pos := strings.LastIndex(name, "."); pos != -1 && isDigits(name[pos+1:]) {
+		name = name[:pos]
+	}
+	return name
+}
+
+// isDigits reports whether s is a non-empty string of decimal digits.
+func isDigits(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package ir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestStructDebugType(t *testing.T) {
+	// Module corresponding to the following C++ source code, compiled with
+	// debug information.
+	//
+	//    namespace ns { struct point { int x, y; static int count; }; }
+	//    struct line : ns::point { ns::point end; };
+	m := NewModule()
+	point := m.NewTypeDef("struct.ns::point", types.NewStruct(types.I32, types.I32)).(*types.StructType)
+	line := m.NewTypeDef("struct.line", types.NewStruct(point, point)).(*types.StructType)
+	other := m.NewTypeDef("struct.other", types.NewStruct(types.I8)).(*types.StructType)
+	member := func(tag enum.DwarfTag, name string, flags enum.DIFlag) *metadata.Def {
+		def := &metadata.Def{ID: int64(len(m.MetadataDefs)), Node: &metadata.DIDerivedType{Tag: tag, Name: name, Flags: flags}}
+		m.MetadataDefs = append(m.MetadataDefs, def)
+		return def
+	}
+	composite := func(name string, flags enum.DIFlag, members ...*metadata.Def) *metadata.DICompositeType {
+		node := &metadata.DICompositeType{Tag: enum.DwarfTagStructureType, Name: name, Flags: flags}
+		if len(members) > 0 {
+			elems := &metadata.Tuple{}
+			for _, member := range members {
+				elems.Fields = append(elems.Fields, member)
+			}
+			node.Elements = &metadata.Def{ID: int64(len(m.MetadataDefs)), Node: elems}
+			m.MetadataDefs = append(m.MetadataDefs, node.Elements.(*metadata.Def))
+		}
+		m.MetadataDefs = append(m.MetadataDefs, &metadata.Def{ID: int64(len(m.MetadataDefs)), Node: node})
+		return node
+	}
+	// Forward declaration before definition.
+	composite("point", enum.DIFlagFwdDecl)
+	pointDef := composite("point", 0, member(enum.DwarfTagMember, "x", 0), member(enum.DwarfTagMember, "y", 0), member(enum.DwarfTagMember, "count", enum.DIFlagStaticMember))
+	lineDef := composite("line", 0, member(enum.DwarfTagInheritance, "", 0), member(enum.DwarfTagMember, "end", 0))
+	golden := []struct {
+		typ  *types.StructType
+		want *metadata.DICompositeType
+		// Struct field names.
+		names string
+	}{
+		{typ: point, want: pointDef, names: "x y"},
+		{typ: line, want: lineDef, names: " end"},
+		// No debug information.
+		{typ: other},
+		{typ: types.NewStruct(types.I32)},
+	}
+	for _, g := range golden {
+		got, ok := m.StructDebugType(g.typ)
+		if got != g.want || ok != (g.want != nil) {
+			t.Errorf("composite debug type mismatch of %v; expected %v, got %v", g.typ, g.want, got)
+			continue
+		}
+		names := metadata.StructFieldNames(got)
+		if g.want == nil && names != nil {
+			t.Errorf("expected no struct field names of %v, got %q", g.typ, names)
+		}
+		if got := strings.Join(names, " "); g.names != got {
+			t.Errorf("struct field names mismatch of %v; expected %q, got %q", g.typ, g.names, got)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package ir
+
+import (
+	"strings"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+// --- [ External references ] -------------------------------------------------
+
+// ExternalReference is a reference to a function or global variable which is
+// declared but not defined in a module.
+type ExternalReference struct {
+	// Function or global variable name (without '@' prefix).
+	Name string
+	// Function signature (*types.FuncType) or global variable content type.
+	Type types.Type
+}
+
+// ExternalReferences returns the functions and global variables declared but
+// not defined in the module which are referenced by the module; i.e. the
+// symbols the module depends on. Global variables are listed before functions,
+// each in order of declaration. Declarations of intrinsic functions (e.g.
+// llvm.memcpy.p0i8.p0i8.i64) are not included.
+func (m *Module) ExternalReferences() []ExternalReference {
+	// Locate referenced declarations.
+	used := make(map[constant.Constant]bool)
+	rewriteConstantUses(m, func(c constant.Constant) (constant.Constant, bool) {
+		switch c := c.(type) {
+		case *Global:
+			if c.Init == nil {
+				used[c] = true
+			}
+		case *Function:
+			if len(c.Blocks) == 0 {
+				used[c] = true
+			}
+		}
+		return nil, false
+	})
+	var refs []ExternalReference
+	for _, g := range m.Globals {
+		if used[g] {
+			refs = append(refs, ExternalReference{Name: g.Name(), Type: g.ContentType})
+		}
+	}
+	for _, f := range m.Funcs {
+		if used[f] && !strings.HasPrefix(f.Name(), "llvm.") {
+			refs = append(refs, ExternalReference{Name: f.Name(), Type: f.Sig})
+		}
+	}
+	return refs
+}
@@ -0,0 +1,34 @@
+package ir
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestExternalReferences(t *testing.T) {
+	m := NewModule()
+	errno := m.NewGlobalDecl("errno", types.I32)
+	m.NewGlobalDecl("unused_global", types.I32)
+	puts := m.NewFunc("puts", types.I32, NewParam("s", types.I8Ptr))
+	m.NewFunc("unused", types.Void)
+	trap := m.NewFunc("llvm.trap", types.Void)
+	msg := m.NewGlobalDef("msg", constant.NewCharArrayFromString("hello\x00"))
+	f := m.NewFunc("main", types.I32)
+	entry := f.NewBlock("")
+	zero := constant.NewInt(types.I64, 0)
+	entry.NewCall(puts, constant.NewGetElementPtr(msg, zero, zero))
+	entry.NewCall(trap)
+	entry.NewRet(entry.NewLoad(errno))
+	want := "errno: i32, puts: i32 (i8*)"
+	var refs []string
+	for _, ref := range m.ExternalReferences() {
+		refs = append(refs, fmt.Sprintf("%s: %s", ref.Name, ref.Type))
+	}
+	if got := strings.Join(refs, ", "); want != got {
+		t.Errorf("external references mismatch; expected %q, got %q", want, got)
+	}
+}
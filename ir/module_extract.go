@@ -0,0 +1,495 @@
+package ir
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
+)
+
+// === [ Module extraction ] ===================================================
+
+// Extract returns a new module containing the given function definitions of m,
+// and the global variables, type definitions, comdats, attribute groups and
+// metadata transitively referenced by them. Functions, aliases and IFuncs
+// referenced by but not among the given functions are turned into declarations.
+//
+// The extracted module shares the given functions, and the referenced global
+// variables and metadata definitions, with m. Operands referring to functions
+// turned into declarations continue to refer to the function definitions of m;
+// the extracted module is thus intended to be written out (e.g. as LLVM IR
+// assembly) rather than modified.
+//
+// The module flags (i.e. the !llvm.module.flags named metadata) of m are
+// retained. All metadata definitions and named metadata definitions of m are
+// retained if the referenced metadata contains specialized metadata nodes (e.g.
+// debug information), as references between specialized metadata nodes are not
+// tracked.
+func (m *Module) Extract(funcs []*Function) (*Module, error) {
+	e := newExtractor(m)
+	for _, f := range funcs {
+		if !containsFunc(m.Funcs, f) {
+			return nil, errors.Errorf("unable to extract function %q; not present in module", f.Ident())
+		}
+		if err := f.Materialize(); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if len(f.Blocks) == 0 {
+			return nil, errors.Errorf("unable to extract function %q; not a function definition", f.Ident())
+		}
+		e.funcs[f] = true
+	}
+	for _, f := range funcs {
+		e.addFunc(f)
+	}
+	if named := findNamedMetadataDef(m, moduleFlagsName); named != nil {
+		for _, node := range named.Nodes {
+			e.addMetadata(node)
+		}
+	}
+	if e.allMetadata {
+		for _, md := range m.MetadataDefs {
+			e.addMetadata(md)
+		}
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.module(), nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// extractor tracks the top-level entities of a module referenced by extracted
+// functions.
+type extractor struct {
+	// Source module.
+	m *Module
+	// Extracted function definitions.
+	funcs map[*Function]bool
+	// Referenced functions, aliases and IFuncs to be turned into declarations.
+	decls map[constant.Constant]bool
+	// Referenced global variables.
+	globals map[*Global]bool
+	// Referenced comdats.
+	comdats map[*ComdatDef]bool
+	// Referenced attribute groups.
+	attrGroups map[*AttrGroupDef]bool
+	// Referenced metadata definitions.
+	mds map[*metadata.Def]bool
+	// Retain all metadata of the source module; set if specialized metadata
+	// nodes are referenced.
+	allMetadata bool
+	// Referenced types.
+	types *typeCollector
+	// First error encountered; or nil if none.
+	err error
+}
+
+// newExtractor returns a new extractor of functions from the given module.
+func newExtractor(m *Module) *extractor {
+	return &extractor{
+		m:          m,
+		funcs:      make(map[*Function]bool),
+		decls:      make(map[constant.Constant]bool),
+		globals:    make(map[*Global]bool),
+		comdats:    make(map[*ComdatDef]bool),
+		attrGroups: make(map[*AttrGroupDef]bool),
+		mds:        make(map[*metadata.Def]bool),
+		types:      &typeCollector{seen: make(map[string]bool)},
+	}
+}
+
+// module returns the extracted module, holding the referenced entities of the
+// source module in their original order.
+func (e *extractor) module() *Module {
+	m := &Module{
+		SourceFilename: e.m.SourceFilename,
+		DataLayout:     e.m.DataLayout,
+		TargetTriple:   e.m.TargetTriple,
+		ModuleAsms:     e.m.ModuleAsms,
+	}
+	for _, t := range e.m.TypeDefs {
+		if e.types.seen[t.String()] {
+			m.TypeDefs = append(m.TypeDefs, t)
+		}
+	}
+	for _, def := range e.m.ComdatDefs {
+		if e.comdats[def] {
+			m.ComdatDefs = append(m.ComdatDefs, def)
+		}
+	}
+	for _, g := range e.m.Globals {
+		if e.globals[g] {
+			m.Globals = append(m.Globals, g)
+		}
+	}
+	for _, f := range e.m.Funcs {
+		switch {
+		case e.funcs[f]:
+			m.Funcs = append(m.Funcs, f)
+		case e.decls[f]:
+			m.Funcs = append(m.Funcs, funcDecl(f))
+		}
+	}
+	// Aliases and IFuncs are turned into declarations of their content type.
+	for _, alias := range e.m.Aliases {
+		if e.decls[alias] {
+			addDecl(m, alias.GlobalIdent, alias.Typ)
+		}
+	}
+	for _, ifunc := range e.m.IFuncs {
+		if e.decls[ifunc] {
+			addDecl(m, ifunc.GlobalIdent, ifunc.Typ)
+		}
+	}
+	for _, def := range e.m.AttrGroupDefs {
+		if e.attrGroups[def] {
+			m.AttrGroupDefs = append(m.AttrGroupDefs, def)
+		}
+	}
+	for _, named := range e.m.NamedMetadataDefs {
+		if e.allMetadata || named.Name == moduleFlagsName {
+			m.NamedMetadataDefs = append(m.NamedMetadataDefs, named)
+		}
+	}
+	for _, md := range e.m.MetadataDefs {
+		if e.allMetadata || e.mds[md] {
+			m.MetadataDefs = append(m.MetadataDefs, md)
+		}
+	}
+	return m
+}
+
+// addDecl adds a function or global variable declaration of the given name and
+// pointer type to the module, based on the element type of typ.
+func addDecl(m *Module, ident GlobalIdent, typ *types.PointerType) {
+	if sig, ok := typ.ElemType.(*types.FuncType); ok {
+		f := &Function{GlobalIdent: ident, Sig: sig, Typ: typ}
+		for _, paramType := range sig.Params {
+			f.Params = append(f.Params, NewParam("", paramType))
+		}
+		m.Funcs = append(m.Funcs, f)
+		return
+	}
+	g := &Global{GlobalIdent: ident, ContentType: typ.ElemType, Typ: typ, Linkage: enum.LinkageExternal}
+	m.Globals = append(m.Globals, g)
+}
+
+// addFunc adds the entities referenced by the given extracted function
+// definition.
+func (e *extractor) addFunc(f *Function) {
+	e.addFuncHeader(f)
+	for _, c := range []constant.Constant{f.Prefix, f.Prologue, f.Personality} {
+		if c != nil {
+			e.addValue(c)
+		}
+	}
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			e.addInst(inst)
+		}
+		if block.Term != nil {
+			e.addInst(block.Term)
+		}
+	}
+}
+
+// addFuncHeader adds the entities referenced by the header of the given
+// function definition or declaration.
+func (e *extractor) addFuncHeader(f *Function) {
+	e.types.addType(f.Type())
+	e.addFuncAttrs(f.FuncAttrs)
+	if f.Comdat != nil {
+		e.comdats[f.Comdat] = true
+	}
+	for _, md := range f.Metadata {
+		e.addMetadata(md.Node)
+	}
+}
+
+// addInst adds the entities referenced by the given instruction or terminator.
+func (e *extractor) addInst(inst interface{}) {
+	if v, ok := inst.(value.Value); ok {
+		e.types.addType(v.Type())
+	}
+	for _, op := range operands(inst) {
+		if *op != nil {
+			e.addValue(*op)
+		}
+	}
+	switch inst := inst.(type) {
+	case *InstCall:
+		e.addFuncAttrs(inst.FuncAttrs)
+	case *TermInvoke:
+		e.addFuncAttrs(inst.FuncAttrs)
+	case *TermCallBr:
+		e.addFuncAttrs(inst.FuncAttrs)
+	}
+	for _, md := range instMetadata(inst) {
+		e.addMetadata(md.Node)
+	}
+}
+
+// addValue adds the entities referenced by the given value.
+func (e *extractor) addValue(v value.Value) {
+	e.types.addType(v.Type())
+	switch v := v.(type) {
+	case *Arg:
+		e.addValue(v.Value)
+	case *metadata.Value:
+		e.addMetadata(v.Value)
+	case *Global:
+		if e.globals[v] {
+			return
+		}
+		e.globals[v] = true
+		e.types.addType(v.ContentType)
+		e.addFuncAttrs(v.FuncAttrs)
+		if v.Comdat != nil {
+			e.comdats[v.Comdat] = true
+		}
+		for _, md := range v.Metadata {
+			e.addMetadata(md.Node)
+		}
+		if v.Init != nil {
+			e.addValue(v.Init)
+		}
+	case *Function:
+		if e.funcs[v] || e.decls[v] {
+			return
+		}
+		e.decls[v] = true
+		if len(v.Blocks) == 0 && v.Lazy == nil {
+			// Function declarations are retained as is.
+			e.addFuncHeader(v)
+		} else {
+			e.types.addType(v.Type())
+			e.addFuncAttrs(v.FuncAttrs)
+		}
+	case *Alias:
+		e.decls[v] = true
+	case *IFunc:
+		e.decls[v] = true
+	case *constant.BlockAddress:
+		if f, ok := v.Func.(*Function); ok && !e.funcs[f] && e.err == nil {
+			e.err = errors.Errorf("unable to extract block address of function %q; function not extracted", f.Ident())
+		}
+		e.addValue(v.Func)
+	case constant.Constant:
+		for _, op := range constantOperands(v) {
+			if *op != nil {
+				e.addValue(*op)
+			}
+		}
+	}
+}
+
+// addFuncAttrs adds the attribute groups referenced by the given function
+// attributes.
+func (e *extractor) addFuncAttrs(attrs []FuncAttribute) {
+	for _, attr := range attrs {
+		if def, ok := attr.(*AttrGroupDef); ok {
+			e.attrGroups[def] = true
+		}
+	}
+}
+
+// addMetadata adds the metadata definitions and values referenced by the given
+// metadata.
+func (e *extractor) addMetadata(md metadata.Field) {
+	switch md := md.(type) {
+	case nil, *metadata.NullLit, *metadata.String, *metadata.DIExpression:
+		// Metadata without references.
+	case *metadata.Def:
+		if e.mds[md] {
+			return
+		}
+		e.mds[md] = true
+		e.addMetadata(md.Node)
+	case *metadata.Tuple:
+		for _, field := range md.Fields {
+			e.addMetadata(field)
+		}
+	case *metadata.Value:
+		e.addMetadata(md.Value)
+	case value.Value:
+		e.addValue(md)
+	default:
+		// Specialized metadata node.
+		e.allMetadata = true
+	}
+}
+
+// funcDecl returns a declaration of the given function; or the function itself
+// if a declaration.
+func funcDecl(f *Function) *Function {
+	if len(f.Blocks) == 0 && f.Lazy == nil {
+		return f
+	}
+	decl := &Function{
+		GlobalIdent: f.GlobalIdent,
+		Sig:         f.Sig,
+		Typ:         f.Typ,
+		CallingConv: f.CallingConv,
+		ReturnAttrs: f.ReturnAttrs,
+		FuncAttrs:   f.FuncAttrs,
+	}
+	for _, param := range f.Params {
+		decl.Params = append(decl.Params, &Param{Typ: param.Typ, Attrs: param.Attrs})
+	}
+	return decl
+}
+
+// containsFunc reports whether the given functions contain f.
+func containsFunc(funcs []*Function, f *Function) bool {
+	for _, fn := range funcs {
+		if fn == f {
+			return true
+		}
+	}
+	return false
+}
+
+// instMetadata returns the metadata attachments of the given instruction or
+// terminator.
+func instMetadata(inst interface{}) []*metadata.Attachment {
+	switch inst := inst.(type) {
+	// Instructions.
+	case *InstAdd:
+		return inst.Metadata
+	case *InstFAdd:
+		return inst.Metadata
+	case *InstSub:
+		return inst.Metadata
+	case *InstFSub:
+		return inst.Metadata
+	case *InstMul:
+		return inst.Metadata
+	case *InstFMul:
+		return inst.Metadata
+	case *InstUDiv:
+		return inst.Metadata
+	case *InstSDiv:
+		return inst.Metadata
+	case *InstFDiv:
+		return inst.Metadata
+	case *InstURem:
+		return inst.Metadata
+	case *InstSRem:
+		return inst.Metadata
+	case *InstFRem:
+		return inst.Metadata
+	case *InstShl:
+		return inst.Metadata
+	case *InstLShr:
+		return inst.Metadata
+	case *InstAShr:
+		return inst.Metadata
+	case *InstAnd:
+		return inst.Metadata
+	case *InstOr:
+		return inst.Metadata
+	case *InstXor:
+		return inst.Metadata
+	case *InstExtractElement:
+		return inst.Metadata
+	case *InstInsertElement:
+		return inst.Metadata
+	case *InstShuffleVector:
+		return inst.Metadata
+	case *InstExtractValue:
+		return inst.Metadata
+	case *InstInsertValue:
+		return inst.Metadata
+	case *InstAlloca:
+		return inst.Metadata
+	case *InstLoad:
+		return inst.Metadata
+	case *InstStore:
+		return inst.Metadata
+	case *InstFence:
+		return inst.Metadata
+	case *InstCmpXchg:
+		return inst.Metadata
+	case *InstAtomicRMW:
+		return inst.Metadata
+	case *InstGetElementPtr:
+		return inst.Metadata
+	case *InstTrunc:
+		return inst.Metadata
+	case *InstZExt:
+		return inst.Metadata
+	case *InstSExt:
+		return inst.Metadata
+	case *InstFPTrunc:
+		return inst.Metadata
+	case *InstFPExt:
+		return inst.Metadata
+	case *InstFPToUI:
+		return inst.Metadata
+	case *InstFPToSI:
+		return inst.Metadata
+	case *InstUIToFP:
+		return inst.Metadata
+	case *InstSIToFP:
+		return inst.Metadata
+	case *InstPtrToInt:
+		return inst.Metadata
+	case *InstIntToPtr:
+		return inst.Metadata
+	case *InstBitCast:
+		return inst.Metadata
+	case *InstAddrSpaceCast:
+		return inst.Metadata
+	case *InstICmp:
+		return inst.Metadata
+	case *InstFCmp:
+		return inst.Metadata
+	case *InstPhi:
+		return inst.Metadata
+	case *InstSelect:
+		return inst.Metadata
+	case *InstCall:
+		return inst.Metadata
+	case *InstVAArg:
+		return inst.Metadata
+	case *InstLandingPad:
+		return inst.Metadata
+	case *InstCatchPad:
+		return inst.Metadata
+	case *InstCleanupPad:
+		return inst.Metadata
+	// Terminators.
+	case *TermRet:
+		return inst.Metadata
+	case *TermBr:
+		return inst.Metadata
+	case *TermCondBr:
+		return inst.Metadata
+	case *TermSwitch:
+		return inst.Metadata
+	case *TermIndirectBr:
+		return inst.Metadata
+	case *TermInvoke:
+		return inst.Metadata
+	case *TermCallBr:
+		return inst.Metadata
+	case *TermResume:
+		return inst.Metadata
+	case *TermCatchSwitch:
+		return inst.Metadata
+	case *TermCatchRet:
+		return inst.Metadata
+	case *TermCleanupRet:
+		return inst.Metadata
+	case *TermUnreachable:
+		return inst.Metadata
+	default:
+		panic(fmt.Errorf("support for instruction %T not yet implemented", inst))
+	}
+}
@@ -0,0 +1,74 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestModuleExtract(t *testing.T) {
+	m := NewModule()
+	m.TargetTriple = "x86_64-unknown-linux-gnu"
+	pair := m.NewTypeDef("pair", types.NewStruct(types.I32, types.I32))
+	m.NewTypeDef("unused", types.NewStruct(types.I8))
+	// Function referenced by global variable initializer.
+	h := m.NewFunc("h", types.Void)
+	h.NewBlock("").NewRet(nil)
+	fp := m.NewGlobalDef("fp", h)
+	p := m.NewGlobalDef("p", constant.NewZeroInitializer(pair))
+	m.NewGlobalDef("unused", constant.NewInt(types.I32, 0))
+	// Function called by extracted function.
+	x := NewParam("x", types.I32)
+	g := m.NewFunc("g", types.I32, x)
+	g.NewBlock("").NewRet(x)
+	// Extracted function.
+	f := m.NewFunc("f", types.I32)
+	entry := f.NewBlock("")
+	entry.NewLoad(fp)
+	zero := constant.NewInt(types.I32, 0)
+	elem := entry.NewGetElementPtr(p, zero, zero)
+	v := entry.NewLoad(elem)
+	entry.NewRet(entry.NewCall(g, v))
+	got, err := m.Extract([]*Function{f})
+	if err != nil {
+		t.Fatalf("unable to extract function %q; %+v", f.Ident(), err)
+	}
+	const want = `target triple = "x86_64-unknown-linux-gnu"
+
+%pair = type { i32, i32 }
+
+@fp = global void ()* @h
+@p = global %pair zeroinitializer
+
+declare void @h()
+
+declare i32 @g(i32)
+
+define i32 @f() {
+; <label>:0
+	%1 = load void ()*, void ()** @fp
+	%2 = getelementptr %pair, %pair* @p, i32 0, i32 0
+	%3 = load i32, i32* %2
+	%4 = call i32 @g(i32 %3)
+	ret i32 %4
+}
+`
+	if got.String() != want {
+		t.Errorf("extracted module mismatch; expected %q, got %q", want, got.String())
+	}
+	// Source module is left unchanged.
+	if len(m.Funcs) != 3 || len(g.Blocks) != 1 {
+		t.Errorf("source module modified by extraction")
+	}
+	// Functions not present in module.
+	if _, err := m.Extract([]*Function{NewFunc("x", types.Void)}); err == nil {
+		t.Errorf("expected error for extraction of function not present in module, got nil")
+	}
+	// Function declarations.
+	m2 := NewModule()
+	decl := m2.NewFunc("decl", types.Void)
+	if _, err := m2.Extract([]*Function{decl}); err == nil {
+		t.Errorf("expected error for extraction of function declaration, got nil")
+	}
+}
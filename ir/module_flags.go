@@ -0,0 +1,353 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+	"github.com/pkg/errors"
+)
+
+// === [ Module flags ] ========================================================
+
+// Behaviors of module flags when merging modules.
+//
+// ref: https://llvm.org/docs/LangRef.html#module-flags-metadata
+const (
+	// Emit an error if the values of the flags differ.
+	moduleFlagError = 1
+	// Keep the value of the destination module if the values of the flags
+	// differ.
+	moduleFlagWarning = 2
+	// Require a flag with the given name and value to be present in the merged
+	// module.
+	moduleFlagRequire = 3
+	// Use the value of the flag with override behavior.
+	moduleFlagOverride = 4
+	// Append the metadata node values of the flags.
+	moduleFlagAppend = 5
+	// Append the metadata node values of the flags, omitting duplicates.
+	moduleFlagAppendUnique = 6
+	// Use the larger integer value of the flags.
+	moduleFlagMax = 7
+	// Use the smaller integer value of the flags.
+	moduleFlagMin = 8
+)
+
+// moduleFlagsName is the name of the named metadata definition holding module
+// flags.
+const moduleFlagsName = "llvm.module.flags"
+
+// moduleFlag is a module flag; a metadata tuple of the form
+// !{i32 behavior, !"name", value}.
+type moduleFlag struct {
+	// Metadata definition of the module flag.
+	def *metadata.Def
+	// Behavior of the module flag when merging modules.
+	behavior int64
+	// Module flag name.
+	name string
+	// Module flag value.
+	value metadata.Field
+}
+
+// MergeModuleFlags merges the module flags (i.e. the !llvm.module.flags named
+// metadata) of src into dst, combining flags present in both modules according
+// to their merge behavior.
+//
+// Flags with Max and Min behavior are combined into the larger and smaller
+// value respectively; flags with Error behavior must have equal values; flags
+// with Override behavior replace flags without; the metadata node values of
+// flags with Append and AppendUnique behavior are concatenated. Requirements
+// of flags with Require behavior are verified for the merged module.
+//
+// Metadata definitions of src referenced by merged module flags are added to
+// dst with new metadata IDs.
+func MergeModuleFlags(dst, src *Module) error {
+	srcNamed := findNamedMetadataDef(src, moduleFlagsName)
+	if srcNamed == nil {
+		return nil
+	}
+	dstNamed := findNamedMetadataDef(dst, moduleFlagsName)
+	if dstNamed == nil {
+		dstNamed = &metadata.NamedDef{Name: moduleFlagsName}
+		dst.NamedMetadataDefs = append(dst.NamedMetadataDefs, dstNamed)
+	}
+	var dstFlags []*moduleFlag
+	for _, node := range dstNamed.Nodes {
+		flag, err := parseModuleFlag(node)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		dstFlags = append(dstFlags, flag)
+	}
+	imp := newMetadataImporter(dst)
+	for _, node := range srcNamed.Nodes {
+		srcFlag, err := parseModuleFlag(node)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		dstFlag := findModuleFlag(dstFlags, srcFlag)
+		if dstFlag == nil {
+			// Add flag not present in destination module.
+			def := imp.importDef(srcFlag.def)
+			dstNamed.Nodes = append(dstNamed.Nodes, def)
+			flag, err := parseModuleFlag(def)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			dstFlags = append(dstFlags, flag)
+			continue
+		}
+		if srcFlag.behavior == moduleFlagRequire {
+			// Identical requirement already present.
+			continue
+		}
+		value, err := mergeModuleFlag(dstFlag, srcFlag, imp)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if value != nil {
+			dstFlag.value = value
+			dstFlag.def.Node = &metadata.Tuple{
+				Fields: []metadata.Field{constant.NewInt(types.I32, dstFlag.behavior), &metadata.String{Value: dstFlag.name}, value},
+			}
+		}
+	}
+	// Verify requirements of merged module flags.
+	for _, flag := range dstFlags {
+		if flag.behavior != moduleFlagRequire {
+			continue
+		}
+		req, ok := metadataTuple(flag.value)
+		if !ok || len(req.Fields) != 2 {
+			return errors.Errorf("invalid requirement of module flag %q; expected metadata tuple of two fields, got %v", flag.name, flag.value)
+		}
+		name, ok := req.Fields[0].(*metadata.String)
+		if !ok {
+			return errors.Errorf("invalid requirement of module flag %q; expected metadata string as flag name, got %T", flag.name, req.Fields[0])
+		}
+		found := false
+		for _, other := range dstFlags {
+			if other.behavior != moduleFlagRequire && other.name == name.Value {
+				if other.value.String() != req.Fields[1].String() {
+					return errors.Errorf("module flag %q requires module flag %q to have value %v; got %v", flag.name, name.Value, req.Fields[1], other.value)
+				}
+				found = true
+			}
+		}
+		if !found {
+			return errors.Errorf("module flag %q requires module flag %q to be present", flag.name, name.Value)
+		}
+	}
+	return nil
+}
+
+// mergeModuleFlag returns the value of the given destination module flag
+// merged with the given source module flag; or nil if the value of the
+// destination module flag is left unchanged.
+func mergeModuleFlag(dstFlag, srcFlag *moduleFlag, imp *metadataImporter) (metadata.Field, error) {
+	name := dstFlag.name
+	// Override behavior takes precedence over other behaviors.
+	if dstFlag.behavior == moduleFlagOverride || srcFlag.behavior == moduleFlagOverride {
+		switch {
+		case dstFlag.behavior != moduleFlagOverride:
+			dstFlag.behavior = moduleFlagOverride
+			return imp.importField(srcFlag.value), nil
+		case srcFlag.behavior != moduleFlagOverride:
+			return nil, nil
+		case dstFlag.value.String() != srcFlag.value.String():
+			return nil, errors.Errorf("conflicting values of module flag %q with override behavior; %v and %v", name, dstFlag.value, srcFlag.value)
+		}
+		return nil, nil
+	}
+	if dstFlag.behavior != srcFlag.behavior {
+		return nil, errors.Errorf("conflicting behaviors of module flag %q; %d and %d", name, dstFlag.behavior, srcFlag.behavior)
+	}
+	switch dstFlag.behavior {
+	case moduleFlagError:
+		if dstFlag.value.String() != srcFlag.value.String() {
+			return nil, errors.Errorf("conflicting values of module flag %q; %v and %v", name, dstFlag.value, srcFlag.value)
+		}
+		return nil, nil
+	case moduleFlagWarning:
+		// Keep value of destination module.
+		return nil, nil
+	case moduleFlagAppend, moduleFlagAppendUnique:
+		x, ok := metadataTuple(dstFlag.value)
+		if !ok {
+			return nil, errors.Errorf("invalid value of module flag %q with append behavior; expected metadata tuple, got %T", name, dstFlag.value)
+		}
+		y, ok := metadataTuple(srcFlag.value)
+		if !ok {
+			return nil, errors.Errorf("invalid value of module flag %q with append behavior; expected metadata tuple, got %T", name, srcFlag.value)
+		}
+		tuple := &metadata.Tuple{Fields: append([]metadata.Field(nil), x.Fields...)}
+		for _, field := range y.Fields {
+			if dstFlag.behavior == moduleFlagAppendUnique && containsField(tuple.Fields, field) {
+				continue
+			}
+			tuple.Fields = append(tuple.Fields, imp.importField(field))
+		}
+		return tuple, nil
+	case moduleFlagMax, moduleFlagMin:
+		x, ok := dstFlag.value.(*constant.Int)
+		if !ok {
+			return nil, errors.Errorf("invalid value of module flag %q with min or max behavior; expected integer constant, got %T", name, dstFlag.value)
+		}
+		y, ok := srcFlag.value.(*constant.Int)
+		if !ok {
+			return nil, errors.Errorf("invalid value of module flag %q with min or max behavior; expected integer constant, got %T", name, srcFlag.value)
+		}
+		cmp := y.X.Cmp(x.X)
+		if (dstFlag.behavior == moduleFlagMax && cmp > 0) || (dstFlag.behavior == moduleFlagMin && cmp < 0) {
+			return y, nil
+		}
+		return nil, nil
+	default:
+		return nil, errors.Errorf("support for behavior %d of module flag %q not yet implemented", dstFlag.behavior, name)
+	}
+}
+
+// parseModuleFlag parses the given module flag node.
+func parseModuleFlag(node metadata.Node) (*moduleFlag, error) {
+	def, ok := node.(*metadata.Def)
+	if !ok {
+		return nil, errors.Errorf("invalid module flag %v; expected metadata definition, got %T", node, node)
+	}
+	tuple, ok := def.Node.(*metadata.Tuple)
+	if !ok || len(tuple.Fields) != 3 {
+		return nil, errors.Errorf("invalid module flag %v; expected metadata tuple of three fields", def)
+	}
+	behavior, ok := tuple.Fields[0].(*constant.Int)
+	if !ok {
+		return nil, errors.Errorf("invalid behavior of module flag %v; expected integer constant, got %T", def, tuple.Fields[0])
+	}
+	name, ok := tuple.Fields[1].(*metadata.String)
+	if !ok {
+		return nil, errors.Errorf("invalid name of module flag %v; expected metadata string, got %T", def, tuple.Fields[1])
+	}
+	flag := &moduleFlag{
+		def:      def,
+		behavior: behavior.X.Int64(),
+		name:     name.Value,
+		value:    tuple.Fields[2],
+	}
+	return flag, nil
+}
+
+// findModuleFlag returns the module flag of flags which corresponds to the
+// given module flag; or nil if not present. Module flags with Require behavior
+// correspond if their values are identical, and other module flags correspond
+// if they have the same name.
+func findModuleFlag(flags []*moduleFlag, flag *moduleFlag) *moduleFlag {
+	for _, f := range flags {
+		if f.name != flag.name || (f.behavior == moduleFlagRequire) != (flag.behavior == moduleFlagRequire) {
+			continue
+		}
+		if flag.behavior == moduleFlagRequire && f.value.String() != flag.value.String() {
+			continue
+		}
+		return f
+	}
+	return nil
+}
+
+// findNamedMetadataDef returns the named metadata definition of the given
+// name in m; or nil if not present.
+func findNamedMetadataDef(m *Module, name string) *metadata.NamedDef {
+	for _, md := range m.NamedMetadataDefs {
+		if md.Name == name {
+			return md
+		}
+	}
+	return nil
+}
+
+// metadataTuple returns the metadata tuple of the given metadata field, which
+// is either a metadata tuple or a metadata definition of a metadata tuple.
+func metadataTuple(field metadata.Field) (*metadata.Tuple, bool) {
+	switch field := field.(type) {
+	case *metadata.Tuple:
+		return field, true
+	case *metadata.Def:
+		tuple, ok := field.Node.(*metadata.Tuple)
+		return tuple, ok
+	default:
+		return nil, false
+	}
+}
+
+// containsField reports whether the given metadata fields contain a field with
+// the same string representation as field.
+func containsField(fields []metadata.Field, field metadata.Field) bool {
+	for _, f := range fields {
+		if f.String() == field.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// ~~~ [ Metadata importer ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// metadataImporter copies metadata definitions from one module to another,
+// assigning new metadata IDs.
+type metadataImporter struct {
+	// Destination module.
+	dst *Module
+	// Next unused metadata ID of the destination module.
+	nextID int64
+	// Map from source metadata definition to copy in destination module.
+	defs map[*metadata.Def]*metadata.Def
+}
+
+// newMetadataImporter returns a new metadata importer into the given
+// destination module.
+func newMetadataImporter(dst *Module) *metadataImporter {
+	imp := &metadataImporter{
+		dst:  dst,
+		defs: make(map[*metadata.Def]*metadata.Def),
+	}
+	for _, md := range dst.MetadataDefs {
+		if md.ID >= imp.nextID {
+			imp.nextID = md.ID + 1
+		}
+	}
+	return imp
+}
+
+// importDef returns a copy of the given metadata definition in the destination
+// module.
+func (imp *metadataImporter) importDef(def *metadata.Def) *metadata.Def {
+	if d, ok := imp.defs[def]; ok {
+		return d
+	}
+	d := &metadata.Def{ID: imp.nextID, Distinct: def.Distinct}
+	imp.nextID++
+	imp.defs[def] = d
+	imp.dst.MetadataDefs = append(imp.dst.MetadataDefs, d)
+	if tuple, ok := def.Node.(*metadata.Tuple); ok {
+		d.Node = imp.importField(tuple)
+	} else {
+		d.Node = def.Node
+	}
+	return d
+}
+
+// importField returns a copy of the given metadata field in the destination
+// module. Metadata definitions and tuples are copied recursively; other
+// metadata is shared.
+func (imp *metadataImporter) importField(field metadata.Field) metadata.Field {
+	switch field := field.(type) {
+	case *metadata.Def:
+		return imp.importDef(field)
+	case *metadata.Tuple:
+		tuple := &metadata.Tuple{Fields: make([]metadata.Field, len(field.Fields))}
+		for i, f := range field.Fields {
+			tuple.Fields[i] = imp.importField(f)
+		}
+		return tuple
+	default:
+		return field
+	}
+}
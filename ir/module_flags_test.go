@@ -0,0 +1,86 @@
+package ir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestMergeModuleFlags(t *testing.T) {
+	golden := []struct {
+		dst, src *Module
+		want     string
+		err      string
+	}{
+		// Max behavior.
+		{
+			dst:  newModuleWithFlags(newModuleFlag(7, "PIC Level", constant.NewInt(types.I32, 1))),
+			src:  newModuleWithFlags(newModuleFlag(7, "PIC Level", constant.NewInt(types.I32, 2))),
+			want: "!llvm.module.flags = !{!0}\n\n!0 = !{i32 7, !\"PIC Level\", i32 2}",
+		},
+		// Min behavior.
+		{
+			dst:  newModuleWithFlags(newModuleFlag(8, "foo", constant.NewInt(types.I32, 4))),
+			src:  newModuleWithFlags(newModuleFlag(8, "foo", constant.NewInt(types.I32, 2))),
+			want: "!llvm.module.flags = !{!0}\n\n!0 = !{i32 8, !\"foo\", i32 2}",
+		},
+		// Override behavior and flag only present in source module.
+		{
+			dst:  newModuleWithFlags(newModuleFlag(1, "foo", constant.NewInt(types.I32, 1))),
+			src:  newModuleWithFlags(newModuleFlag(4, "foo", constant.NewInt(types.I32, 2)), newModuleFlag(1, "bar", constant.NewInt(types.I32, 3))),
+			want: "!llvm.module.flags = !{!0, !1}\n\n!0 = !{i32 4, !\"foo\", i32 2}\n!1 = !{i32 1, !\"bar\", i32 3}",
+		},
+		// Error behavior with conflicting values.
+		{
+			dst: newModuleWithFlags(newModuleFlag(1, "foo", constant.NewInt(types.I32, 1))),
+			src: newModuleWithFlags(newModuleFlag(1, "foo", constant.NewInt(types.I32, 2))),
+			err: `conflicting values of module flag "foo"; i32 1 and i32 2`,
+		},
+		// Unsatisfied requirement.
+		{
+			dst: newModuleWithFlags(newModuleFlag(1, "foo", constant.NewInt(types.I32, 1))),
+			src: newModuleWithFlags(newModuleFlag(3, "bar", &metadata.Tuple{Fields: []metadata.Field{&metadata.String{Value: "foo"}, constant.NewInt(types.I32, 2)}})),
+			err: `module flag "bar" requires module flag "foo" to have value i32 2; got i32 1`,
+		},
+	}
+	for _, g := range golden {
+		var gotErr string
+		if err := MergeModuleFlags(g.dst, g.src); err != nil {
+			gotErr = err.Error()
+		}
+		if g.err != gotErr {
+			t.Errorf("error mismatch; expected `%v`, got `%v`", g.err, gotErr)
+			continue
+		}
+		if len(g.err) > 0 {
+			continue
+		}
+		if got := strings.TrimSpace(g.dst.String()); g.want != got {
+			t.Errorf("module mismatch; expected `%v`, got `%v`", g.want, got)
+		}
+	}
+}
+
+// newModuleWithFlags returns a new module with the given module flags.
+func newModuleWithFlags(flags ...*metadata.Tuple) *Module {
+	m := NewModule()
+	named := &metadata.NamedDef{Name: "llvm.module.flags"}
+	for i, flag := range flags {
+		def := &metadata.Def{ID: int64(i), Node: flag}
+		m.MetadataDefs = append(m.MetadataDefs, def)
+		named.Nodes = append(named.Nodes, def)
+	}
+	m.NamedMetadataDefs = append(m.NamedMetadataDefs, named)
+	return m
+}
+
+// newModuleFlag returns a new module flag based on the given behavior, name
+// and value.
+func newModuleFlag(behavior int64, name string, value metadata.Field) *metadata.Tuple {
+	return &metadata.Tuple{
+		Fields: []metadata.Field{constant.NewInt(types.I32, behavior), &metadata.String{Value: name}, value},
+	}
+}
@@ -1,6 +1,11 @@
 package ir
 
-import "github.com/llir/llvm/ir/types"
+import (
+	"strconv"
+
+	"github.com/llir/llvm/ir/types"
+	"github.com/pkg/errors"
+)
 
 // --- [ Functions ] -----------------------------------------------------------
 
@@ -11,3 +16,117 @@ func (m *Module) NewFunc(name string, retType types.Type, params ...*Param) *Fun
 	m.Funcs = append(m.Funcs, f)
 	return f
 }
+
+// RenameFunction renames the given function of the module. An error is
+// returned if the function is not part of the module, or if the new name is
+// already used by a global variable, function, alias or IFunc of the module.
+//
+// Call sites and other references to the function refer to the function value
+// itself, and thus use the new name once renamed.
+func (m *Module) RenameFunction(f *Function, newName string) error {
+	pos := -1
+	for i, g := range m.Funcs {
+		if g == f {
+			pos = i
+			break
+		}
+	}
+	if pos == -1 {
+		return errors.Errorf("unable to locate function %s in module", f.Ident())
+	}
+	if newName == f.GlobalName {
+		return nil
+	}
+	if kind := m.nameUsedBy(newName); len(kind) > 0 {
+		return errors.Errorf("unable to rename function %s; name %q already used by %s", f.Ident(), newName, kind)
+	}
+	oldName := indexName(f.GlobalIdent)
+	f.SetName(newName)
+	if m.funcIndex != nil {
+		if i, ok := m.funcIndex[oldName]; ok && i == pos {
+			delete(m.funcIndex, oldName)
+		}
+		m.funcIndex[newName] = pos
+	}
+	return nil
+}
+
+// Func returns the function of the module with the given name (without '@'
+// prefix), and a boolean indicating whether the function was present.
+//
+// Functions are located using an index which is built on first use. Index
+// entries are validated against the functions of the module, and the index is
+// rebuilt on a miss; thus functions may be added, removed, replaced or renamed
+// directly.
+func (m *Module) Func(name string) (*Function, bool) {
+	if f, ok := m.lookupFunc(name); ok {
+		return f, true
+	}
+	// Rebuild the index before reporting absence, as functions may have been
+	// added, removed, replaced or renamed since the index was built.
+	m.indexFuncs()
+	return m.lookupFunc(name)
+}
+
+// lookupFunc returns the function with the given name as located by the
+// index, and a boolean indicating whether the index entry was present and up
+// to date.
+func (m *Module) lookupFunc(name string) (*Function, bool) {
+	i, ok := m.funcIndex[name]
+	if !ok || i >= len(m.Funcs) {
+		return nil, false
+	}
+	f := m.Funcs[i]
+	if indexName(f.GlobalIdent) != name {
+		return nil, false
+	}
+	return f, true
+}
+
+// indexFuncs builds the index of functions by name. The first function with a
+// given name is indexed, if several functions share the same name.
+func (m *Module) indexFuncs() {
+	m.funcIndex = make(map[string]int, len(m.Funcs))
+	for i, f := range m.Funcs {
+		name := indexName(f.GlobalIdent)
+		if _, ok := m.funcIndex[name]; !ok {
+			m.funcIndex[name] = i
+		}
+	}
+}
+
+// nameUsedBy returns the kind of top-level entity of the module (e.g. "global
+// variable" or "function") which uses the given name; or the empty string if
+// the name is not used.
+func (m *Module) nameUsedBy(name string) string {
+	for _, g := range m.Globals {
+		if g.GlobalName == name {
+			return "global variable"
+		}
+	}
+	for _, f := range m.Funcs {
+		if f.GlobalName == name {
+			return "function"
+		}
+	}
+	for _, alias := range m.Aliases {
+		if alias.GlobalName == name {
+			return "alias"
+		}
+	}
+	for _, ifunc := range m.IFuncs {
+		if ifunc.GlobalName == name {
+			return "IFunc"
+		}
+	}
+	return ""
+}
+
+// indexName returns the name of the given global identifier used as key in the
+// indices of the module.
+func indexName(ident GlobalIdent) string {
+	if ident.IsUnnamed() {
+		return strconv.FormatInt(ident.GlobalID, 10)
+	}
+	return ident.GlobalName
+}
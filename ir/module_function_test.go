@@ -0,0 +1,166 @@
+package ir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestRenameFunction(t *testing.T) {
+	m := NewModule()
+	callee := m.NewFunc("foo", types.I32)
+	entry := callee.NewBlock("")
+	entry.NewRet(constant.NewInt(types.I32, 42))
+	caller := m.NewFunc("caller", types.I32)
+	entry = caller.NewBlock("")
+	x := entry.NewCall(callee)
+	x.SetName("x")
+	y := entry.NewCall(callee)
+	y.SetName("y")
+	entry.NewRet(entry.NewAdd(x, y))
+	if err := m.RenameFunction(callee, "bar"); err != nil {
+		t.Fatalf("unable to rename function; %v", err)
+	}
+	want := `define i32 @caller() {
+; <label>:0
+	%x = call i32 @bar()
+	%y = call i32 @bar()
+	%1 = add i32 %x, %y
+	ret i32 %1
+}`
+	if got := caller.Def(); want != got {
+		t.Errorf("function mismatch; expected `%v`, got `%v`", want, got)
+	}
+	// Name collision.
+	if err := m.RenameFunction(callee, "caller"); err == nil {
+		t.Errorf("expected error for name collision, got nil")
+	}
+	if got := callee.Name(); got != "bar" {
+		t.Errorf("function name mismatch; expected %q, got %q", "bar", got)
+	}
+}
+
+func TestRenameFunctionReferences(t *testing.T) {
+	m := NewModule()
+	n := NewParam("n", types.I32)
+	f := m.NewFunc("fact", types.I32, n)
+	entry := f.NewBlock("entry")
+	rec := f.NewBlock("rec")
+	done := f.NewBlock("done")
+	entry.NewCondBr(entry.NewICmp(enum.IPredEQ, n, constant.NewInt(types.I32, 0)), done, rec)
+	x := rec.NewCall(f, rec.NewSub(n, constant.NewInt(types.I32, 1)))
+	rec.NewRet(rec.NewMul(n, x))
+	done.NewRet(constant.NewInt(types.I32, 1))
+	m.NewGlobalDef("addr", constant.NewBlockAddress(f, rec))
+	m.NewAlias("alias", f)
+	md := &metadata.Def{ID: 0, Node: &metadata.Tuple{Fields: []metadata.Field{f}}}
+	m.MetadataDefs = append(m.MetadataDefs, md)
+	if err := m.RenameFunction(f, "factorial"); err != nil {
+		t.Fatalf("unable to rename function; %v", err)
+	}
+	for _, want := range []string{
+		"define i32 @factorial(i32 %n) {",
+		"%2 = call i32 @factorial(i32 %1)",
+		"@addr = global i8* blockaddress(@factorial, %rec)",
+		"@alias = alias i32 (i32), i32 (i32)* @factorial",
+		"!0 = !{i32 (i32)* @factorial}",
+	} {
+		if got := m.String(); !strings.Contains(got, want) {
+			t.Errorf("module mismatch; expected %q in `%s`", want, got)
+		}
+	}
+	if strings.Contains(m.String(), "@fact(") {
+		t.Errorf("module mismatch; reference to old function name in `%s`", m.String())
+	}
+}
+
+func TestModuleIndex(t *testing.T) {
+	m := NewModule()
+	foo := m.NewFunc("foo", types.Void)
+	x := m.NewGlobalDef("x", constant.NewInt(types.I32, 1))
+	if f, ok := m.Func("foo"); !ok || f != foo {
+		t.Errorf("unable to locate function %q", "foo")
+	}
+	if g, ok := m.Global("x"); !ok || g != x {
+		t.Errorf("unable to locate global variable %q", "x")
+	}
+	if _, ok := m.Func("x"); ok {
+		t.Errorf("unexpected function %q", "x")
+	}
+	// Index invalidated on append.
+	bar := NewFunc("bar", types.Void)
+	m.Funcs = append(m.Funcs, bar)
+	if f, ok := m.Func("bar"); !ok || f != bar {
+		t.Errorf("unable to locate appended function %q", "bar")
+	}
+	y := NewGlobalDecl("y", types.I32)
+	m.Globals = append(m.Globals, y)
+	if g, ok := m.Global("y"); !ok || g != y {
+		t.Errorf("unable to locate appended global variable %q", "y")
+	}
+	// Index updated on rename.
+	if err := m.RenameFunction(foo, "baz"); err != nil {
+		t.Fatalf("unable to rename function; %v", err)
+	}
+	if _, ok := m.Func("foo"); ok {
+		t.Errorf("unexpected function %q after rename", "foo")
+	}
+	if f, ok := m.Func("baz"); !ok || f != foo {
+		t.Errorf("unable to locate renamed function %q", "baz")
+	}
+	if err := m.RenameGlobal(x, "bar"); err == nil {
+		t.Errorf("expected error for name collision, got nil")
+	}
+	if err := m.RenameGlobal(x, "z"); err != nil {
+		t.Fatalf("unable to rename global variable; %v", err)
+	}
+	if g, ok := m.Global("z"); !ok || g != x {
+		t.Errorf("unable to locate renamed global variable %q", "z")
+	}
+	// Stale index entry of function renamed without RenameFunction.
+	bar.SetName("qux")
+	if _, ok := m.Func("bar"); ok {
+		t.Errorf("unexpected function %q after rename", "bar")
+	}
+	if f, ok := m.Func("qux"); !ok || f != bar {
+		t.Errorf("unable to locate renamed function %q", "qux")
+	}
+	// Lookup of function renamed without RenameFunction after it was located.
+	bar.SetName("quux")
+	if f, ok := m.Func("quux"); !ok || f != bar {
+		t.Errorf("unable to locate renamed function %q", "quux")
+	}
+	// Function replaced in place.
+	other := NewFunc("quux", types.Void)
+	m.Funcs[1] = other
+	if f, ok := m.Func("quux"); !ok || f != other {
+		t.Errorf("unable to locate replacement function %q", "quux")
+	}
+	// Function removed and another appended.
+	m.Funcs = m.Funcs[:1]
+	if _, ok := m.Func("quux"); ok {
+		t.Errorf("unexpected function %q after removal", "quux")
+	}
+	last := NewFunc("last", types.Void)
+	m.Funcs = append(m.Funcs, last)
+	if f, ok := m.Func("last"); !ok || f != last {
+		t.Errorf("unable to locate appended function %q", "last")
+	}
+	// Global variable replaced in place, and removed and another appended.
+	w := NewGlobalDecl("y", types.I64)
+	m.Globals[1] = w
+	if g, ok := m.Global("y"); !ok || g != w {
+		t.Errorf("unable to locate replacement global variable %q", "y")
+	}
+	m.Globals = append(m.Globals[:1], NewGlobalDecl("v", types.I8))
+	if _, ok := m.Global("y"); ok {
+		t.Errorf("unexpected global variable %q after removal", "y")
+	}
+	if g, ok := m.Global("v"); !ok || g != m.Globals[1] {
+		t.Errorf("unable to locate appended global variable %q", "v")
+	}
+}
@@ -3,6 +3,7 @@ package ir
 import (
 	"github.com/llir/llvm/ir/constant"
 	"github.com/llir/llvm/ir/types"
+	"github.com/pkg/errors"
 )
 
 // --- [ Global variables ] ----------------------------------------------------
@@ -22,3 +23,83 @@ func (m *Module) NewGlobalDef(name string, init constant.Constant) *Global {
 	m.Globals = append(m.Globals, g)
 	return g
 }
+
+// RenameGlobal renames the given global variable of the module. An error is
+// returned if the global variable is not part of the module, or if the new name
+// is already used by a global variable, function, alias or IFunc of the module.
+//
+// Instructions and constants referring to the global variable refer to the
+// global variable value itself, and thus use the new name once renamed.
+func (m *Module) RenameGlobal(g *Global, newName string) error {
+	pos := -1
+	for i, h := range m.Globals {
+		if h == g {
+			pos = i
+			break
+		}
+	}
+	if pos == -1 {
+		return errors.Errorf("unable to locate global variable %s in module", g.Ident())
+	}
+	if newName == g.GlobalName {
+		return nil
+	}
+	if kind := m.nameUsedBy(newName); len(kind) > 0 {
+		return errors.Errorf("unable to rename global variable %s; name %q already used by %s", g.Ident(), newName, kind)
+	}
+	oldName := indexName(g.GlobalIdent)
+	g.SetName(newName)
+	if m.globalIndex != nil {
+		if i, ok := m.globalIndex[oldName]; ok && i == pos {
+			delete(m.globalIndex, oldName)
+		}
+		m.globalIndex[newName] = pos
+	}
+	return nil
+}
+
+// Global returns the global variable of the module with the given name
+// (without '@' prefix), and a boolean indicating whether the global variable
+// was present.
+//
+// Global variables are located using an index which is built on first use.
+// Index entries are validated against the global variables of the module, and
+// the index is rebuilt on a miss; thus global variables may be added, removed,
+// replaced or renamed directly.
+func (m *Module) Global(name string) (*Global, bool) {
+	if g, ok := m.lookupGlobal(name); ok {
+		return g, true
+	}
+	// Rebuild the index before reporting absence, as global variables may have
+	// been added, removed, replaced or renamed since the index was built.
+	m.indexGlobals()
+	return m.lookupGlobal(name)
+}
+
+// lookupGlobal returns the global variable with the given name as located by the
+// index, and a boolean indicating whether the index entry was present and up
+// to date.
+func (m *Module) lookupGlobal(name string) (*Global, bool) {
+	i, ok := m.globalIndex[name]
+	if !ok || i >= len(m.Globals) {
+		return nil, false
+	}
+	g := m.Globals[i]
+	if indexName(g.GlobalIdent) != name {
+		return nil, false
+	}
+	return g, true
+}
+
+// indexGlobals builds the index of global variables by name. The first global
+// variable with a given name is indexed, if several global variables share the
+// same name.
+func (m *Module) indexGlobals() {
+	m.globalIndex = make(map[string]int, len(m.Globals))
+	for i, g := range m.Globals {
+		name := indexName(g.GlobalIdent)
+		if _, ok := m.globalIndex[name]; !ok {
+			m.globalIndex[name] = i
+		}
+	}
+}
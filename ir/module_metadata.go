@@ -0,0 +1,205 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
+)
+
+// === [ Metadata uniquing ] ===================================================
+
+// UniqueMetadata merges the structurally identical metadata definitions of the
+// module into one, and redirects references to the merged metadata definitions
+// to the remaining one. Distinct metadata definitions are never merged.
+//
+// Two metadata definitions are structurally identical if their metadata nodes
+// have identical fields, where references to metadata definitions are compared
+// by metadata ID. Merging is repeated until no more metadata definitions are
+// merged, as merging may make the metadata definitions referring to the merged
+// ones identical. Cyclic metadata definitions (e.g. self-referential metadata
+// nodes) are thus only merged if they refer to the same metadata definitions.
+//
+// Lazily parsed function bodies are materialized, as they may refer to the
+// merged metadata definitions.
+func (m *Module) UniqueMetadata() error {
+	for _, f := range m.Funcs {
+		if err := f.Materialize(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	for {
+		// Locate metadata definitions to merge.
+		canon := make(map[string]*metadata.Def)
+		repl := make(metadataRewriter)
+		mds := m.MetadataDefs[:0]
+		for _, md := range m.MetadataDefs {
+			if md.Distinct {
+				mds = append(mds, md)
+				continue
+			}
+			key := md.Node.String()
+			if prev, ok := canon[key]; ok {
+				repl[md] = prev
+				continue
+			}
+			canon[key] = md
+			mds = append(mds, md)
+		}
+		for i := len(mds); i < len(m.MetadataDefs); i++ {
+			m.MetadataDefs[i] = nil
+		}
+		m.MetadataDefs = mds
+		if len(repl) == 0 {
+			return nil
+		}
+		// Redirect references to merged metadata definitions.
+		repl.module(m)
+	}
+}
+
+// ### [ Helper functions ] ####################################################
+
+// metadataRewriter redirects references to metadata definitions, mapping from
+// metadata definition to replacement.
+type metadataRewriter map[*metadata.Def]*metadata.Def
+
+// module redirects the references to metadata definitions of the given module.
+func (r metadataRewriter) module(m *Module) {
+	for _, md := range m.MetadataDefs {
+		md.Node = r.field(md.Node)
+	}
+	for _, named := range m.NamedMetadataDefs {
+		for i, node := range named.Nodes {
+			named.Nodes[i] = r.field(node)
+		}
+	}
+	for _, g := range m.Globals {
+		r.attachments(g.Metadata)
+	}
+	for _, f := range m.Funcs {
+		r.attachments(f.Metadata)
+		for _, block := range f.Blocks {
+			for _, inst := range block.Insts {
+				r.inst(inst)
+			}
+			if block.Term != nil {
+				r.inst(block.Term)
+			}
+		}
+	}
+}
+
+// inst redirects the references to metadata definitions of the given
+// instruction or terminator.
+func (r metadataRewriter) inst(inst interface{}) {
+	for _, op := range operands(inst) {
+		if *op != nil {
+			r.value(*op)
+		}
+	}
+	r.attachments(instMetadata(inst))
+}
+
+// attachments redirects the references to metadata definitions of the given
+// metadata attachments.
+func (r metadataRewriter) attachments(mds []*metadata.Attachment) {
+	for _, md := range mds {
+		md.Node = r.field(md.Node)
+	}
+}
+
+// value redirects the references to metadata definitions of the given metadata
+// value operand.
+func (r metadataRewriter) value(v value.Value) {
+	switch v := v.(type) {
+	case *Arg:
+		r.value(v.Value)
+	case *metadata.Value:
+		v.Value = r.field(v.Value)
+	}
+}
+
+// field redirects the references to metadata definitions of the given metadata
+// field, and returns the (possibly replaced) metadata field. Metadata nodes
+// are updated in place. Metadata definitions are not descended into, as their
+// metadata nodes are updated separately.
+func (r metadataRewriter) field(field metadata.Field) metadata.Field {
+	switch field := field.(type) {
+	case *metadata.Def:
+		if new, ok := r[field]; ok {
+			return new
+		}
+	case *metadata.Tuple:
+		for i, f := range field.Fields {
+			field.Fields[i] = r.field(f)
+		}
+	case *metadata.Value:
+		field.Value = r.field(field.Value)
+	case *metadata.DISubrange:
+		field.Count = r.field(field.Count)
+	default:
+		for _, f := range specializedFields(field) {
+			if *f != nil {
+				*f = r.field(*f)
+			}
+		}
+	}
+	return field
+}
+
+// specializedFields returns the metadata fields of the given specialized
+// metadata node which may refer to metadata definitions, in order of
+// occurrence. The count of DISubrange, which may be an integer, is not
+// included.
+func specializedFields(node metadata.Field) []*metadata.Field {
+	switch node := node.(type) {
+	case *metadata.DICompileUnit:
+		return []*metadata.Field{&node.File, &node.Enums, &node.RetainedTypes, &node.Globals, &node.Imports, &node.Macros}
+	case *metadata.DICompositeType:
+		return []*metadata.Field{&node.Scope, &node.File, &node.BaseType, &node.Elements, &node.VtableHolder, &node.TemplateParams, &node.Discriminator}
+	case *metadata.DIDerivedType:
+		return []*metadata.Field{&node.Scope, &node.File, &node.BaseType, &node.ExtraData}
+	case *metadata.DIGlobalVariable:
+		return []*metadata.Field{&node.Scope, &node.File, &node.Type, &node.TemplateParams, &node.Declaration}
+	case *metadata.DIGlobalVariableExpression:
+		return []*metadata.Field{&node.Var, &node.Expr}
+	case *metadata.DIImportedEntity:
+		return []*metadata.Field{&node.Scope, &node.Entity, &node.File}
+	case *metadata.DILabel:
+		return []*metadata.Field{&node.Scope, &node.File}
+	case *metadata.DILexicalBlock:
+		return []*metadata.Field{&node.Scope, &node.File}
+	case *metadata.DILexicalBlockFile:
+		return []*metadata.Field{&node.Scope, &node.File}
+	case *metadata.DILocalVariable:
+		return []*metadata.Field{&node.Scope, &node.File, &node.Type}
+	case *metadata.DILocation:
+		return []*metadata.Field{&node.Scope, &node.InlinedAt}
+	case *metadata.DIMacroFile:
+		return []*metadata.Field{&node.File, &node.Nodes}
+	case *metadata.DIModule:
+		return []*metadata.Field{&node.Scope}
+	case *metadata.DINamespace:
+		return []*metadata.Field{&node.Scope}
+	case *metadata.DIObjCProperty:
+		return []*metadata.Field{&node.File, &node.Type}
+	case *metadata.DISubprogram:
+		return []*metadata.Field{&node.Scope, &node.File, &node.Type, &node.ContainingType, &node.Unit, &node.TemplateParams, &node.Declaration, &node.RetainedNodes, &node.ThrownTypes}
+	case *metadata.DISubroutineType:
+		return []*metadata.Field{&node.Types}
+	case *metadata.DITemplateTypeParameter:
+		return []*metadata.Field{&node.Type}
+	case *metadata.DITemplateValueParameter:
+		return []*metadata.Field{&node.Type, &node.Value}
+	case *metadata.GenericDINode:
+		var fields []*metadata.Field
+		for i := range node.Operands {
+			fields = append(fields, &node.Operands[i])
+		}
+		return fields
+	default:
+		// Metadata without references to metadata definitions; e.g. metadata
+		// strings, DIBasicType and DIExpression.
+		return nil
+	}
+}
@@ -0,0 +1,49 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestUniqueMetadata(t *testing.T) {
+	m := NewModule()
+	tuple := func(id int64, distinct bool, fields ...metadata.Field) *metadata.Def {
+		md := &metadata.Def{ID: id, Node: &metadata.Tuple{Fields: fields}, Distinct: distinct}
+		m.MetadataDefs = append(m.MetadataDefs, md)
+		return md
+	}
+	one := constant.NewInt(types.I32, 1)
+	two := constant.NewInt(types.I32, 2)
+	md0 := tuple(0, false, one, two)
+	md1 := tuple(1, false, one, two)
+	md2 := tuple(2, false, md0)
+	md3 := tuple(3, false, md1)
+	md4 := tuple(4, true, one, two)
+	// Self-referential metadata nodes.
+	md5 := tuple(5, false)
+	md5.Node.(*metadata.Tuple).Fields = []metadata.Field{md5}
+	md6 := tuple(6, false)
+	md6.Node.(*metadata.Tuple).Fields = []metadata.Field{md6}
+	m.NamedMetadataDefs = append(m.NamedMetadataDefs, &metadata.NamedDef{Name: "foo", Nodes: []metadata.Node{md2, md3, md4, md5, md6}})
+	g := m.NewGlobalDef("x", one)
+	g.Metadata = append(g.Metadata, &metadata.Attachment{Name: "bar", Node: md1})
+	if err := m.UniqueMetadata(); err != nil {
+		t.Fatalf("unable to unique metadata; %v", err)
+	}
+	want := `@x = global i32 1, !bar !0
+
+!foo = !{!2, !2, !4, !5, !6}
+
+!0 = !{i32 1, i32 2}
+!2 = !{!0}
+!4 = distinct !{i32 1, i32 2}
+!5 = !{!5}
+!6 = !{!6}
+`
+	if got := m.String(); got != want {
+		t.Errorf("module mismatch; expected `%s`, got `%s`", want, got)
+	}
+}
@@ -0,0 +1,52 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+)
+
+// === [ Symbol table ] ========================================================
+
+// Symbol is a global symbol of a module; i.e. a global variable, function,
+// alias or IFunc.
+type Symbol struct {
+	// Global identifier of the symbol (with '@' prefix); e.g. @foo or @42.
+	Ident string
+	// Symbol kind.
+	Kind enum.SymbolKind
+	// Linkage; zero value if not present.
+	Linkage enum.Linkage
+	// Visibility; zero value if not present.
+	Visibility enum.Visibility
+	// Symbol is a definition, as opposed to a declaration.
+	Def bool
+	// Global variable, function, alias or IFunc of the symbol.
+	Value constant.Constant
+}
+
+// Symbols returns the symbol table of the module (similar to the output of nm);
+// i.e. the global variables, functions, aliases and IFuncs of the module, in
+// that order and each in order of declaration. Both named and unnamed global
+// symbols are included.
+//
+// Functions with lazily parsed function bodies are definitions.
+func (m *Module) Symbols() []Symbol {
+	var syms []Symbol
+	for _, g := range m.Globals {
+		sym := Symbol{Ident: g.Ident(), Kind: enum.SymbolKindGlobal, Linkage: g.Linkage, Visibility: g.Visibility, Def: g.Init != nil, Value: g}
+		syms = append(syms, sym)
+	}
+	for _, f := range m.Funcs {
+		sym := Symbol{Ident: f.Ident(), Kind: enum.SymbolKindFunc, Linkage: f.Linkage, Visibility: f.Visibility, Def: len(f.Blocks) > 0 || f.Lazy != nil, Value: f}
+		syms = append(syms, sym)
+	}
+	for _, alias := range m.Aliases {
+		sym := Symbol{Ident: alias.Ident(), Kind: enum.SymbolKindAlias, Linkage: alias.Linkage, Visibility: alias.Visibility, Def: true, Value: alias}
+		syms = append(syms, sym)
+	}
+	for _, ifunc := range m.IFuncs {
+		sym := Symbol{Ident: ifunc.Ident(), Kind: enum.SymbolKindIFunc, Linkage: ifunc.Linkage, Visibility: ifunc.Visibility, Def: true, Value: ifunc}
+		syms = append(syms, sym)
+	}
+	return syms
+}
@@ -0,0 +1,42 @@
+package ir
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestSymbols(t *testing.T) {
+	m := NewModule()
+	x := m.NewGlobalDef("x", constant.NewInt(types.I32, 1))
+	x.Linkage = enum.LinkageInternal
+	y := m.NewGlobalDecl("y", types.I32)
+	y.Linkage = enum.LinkageExternal
+	anon := m.NewGlobalDef("", constant.NewInt(types.I8, 0))
+	anon.Linkage = enum.LinkagePrivate
+	anon.SetID(0)
+	f := m.NewFunc("f", types.Void)
+	f.Visibility = enum.VisibilityHidden
+	f.NewBlock("").NewRet(nil)
+	m.NewFunc("g", types.Void)
+	m.NewAlias("h", f)
+	var got []string
+	for _, sym := range m.Symbols() {
+		got = append(got, fmt.Sprintf("%s %s %s %s %t", sym.Ident, sym.Kind, sym.Linkage, sym.Visibility, sym.Def))
+	}
+	want := []string{
+		"@x global internal none true",
+		"@y global external none false",
+		"@0 global private none true",
+		"@f function none hidden true",
+		"@g function none none false",
+		"@h alias none none true",
+	}
+	if g, w := strings.Join(got, "\n"), strings.Join(want, "\n"); g != w {
+		t.Errorf("symbols mismatch; expected\n%s\ngot\n%s", w, g)
+	}
+}
@@ -0,0 +1,341 @@
+package ir
+
+import "github.com/llir/llvm/ir/enum"
+
+// === [ Instruction opcodes ] =================================================
+
+// --- [ Binary instructions ] -------------------------------------------------
+
+// Opcode returns the opcode of the instruction.
+func (*InstAdd) Opcode() enum.Opcode {
+	return enum.OpcodeAdd
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstFAdd) Opcode() enum.Opcode {
+	return enum.OpcodeFAdd
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstSub) Opcode() enum.Opcode {
+	return enum.OpcodeSub
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstFSub) Opcode() enum.Opcode {
+	return enum.OpcodeFSub
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstMul) Opcode() enum.Opcode {
+	return enum.OpcodeMul
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstFMul) Opcode() enum.Opcode {
+	return enum.OpcodeFMul
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstUDiv) Opcode() enum.Opcode {
+	return enum.OpcodeUDiv
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstSDiv) Opcode() enum.Opcode {
+	return enum.OpcodeSDiv
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstFDiv) Opcode() enum.Opcode {
+	return enum.OpcodeFDiv
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstURem) Opcode() enum.Opcode {
+	return enum.OpcodeURem
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstSRem) Opcode() enum.Opcode {
+	return enum.OpcodeSRem
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstFRem) Opcode() enum.Opcode {
+	return enum.OpcodeFRem
+}
+
+// --- [ Bitwise instructions ] ------------------------------------------------
+
+// Opcode returns the opcode of the instruction.
+func (*InstShl) Opcode() enum.Opcode {
+	return enum.OpcodeShl
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstLShr) Opcode() enum.Opcode {
+	return enum.OpcodeLShr
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstAShr) Opcode() enum.Opcode {
+	return enum.OpcodeAShr
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstAnd) Opcode() enum.Opcode {
+	return enum.OpcodeAnd
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstOr) Opcode() enum.Opcode {
+	return enum.OpcodeOr
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstXor) Opcode() enum.Opcode {
+	return enum.OpcodeXor
+}
+
+// --- [ Vector instructions ] -------------------------------------------------
+
+// Opcode returns the opcode of the instruction.
+func (*InstExtractElement) Opcode() enum.Opcode {
+	return enum.OpcodeExtractElement
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstInsertElement) Opcode() enum.Opcode {
+	return enum.OpcodeInsertElement
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstShuffleVector) Opcode() enum.Opcode {
+	return enum.OpcodeShuffleVector
+}
+
+// --- [ Aggregate instructions ] ----------------------------------------------
+
+// Opcode returns the opcode of the instruction.
+func (*InstExtractValue) Opcode() enum.Opcode {
+	return enum.OpcodeExtractValue
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstInsertValue) Opcode() enum.Opcode {
+	return enum.OpcodeInsertValue
+}
+
+// --- [ Memory instructions ] -------------------------------------------------
+
+// Opcode returns the opcode of the instruction.
+func (*InstAlloca) Opcode() enum.Opcode {
+	return enum.OpcodeAlloca
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstLoad) Opcode() enum.Opcode {
+	return enum.OpcodeLoad
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstStore) Opcode() enum.Opcode {
+	return enum.OpcodeStore
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstFence) Opcode() enum.Opcode {
+	return enum.OpcodeFence
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstCmpXchg) Opcode() enum.Opcode {
+	return enum.OpcodeCmpXchg
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstAtomicRMW) Opcode() enum.Opcode {
+	return enum.OpcodeAtomicRMW
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstGetElementPtr) Opcode() enum.Opcode {
+	return enum.OpcodeGetElementPtr
+}
+
+// --- [ Conversion instructions ] ---------------------------------------------
+
+// Opcode returns the opcode of the instruction.
+func (*InstTrunc) Opcode() enum.Opcode {
+	return enum.OpcodeTrunc
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstZExt) Opcode() enum.Opcode {
+	return enum.OpcodeZExt
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstSExt) Opcode() enum.Opcode {
+	return enum.OpcodeSExt
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstFPTrunc) Opcode() enum.Opcode {
+	return enum.OpcodeFPTrunc
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstFPExt) Opcode() enum.Opcode {
+	return enum.OpcodeFPExt
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstFPToUI) Opcode() enum.Opcode {
+	return enum.OpcodeFPToUI
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstFPToSI) Opcode() enum.Opcode {
+	return enum.OpcodeFPToSI
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstUIToFP) Opcode() enum.Opcode {
+	return enum.OpcodeUIToFP
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstSIToFP) Opcode() enum.Opcode {
+	return enum.OpcodeSIToFP
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstPtrToInt) Opcode() enum.Opcode {
+	return enum.OpcodePtrToInt
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstIntToPtr) Opcode() enum.Opcode {
+	return enum.OpcodeIntToPtr
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstBitCast) Opcode() enum.Opcode {
+	return enum.OpcodeBitCast
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstAddrSpaceCast) Opcode() enum.Opcode {
+	return enum.OpcodeAddrSpaceCast
+}
+
+// --- [ Other instructions ] --------------------------------------------------
+
+// Opcode returns the opcode of the instruction.
+func (*InstICmp) Opcode() enum.Opcode {
+	return enum.OpcodeICmp
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstFCmp) Opcode() enum.Opcode {
+	return enum.OpcodeFCmp
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstPhi) Opcode() enum.Opcode {
+	return enum.OpcodePhi
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstSelect) Opcode() enum.Opcode {
+	return enum.OpcodeSelect
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstCall) Opcode() enum.Opcode {
+	return enum.OpcodeCall
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstVAArg) Opcode() enum.Opcode {
+	return enum.OpcodeVAArg
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstLandingPad) Opcode() enum.Opcode {
+	return enum.OpcodeLandingPad
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstCatchPad) Opcode() enum.Opcode {
+	return enum.OpcodeCatchPad
+}
+
+// Opcode returns the opcode of the instruction.
+func (*InstCleanupPad) Opcode() enum.Opcode {
+	return enum.OpcodeCleanupPad
+}
+
+// === [ Terminator opcodes ] ==================================================
+
+// Opcode returns the opcode of the terminator.
+func (*TermRet) Opcode() enum.Opcode {
+	return enum.OpcodeRet
+}
+
+// Opcode returns the opcode of the terminator.
+func (*TermBr) Opcode() enum.Opcode {
+	return enum.OpcodeBr
+}
+
+// Opcode returns the opcode of the terminator.
+func (*TermCondBr) Opcode() enum.Opcode {
+	return enum.OpcodeBr
+}
+
+// Opcode returns the opcode of the terminator.
+func (*TermSwitch) Opcode() enum.Opcode {
+	return enum.OpcodeSwitch
+}
+
+// Opcode returns the opcode of the terminator.
+func (*TermIndirectBr) Opcode() enum.Opcode {
+	return enum.OpcodeIndirectBr
+}
+
+// Opcode returns the opcode of the terminator.
+func (*TermInvoke) Opcode() enum.Opcode {
+	return enum.OpcodeInvoke
+}
+
+// Opcode returns the opcode of the terminator.
+func (*TermCallBr) Opcode() enum.Opcode {
+	return enum.OpcodeCallBr
+}
+
+// Opcode returns the opcode of the terminator.
+func (*TermResume) Opcode() enum.Opcode {
+	return enum.OpcodeResume
+}
+
+// Opcode returns the opcode of the terminator.
+func (*TermCatchSwitch) Opcode() enum.Opcode {
+	return enum.OpcodeCatchSwitch
+}
+
+// Opcode returns the opcode of the terminator.
+func (*TermCatchRet) Opcode() enum.Opcode {
+	return enum.OpcodeCatchRet
+}
+
+// Opcode returns the opcode of the terminator.
+func (*TermCleanupRet) Opcode() enum.Opcode {
+	return enum.OpcodeCleanupRet
+}
+
+// Opcode returns the opcode of the terminator.
+func (*TermUnreachable) Opcode() enum.Opcode {
+	return enum.OpcodeUnreachable
+}
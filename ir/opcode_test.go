@@ -0,0 +1,89 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/enum"
+)
+
+func TestOpcode(t *testing.T) {
+	golden := []struct {
+		in interface {
+			Opcode() enum.Opcode
+		}
+		want string
+	}{
+		// Instructions.
+		{in: &InstAdd{}, want: "add"},
+		{in: &InstFAdd{}, want: "fadd"},
+		{in: &InstSub{}, want: "sub"},
+		{in: &InstFSub{}, want: "fsub"},
+		{in: &InstMul{}, want: "mul"},
+		{in: &InstFMul{}, want: "fmul"},
+		{in: &InstUDiv{}, want: "udiv"},
+		{in: &InstSDiv{}, want: "sdiv"},
+		{in: &InstFDiv{}, want: "fdiv"},
+		{in: &InstURem{}, want: "urem"},
+		{in: &InstSRem{}, want: "srem"},
+		{in: &InstFRem{}, want: "frem"},
+		{in: &InstShl{}, want: "shl"},
+		{in: &InstLShr{}, want: "lshr"},
+		{in: &InstAShr{}, want: "ashr"},
+		{in: &InstAnd{}, want: "and"},
+		{in: &InstOr{}, want: "or"},
+		{in: &InstXor{}, want: "xor"},
+		{in: &InstExtractElement{}, want: "extractelement"},
+		{in: &InstInsertElement{}, want: "insertelement"},
+		{in: &InstShuffleVector{}, want: "shufflevector"},
+		{in: &InstExtractValue{}, want: "extractvalue"},
+		{in: &InstInsertValue{}, want: "insertvalue"},
+		{in: &InstAlloca{}, want: "alloca"},
+		{in: &InstLoad{}, want: "load"},
+		{in: &InstStore{}, want: "store"},
+		{in: &InstFence{}, want: "fence"},
+		{in: &InstCmpXchg{}, want: "cmpxchg"},
+		{in: &InstAtomicRMW{}, want: "atomicrmw"},
+		{in: &InstGetElementPtr{}, want: "getelementptr"},
+		{in: &InstTrunc{}, want: "trunc"},
+		{in: &InstZExt{}, want: "zext"},
+		{in: &InstSExt{}, want: "sext"},
+		{in: &InstFPTrunc{}, want: "fptrunc"},
+		{in: &InstFPExt{}, want: "fpext"},
+		{in: &InstFPToUI{}, want: "fptoui"},
+		{in: &InstFPToSI{}, want: "fptosi"},
+		{in: &InstUIToFP{}, want: "uitofp"},
+		{in: &InstSIToFP{}, want: "sitofp"},
+		{in: &InstPtrToInt{}, want: "ptrtoint"},
+		{in: &InstIntToPtr{}, want: "inttoptr"},
+		{in: &InstBitCast{}, want: "bitcast"},
+		{in: &InstAddrSpaceCast{}, want: "addrspacecast"},
+		{in: &InstICmp{}, want: "icmp"},
+		{in: &InstFCmp{}, want: "fcmp"},
+		{in: &InstPhi{}, want: "phi"},
+		{in: &InstSelect{}, want: "select"},
+		{in: &InstCall{}, want: "call"},
+		{in: &InstVAArg{}, want: "va_arg"},
+		{in: &InstLandingPad{}, want: "landingpad"},
+		{in: &InstCatchPad{}, want: "catchpad"},
+		{in: &InstCleanupPad{}, want: "cleanuppad"},
+		// Terminators.
+		{in: &TermRet{}, want: "ret"},
+		{in: &TermBr{}, want: "br"},
+		{in: &TermCondBr{}, want: "br"},
+		{in: &TermSwitch{}, want: "switch"},
+		{in: &TermIndirectBr{}, want: "indirectbr"},
+		{in: &TermInvoke{}, want: "invoke"},
+		{in: &TermCallBr{}, want: "callbr"},
+		{in: &TermResume{}, want: "resume"},
+		{in: &TermCatchSwitch{}, want: "catchswitch"},
+		{in: &TermCatchRet{}, want: "catchret"},
+		{in: &TermCleanupRet{}, want: "cleanupret"},
+		{in: &TermUnreachable{}, want: "unreachable"},
+	}
+	for _, g := range golden {
+		got := g.in.Opcode().String()
+		if g.want != got {
+			t.Errorf("opcode mismatch of %T; expected %q, got %q", g.in, g.want, got)
+		}
+	}
+}
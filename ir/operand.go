@@ -0,0 +1,376 @@
+package ir
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/value"
+)
+
+// operands returns pointers to the value operands of the given instruction or
+// terminator, in order of occurrence. Basic block operands (e.g. branch
+// targets and predecessors of incoming values) and constant operands of
+// compound fields (e.g. switch case comparands) are not included.
+//
+// The argument value of function arguments with parameter attributes is
+// returned in place of the *Arg wrapper. Exception pad operands (e.g. the
+// parent pad of catchswitch and the catchpad of catchret) are stored in fields
+// of concrete type, and are thus returned as pointers to copies; these must be
+// replaced through their respective fields.
+func operands(inst interface{}) []*value.Value {
+	switch inst := inst.(type) {
+	// Binary instructions.
+	case *InstAdd:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstFAdd:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstSub:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstFSub:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstMul:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstFMul:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstUDiv:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstSDiv:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstFDiv:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstURem:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstSRem:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstFRem:
+		return []*value.Value{&inst.X, &inst.Y}
+	// Bitwise instructions.
+	case *InstShl:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstLShr:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstAShr:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstAnd:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstOr:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstXor:
+		return []*value.Value{&inst.X, &inst.Y}
+	// Vector instructions.
+	case *InstExtractElement:
+		return []*value.Value{&inst.X, &inst.Index}
+	case *InstInsertElement:
+		return []*value.Value{&inst.X, &inst.Elem, &inst.Index}
+	case *InstShuffleVector:
+		return []*value.Value{&inst.X, &inst.Y, &inst.Mask}
+	// Aggregate instructions.
+	case *InstExtractValue:
+		return []*value.Value{&inst.X}
+	case *InstInsertValue:
+		return []*value.Value{&inst.X, &inst.Elem}
+	// Memory instructions.
+	case *InstAlloca:
+		if inst.NElems != nil {
+			return []*value.Value{&inst.NElems}
+		}
+		return nil
+	case *InstLoad:
+		return []*value.Value{&inst.Src}
+	case *InstStore:
+		return []*value.Value{&inst.Src, &inst.Dst}
+	case *InstFence:
+		return nil
+	case *InstCmpXchg:
+		return []*value.Value{&inst.Ptr, &inst.Cmp, &inst.New}
+	case *InstAtomicRMW:
+		return []*value.Value{&inst.Dst, &inst.X}
+	case *InstGetElementPtr:
+		ops := []*value.Value{&inst.Src}
+		for i := range inst.Indices {
+			ops = append(ops, &inst.Indices[i])
+		}
+		return ops
+	// Conversion instructions.
+	case *InstTrunc:
+		return []*value.Value{&inst.From}
+	case *InstZExt:
+		return []*value.Value{&inst.From}
+	case *InstSExt:
+		return []*value.Value{&inst.From}
+	case *InstFPTrunc:
+		return []*value.Value{&inst.From}
+	case *InstFPExt:
+		return []*value.Value{&inst.From}
+	case *InstFPToUI:
+		return []*value.Value{&inst.From}
+	case *InstFPToSI:
+		return []*value.Value{&inst.From}
+	case *InstUIToFP:
+		return []*value.Value{&inst.From}
+	case *InstSIToFP:
+		return []*value.Value{&inst.From}
+	case *InstPtrToInt:
+		return []*value.Value{&inst.From}
+	case *InstIntToPtr:
+		return []*value.Value{&inst.From}
+	case *InstBitCast:
+		return []*value.Value{&inst.From}
+	case *InstAddrSpaceCast:
+		return []*value.Value{&inst.From}
+	// Other instructions.
+	case *InstICmp:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstFCmp:
+		return []*value.Value{&inst.X, &inst.Y}
+	case *InstPhi:
+		var ops []*value.Value
+		for _, inc := range inst.Incs {
+			ops = append(ops, &inc.X)
+		}
+		return ops
+	case *InstSelect:
+		return []*value.Value{&inst.Cond, &inst.X, &inst.Y}
+	case *InstCall:
+		ops := []*value.Value{&inst.Callee}
+		for i := range inst.Args {
+			ops = append(ops, argOperand(&inst.Args[i]))
+		}
+		return append(ops, bundleOperands(inst.OperandBundles)...)
+	case *InstVAArg:
+		return []*value.Value{&inst.ArgList}
+	case *InstLandingPad:
+		var ops []*value.Value
+		for _, clause := range inst.Clauses {
+			ops = append(ops, &clause.X)
+		}
+		return ops
+	case *InstCatchPad:
+		var ops []*value.Value
+		if inst.Scope != nil {
+			ops = append(ops, padOperand(inst.Scope))
+		}
+		for i := range inst.Args {
+			ops = append(ops, argOperand(&inst.Args[i]))
+		}
+		return ops
+	case *InstCleanupPad:
+		var ops []*value.Value
+		if inst.Scope != nil {
+			ops = append(ops, padOperand(inst.Scope))
+		}
+		for i := range inst.Args {
+			ops = append(ops, argOperand(&inst.Args[i]))
+		}
+		return ops
+	// Terminators.
+	case *TermRet:
+		if inst.X != nil {
+			return []*value.Value{&inst.X}
+		}
+		return nil
+	case *TermBr:
+		return nil
+	case *TermCondBr:
+		return []*value.Value{&inst.Cond}
+	case *TermSwitch:
+		return []*value.Value{&inst.X}
+	case *TermIndirectBr:
+		return []*value.Value{&inst.Addr}
+	case *TermInvoke:
+		ops := []*value.Value{&inst.Invokee}
+		for i := range inst.Args {
+			ops = append(ops, argOperand(&inst.Args[i]))
+		}
+		return append(ops, bundleOperands(inst.OperandBundles)...)
+	case *TermCallBr:
+		ops := []*value.Value{&inst.Callee}
+		for i := range inst.Args {
+			ops = append(ops, argOperand(&inst.Args[i]))
+		}
+		return append(ops, bundleOperands(inst.OperandBundles)...)
+	case *TermResume:
+		return []*value.Value{&inst.X}
+	case *TermCatchSwitch:
+		if inst.Scope != nil {
+			return []*value.Value{padOperand(inst.Scope)}
+		}
+		return nil
+	case *TermCatchRet:
+		if inst.From != nil {
+			return []*value.Value{padOperand(inst.From)}
+		}
+		return nil
+	case *TermCleanupRet:
+		if inst.From != nil {
+			return []*value.Value{padOperand(inst.From)}
+		}
+		return nil
+	case *TermUnreachable:
+		return nil
+	default:
+		panic(fmt.Errorf("support for instruction or terminator %T not yet implemented", inst))
+	}
+}
+
+// argOperand returns a pointer to the argument value of the given function
+// argument, unwrapping arguments with parameter attributes.
+func argOperand(arg *value.Value) *value.Value {
+	if a, ok := (*arg).(*Arg); ok {
+		return &a.Value
+	}
+	return arg
+}
+
+// padOperand returns a pointer to a copy of the given exception pad operand.
+func padOperand(pad value.Value) *value.Value {
+	return &pad
+}
+
+// bundleOperands returns pointers to the input values of the given operand
+// bundles.
+func bundleOperands(bundles []*OperandBundle) []*value.Value {
+	var ops []*value.Value
+	for _, bundle := range bundles {
+		for i := range bundle.Inputs {
+			ops = append(ops, &bundle.Inputs[i])
+		}
+	}
+	return ops
+}
+
+// constantOperands returns pointers to the constant operands of the given
+// constant, in order of occurrence. Global variables, functions, aliases and
+// IFuncs are not considered to have constant operands.
+func constantOperands(c constant.Constant) []*constant.Constant {
+	switch c := c.(type) {
+	// Simple constants.
+	case *constant.Int, *constant.Float, *constant.Null, *constant.NoneToken:
+		return nil
+	// Complex constants.
+	case *constant.Struct:
+		return constantSlice(c.Fields)
+	case *constant.Array:
+		return constantSlice(c.Elems)
+	case *constant.CharArray:
+		return nil
+	case *constant.Vector:
+		return constantSlice(c.Elems)
+	case *constant.ZeroInitializer:
+		return nil
+	// Global variable and function addresses.
+	case *Global, *Function, *Alias, *IFunc:
+		return nil
+	// Undefined and poison values.
+	case *constant.Undef, *constant.Poison:
+		return nil
+	// Addresses of basic blocks.
+	case *constant.BlockAddress:
+		return []*constant.Constant{&c.Func}
+	// Function address wrappers.
+	case *constant.DSOLocalEquivalent:
+		return []*constant.Constant{&c.Func}
+	case *constant.NoCFI:
+		return []*constant.Constant{&c.Func}
+	// Binary expressions.
+	case *constant.ExprAdd:
+		return []*constant.Constant{&c.X, &c.Y}
+	case *constant.ExprFAdd:
+		return []*constant.Constant{&c.X, &c.Y}
+	case *constant.ExprSub:
+		return []*constant.Constant{&c.X, &c.Y}
+	case *constant.ExprFSub:
+		return []*constant.Constant{&c.X, &c.Y}
+	case *constant.ExprMul:
+		return []*constant.Constant{&c.X, &c.Y}
+	case *constant.ExprFMul:
+		return []*constant.Constant{&c.X, &c.Y}
+	case *constant.ExprUDiv:
+		return []*constant.Constant{&c.X, &c.Y}
+	case *constant.ExprSDiv:
+		return []*constant.Constant{&c.X, &c.Y}
+	case *constant.ExprFDiv:
+		return []*constant.Constant{&c.X, &c.Y}
+	case *constant.ExprURem:
+		return []*constant.Constant{&c.X, &c.Y}
+	case *constant.ExprSRem:
+		return []*constant.Constant{&c.X, &c.Y}
+	case *constant.ExprFRem:
+		return []*constant.Constant{&c.X, &c.Y}
+	// Bitwise expressions.
+	case *constant.ExprShl:
+		return []*constant.Constant{&c.X, &c.Y}
+	case *constant.ExprLShr:
+		return []*constant.Constant{&c.X, &c.Y}
+	case *constant.ExprAShr:
+		return []*constant.Constant{&c.X, &c.Y}
+	case *constant.ExprAnd:
+		return []*constant.Constant{&c.X, &c.Y}
+	case *constant.ExprOr:
+		return []*constant.Constant{&c.X, &c.Y}
+	case *constant.ExprXor:
+		return []*constant.Constant{&c.X, &c.Y}
+	// Vector expressions.
+	case *constant.ExprExtractElement:
+		return []*constant.Constant{&c.X, &c.Index}
+	case *constant.ExprInsertElement:
+		return []*constant.Constant{&c.X, &c.Elem, &c.Index}
+	case *constant.ExprShuffleVector:
+		return []*constant.Constant{&c.X, &c.Y, &c.Mask}
+	// Aggregate expressions.
+	case *constant.ExprExtractValue:
+		return []*constant.Constant{&c.X}
+	case *constant.ExprInsertValue:
+		return []*constant.Constant{&c.X, &c.Elem}
+	// Memory expressions.
+	case *constant.ExprGetElementPtr:
+		return append([]*constant.Constant{&c.Src}, constantSlice(c.Indices)...)
+	case *constant.Index:
+		return []*constant.Constant{&c.Constant}
+	// Conversion expressions.
+	case *constant.ExprTrunc:
+		return []*constant.Constant{&c.From}
+	case *constant.ExprZExt:
+		return []*constant.Constant{&c.From}
+	case *constant.ExprSExt:
+		return []*constant.Constant{&c.From}
+	case *constant.ExprFPTrunc:
+		return []*constant.Constant{&c.From}
+	case *constant.ExprFPExt:
+		return []*constant.Constant{&c.From}
+	case *constant.ExprFPToUI:
+		return []*constant.Constant{&c.From}
+	case *constant.ExprFPToSI:
+		return []*constant.Constant{&c.From}
+	case *constant.ExprUIToFP:
+		return []*constant.Constant{&c.From}
+	case *constant.ExprSIToFP:
+		return []*constant.Constant{&c.From}
+	case *constant.ExprPtrToInt:
+		return []*constant.Constant{&c.From}
+	case *constant.ExprIntToPtr:
+		return []*constant.Constant{&c.From}
+	case *constant.ExprBitCast:
+		return []*constant.Constant{&c.From}
+	case *constant.ExprAddrSpaceCast:
+		return []*constant.Constant{&c.From}
+	// Other expressions.
+	case *constant.ExprICmp:
+		return []*constant.Constant{&c.X, &c.Y}
+	case *constant.ExprFCmp:
+		return []*constant.Constant{&c.X, &c.Y}
+	case *constant.ExprSelect:
+		return []*constant.Constant{&c.Cond, &c.X, &c.Y}
+	default:
+		panic(fmt.Errorf("support for constant %T not yet implemented", c))
+	}
+}
+
+// constantSlice returns pointers to the constants of the given slice.
+func constantSlice(cs []constant.Constant) []*constant.Constant {
+	ops := make([]*constant.Constant, len(cs))
+	for i := range cs {
+		ops[i] = &cs[i]
+	}
+	return ops
+}
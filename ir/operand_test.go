@@ -0,0 +1,54 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestOperands(t *testing.T) {
+	x := NewParam("x", types.I32)
+	g := NewFunc("g", types.Void, NewParam("", types.I32))
+	// Argument values of arguments with parameter attributes.
+	arg := NewArg(x, enum.ParamAttrSignExt)
+	call := NewCall(g, arg)
+	ops := operands(call)
+	if len(ops) != 2 || *ops[1] != x {
+		t.Errorf("operands mismatch of call; expected argument value %v, got %v", x, ops)
+	}
+	// Replace argument value, retaining parameter attributes.
+	y := NewParam("y", types.I32)
+	*ops[1] = y
+	if arg.Value != y || call.Args[0] != arg {
+		t.Errorf("argument mismatch; expected %v with parameter attributes, got %v", y, call.Args[0])
+	}
+	// Exception pad operands.
+	catchSwitch := NewCatchSwitch(constant.None, nil, &UnwindToCaller{})
+	catchPad := NewCatchPad(catchSwitch)
+	cleanupPad := NewCleanupPad(catchPad)
+	golden := []struct {
+		inst interface{}
+		want []value.Value
+	}{
+		{inst: catchSwitch, want: []value.Value{constant.None}},
+		{inst: catchPad, want: []value.Value{catchSwitch}},
+		{inst: cleanupPad, want: []value.Value{catchPad}},
+		{inst: NewCatchRet(catchPad, &BasicBlock{}), want: []value.Value{catchPad}},
+		{inst: NewCleanupRet(cleanupPad, &UnwindToCaller{}), want: []value.Value{cleanupPad}},
+	}
+	for _, g := range golden {
+		ops := operands(g.inst)
+		if len(ops) != len(g.want) {
+			t.Errorf("operand count mismatch of %T; expected %d, got %d", g.inst, len(g.want), len(ops))
+			continue
+		}
+		for i, op := range ops {
+			if *op != g.want[i] {
+				t.Errorf("operand mismatch of %T; expected %v, got %v", g.inst, g.want[i], *op)
+			}
+		}
+	}
+}
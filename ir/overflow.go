@@ -0,0 +1,102 @@
+package ir
+
+import (
+	"math/big"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/value"
+)
+
+// PoisonOverflows returns the add, sub, mul and shl instructions of the given
+// function with integer constant operands and nsw or nuw overflow flags, whose
+// results overflow; i.e. instructions which always produce a poison value.
+func PoisonOverflows(f *Function) []Instruction {
+	var insts []Instruction
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			var (
+				op    func(x, y *big.Int) *big.Int
+				x, y  value.Value
+				flags []enum.OverflowFlag
+			)
+			switch inst := inst.(type) {
+			case *InstAdd:
+				op, x, y, flags = new(big.Int).Add, inst.X, inst.Y, inst.OverflowFlags
+			case *InstSub:
+				op, x, y, flags = new(big.Int).Sub, inst.X, inst.Y, inst.OverflowFlags
+			case *InstMul:
+				op, x, y, flags = new(big.Int).Mul, inst.X, inst.Y, inst.OverflowFlags
+			case *InstShl:
+				op, x, y, flags = shl, inst.X, inst.Y, inst.OverflowFlags
+			default:
+				continue
+			}
+			if len(flags) == 0 {
+				continue
+			}
+			if overflows(op, x, y, flags) {
+				insts = append(insts, inst)
+			}
+		}
+	}
+	return insts
+}
+
+// ### [ Helper functions ] ####################################################
+
+// overflows reports whether the result of the given operation on the operands
+// x and y overflows according to the given overflow flags. Operands other than
+// integer constants are reported as not overflowing.
+func overflows(op func(x, y *big.Int) *big.Int, x, y value.Value, flags []enum.OverflowFlag) bool {
+	cx, ok := x.(*constant.Int)
+	if !ok {
+		return false
+	}
+	cy, ok := y.(*constant.Int)
+	if !ok {
+		return false
+	}
+	n := cx.Typ.BitSize
+	for _, flag := range flags {
+		var result, min, max *big.Int
+		switch flag {
+		case enum.OverflowFlagNSW:
+			result = op(signedValue(cx.X, n), signedValue(cy.X, n))
+			min = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), uint(n-1)))
+			max = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(n-1)), big.NewInt(1))
+		case enum.OverflowFlagNUW:
+			result = op(unsignedValue(cx.X, n), unsignedValue(cy.X, n))
+			min = big.NewInt(0)
+			max = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(n)), big.NewInt(1))
+		}
+		if result == nil || result.Cmp(min) < 0 || result.Cmp(max) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// shl returns x shifted left by y bits; or nil if the shift amount is out of
+// range.
+func shl(x, y *big.Int) *big.Int {
+	if y.Sign() < 0 || !y.IsUint64() || y.Uint64() > 1<<16 {
+		return nil
+	}
+	return new(big.Int).Lsh(x, uint(y.Uint64()))
+}
+
+// unsignedValue returns the unsigned interpretation of the given n-bit integer.
+func unsignedValue(x *big.Int, n uint64) *big.Int {
+	mod := new(big.Int).Lsh(big.NewInt(1), uint(n))
+	return new(big.Int).Mod(x, mod)
+}
+
+// signedValue returns the signed interpretation of the given n-bit integer.
+func signedValue(x *big.Int, n uint64) *big.Int {
+	u := unsignedValue(x, n)
+	if u.Bit(int(n-1)) == 1 {
+		return u.Sub(u, new(big.Int).Lsh(big.NewInt(1), uint(n)))
+	}
+	return u
+}
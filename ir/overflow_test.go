@@ -0,0 +1,53 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestPoisonOverflows(t *testing.T) {
+	f := NewFunc("f", types.Void, NewParam("x", types.I8))
+	entry := f.NewBlock("")
+	i8 := func(x int64) *constant.Int {
+		return constant.NewInt(types.I8, x)
+	}
+	nsw := []enum.OverflowFlag{enum.OverflowFlagNSW}
+	nuw := []enum.OverflowFlag{enum.OverflowFlagNUW}
+	// add nsw i8 127, 1
+	addNSW := entry.NewAdd(i8(127), i8(1))
+	addNSW.OverflowFlags = nsw
+	// add nsw i8 126, 1
+	entry.NewAdd(i8(126), i8(1)).OverflowFlags = nsw
+	// add i8 127, 1
+	entry.NewAdd(i8(127), i8(1))
+	// add nuw i8 -1, 1
+	addNUW := entry.NewAdd(i8(-1), i8(1))
+	addNUW.OverflowFlags = nuw
+	// add nsw i8 -1, 1
+	entry.NewAdd(i8(-1), i8(1)).OverflowFlags = nsw
+	// sub nuw i8 0, 1
+	subNUW := entry.NewSub(i8(0), i8(1))
+	subNUW.OverflowFlags = nuw
+	// mul nsw i8 -128, -1
+	mulNSW := entry.NewMul(i8(-128), i8(-1))
+	mulNSW.OverflowFlags = nsw
+	// shl nuw i8 -128, 1
+	shlNUW := entry.NewShl(i8(-128), i8(1))
+	shlNUW.OverflowFlags = nuw
+	// add nsw i8 %x, 1
+	entry.NewAdd(f.Params[0], i8(1)).OverflowFlags = nsw
+	entry.NewRet(nil)
+	want := []Instruction{addNSW, addNUW, subNUW, mulNSW, shlNUW}
+	got := PoisonOverflows(f)
+	if len(want) != len(got) {
+		t.Fatalf("number of overflowing instructions mismatch; expected %d, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if want[i] != got[i] {
+			t.Errorf("overflowing instruction mismatch; expected `%s`, got `%s`", want[i].Def(), got[i].Def())
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package ir
+
+import "github.com/llir/llvm/ir/types"
+
+// === [ Select lowering ] =====================================================
+
+// SelectsToBranches lowers the select instructions of the given function into
+// control flow. The basic block of each select instruction is split at the
+// select instruction, and a conditional branch to two new basic blocks is
+// inserted, both of which branch to a new basic block holding the instructions
+// following the select instruction; the select instruction is replaced by a phi
+// instruction of the select operands in the new basic block.
+//
+// Selects on vectors of booleans are not lowered. The !prof branch weights of
+// select instructions are transferred to the inserted conditional branches.
+//
+// The IDs of unnamed local variables are reset, and reassigned by AssignIDs.
+func SelectsToBranches(f *Function) {
+	repl := make(map[*InstSelect]*InstPhi)
+	// Basic blocks inserted during lowering are appended to f.Blocks after the
+	// basic block being split, and are thus visited later in the loop; which
+	// lowers select instructions following a lowered select instruction.
+	for i := 0; i < len(f.Blocks); i++ {
+		block := f.Blocks[i]
+		for j, inst := range block.Insts {
+			sel, ok := inst.(*InstSelect)
+			if !ok || types.IsVector(sel.Cond.Type()) {
+				continue
+			}
+			trueBlock, falseBlock, tail := splitSelect(f, block, j, sel)
+			repl[sel] = tail.Insts[0].(*InstPhi)
+			blocks := append([]*BasicBlock{trueBlock, falseBlock, tail}, f.Blocks[i+1:]...)
+			f.Blocks = append(f.Blocks[:i+1], blocks...)
+			break
+		}
+	}
+	if len(repl) == 0 {
+		return
+	}
+	// Replace uses of lowered select instructions.
+	replace := func(inst interface{}) {
+		for _, op := range operands(inst) {
+			if sel, ok := (*op).(*InstSelect); ok {
+				if phi, ok := repl[sel]; ok {
+					*op = phi
+				}
+			}
+		}
+	}
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			replace(inst)
+		}
+		if block.Term != nil {
+			replace(block.Term)
+		}
+	}
+	resetLocalIDs(f)
+	f.InvalidateCFG()
+}
+
+// ### [ Helper functions ] ####################################################
+
+// splitSelect splits the given basic block of the function at the select instruction with the
+// specified index, and returns the basic blocks of the true and false
+// conditions, and the basic block holding the instructions following the
+// select instruction; the first instruction of which is a phi instruction
+// replacing the select instruction.
+func splitSelect(f *Function, block *BasicBlock, i int, sel *InstSelect) (trueBlock, falseBlock, tail *BasicBlock) {
+	trueBlock = &BasicBlock{Parent: f}
+	falseBlock = &BasicBlock{Parent: f}
+	tail = &BasicBlock{Parent: f}
+	phi := NewPhi(NewIncoming(sel.X, trueBlock), NewIncoming(sel.Y, falseBlock))
+	phi.LocalIdent = sel.LocalIdent
+	tail.Insts = append([]Instruction{phi}, block.Insts[i+1:]...)
+	tail.Term = block.Term
+	setCallParents(tail)
+	trueBlock.Term = NewBr(tail)
+	falseBlock.Term = NewBr(tail)
+	term := NewCondBr(sel.Cond, trueBlock, falseBlock)
+	for _, md := range sel.Metadata {
+		if md.Name == "prof" {
+			term.Metadata = append(term.Metadata, md)
+		}
+	}
+	block.Insts = block.Insts[:i]
+	block.Term = term
+	// Update incoming basic blocks of phi instructions in successors of the
+	// split basic block.
+	if tail.Term != nil {
+		resetSuccs(tail.Term)
+		for _, succ := range tail.Term.Succs() {
+			for _, inst := range succ.Insts {
+				phi, ok := inst.(*InstPhi)
+				if !ok {
+					break
+				}
+				for _, inc := range phi.Incs {
+					if inc.Pred == block {
+						inc.Pred = tail
+					}
+				}
+			}
+		}
+	}
+	return trueBlock, falseBlock, tail
+}
+
+// resetLocalIDs resets the IDs of the unnamed local variables of the given
+// function.
+func resetLocalIDs(f *Function) {
+	reset := func(v interface{}) {
+		if n, ok := v.(local); ok && n.IsUnnamed() {
+			n.SetID(0)
+		}
+	}
+	for _, param := range f.Params {
+		reset(param)
+	}
+	for _, block := range f.Blocks {
+		reset(block)
+		for _, inst := range block.Insts {
+			reset(inst)
+		}
+		reset(block.Term)
+	}
+}
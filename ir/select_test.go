@@ -0,0 +1,76 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestSelectsToBranches(t *testing.T) {
+	m := NewModule()
+	cond := NewParam("cond", types.I1)
+	a := NewParam("a", types.I32)
+	b := NewParam("b", types.I32)
+	f := m.NewFunc("f", types.I32, cond, a, b)
+	entry := f.NewBlock("entry")
+	exit := f.NewBlock("exit")
+	x := entry.NewSelect(cond, a, b)
+	x.SetName("x")
+	y := entry.NewAdd(x, constant.NewInt(types.I32, 1))
+	entry.NewBr(exit)
+	phi := exit.NewPhi(NewIncoming(y, entry))
+	exit.NewRet(phi)
+	SelectsToBranches(f)
+	want := `define i32 @f(i1 %cond, i32 %a, i32 %b) {
+entry:
+	br i1 %cond, label %0, label %1
+
+; <label>:0
+	br label %2
+
+; <label>:1
+	br label %2
+
+; <label>:2
+	%x = phi i32 [ %a, %0 ], [ %b, %1 ]
+	%3 = add i32 %x, 1
+	br label %exit
+
+exit:
+	%4 = phi i32 [ %3, %2 ]
+	ret i32 %4
+}`
+	if got := f.Def(); got != want {
+		t.Errorf("function mismatch; expected %q, got %q", want, got)
+	}
+	preds := exit.Preds()
+	if len(preds) != 1 || preds[0] != f.Blocks[3] {
+		t.Errorf("predecessor mismatch of %s; expected %s, got %v", exit.Ident(), f.Blocks[3].Ident(), preds)
+	}
+}
+
+func TestSelectsToBranchesArg(t *testing.T) {
+	usei := NewFunc("usei", types.Void, NewParam("", types.I32))
+	cond := NewParam("cond", types.I1)
+	a := NewParam("a", types.I32)
+	b := NewParam("b", types.I32)
+	f := NewFunc("f", types.Void, cond, a, b)
+	entry := f.NewBlock("entry")
+	x := entry.NewSelect(cond, a, b)
+	x.SetName("x")
+	// Select used as argument with parameter attributes.
+	arg := NewArg(x, enum.ParamAttrSignExt)
+	call := entry.NewCall(usei, arg)
+	entry.NewRet(nil)
+	SelectsToBranches(f)
+	tail := f.Blocks[3]
+	phi, ok := tail.Insts[0].(*InstPhi)
+	if !ok {
+		t.Fatalf("invalid instruction type; expected *ir.InstPhi, got %T", tail.Insts[0])
+	}
+	if tail.Insts[1] != call || call.Args[0] != arg || arg.Value != phi {
+		t.Errorf("argument mismatch; expected phi instruction %s with parameter attributes, got %v", phi.Ident(), call.Args[0])
+	}
+}
@@ -0,0 +1,74 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/constant"
+)
+
+// === [ Stack allocations ] ===================================================
+
+// Allocas returns the alloca instructions of the function, in order of
+// occurrence.
+func (f *Function) Allocas() []*InstAlloca {
+	var allocas []*InstAlloca
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			if alloca, ok := inst.(*InstAlloca); ok {
+				allocas = append(allocas, alloca)
+			}
+		}
+	}
+	return allocas
+}
+
+// StaticStackSize returns the size in bytes of the static stack allocations of
+// the function, based on the type layouts of the given data layout; i.e. the
+// alloca instructions of the entry basic block with a constant number of
+// elements, laid out in order of occurrence and padded to their alignment.
+// The remaining (dynamic) alloca instructions, the size of which is unknown
+// until run time, are returned separately.
+func (f *Function) StaticStackSize(dl *DataLayout) (size uint64, dynamic []*InstAlloca) {
+	for _, alloca := range f.Allocas() {
+		n, ok := staticAllocaLen(f, alloca)
+		if !ok {
+			dynamic = append(dynamic, alloca)
+			continue
+		}
+		align := dl.Alignof(alloca.ElemType)
+		if uint64(alloca.Align) > align {
+			align = uint64(alloca.Align)
+		}
+		size = alignTo(size, align) + n*dl.Sizeof(alloca.ElemType)
+	}
+	return size, dynamic
+}
+
+// ### [ Helper functions ] ####################################################
+
+// staticAllocaLen returns the number of elements allocated by the given alloca
+// instruction, and a boolean indicating whether the alloca instruction is a
+// static stack allocation of the function; i.e. located in the entry basic
+// block and with a constant number of elements.
+func staticAllocaLen(f *Function, alloca *InstAlloca) (uint64, bool) {
+	if len(f.Blocks) == 0 || !containsInst(f.Blocks[0], alloca) {
+		return 0, false
+	}
+	if alloca.NElems == nil {
+		return 1, true
+	}
+	n, ok := alloca.NElems.(*constant.Int)
+	if !ok || !n.X.IsUint64() {
+		return 0, false
+	}
+	return n.X.Uint64(), true
+}
+
+// containsInst reports whether the given basic block contains the given
+// instruction.
+func containsInst(block *BasicBlock, inst Instruction) bool {
+	for _, i := range block.Insts {
+		if i == inst {
+			return true
+		}
+	}
+	return false
+}
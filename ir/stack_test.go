@@ -0,0 +1,55 @@
+package ir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestStaticStackSize(t *testing.T) {
+	dl, err := ParseDataLayout("e-m:e-i64:64-f80:128-n8:16:32:64-S128")
+	if err != nil {
+		t.Fatalf("unable to parse data layout; %+v", err)
+	}
+	n := NewParam("n", types.I32)
+	f := NewFunc("f", types.Void, n)
+	entry := f.NewBlock("entry")
+	exit := f.NewBlock("exit")
+	// Static allocas; i32 at offset 0, i64 at offset 8, [10 x i8] at offset 16
+	// and i8 with explicit alignment at offset 32.
+	entry.NewAlloca(types.I32)
+	entry.NewAlloca(types.I64)
+	arr := entry.NewAlloca(types.I8)
+	arr.NElems = constant.NewInt(types.I32, 10)
+	c := entry.NewAlloca(types.I8)
+	c.Align = 16
+	// Dynamic allocas; non-constant number of elements and outside of the entry
+	// basic block.
+	dyn := entry.NewAlloca(types.I32)
+	dyn.NElems = n
+	entry.NewBr(exit)
+	exit.NewAlloca(types.I16)
+	exit.NewRet(nil)
+	if err := f.AssignIDs(); err != nil {
+		t.Fatalf("unable to assign IDs; %+v", err)
+	}
+	idents := func(allocas []*InstAlloca) string {
+		var ss []string
+		for _, alloca := range allocas {
+			ss = append(ss, alloca.Ident())
+		}
+		return strings.Join(ss, " ")
+	}
+	if want, got := "%0 %1 %2 %3 %4 %5", idents(f.Allocas()); want != got {
+		t.Errorf("allocas mismatch; expected %q, got %q", want, got)
+	}
+	size, dynamic := f.StaticStackSize(dl)
+	if want := uint64(33); size != want {
+		t.Errorf("static stack size mismatch; expected %d, got %d", want, size)
+	}
+	if want, got := "%4 %5", idents(dynamic); want != got {
+		t.Errorf("dynamic allocas mismatch; expected %q, got %q", want, got)
+	}
+}
@@ -132,6 +132,26 @@ func (Align) IsParamAttribute() {}
 // the ir.ParamAttribute interface.
 func (Dereferenceable) IsParamAttribute() {}
 
+// IsParamAttribute ensures that only parameter attributes can be assigned to
+// the ir.ParamAttribute interface.
+func (Byval) IsParamAttribute() {}
+
+// IsParamAttribute ensures that only parameter attributes can be assigned to
+// the ir.ParamAttribute interface.
+func (SRet) IsParamAttribute() {}
+
+// IsParamAttribute ensures that only parameter attributes can be assigned to
+// the ir.ParamAttribute interface.
+func (ByRef) IsParamAttribute() {}
+
+// IsParamAttribute ensures that only parameter attributes can be assigned to
+// the ir.ParamAttribute interface.
+func (Preallocated) IsParamAttribute() {}
+
+// IsParamAttribute ensures that only parameter attributes can be assigned to
+// the ir.ParamAttribute interface.
+func (InAlloca) IsParamAttribute() {}
+
 // === [ ir.ReturnAttribute ] ==================================================
 
 // IsReturnAttribute ensures that only return attributes can be assigned to
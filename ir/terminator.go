@@ -27,6 +27,7 @@ import (
 //    *ir.TermSwitch        // https://godoc.org/github.com/llir/llvm/ir#TermSwitch
 //    *ir.TermIndirectBr    // https://godoc.org/github.com/llir/llvm/ir#TermIndirectBr
 //    *ir.TermInvoke        // https://godoc.org/github.com/llir/llvm/ir#TermInvoke
+//    *ir.TermCallBr        // https://godoc.org/github.com/llir/llvm/ir#TermCallBr
 //    *ir.TermResume        // https://godoc.org/github.com/llir/llvm/ir#TermResume
 //    *ir.TermCatchSwitch   // https://godoc.org/github.com/llir/llvm/ir#TermCatchSwitch
 //    *ir.TermCatchRet      // https://godoc.org/github.com/llir/llvm/ir#TermCatchRet
@@ -37,6 +38,8 @@ type Terminator interface {
 	Def() string
 	// Succs returns the successor basic blocks of the terminator.
 	Succs() []*BasicBlock
+	// Opcode returns the opcode of the terminator.
+	Opcode() enum.Opcode
 }
 
 // --- [ ret ] -----------------------------------------------------------------
@@ -265,9 +268,9 @@ type TermIndirectBr struct {
 }
 
 // NewIndirectBr returns a new indirectbr terminator based on the given target
-// address (derived from a blockaddress constant) and set of valid target basic
-// blocks.
-func NewIndirectBr(addr constant.Constant, validTargets ...*BasicBlock) *TermIndirectBr {
+// address (derived from a blockaddress constant of type i8*) and set of valid
+// target basic blocks.
+func NewIndirectBr(addr value.Value, validTargets ...*BasicBlock) *TermIndirectBr {
 	return &TermIndirectBr{Addr: addr, ValidTargets: validTargets}
 }
 
@@ -439,6 +442,157 @@ func (term *TermInvoke) Def() string {
 	return buf.String()
 }
 
+// --- [ callbr ] --------------------------------------------------------------
+
+// TermCallBr is an LLVM IR callbr terminator.
+type TermCallBr struct {
+	// Name of local variable associated with the result.
+	LocalIdent
+	// Callee (inline assembly).
+	// TODO: specify the set of underlying types of Callee.
+	Callee value.Value
+	// Function arguments.
+	//
+	// Arg has one of the following underlying types:
+	//    value.Value
+	//    TODO: add metadata value?
+	Args []value.Value
+	// Normal control flow return point.
+	Normal *BasicBlock
+	// Other control flow return points.
+	Others []*BasicBlock
+
+	// extra.
+
+	// Type of result produced by the terminator, or function signature of the
+	// callee (as used when callee is variadic).
+	Typ types.Type
+	// Successor basic blocks of the terminator.
+	Successors []*BasicBlock
+	// (optional) Calling convention; zero if not present.
+	CallingConv enum.CallingConv
+	// (optional) Return attributes.
+	ReturnAttrs []ReturnAttribute
+	// (optional) Address space; zero if not present.
+	AddrSpace types.AddrSpace
+	// (optional) Function attributes.
+	FuncAttrs []FuncAttribute
+	// (optional) Operand bundles.
+	OperandBundles []*OperandBundle
+	// (optional) Metadata.
+	Metadata []*metadata.Attachment
+}
+
+// NewCallBr returns a new callbr terminator based on the given callee, function
+// arguments and control flow return points for normal and other execution.
+//
+// TODO: specify the set of underlying types of callee.
+func NewCallBr(callee value.Value, args []value.Value, normal *BasicBlock, others ...*BasicBlock) *TermCallBr {
+	term := &TermCallBr{Callee: callee, Args: args, Normal: normal, Others: others}
+	// Compute type.
+	term.Type()
+	return term
+}
+
+// String returns the LLVM syntax representation of the terminator as a type-
+// value pair.
+func (term *TermCallBr) String() string {
+	return fmt.Sprintf("%s %s", term.Type(), term.Ident())
+}
+
+// Type returns the type of the terminator.
+func (term *TermCallBr) Type() types.Type {
+	// Cache type if not present.
+	if term.Typ == nil {
+		t, ok := term.Callee.Type().(*types.PointerType)
+		if !ok {
+			panic(fmt.Errorf("invalid callee type; expected *types.PointerType, got %T", term.Callee.Type()))
+		}
+		sig, ok := t.ElemType.(*types.FuncType)
+		if !ok {
+			panic(fmt.Errorf("invalid callee type; expected *types.FuncType, got %T", t.ElemType))
+		}
+		if sig.Variadic {
+			term.Typ = sig
+		} else {
+			term.Typ = sig.RetType
+		}
+	}
+	if t, ok := term.Typ.(*types.FuncType); ok {
+		return t.RetType
+	}
+	return term.Typ
+}
+
+// Succs returns the successor basic blocks of the terminator.
+func (term *TermCallBr) Succs() []*BasicBlock {
+	// Cache successors if not present.
+	if term.Successors == nil {
+		term.Successors = append([]*BasicBlock{term.Normal}, term.Others...)
+	}
+	return term.Successors
+}
+
+// Def returns the LLVM syntax representation of the terminator.
+func (term *TermCallBr) Def() string {
+	// 'callbr' CallingConvopt ReturnAttrs=ReturnAttribute* AddrSpaceopt
+	// Typ=Type Callee=Value '(' Args ')' FuncAttrs=FuncAttribute*
+	// OperandBundles=('[' (OperandBundle separator ',')+ ']')? 'to' Normal=Label
+	// '[' Others=(Label separator ',')* ']' Metadata=(','
+	// MetadataAttachment)+?
+	buf := &strings.Builder{}
+	if !term.Type().Equal(types.Void) {
+		fmt.Fprintf(buf, "%s = ", term.Ident())
+	}
+	buf.WriteString("callbr")
+	if term.CallingConv != enum.CallingConvNone {
+		fmt.Fprintf(buf, " %s", callingConvString(term.CallingConv))
+	}
+	for _, attr := range term.ReturnAttrs {
+		fmt.Fprintf(buf, " %s", attr)
+	}
+	// Use function signature instead of return type for variadic functions.
+	typ := term.Type()
+	if t, ok := term.Typ.(*types.FuncType); ok {
+		if t.Variadic {
+			typ = t
+		}
+	}
+	fmt.Fprintf(buf, " %s %s(", typ, term.Callee.Ident())
+	for i, arg := range term.Args {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(arg.String())
+	}
+	buf.WriteString(")")
+	for _, attr := range term.FuncAttrs {
+		fmt.Fprintf(buf, " %s", attr)
+	}
+	if len(term.OperandBundles) > 0 {
+		buf.WriteString(" [ ")
+		for i, operandBundle := range term.OperandBundles {
+			if i != 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(operandBundle.String())
+		}
+		buf.WriteString(" ]")
+	}
+	fmt.Fprintf(buf, "\n\t\tto %s [", term.Normal)
+	for i, other := range term.Others {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(other.String())
+	}
+	buf.WriteString("]")
+	for _, md := range term.Metadata {
+		fmt.Fprintf(buf, ", %s", md)
+	}
+	return buf.String()
+}
+
 // --- [ resume ] --------------------------------------------------------------
 
 // TermResume is an LLVM IR resume terminator.
@@ -517,11 +671,12 @@ func (term *TermCatchSwitch) Type() types.Type {
 func (term *TermCatchSwitch) Succs() []*BasicBlock {
 	// Cache successors if not present.
 	if term.Successors == nil {
+		succs := make([]*BasicBlock, 0, len(term.Handlers)+1)
+		succs = append(succs, term.Handlers...)
 		if unwindTarget, ok := term.UnwindTarget.(*BasicBlock); ok {
-			term.Successors = append(term.Handlers, unwindTarget)
-		} else {
-			term.Successors = term.Handlers
+			succs = append(succs, unwindTarget)
 		}
+		term.Successors = succs
 	}
 	return term.Successors
 }
@@ -547,6 +702,18 @@ func (term *TermCatchSwitch) Def() string {
 	return buf.String()
 }
 
+// AddHandler appends the given exception handler to the catchswitch
+// terminator. Exception handlers are typically added after creating the
+// catchswitch terminator, as the catchpad instructions of the exception
+// handlers refer to the catchswitch terminator as their exception scope.
+//
+// Function.InvalidateCFG should be invoked on the parent function after adding
+// exception handlers, as the control flow of the function is modified.
+func (term *TermCatchSwitch) AddHandler(handler *BasicBlock) {
+	term.Handlers = append(term.Handlers, handler)
+	term.Successors = nil
+}
+
 // --- [ catchret ] ------------------------------------------------------------
 
 // TermCatchRet is an LLVM IR catchret terminator.
@@ -670,3 +837,37 @@ func (term *TermUnreachable) Def() string {
 	}
 	return buf.String()
 }
+
+// ### [ Helper functions ] ####################################################
+
+// resetSuccs resets the cached successor basic blocks of the given terminator.
+func resetSuccs(term Terminator) {
+	switch term := term.(type) {
+	case *TermBr:
+		term.Successors = nil
+	case *TermCondBr:
+		term.Successors = nil
+	case *TermSwitch:
+		term.Successors = nil
+	case *TermInvoke:
+		term.Successors = nil
+	case *TermCallBr:
+		term.Successors = nil
+	case *TermCatchSwitch:
+		term.Successors = nil
+	case *TermCatchRet:
+		term.Successors = nil
+	case *TermCleanupRet:
+		term.Successors = nil
+	}
+}
+
+// containsBlock reports whether the given basic block is present in blocks.
+func containsBlock(blocks []*BasicBlock, block *BasicBlock) bool {
+	for _, b := range blocks {
+		if b == block {
+			return true
+		}
+	}
+	return false
+}
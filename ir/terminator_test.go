@@ -0,0 +1,97 @@
+package ir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestCallBr(t *testing.T) {
+	// asm goto with two indirect targets.
+	sig := types.NewFunc(types.Void, types.I32)
+	asm := NewInlineAsm(types.NewPointer(sig), "jmp ${1:l}", "r,X,X")
+	f := NewFunc("f", types.Void, NewParam("x", types.I32))
+	entry := f.NewBlock("entry")
+	normal := f.NewBlock("normal")
+	other1 := f.NewBlock("other1")
+	other2 := f.NewBlock("other2")
+	term := entry.NewCallBr(asm, []value.Value{f.Params[0]}, normal, other1, other2)
+	normal.NewRet(nil)
+	other1.NewRet(nil)
+	other2.NewRet(nil)
+	want := `callbr void asm "jmp ${1:l}", "r,X,X"(i32 %x)
+		to label %normal [label %other1, label %other2]`
+	if got := term.Def(); want != got {
+		t.Errorf("callbr terminator mismatch; expected `%v`, got `%v`", want, got)
+	}
+	var succs []string
+	for _, succ := range term.Succs() {
+		succs = append(succs, succ.Name())
+	}
+	if want, got := "normal other1 other2", strings.Join(succs, " "); want != got {
+		t.Errorf("successors mismatch; expected %q, got %q", want, got)
+	}
+}
+
+func TestFunclets(t *testing.T) {
+	m := NewModule()
+	i8Ptr := types.NewPointer(types.I8)
+	personality := m.NewFunc("__CxxFrameHandler3", types.I32)
+	personality.Sig.Variadic = true
+	g := m.NewFunc("g", types.Void)
+	f := m.NewFunc("f", types.Void)
+	f.SetPersonality(constant.NewBitCast(personality, i8Ptr))
+	entry := f.NewBlock("entry")
+	dispatch := f.NewBlock("dispatch")
+	handler := f.NewBlock("handler")
+	cleanup := f.NewBlock("cleanup")
+	exit := f.NewBlock("exit")
+	// try { g() } catch (...) { g() }
+	entry.NewInvoke(g, nil, exit, dispatch)
+	cs := dispatch.NewCatchSwitch(constant.None, nil, UnwindToCaller{})
+	cs.SetName("cs")
+	cs.AddHandler(handler)
+	cp := handler.NewCatchPad(cs, constant.NewNull(i8Ptr), constant.NewInt(types.I32, 64), constant.NewNull(i8Ptr))
+	cp.SetName("cp")
+	call := handler.NewInvoke(g, nil, exit, cleanup)
+	call.OperandBundles = []*OperandBundle{NewOperandBundle("funclet", cp)}
+	// Cleanup funclet nested within the catch funclet.
+	cl := cleanup.NewCleanupPad(cp)
+	cl.SetName("cl")
+	cleanup.NewCleanupRet(cl, UnwindToCaller{})
+	exit.NewRet(nil)
+	f.InvalidateCFG()
+	const want = `define void @f() personality i8* bitcast (i32 (...)* @__CxxFrameHandler3 to i8*) {
+entry:
+	invoke void @g()
+		to label %exit unwind label %dispatch
+
+dispatch:
+	%cs = catchswitch within none [label %handler] unwind to caller
+
+handler:
+	%cp = catchpad within %cs [i8* null, i32 64, i8* null]
+	invoke void @g() [ "funclet"(token %cp) ]
+		to label %exit unwind label %cleanup
+
+cleanup:
+	%cl = cleanuppad within %cp []
+	cleanupret from %cl unwind to caller
+
+exit:
+	ret void
+}`
+	if got := f.Def(); got != want {
+		t.Errorf("function mismatch; expected %q, got %q", want, got)
+	}
+	// Exception handlers are successors of the catchswitch terminator.
+	if succs := cs.Succs(); len(succs) != 1 || succs[0] != handler {
+		t.Errorf("successors mismatch of catchswitch; expected [%v], got %v", handler, succs)
+	}
+	if preds := handler.Preds(); len(preds) != 1 || preds[0] != dispatch {
+		t.Errorf("predecessors mismatch of exception handler; expected [%v], got %v", dispatch, preds)
+	}
+}
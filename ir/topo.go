@@ -0,0 +1,107 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
+)
+
+// === [ Topological sort ] ====================================================
+
+// TopoSort reorders the instructions of the basic block so that the definition
+// of each value precedes its uses within the basic block. The relative order of
+// instructions is otherwise preserved where possible; in particular, phi
+// instructions are kept first, instructions which access memory or have side
+// effects are kept in their original relative order, and the terminator is
+// kept last.
+//
+// An error is returned if the instructions of the basic block contain a cycle
+// of uses, in which case the basic block is left unchanged.
+func (block *BasicBlock) TopoSort() error {
+	// Index instructions defined within the basic block.
+	index := make(map[value.Value]int)
+	for i, inst := range block.Insts {
+		if v, ok := inst.(value.Value); ok {
+			index[v] = i
+		}
+	}
+	// Compute the dependencies of each instruction; i.e. the instructions of
+	// the basic block which must precede it.
+	deps := make([][]int, len(block.Insts))
+	prevOrdered := -1
+	for i, inst := range block.Insts {
+		if _, ok := inst.(*InstPhi); ok {
+			// Phi instructions use values of predecessor basic blocks; thus
+			// they depend on no instructions of the basic block.
+			continue
+		}
+		for _, op := range operands(inst) {
+			if j, ok := index[*op]; ok {
+				deps[i] = append(deps[i], j)
+			}
+		}
+		if isOrdered(inst) {
+			if prevOrdered != -1 {
+				deps[i] = append(deps[i], prevOrdered)
+			}
+			prevOrdered = i
+		}
+	}
+	// Place phi instructions first.
+	placed := make([]bool, len(block.Insts))
+	insts := make([]Instruction, 0, len(block.Insts))
+	for i, inst := range block.Insts {
+		if _, ok := inst.(*InstPhi); ok {
+			placed[i] = true
+			insts = append(insts, inst)
+		}
+	}
+	// Repeatedly place the first remaining instruction whose dependencies have
+	// all been placed.
+	for len(insts) < len(block.Insts) {
+		next := -1
+		for i := range block.Insts {
+			if placed[i] {
+				continue
+			}
+			if ready(deps[i], placed) {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			for i, inst := range block.Insts {
+				if !placed[i] {
+					return errors.Errorf("cycle of uses in basic block %s involving instruction %q", block.Ident(), inst.Def())
+				}
+			}
+		}
+		placed[next] = true
+		insts = append(insts, block.Insts[next])
+	}
+	block.Insts = insts
+	return nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// ready reports whether all of the given dependencies have been placed.
+func ready(deps []int, placed []bool) bool {
+	for _, dep := range deps {
+		if !placed[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// isOrdered reports whether the given instruction accesses memory or has side
+// effects, and must thus not be reordered with respect to other such
+// instructions.
+func isOrdered(inst Instruction) bool {
+	switch inst.(type) {
+	case *InstLoad, *InstCall:
+		return true
+	default:
+		return !isRemovable(inst)
+	}
+}
@@ -0,0 +1,69 @@
+package ir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestTopoSort(t *testing.T) {
+	x := NewParam("x", types.I32)
+	p := NewParam("p", types.I32Ptr)
+	one := constant.NewInt(types.I32, 1)
+	d := NewMul(x, constant.NewInt(types.I32, 2))
+	d.SetName("d")
+	e := NewAdd(d, one)
+	e.SetName("e")
+	l := NewLoad(p)
+	l.SetName("l")
+	f := NewAdd(l, d)
+	f.SetName("f")
+	block := NewBlock("entry")
+	block.Insts = []Instruction{NewStore(e, p), e, d, l, f, NewStore(f, p)}
+	block.NewRet(nil)
+	if err := block.TopoSort(); err != nil {
+		t.Fatalf("unable to sort instructions; %v", err)
+	}
+	var names []string
+	for _, inst := range block.Insts {
+		if v, ok := inst.(value.Named); ok {
+			names = append(names, v.Name())
+		} else {
+			names = append(names, "store")
+		}
+	}
+	if want, got := "d e store l f store", strings.Join(names, " "); want != got {
+		t.Errorf("instructions mismatch; expected %q, got %q", want, got)
+	}
+	// Cycle of uses.
+	a := NewAdd(one, one)
+	a.SetName("a")
+	b := NewAdd(a, one)
+	b.SetName("b")
+	a.X = b
+	block = NewBlock("cycle")
+	block.Insts = []Instruction{b, a}
+	if err := block.TopoSort(); err == nil {
+		t.Errorf("expected error for cycle of uses, got nil")
+	}
+	if block.Insts[0] != b || block.Insts[1] != a {
+		t.Errorf("instructions of basic block with cycle of uses reordered")
+	}
+	// Use as argument with parameter attributes.
+	usei := NewFunc("usei", types.Void, NewParam("", types.I32))
+	c := NewAdd(x, one)
+	c.SetName("c")
+	call := NewCall(usei, NewArg(c, enum.ParamAttrSignExt))
+	block = NewBlock("arg")
+	block.Insts = []Instruction{call, c}
+	if err := block.TopoSort(); err != nil {
+		t.Fatalf("unable to sort instructions; %v", err)
+	}
+	if block.Insts[0] != c || block.Insts[1] != call {
+		t.Errorf("instructions mismatch; expected definition of %s before call", c.Ident())
+	}
+}
@@ -85,6 +85,12 @@ func IsVector(t Type) bool {
 	return ok
 }
 
+// IsScalableVector reports whether the given type is a scalable vector type.
+func IsScalableVector(t Type) bool {
+	vt, ok := t.(*VectorType)
+	return ok && vt.Scalable
+}
+
 // IsLabel reports whether the given type is a label type.
 func IsLabel(t Type) bool {
 	_, ok := t.(*LabelType)
@@ -116,6 +122,12 @@ func IsStruct(t Type) bool {
 }
 
 // Equal reports whether t and u are of equal type.
+//
+// Types are compared structurally, except for identified (named) struct types
+// which are uniqued by type names; i.e. two literal struct types with equal
+// fields are equal, while two identified struct types are equal only if they
+// share the same type name. As recursive types may only be defined through
+// identified struct types, the comparison of recursive types terminates.
 func Equal(t, u Type) bool {
 	return t.Equal(u)
 }
@@ -492,7 +504,10 @@ func (a AddrSpace) String() string {
 type VectorType struct {
 	// Type name; or empty if not present.
 	TypeName string
-	// Vector length.
+	// Scalable vector length; the vector holds a runtime multiple (vscale) of
+	// Len elements.
+	Scalable bool
+	// Vector length; or minimum vector length if scalable.
 	Len uint64
 	// Element type.
 	ElemType Type
@@ -507,10 +522,20 @@ func NewVector(len uint64, elemType Type) *VectorType {
 	}
 }
 
+// NewScalableVector returns a new scalable vector type based on the given
+// minimum vector length and element type.
+func NewScalableVector(len uint64, elemType Type) *VectorType {
+	return &VectorType{
+		Scalable: true,
+		Len:      len,
+		ElemType: elemType,
+	}
+}
+
 // Equal reports whether t and u are of equal type.
 func (t *VectorType) Equal(u Type) bool {
 	if u, ok := u.(*VectorType); ok {
-		if t.Len != u.Len {
+		if t.Scalable != u.Scalable || t.Len != u.Len {
 			return false
 		}
 		return t.ElemType.Equal(u.ElemType)
@@ -529,6 +554,10 @@ func (t *VectorType) String() string {
 // Def returns the LLVM syntax representation of the definition of the type.
 func (t *VectorType) Def() string {
 	// '<' Len=UintLit 'x' Elem=Type '>'
+	// '<' 'vscale' 'x' Len=UintLit 'x' Elem=Type '>'
+	if t.Scalable {
+		return fmt.Sprintf("<vscale x %d x %s>", t.Len, t.ElemType)
+	}
 	return fmt.Sprintf("<%d x %s>", t.Len, t.ElemType)
 }
 
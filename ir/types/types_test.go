@@ -179,6 +179,24 @@ func TestIsVector(t *testing.T) {
 	}
 }
 
+func TestIsScalableVector(t *testing.T) {
+	golden := []struct {
+		t    Type
+		want bool
+	}{
+		{t: &VectorType{Scalable: true, Len: 4, ElemType: I32}, want: true},
+		{t: NewScalableVector(4, I32), want: true},
+		{t: NewVector(4, I32), want: false},
+		{t: I32, want: false},
+	}
+	for _, g := range golden {
+		got := IsScalableVector(g.t)
+		if g.want != got {
+			t.Errorf("check if `%s` is a scalable vector type mismatch; expected %t, got %t", g.t, g.want, got)
+		}
+	}
+}
+
 func TestIsLabel(t *testing.T) {
 	golden := []struct {
 		t    Type
@@ -293,6 +311,8 @@ func TestEqual(t *testing.T) {
 		{t: NewVector(5, I8), u: &VectorType{Len: 5, ElemType: I8}, want: true},
 		{t: NewVector(5, I8), u: NewVector(3, I8), want: false},
 		{t: NewVector(5, I8), u: I8, want: false},
+		{t: NewScalableVector(4, I8), u: &VectorType{Scalable: true, Len: 4, ElemType: I8}, want: true},
+		{t: NewScalableVector(4, I8), u: NewVector(4, I8), want: false},
 		{t: Label, u: &LabelType{}, want: true},
 		{t: Label, u: I8, want: false},
 		{t: Token, u: &TokenType{}, want: true},
@@ -395,3 +415,42 @@ var (
 	_ Type = (*ArrayType)(nil)
 	_ Type = (*StructType)(nil)
 )
+
+func TestEqualRecursive(t *testing.T) {
+	// %list = type { i32, %list* }
+	list := &StructType{TypeName: "list"}
+	list.Fields = []Type{I32, NewPointer(list)}
+	// Distinct instance of the same type definition; e.g. from another module.
+	list2 := &StructType{TypeName: "list"}
+	list2.Fields = []Type{I32, NewPointer(list2)}
+	// %node = type { i32, %node* }
+	node := &StructType{TypeName: "node"}
+	node.Fields = []Type{I32, NewPointer(node)}
+	golden := []struct {
+		t    Type
+		u    Type
+		want bool
+	}{
+		// Recursive identified struct types.
+		{t: list, u: list, want: true},
+		{t: list, u: list2, want: true},
+		{t: list, u: node, want: false},
+		{t: NewPointer(list), u: NewPointer(list2), want: true},
+		{t: NewPointer(list), u: NewPointer(node), want: false},
+		// Literal struct types containing recursive identified struct types.
+		{t: NewStruct(NewPointer(list), I64), u: NewStruct(NewPointer(list2), I64), want: true},
+		{t: NewStruct(NewPointer(list), I64), u: NewStruct(NewPointer(node), I64), want: false},
+		// Identical anonymous tuples.
+		{t: NewStruct(I32, Double), u: NewStruct(I32, Double), want: true},
+		{t: NewStruct(I32, NewStruct(I8, I8)), u: NewStruct(I32, NewStruct(I8, I8)), want: true},
+		{t: NewStruct(I32, NewStruct(I8, I8)), u: NewStruct(I32, NewStruct(I8, I16)), want: false},
+		// Anonymous tuple and identified struct type with equal fields.
+		{t: NewStruct(I32, NewPointer(list)), u: list, want: false},
+	}
+	for _, g := range golden {
+		got := Equal(g.t, g.u)
+		if g.want != got {
+			t.Errorf("equality mismatch between `%s` and `%s`; expected %t, got %t", g.t, g.u, g.want, got)
+		}
+	}
+}
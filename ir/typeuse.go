@@ -0,0 +1,101 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Type uses ] ===========================================================
+
+// TypesUsedBy returns the types referenced by the given function, in order of
+// first occurrence. The types referenced by a function are the types of its
+// signature and typed parameter attributes (e.g. byval(%T)), the types of its
+// instructions and terminators and their operands (including the operands of
+// constant expressions), and transitively the types contained within these
+// types; e.g. the element types of pointer types, and the field types of named
+// struct types.
+//
+// Types are deduplicated by their string representation; i.e. named types by
+// name and literal types by structure.
+func TypesUsedBy(f *Function) []types.Type {
+	c := &typeCollector{seen: make(map[string]bool)}
+	c.addType(f.Sig)
+	for _, param := range f.Params {
+		for _, attr := range param.Attrs {
+			if t := paramAttrType(attr); t != nil {
+				c.addType(t)
+			}
+		}
+	}
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			c.addInst(inst)
+		}
+		if block.Term != nil {
+			c.addInst(block.Term)
+		}
+	}
+	return c.types
+}
+
+// typeCollector collects the types referenced by a function.
+type typeCollector struct {
+	// Types in order of first occurrence.
+	types []types.Type
+	// seen tracks visited types, keyed by string representation.
+	seen map[string]bool
+}
+
+// addInst adds the types of the given instruction or terminator and its
+// operands.
+func (c *typeCollector) addInst(inst interface{}) {
+	if v, ok := inst.(value.Value); ok {
+		c.addType(v.Type())
+	}
+	for _, op := range operands(inst) {
+		if *op != nil {
+			c.addValue(*op)
+		}
+	}
+}
+
+// addValue adds the type of the given value and, if constant, the types of its
+// constant operands.
+func (c *typeCollector) addValue(v value.Value) {
+	c.addType(v.Type())
+	if k, ok := v.(constant.Constant); ok {
+		for _, op := range constantOperands(k) {
+			if *op != nil {
+				c.addValue(*op)
+			}
+		}
+	}
+}
+
+// addType adds the given type and the types contained within it.
+func (c *typeCollector) addType(t types.Type) {
+	key := t.String()
+	if c.seen[key] {
+		return
+	}
+	c.seen[key] = true
+	c.types = append(c.types, t)
+	switch t := t.(type) {
+	case *types.FuncType:
+		c.addType(t.RetType)
+		for _, param := range t.Params {
+			c.addType(param)
+		}
+	case *types.PointerType:
+		c.addType(t.ElemType)
+	case *types.VectorType:
+		c.addType(t.ElemType)
+	case *types.ArrayType:
+		c.addType(t.ElemType)
+	case *types.StructType:
+		for _, field := range t.Fields {
+			c.addType(field)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package ir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestTypesUsedBy(t *testing.T) {
+	m := NewModule()
+	vec := types.NewVector(4, types.Float)
+	pair := m.NewTypeDef("pair", types.NewStruct(types.I32, vec))
+	p := NewParam("p", types.NewPointer(pair))
+	f := m.NewFunc("f", vec, p)
+	entry := f.NewBlock("entry")
+	zero := constant.NewInt(types.I32, 0)
+	one := constant.NewInt(types.I32, 1)
+	elem := entry.NewGetElementPtr(p, zero, one)
+	v := entry.NewLoad(elem)
+	entry.NewRet(v)
+	var got []string
+	for _, typ := range TypesUsedBy(f) {
+		got = append(got, typ.String())
+	}
+	want := []string{"<4 x float> (%pair*)", "<4 x float>", "float", "%pair*", "%pair", "i32", "<4 x float>*"}
+	if strings.Join(got, "; ") != strings.Join(want, "; ") {
+		t.Errorf("types mismatch; expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,81 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Constant uses ] =======================================================
+
+// rewriteConstantUses invokes f for each use of a constant in the given module
+// (e.g. in initializers of global variables, and operands of instructions and
+// constant expressions). If f reports true, the use is replaced by the returned
+// constant; otherwise, the constants used by the constant are visited in turn.
+func rewriteConstantUses(m *Module, f func(c constant.Constant) (constant.Constant, bool)) {
+	for _, g := range m.Globals {
+		if g.Init != nil {
+			rewriteConstant(&g.Init, f)
+		}
+	}
+	for _, alias := range m.Aliases {
+		rewriteConstant(&alias.Aliasee, f)
+	}
+	for _, ifunc := range m.IFuncs {
+		rewriteConstant(&ifunc.Resolver, f)
+	}
+	for _, fn := range m.Funcs {
+		for _, c := range []*constant.Constant{&fn.Prefix, &fn.Prologue, &fn.Personality} {
+			if *c != nil {
+				rewriteConstant(c, f)
+			}
+		}
+		for _, block := range fn.Blocks {
+			for _, inst := range block.Insts {
+				for _, op := range operands(inst) {
+					rewriteValue(op, f)
+				}
+			}
+			if block.Term != nil {
+				for _, op := range operands(block.Term) {
+					rewriteValue(op, f)
+				}
+				if term, ok := block.Term.(*TermSwitch); ok {
+					for _, c := range term.Cases {
+						rewriteConstant(&c.X, f)
+					}
+				}
+			}
+		}
+	}
+}
+
+// ### [ Helper functions ] ####################################################
+
+// rewriteValue rewrites the given value operand, or the constants used by it,
+// using f.
+func rewriteValue(op *value.Value, f func(c constant.Constant) (constant.Constant, bool)) {
+	switch v := (*op).(type) {
+	case *Arg:
+		rewriteValue(&v.Value, f)
+	case constant.Constant:
+		if new, ok := f(v); ok {
+			*op = new
+			return
+		}
+		for _, c := range constantOperands(v) {
+			rewriteConstant(c, f)
+		}
+	}
+}
+
+// rewriteConstant rewrites the given constant operand, or the constants used by
+// it, using f.
+func rewriteConstant(op *constant.Constant, f func(c constant.Constant) (constant.Constant, bool)) {
+	if new, ok := f(*op); ok {
+		*op = new
+		return
+	}
+	for _, c := range constantOperands(*op) {
+		rewriteConstant(c, f)
+	}
+}
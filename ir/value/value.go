@@ -36,6 +36,7 @@ type Value interface {
 //    TODO: add named metadata value?
 //    ir.Instruction        // https://godoc.org/github.com/llir/llvm/ir#Instruction (except store and fence)
 //    *ir.TermInvoke        // https://godoc.org/github.com/llir/llvm/ir#TermInvoke
+//    *ir.TermCallBr        // https://godoc.org/github.com/llir/llvm/ir#TermCallBr
 //    *ir.TermCatchSwitch   // https://godoc.org/github.com/llir/llvm/ir#TermCatchSwitch (token result used by catchpad)
 type Named interface {
 	Value
@@ -44,3 +45,13 @@ type Named interface {
 	// SetName sets the name of the value.
 	SetName(name string)
 }
+
+// IsConstant reports whether the given value is a constant; i.e. whether it
+// implements the constant.Constant interface. Note, global variables and
+// functions are constants, as their addresses are known at link time.
+func IsConstant(v Value) bool {
+	// Use an interface query, as the constant package imports the value
+	// package.
+	_, ok := v.(interface{ IsConstant() })
+	return ok
+}
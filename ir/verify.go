@@ -0,0 +1,481 @@
+package ir
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/llir/llvm/internal/enc"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Verifier ] ============================================================
+
+// VerifyError is a list of rule violations found by the verifier.
+type VerifyError struct {
+	// Violations in order of occurrence.
+	Violations []string
+}
+
+// Error returns the string representation of the verification error.
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf("invalid LLVM IR; %s", strings.Join(e.Violations, "; "))
+}
+
+// Verify verifies that the module is well-formed. The returned error is of
+// type *VerifyError if the module violates any rule of LLVM IR; and nil
+// otherwise.
+func (m *Module) Verify() error {
+	v := &verifier{}
+	v.verifyComdats(m)
+	for _, f := range m.Funcs {
+		v.verifyFunc(f)
+	}
+	return v.err()
+}
+
+// Verify verifies that the function is well-formed. The returned error is of
+// type *VerifyError if the function violates any rule of LLVM IR; and nil
+// otherwise.
+func (f *Function) Verify() error {
+	v := &verifier{}
+	v.verifyFunc(f)
+	return v.err()
+}
+
+// verifier records rule violations of LLVM IR.
+type verifier struct {
+	// Violations in order of occurrence.
+	violations []string
+}
+
+// errorf records a rule violation based on the given format specifier and
+// arguments.
+func (v *verifier) errorf(format string, args ...interface{}) {
+	v.violations = append(v.violations, fmt.Sprintf(format, args...))
+}
+
+// err returns the rule violations recorded by the verifier as an error; or nil
+// if no violations were recorded.
+func (v *verifier) err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &VerifyError{Violations: v.violations}
+}
+
+// verifyFunc verifies the given function.
+func (v *verifier) verifyFunc(f *Function) {
+	v.verifyRet(f)
+	v.verifyMaskedIntrinsics(f)
+	v.verifySwiftError(f)
+	v.verifyMustTail(f)
+	v.verifyCallBr(f)
+}
+
+// --- [ comdat ] --------------------------------------------------------------
+
+// verifyComdats verifies that the comdats referenced by global variables and
+// functions of the given module are defined in the module.
+func (v *verifier) verifyComdats(m *Module) {
+	defined := make(map[string]bool)
+	for _, def := range m.ComdatDefs {
+		defined[def.Name] = true
+	}
+	for _, g := range m.Globals {
+		if g.Comdat != nil && !defined[g.Comdat.Name] {
+			v.errorf("%s: reference to undefined comdat %s", g.Ident(), enc.Comdat(g.Comdat.Name))
+		}
+	}
+	for _, f := range m.Funcs {
+		if f.Comdat != nil && !defined[f.Comdat.Name] {
+			v.errorf("%s: reference to undefined comdat %s", f.Ident(), enc.Comdat(f.Comdat.Name))
+		}
+	}
+}
+
+// --- [ ret ] -----------------------------------------------------------------
+
+// verifyRet verifies that the types of values returned by ret terminators of
+// the given function match the return type of the function.
+func (v *verifier) verifyRet(f *Function) {
+	retType := f.Sig.RetType
+	for _, block := range f.Blocks {
+		term, ok := block.Term.(*TermRet)
+		if !ok {
+			continue
+		}
+		switch {
+		case term.X == nil:
+			if !retType.Equal(types.Void) {
+				v.errorf("%s: function returning %s has `%s` terminator without return value", f.Ident(), retType, term.Def())
+			}
+		case !term.X.Type().Equal(retType):
+			v.errorf("%s: return type mismatch; expected %s, got %s in `%s`", f.Ident(), retType, term.X.Type(), term.Def())
+		}
+	}
+}
+
+// --- [ masked gather and scatter ] -------------------------------------------
+
+// verifyMaskedIntrinsics verifies the types of the operands of calls to the
+// llvm.masked.gather and llvm.masked.scatter intrinsics in the given function.
+//
+// A masked gather takes a vector of pointers, an i32 alignment, a vector of i1
+// mask bits and a pass-through vector, and returns a vector of the pointed-to
+// element type. A masked scatter takes a vector of values, a vector of
+// pointers, an i32 alignment and a vector of i1 mask bits, and returns void.
+//
+// ref: https://llvm.org/docs/LangRef.html#masked-vector-gather-and-scatter-intrinsics
+func (v *verifier) verifyMaskedIntrinsics(f *Function) {
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			call, ok := inst.(*InstCall)
+			if !ok {
+				continue
+			}
+			callee, ok := call.Callee.(*Function)
+			if !ok {
+				continue
+			}
+			var valType types.Type
+			var ops []value.Value
+			switch name := callee.Name(); {
+			case strings.HasPrefix(name, "llvm.masked.gather."):
+				valType = call.Type()
+				if len(call.Args) == 4 {
+					ops = []value.Value{call.Args[0], call.Args[1], call.Args[2], call.Args[3]}
+				}
+			case strings.HasPrefix(name, "llvm.masked.scatter."):
+				if !call.Type().Equal(types.Void) {
+					v.errorf("%s: invalid return type %s of masked scatter in `%s`; expected void", f.Ident(), call.Type(), call.Def())
+					continue
+				}
+				if len(call.Args) == 4 {
+					valType = call.Args[0].Type()
+					ops = []value.Value{call.Args[1], call.Args[2], call.Args[3]}
+				}
+			default:
+				continue
+			}
+			if ops == nil {
+				v.errorf("%s: invalid number of arguments to %s in `%s`; expected 4, got %d", f.Ident(), callee.Ident(), call.Def(), len(call.Args))
+				continue
+			}
+			if msg := checkMaskedOperands(valType, ops); len(msg) > 0 {
+				v.errorf("%s: %s in `%s`", f.Ident(), msg, call.Def())
+			}
+		}
+	}
+}
+
+// --- [ swifterror ] ----------------------------------------------------------
+
+// verifySwiftError verifies the uses of swifterror values in the given
+// function.
+//
+// A swifterror value is either a swifterror parameter or a swifterror alloca.
+// Swifterror values may only be used as the source operand of load
+// instructions, the destination operand of store instructions, or as
+// swifterror arguments of call and invoke instructions.
+//
+// ref: https://llvm.org/docs/LangRef.html#parameter-attributes
+func (v *verifier) verifySwiftError(f *Function) {
+	// Locate swifterror values.
+	swiftErrs := make(map[value.Value]bool)
+	for _, param := range f.Params {
+		if hasSwiftErrorAttr(param.Attrs) {
+			swiftErrs[param] = true
+		}
+	}
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			if inst, ok := inst.(*InstAlloca); ok && inst.SwiftError {
+				swiftErrs[inst] = true
+			}
+		}
+	}
+	if len(swiftErrs) == 0 {
+		return
+	}
+	// Verify uses of swifterror values.
+	check := func(inst interface{ Def() string }, op value.Value) {
+		if arg, ok := op.(*Arg); ok {
+			op = arg.Value
+		}
+		if swiftErrs[op] {
+			v.errorf("%s: swifterror value %s can only be loaded and stored from, or used as a swifterror argument; invalid use in `%s`", f.Ident(), op.Ident(), inst.Def())
+		}
+	}
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			switch inst := inst.(type) {
+			case *InstLoad:
+				// Source operand may be a swifterror value.
+			case *InstStore:
+				// Destination operand may be a swifterror value.
+				check(inst, inst.Src)
+			case *InstCall:
+				check(inst, inst.Callee)
+				for i, arg := range inst.Args {
+					if !isSwiftErrorArg(inst.Callee, i, arg) {
+						check(inst, arg)
+					}
+				}
+				for _, op := range bundleOperands(inst.OperandBundles) {
+					check(inst, *op)
+				}
+			default:
+				for _, op := range operands(inst) {
+					check(inst, *op)
+				}
+			}
+		}
+		switch term := block.Term.(type) {
+		case nil:
+			// Skip missing terminator.
+		case *TermInvoke:
+			check(term, term.Invokee)
+			for i, arg := range term.Args {
+				if !isSwiftErrorArg(term.Invokee, i, arg) {
+					check(term, arg)
+				}
+			}
+			for _, op := range bundleOperands(term.OperandBundles) {
+				check(term, *op)
+			}
+		default:
+			for _, op := range operands(term) {
+				check(term, *op)
+			}
+		}
+	}
+}
+
+// --- [ musttail ] ------------------------------------------------------------
+
+// verifyMustTail verifies the musttail calls of the given function.
+//
+// A musttail call must be followed by a ret terminator, optionally through a
+// bitcast of the result of the call, which returns the result of the call (or
+// void). The signature and calling convention of the callee must match those
+// of the caller; pointer types of parameters and return types may however
+// differ in element type.
+//
+// ref: https://llvm.org/docs/LangRef.html#call-instruction
+func (v *verifier) verifyMustTail(f *Function) {
+	for _, block := range f.Blocks {
+		for i, inst := range block.Insts {
+			call, ok := inst.(*InstCall)
+			if !ok || call.Tail != enum.TailMustTail {
+				continue
+			}
+			// Verify that the call is followed by a ret terminator.
+			var result value.Value = call
+			next := block.Insts[i+1:]
+			if len(next) > 0 {
+				if conv, ok := next[0].(*InstBitCast); ok && conv.From == call {
+					result = conv
+					next = next[1:]
+				}
+			}
+			ret, ok := block.Term.(*TermRet)
+			if len(next) > 0 || !ok {
+				v.errorf("%s: musttail call must precede a ret terminator; invalid `%s`", f.Ident(), call.Def())
+				continue
+			}
+			if ret.X != nil && ret.X != result {
+				v.errorf("%s: musttail call result must be returned; invalid `%s`", f.Ident(), ret.Def())
+			}
+			// Verify that the signatures of the caller and callee match.
+			sig, ok := calleeSig(call.Callee)
+			if !ok {
+				continue
+			}
+			if !prototypesMatch(sig, f.Sig) {
+				v.errorf("%s: musttail call signature mismatch; expected %s, got %s in `%s`", f.Ident(), f.Sig, sig, call.Def())
+			}
+			if call.CallingConv != f.CallingConv {
+				v.errorf("%s: musttail call calling convention mismatch; expected %s, got %s in `%s`", f.Ident(), callingConvString(f.CallingConv), callingConvString(call.CallingConv), call.Def())
+			}
+		}
+	}
+}
+
+// --- [ callbr ] --------------------------------------------------------------
+
+// verifyCallBr verifies the callbr terminators of the given function.
+//
+// The callee of a callbr terminator must be inline assembly (i.e. asm goto),
+// and its destinations (both the fallthrough and the indirect destinations)
+// must be distinct basic blocks of the function. The result of a callbr
+// terminator is only available on the fallthrough edge, and may thus not be
+// used by the indirect destinations.
+//
+// ref: https://llvm.org/docs/LangRef.html#callbr-instruction
+func (v *verifier) verifyCallBr(f *Function) {
+	blocks := make(map[*BasicBlock]bool)
+	for _, block := range f.Blocks {
+		blocks[block] = true
+	}
+	for _, block := range f.Blocks {
+		term, ok := block.Term.(*TermCallBr)
+		if !ok {
+			continue
+		}
+		if _, ok := term.Callee.(*InlineAsm); !ok {
+			v.errorf("%s: callbr callee must be inline assembly; invalid callee %s of callbr terminator in basic block %s", f.Ident(), term.Callee.Ident(), block.Ident())
+		}
+		seen := make(map[*BasicBlock]bool)
+		for _, dest := range append([]*BasicBlock{term.Normal}, term.Others...) {
+			switch {
+			case !blocks[dest]:
+				v.errorf("%s: callbr destination %s is not a basic block of the function; invalid callbr terminator in basic block %s", f.Ident(), dest.Ident(), block.Ident())
+			case seen[dest]:
+				v.errorf("%s: duplicate callbr destination %s; invalid callbr terminator in basic block %s", f.Ident(), dest.Ident(), block.Ident())
+			}
+			seen[dest] = true
+		}
+		if isVoidValue(term) {
+			continue
+		}
+		// Verify that the result is not used by the indirect destinations.
+		for _, dest := range term.Others {
+			if dest == term.Normal || !blocks[dest] {
+				continue
+			}
+			for _, inst := range dest.Insts {
+				if phi, ok := inst.(*InstPhi); ok {
+					for _, inc := range phi.Incs {
+						if inc.Pred == block && inc.X == term {
+							v.errorf("%s: callbr result %s used on indirect edge from basic block %s; invalid `%s` in basic block %s", f.Ident(), term.Ident(), block.Ident(), phi.Def(), dest.Ident())
+						}
+					}
+					continue
+				}
+				if usesValue(inst, term) {
+					v.errorf("%s: callbr result %s used in indirect destination; invalid `%s` in basic block %s", f.Ident(), term.Ident(), inst.Def(), dest.Ident())
+				}
+			}
+			if dest.Term != nil && usesValue(dest.Term, term) {
+				v.errorf("%s: callbr result %s used in indirect destination; invalid `%s` in basic block %s", f.Ident(), term.Ident(), dest.Term.Def(), dest.Ident())
+			}
+		}
+	}
+}
+
+// ### [ Helper functions ] ####################################################
+
+// isSwiftErrorArg reports whether the i-th argument of a call to the given
+// callee is passed as a swifterror argument, either through the parameter
+// attributes of the argument or of the corresponding parameter of the callee.
+func isSwiftErrorArg(callee value.Value, i int, arg value.Value) bool {
+	if arg, ok := arg.(*Arg); ok && hasSwiftErrorAttr(arg.Attrs) {
+		return true
+	}
+	if f, ok := callee.(*Function); ok && i < len(f.Params) {
+		return hasSwiftErrorAttr(f.Params[i].Attrs)
+	}
+	return false
+}
+
+// hasSwiftErrorAttr reports whether the given parameter attributes contain the
+// swifterror attribute.
+func hasSwiftErrorAttr(attrs []ParamAttribute) bool {
+	for _, attr := range attrs {
+		if attr == enum.ParamAttrSwiftError {
+			return true
+		}
+	}
+	return false
+}
+
+// checkMaskedOperands checks the operands of a masked gather or scatter
+// intrinsic, where valType is the vector type of the gathered or scattered
+// values, and ops holds the vector of pointers, the alignment, the mask and
+// optionally the pass-through value. An empty string is returned if valid; and
+// a description of the violation otherwise.
+func checkMaskedOperands(valType types.Type, ops []value.Value) string {
+	vt, ok := valType.(*types.VectorType)
+	if !ok {
+		return fmt.Sprintf("invalid value type %s of masked gather or scatter; expected vector type", valType)
+	}
+	ptrsType := types.NewVector(vt.Len, types.NewPointer(vt.ElemType))
+	if ptrs := ops[0].Type(); !isVectorOfPointersTo(ptrs, vt) {
+		return fmt.Sprintf("invalid pointers type %s of masked gather or scatter; expected %s", ptrs, ptrsType)
+	}
+	if align := ops[1].Type(); !align.Equal(types.I32) {
+		return fmt.Sprintf("invalid alignment type %s of masked gather or scatter; expected i32", align)
+	}
+	maskType := types.NewVector(vt.Len, types.I1)
+	if mask := ops[2].Type(); !mask.Equal(maskType) {
+		return fmt.Sprintf("invalid mask type %s of masked gather or scatter; expected %s", mask, maskType)
+	}
+	if len(ops) > 3 {
+		if passthru := ops[3].Type(); !passthru.Equal(valType) {
+			return fmt.Sprintf("invalid pass-through type %s of masked gather; expected %s", passthru, valType)
+		}
+	}
+	return ""
+}
+
+// isVectorOfPointersTo reports whether t is a vector of pointers to the
+// elements of the given vector type, with matching vector length. The address
+// space of the pointers is not considered.
+func isVectorOfPointersTo(t types.Type, vt *types.VectorType) bool {
+	pt, ok := t.(*types.VectorType)
+	if !ok || pt.Len != vt.Len {
+		return false
+	}
+	ptr, ok := pt.ElemType.(*types.PointerType)
+	return ok && ptr.ElemType.Equal(vt.ElemType)
+}
+
+// calleeSig returns the function signature of the given callee, and a boolean
+// indicating success.
+func calleeSig(callee value.Value) (*types.FuncType, bool) {
+	t, ok := callee.Type().(*types.PointerType)
+	if !ok {
+		return nil, false
+	}
+	sig, ok := t.ElemType.(*types.FuncType)
+	return sig, ok
+}
+
+// prototypesMatch reports whether the given function signatures match, as
+// required for musttail calls; pointer types of parameters and return types
+// match if in the same address space.
+func prototypesMatch(t, u *types.FuncType) bool {
+	if len(t.Params) != len(u.Params) || t.Variadic != u.Variadic {
+		return false
+	}
+	match := func(a, b types.Type) bool {
+		if p, ok := a.(*types.PointerType); ok {
+			if q, ok := b.(*types.PointerType); ok {
+				return p.AddrSpace == q.AddrSpace
+			}
+		}
+		return a.Equal(b)
+	}
+	if !match(t.RetType, u.RetType) {
+		return false
+	}
+	for i := range t.Params {
+		if !match(t.Params[i], u.Params[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// usesValue reports whether the given instruction or terminator uses the given
+// value as operand.
+func usesValue(inst interface{}, v value.Value) bool {
+	for _, op := range operands(inst) {
+		if *op == v {
+			return true
+		}
+	}
+	return false
+}
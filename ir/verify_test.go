@@ -0,0 +1,279 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestVerifyMaskedIntrinsics(t *testing.T) {
+	vec := types.NewVector(4, types.I32)
+	ptrsType := types.NewVector(4, types.I32Ptr)
+	maskType := types.NewVector(4, types.I1)
+	gather := NewFunc("llvm.masked.gather.v4i32.v4p0i32", vec, NewParam("", ptrsType), NewParam("", types.I32), NewParam("", maskType), NewParam("", vec))
+	scatter := NewFunc("llvm.masked.scatter.v4i32.v4p0i32", types.Void, NewParam("", vec), NewParam("", ptrsType), NewParam("", types.I32), NewParam("", maskType))
+	align := constant.NewInt(types.I32, 4)
+	// Valid gather and scatter.
+	valid := NewFunc("valid", types.Void, NewParam("ptrs", ptrsType), NewParam("mask", maskType))
+	entry := valid.NewBlock("")
+	x := entry.NewCall(gather, valid.Params[0], align, valid.Params[1], constant.NewUndef(vec))
+	x.SetName("x")
+	entry.NewCall(scatter, x, valid.Params[0], align, valid.Params[1])
+	entry.NewRet(nil)
+	// Invalid gather; mask of mismatched vector length.
+	invalid := NewFunc("invalid", types.Void, NewParam("ptrs", ptrsType), NewParam("mask", types.NewVector(2, types.I1)))
+	entry = invalid.NewBlock("")
+	x = entry.NewCall(gather, invalid.Params[0], align, invalid.Params[1], constant.NewUndef(vec))
+	x.SetName("x")
+	entry.NewRet(nil)
+	golden := []struct {
+		f    *Function
+		want string
+	}{
+		{f: valid, want: ""},
+		{f: invalid, want: "invalid LLVM IR; @invalid: invalid mask type <2 x i1> of masked gather or scatter; expected <4 x i1> in `%x = call <4 x i32> @llvm.masked.gather.v4i32.v4p0i32(<4 x i32*> %ptrs, i32 4, <2 x i1> %mask, <4 x i32> undef)`"},
+	}
+	for _, g := range golden {
+		var got string
+		if err := g.f.Verify(); err != nil {
+			got = err.Error()
+		}
+		if g.want != got {
+			t.Errorf("verification error mismatch of %q; expected `%v`, got `%v`", g.f.Ident(), g.want, got)
+		}
+	}
+}
+
+func TestVerifySwiftError(t *testing.T) {
+	// Callee with swifterror parameter.
+	errPtr := types.NewPointer(types.I8Ptr)
+	param := NewParam("err", errPtr)
+	param.Attrs = append(param.Attrs, enum.ParamAttrSwiftError)
+	callee := NewFunc("callee", types.Void, param)
+	// Valid uses of swifterror alloca.
+	valid := NewFunc("valid", types.I8Ptr)
+	entry := valid.NewBlock("")
+	errVar := entry.NewAlloca(types.I8Ptr)
+	errVar.SwiftError = true
+	entry.NewStore(constant.NewNull(types.I8Ptr), errVar)
+	entry.NewCall(callee, errVar)
+	entry.NewRet(entry.NewLoad(errVar))
+	// Invalid use; swifterror pointer stored into normal memory.
+	invalid := NewFunc("invalid", types.Void, NewParam("p", types.NewPointer(errPtr)))
+	entry = invalid.NewBlock("")
+	errVar = entry.NewAlloca(types.I8Ptr)
+	errVar.SetName("err")
+	errVar.SwiftError = true
+	entry.NewStore(errVar, invalid.Params[0])
+	entry.NewRet(nil)
+	golden := []struct {
+		f    *Function
+		want string
+	}{
+		{f: valid, want: ""},
+		{f: invalid, want: "invalid LLVM IR; @invalid: swifterror value %err can only be loaded and stored from, or used as a swifterror argument; invalid use in `store i8** %err, i8*** %p`"},
+	}
+	for _, g := range golden {
+		var got string
+		if err := g.f.Verify(); err != nil {
+			got = err.Error()
+		}
+		if g.want != got {
+			t.Errorf("verification error mismatch of %q; expected `%v`, got `%v`", g.f.Ident(), g.want, got)
+		}
+	}
+}
+
+func TestVerifyRet(t *testing.T) {
+	pair := types.NewStruct(types.I32, types.I32)
+	// Valid aggregate return.
+	valid := NewFunc("valid", pair, NewParam("x", pair))
+	entry := valid.NewBlock("")
+	entry.NewRet(valid.Params[0])
+	// Valid vector return.
+	vec := types.NewVector(4, types.Float)
+	validVec := NewFunc("validVec", vec)
+	entry = validVec.NewBlock("")
+	entry.NewRet(constant.NewZeroInitializer(vec))
+	// Invalid return of scalar in function returning aggregate.
+	invalid := NewFunc("invalid", pair)
+	entry = invalid.NewBlock("")
+	entry.NewRet(constant.NewInt(types.I32, 42))
+	// Invalid return without value in function returning aggregate.
+	invalidVoid := NewFunc("invalidVoid", pair)
+	entry = invalidVoid.NewBlock("")
+	entry.NewRet(nil)
+	golden := []struct {
+		f    *Function
+		want string
+	}{
+		{f: valid, want: ""},
+		{f: validVec, want: ""},
+		{f: invalid, want: "invalid LLVM IR; @invalid: return type mismatch; expected { i32, i32 }, got i32 in `ret i32 42`"},
+		{f: invalidVoid, want: "invalid LLVM IR; @invalidVoid: function returning { i32, i32 } has `ret void` terminator without return value"},
+	}
+	for _, g := range golden {
+		var got string
+		if err := g.f.Verify(); err != nil {
+			got = err.Error()
+		}
+		if g.want != got {
+			t.Errorf("verification error mismatch of %q; expected `%v`, got `%v`", g.f.Ident(), g.want, got)
+		}
+	}
+	want := `define { i32, i32 } @valid({ i32, i32 } %x) {
+; <label>:0
+	ret { i32, i32 } %x
+}`
+	if got := valid.Def(); want != got {
+		t.Errorf("function mismatch; expected `%v`, got `%v`", want, got)
+	}
+}
+
+func TestVerifyComdat(t *testing.T) {
+	m := NewModule()
+	foo := &ComdatDef{Name: "foo", Kind: enum.SelectionKindAny}
+	m.ComdatDefs = append(m.ComdatDefs, foo)
+	// Valid comdat references.
+	g := m.NewGlobalDef("foo", constant.NewInt(types.I32, 0))
+	g.Linkage = enum.LinkageLinkOnceODR
+	g.Comdat = foo
+	f := m.NewFunc("f", types.Void)
+	f.Linkage = enum.LinkageWeak
+	f.Comdat = foo
+	f.NewBlock("").NewRet(nil)
+	if err := m.Verify(); err != nil {
+		t.Errorf("unexpected verification error; %v", err)
+	}
+	// Dangling comdat references.
+	bar := &ComdatDef{Name: "bar", Kind: enum.SelectionKindAny}
+	g.Comdat = bar
+	f.Comdat = bar
+	want := "invalid LLVM IR; @foo: reference to undefined comdat $bar; @f: reference to undefined comdat $bar"
+	var got string
+	if err := m.Verify(); err != nil {
+		got = err.Error()
+	}
+	if want != got {
+		t.Errorf("verification error mismatch; expected `%v`, got `%v`", want, got)
+	}
+}
+
+func TestVerifyMustTail(t *testing.T) {
+	x := NewParam("x", types.I32)
+	callee := NewFunc("callee", types.I32, NewParam("", types.I32))
+	// Valid musttail call.
+	valid := NewFunc("valid", types.I32, x)
+	entry := valid.NewBlock("")
+	entry.NewRet(entry.NewCall(callee, x).SetTail(enum.TailMustTail))
+	// Valid musttail call with bitcast of result.
+	ptr := types.NewPointer(types.I8)
+	ptrCallee := NewFunc("ptrCallee", ptr)
+	validCast := NewFunc("validCast", types.NewPointer(types.I32))
+	entry = validCast.NewBlock("")
+	call := entry.NewCall(ptrCallee).SetTail(enum.TailMustTail)
+	entry.NewRet(entry.NewBitCast(call, types.NewPointer(types.I32)))
+	// Invalid musttail call not followed by ret.
+	notLast := NewFunc("notLast", types.I32, NewParam("x", types.I32))
+	entry = notLast.NewBlock("")
+	call = entry.NewCall(callee, notLast.Params[0]).SetTail(enum.TailMustTail)
+	entry.NewRet(entry.NewAdd(call, constant.NewInt(types.I32, 1)))
+	// Invalid musttail call with mismatching signature.
+	mismatch := NewFunc("mismatch", types.I32)
+	entry = mismatch.NewBlock("")
+	entry.NewRet(entry.NewCall(callee, constant.NewInt(types.I32, 1)).SetTail(enum.TailMustTail))
+	// Invalid musttail call with mismatching calling convention.
+	cc := NewFunc("cc", types.I32, NewParam("x", types.I32))
+	entry = cc.NewBlock("")
+	call = entry.NewCall(callee, cc.Params[0]).SetTail(enum.TailMustTail)
+	call.CallingConv = enum.CallingConvFast
+	entry.NewRet(call)
+	golden := []struct {
+		f    *Function
+		want string
+	}{
+		{f: valid, want: ""},
+		{f: validCast, want: ""},
+		{f: notLast, want: "invalid LLVM IR; @notLast: musttail call must precede a ret terminator; invalid `%1 = musttail call i32 @callee(i32 %x)`"},
+		{f: mismatch, want: "invalid LLVM IR; @mismatch: musttail call signature mismatch; expected i32 (), got i32 (i32) in `%1 = musttail call i32 @callee(i32 1)`"},
+		{f: cc, want: "invalid LLVM IR; @cc: musttail call calling convention mismatch; expected none, got fastcc in `%1 = musttail call fastcc i32 @callee(i32 %x)`"},
+	}
+	for _, g := range golden {
+		if err := g.f.AssignIDs(); err != nil {
+			t.Fatalf("unable to assign IDs of %q; %+v", g.f.Ident(), err)
+		}
+		var got string
+		if err := g.f.Verify(); err != nil {
+			got = err.Error()
+		}
+		if g.want != got {
+			t.Errorf("verification error mismatch of %q; expected `%v`, got `%v`", g.f.Ident(), g.want, got)
+		}
+	}
+}
+
+func TestVerifyCallBr(t *testing.T) {
+	sig := types.NewFunc(types.I32, types.I32)
+	asm := NewInlineAsm(types.NewPointer(sig), "jmp ${1:l}", "=r,r,X")
+	// Valid asm goto.
+	valid := NewFunc("valid", types.I32, NewParam("x", types.I32))
+	entry := valid.NewBlock("entry")
+	normal := valid.NewBlock("normal")
+	other := valid.NewBlock("other")
+	term := entry.NewCallBr(asm, []value.Value{valid.Params[0]}, normal, other)
+	normal.NewRet(term)
+	other.NewRet(constant.NewInt(types.I32, 0))
+	// Invalid indirect destination in another function.
+	foreign := NewFunc("foreign", types.I32, NewParam("x", types.I32))
+	entry = foreign.NewBlock("entry")
+	normal = foreign.NewBlock("normal")
+	term = entry.NewCallBr(asm, []value.Value{foreign.Params[0]}, normal, valid.Blocks[2])
+	normal.NewRet(term)
+	// Invalid duplicate destination.
+	dup := NewFunc("dup", types.I32, NewParam("x", types.I32))
+	entry = dup.NewBlock("entry")
+	normal = dup.NewBlock("normal")
+	term = entry.NewCallBr(asm, []value.Value{dup.Params[0]}, normal, normal)
+	normal.NewRet(term)
+	// Invalid use of result on indirect edge.
+	phiUse := NewFunc("phiUse", types.I32, NewParam("x", types.I32))
+	entry = phiUse.NewBlock("entry")
+	normal = phiUse.NewBlock("normal")
+	other = phiUse.NewBlock("other")
+	term = entry.NewCallBr(asm, []value.Value{phiUse.Params[0]}, normal, other)
+	normal.NewRet(term)
+	other.NewRet(other.NewPhi(NewIncoming(term, entry)))
+	// Invalid use of result in indirect destination.
+	use := NewFunc("use", types.I32, NewParam("x", types.I32))
+	entry = use.NewBlock("entry")
+	normal = use.NewBlock("normal")
+	other = use.NewBlock("other")
+	term = entry.NewCallBr(asm, []value.Value{use.Params[0]}, normal, other)
+	normal.NewRet(term)
+	other.NewRet(other.NewAdd(term, constant.NewInt(types.I32, 1)))
+	golden := []struct {
+		f    *Function
+		want string
+	}{
+		{f: valid, want: ""},
+		{f: foreign, want: "invalid LLVM IR; @foreign: callbr destination %other is not a basic block of the function; invalid callbr terminator in basic block %entry"},
+		{f: dup, want: "invalid LLVM IR; @dup: duplicate callbr destination %normal; invalid callbr terminator in basic block %entry"},
+		{f: phiUse, want: "invalid LLVM IR; @phiUse: callbr result %0 used on indirect edge from basic block %entry; invalid `%1 = phi i32 [ %0, %entry ]` in basic block %other"},
+		{f: use, want: "invalid LLVM IR; @use: callbr result %0 used in indirect destination; invalid `%1 = add i32 %0, 1` in basic block %other"},
+	}
+	for _, g := range golden {
+		if err := g.f.AssignIDs(); err != nil {
+			t.Fatalf("unable to assign IDs of %q; %+v", g.f.Ident(), err)
+		}
+		var got string
+		if err := g.f.Verify(); err != nil {
+			got = err.Error()
+		}
+		if g.want != got {
+			t.Errorf("verification error mismatch of %q; expected `%v`, got `%v`", g.f.Ident(), g.want, got)
+		}
+	}
+}
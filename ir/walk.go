@@ -0,0 +1,99 @@
+package ir
+
+import (
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Walk ] ================================================================
+
+// Visitor is a module visitor. The Visit method of a visitor may be passed to
+// Walk; e.g. Walk(m, v.Visit).
+type Visitor interface {
+	// Visit visits the given node, and reports whether to descend into the
+	// children of the node.
+	Visit(node interface{}) bool
+}
+
+// Walk traverses the module in depth-first order. It invokes visit for each
+// node of the module, and descends into the children of the node if visit
+// returns true.
+//
+// Nodes are visited in the following order: global variables, aliases, IFuncs
+// and functions of the module, each in order of declaration. The children of a
+// global variable, alias or IFunc are the operands of its definition (e.g. the
+// initializer of a global variable). The children of a function are its
+// parameters and basic blocks; the children of a basic block are its
+// instructions and terminator; the children of an instruction or terminator
+// are its value operands. The children of a constant are its constant operands
+// (e.g. the operands of a constant expression).
+//
+// Global variables and functions used as operands are visited as operands, but
+// not descended into, as their children are visited as part of the module.
+func Walk(m *Module, visit func(node interface{}) bool) {
+	for _, g := range m.Globals {
+		if visit(g) && g.Init != nil {
+			walkValue(g.Init, visit)
+		}
+	}
+	for _, alias := range m.Aliases {
+		if visit(alias) {
+			walkValue(alias.Aliasee, visit)
+		}
+	}
+	for _, ifunc := range m.IFuncs {
+		if visit(ifunc) {
+			walkValue(ifunc.Resolver, visit)
+		}
+	}
+	for _, f := range m.Funcs {
+		if !visit(f) {
+			continue
+		}
+		for _, param := range f.Params {
+			visit(param)
+		}
+		for _, block := range f.Blocks {
+			if !visit(block) {
+				continue
+			}
+			for _, inst := range block.Insts {
+				walkInst(inst, visit)
+			}
+			if block.Term != nil {
+				walkInst(block.Term, visit)
+			}
+		}
+	}
+}
+
+// ### [ Helper functions ] ####################################################
+
+// walkInst traverses the given instruction or terminator and its value
+// operands.
+func walkInst(inst interface{}, visit func(node interface{}) bool) {
+	if !visit(inst) {
+		return
+	}
+	for _, op := range operands(inst) {
+		if *op != nil {
+			walkValue(*op, visit)
+		}
+	}
+}
+
+// walkValue traverses the given value and, if constant, its constant operands.
+func walkValue(v value.Value, visit func(node interface{}) bool) {
+	if !visit(v) {
+		return
+	}
+	c, ok := v.(constant.Constant)
+	if !ok {
+		return
+	}
+	for _, op := range constantOperands(c) {
+		if *op != nil {
+			walkValue(*op, visit)
+		}
+	}
+}
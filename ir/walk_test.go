@@ -0,0 +1,91 @@
+package ir_test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+)
+
+func Example_walk() {
+	// Create a module with a function computing x*x + 1.
+	m := ir.NewModule()
+	x := ir.NewParam("x", types.I32)
+	f := m.NewFunc("f", types.I32, x)
+	entry := f.NewBlock("entry")
+	sq := entry.NewMul(x, x)
+	sum := entry.NewAdd(sq, constant.NewInt(types.I32, 1))
+	entry.NewRet(sum)
+
+	// Count instructions by opcode.
+	c := newOpcodeCounter()
+	ir.Walk(m, c.Visit)
+	var opcodes []string
+	for opcode := range c.counts {
+		opcodes = append(opcodes, opcode)
+	}
+	sort.Strings(opcodes)
+	for _, opcode := range opcodes {
+		fmt.Printf("%s: %d\n", opcode, c.counts[opcode])
+	}
+
+	// Output:
+	//
+	// add: 1
+	// mul: 1
+	// ret: 1
+}
+
+// opcodeCounter is a module visitor which counts instructions and terminators
+// by opcode.
+type opcodeCounter struct {
+	// Number of instructions and terminators, keyed by opcode.
+	counts map[string]int
+}
+
+// newOpcodeCounter returns a new opcode counter.
+func newOpcodeCounter() *opcodeCounter {
+	return &opcodeCounter{counts: make(map[string]int)}
+}
+
+// Visit visits the given node; counting instructions and terminators by opcode.
+func (c *opcodeCounter) Visit(node interface{}) bool {
+	switch node := node.(type) {
+	case ir.Instruction:
+		c.counts[node.Opcode().String()]++
+	case ir.Terminator:
+		c.counts[node.Opcode().String()]++
+	default:
+		return true
+	}
+	// Skip operands of instructions and terminators.
+	return false
+}
+
+// Ensure that the opcode counter implements the ir.Visitor interface.
+var _ ir.Visitor = (*opcodeCounter)(nil)
+
+func TestWalk(t *testing.T) {
+	m := ir.NewModule()
+	g := m.NewGlobalDef("g", constant.NewInt(types.I32, 1))
+	x := ir.NewParam("x", types.I32)
+	f := m.NewFunc("f", types.I32, x)
+	entry := f.NewBlock("entry")
+	load := entry.NewLoad(g)
+	sum := entry.NewAdd(load, x)
+	entry.NewRet(sum)
+	var got []string
+	ir.Walk(m, func(node interface{}) bool {
+		got = append(got, fmt.Sprintf("%T", node))
+		// Skip operands of the add instruction.
+		return node != sum
+	})
+	want := "*ir.Global *constant.Int *ir.Function *ir.Param *ir.BasicBlock *ir.InstLoad *ir.Global *ir.InstAdd *ir.TermRet *ir.InstAdd"
+	if s := strings.Join(got, " "); s != want {
+		t.Errorf("walk mismatch; expected %q, got %q", want, s)
+	}
+}